@@ -11,6 +11,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
 	"github.com/raids-lab/crater/pkg/cronjob"
@@ -81,22 +82,31 @@ func (ci *ConfigInitializer) InitializeRegisterConfig() (*handler.RegisterConfig
 	prometheusClient := monitor.NewPrometheusClient(ci.backendConfig.PrometheusAPI)
 	registerConfig.PrometheusClient = prometheusClient
 
-	// init cron job manager
-	cronJobManager := cronjob.NewCronJobManager(
-		registerConfig.Client,
-		registerConfig.KubeClient,
-		prometheusClient,
-	)
-	registerConfig.CronJobManager = cronJobManager
-	go cronJobManager.SyncCronJob()
 	return registerConfig, nil
 }
 
 // SetupManagerDependencies 设置管理器依赖项
-func (ci *ConfigInitializer) SetupManagerDependencies(registerConfig *handler.RegisterConfig, mgr ctrl.Manager) {
+func (ci *ConfigInitializer) SetupManagerDependencies(registerConfig *handler.RegisterConfig, mgr ctrl.Manager) error {
 	registerConfig.Client = mgr.GetClient()
 
 	// 初始化 ServiceManager
 	serviceManager := crclient.NewServiceManager(mgr.GetClient(), registerConfig.KubeClient)
 	registerConfig.ServiceManager = serviceManager
+
+	// 初始化定时任务管理器，由 manager 负责在选主成功后启动调度器
+	cronJobManager := cronjob.NewCronJobManager(
+		registerConfig.Client,
+		registerConfig.KubeClient,
+		registerConfig.PrometheusClient,
+	)
+	registerConfig.CronJobManager = cronJobManager
+	if err := mgr.Add(cronJobManager); err != nil {
+		return fmt.Errorf("unable to add cron job manager: %w", err)
+	}
+
+	// 通知发件箱的投递任务同样只在主副本上运行
+	if err := mgr.Add(alert.NewOutboxWorker()); err != nil {
+		return fmt.Errorf("unable to add notification outbox worker: %w", err)
+	}
+	return nil
 }
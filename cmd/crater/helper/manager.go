@@ -17,6 +17,7 @@ import (
 
 	"github.com/raids-lab/crater/internal/handler"
 	"github.com/raids-lab/crater/pkg/aitaskctl"
+	"github.com/raids-lab/crater/pkg/alert"
 	aisystemv1alpha1 "github.com/raids-lab/crater/pkg/apis/aijob/v1alpha1"
 	recommenddljob "github.com/raids-lab/crater/pkg/apis/recommenddljob/v1"
 	"github.com/raids-lab/crater/pkg/config"
@@ -71,10 +72,9 @@ func (ms *ManagerSetup) CreateCRDManager() (manager.Manager, error) {
 func (ms *ManagerSetup) SetupCustomCRDAddon(
 	mgr manager.Manager,
 	registerConfig *handler.RegisterConfig,
-	stopCh context.Context,
 ) error {
 	// Setup AIJob
-	if err := ms.setupEMIASJob(mgr, registerConfig, stopCh); err != nil {
+	if err := ms.setupEMIASJob(mgr, registerConfig); err != nil {
 		return err
 	}
 
@@ -98,11 +98,16 @@ func (ms *ManagerSetup) SetupCustomCRDAddon(
 		return err
 	}
 
+	// Setup ClusterAlert
+	if err := ms.setupClusterAlert(mgr, registerConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // setupEMIASJob 设置AIJob相关组件
-func (ms *ManagerSetup) setupEMIASJob(mgr manager.Manager, registerConfig *handler.RegisterConfig, stopCh context.Context) error {
+func (ms *ManagerSetup) setupEMIASJob(mgr manager.Manager, registerConfig *handler.RegisterConfig) error {
 	var taskCtrl aitaskctl.TaskControllerInterface
 	if ms.backendConfig.SchedulerPlugins.EMIAS.Enable {
 		utilruntime.Must(aisystemv1alpha1.AddToScheme(mgr.GetScheme()))
@@ -133,13 +138,19 @@ func (ms *ManagerSetup) setupEMIASJob(mgr manager.Manager, registerConfig *handl
 		}
 
 		// 3. profiler config
+		var aijobProfiler *aitaskctl.Profiler
 		if ms.backendConfig.SchedulerPlugins.EMIAS.EnableProfiling {
-			aijobProfiler := aitaskctl.NewProfiler(mgr, registerConfig.PrometheusClient, ms.backendConfig.SchedulerPlugins.EMIAS.ProfilingTimeout)
+			aijobProfiler = aitaskctl.NewProfiler(mgr, registerConfig.PrometheusClient, ms.backendConfig.SchedulerPlugins.EMIAS.ProfilingTimeout)
 			taskCtrl.SetProfiler(aijobProfiler)
-			aijobProfiler.Start(stopCh)
 		}
 
-		err = taskCtrl.Start(stopCh)
+		// 4. 调度循环只在主副本上运行，避免多副本重复调度
+		err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			if aijobProfiler != nil {
+				aijobProfiler.Start(ctx)
+			}
+			return taskCtrl.Start(ctx)
+		}))
 		if err != nil {
 			return fmt.Errorf("unable to start task controller: %w", err)
 		}
@@ -190,3 +201,18 @@ func (ms *ManagerSetup) setupVolcano(mgr manager.Manager, registerConfig *handle
 	}
 	return nil
 }
+
+// setupClusterAlert 设置集群异常告警相关组件，节点状态实时检查，其余状态周期性检查
+func (ms *ManagerSetup) setupClusterAlert(mgr manager.Manager, registerConfig *handler.RegisterConfig) error {
+	if !ms.backendConfig.ClusterAlert.Enable {
+		return nil
+	}
+	nodeReconciler := reconciler.NewNodeReconciler(mgr.GetClient())
+	if err := nodeReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to set up node controller: %w", err)
+	}
+	if err := mgr.Add(alert.NewClusterChecker(mgr.GetClient(), registerConfig.PrometheusClient)); err != nil {
+		return fmt.Errorf("unable to add cluster checker: %w", err)
+	}
+	return nil
+}
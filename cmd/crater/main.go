@@ -88,10 +88,12 @@ func main() {
 	}
 
 	// Setup manager dependencies
-	configInit.SetupManagerDependencies(registerConfig, mgr)
+	if err = configInit.SetupManagerDependencies(registerConfig, mgr); err != nil {
+		klog.Fatalf("Failed to set up manager dependencies: %s", err)
+	}
 
 	// Setup custom CRD addons
-	err = managerSetup.SetupCustomCRDAddon(mgr, registerConfig, ctx)
+	err = managerSetup.SetupCustomCRDAddon(mgr, registerConfig)
 	if err != nil {
 		klog.Fatalf("Failed to set up custom CRD addon: %s", err)
 	}
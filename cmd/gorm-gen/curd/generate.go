@@ -44,6 +44,22 @@ func main() {
 		model.ApprovalOrder{},
 		model.CronJobRecord{},
 		model.CronJobConfig{},
+		model.NotificationPreference{},
+		model.InboxMessage{},
+		model.OutboxMessage{},
+		model.ClusterIncident{},
+		model.JobWebhook{},
+		model.JobWebhookDelivery{},
+		model.APIToken{},
+		model.UserSession{},
+		model.PermissionRole{},
+		model.UserPermissionRole{},
+		model.AuditLog{},
+		model.PasswordHistory{},
+		model.PasswordReset{},
+		model.UserMFA{},
+		model.TerminalRecording{},
+		model.SSHKey{},
 	)
 
 	// 执行并生成代码
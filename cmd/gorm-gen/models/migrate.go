@@ -627,6 +627,465 @@ func main() {
 				return tx.Migrator().DropTable("cron_job_configs")
 			},
 		},
+		{
+			ID: "202511031000",
+			Migrate: func(tx *gorm.DB) error {
+				type CronJobRecord struct {
+					Replica string `gorm:"type:varchar(256);index;comment:执行该任务的后端副本" json:"replica"`
+				}
+				return tx.Table("cron_job_records").Migrator().AddColumn(&CronJobRecord{}, "Replica")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				type CronJobRecord struct {
+					Replica string `gorm:"type:varchar(256);index;comment:执行该任务的后端副本" json:"replica"`
+				}
+				return tx.Table("cron_job_records").Migrator().DropColumn(&CronJobRecord{}, "Replica")
+			},
+		},
+		{
+			ID: "202511051400",
+			Migrate: func(tx *gorm.DB) error {
+				type NotificationPreference struct {
+					gorm.Model
+					UserID uint `gorm:"uniqueIndex;not null;comment:用户ID"`
+
+					AlertTypes datatypes.JSONType[[]model.AlertTypePreference] `gorm:"comment:各类型通知的接收设置"`
+
+					QuietHoursEnabled bool   `gorm:"type:boolean;default:false;comment:是否启用免打扰时段"`
+					QuietHoursStart   string `gorm:"type:varchar(5);comment:免打扰开始时间 (HH:MM)"`
+					QuietHoursEnd     string `gorm:"type:varchar(5);comment:免打扰结束时间 (HH:MM)"`
+
+					ReceiveAccountReminders bool `gorm:"type:boolean;default:false;comment:作为账户管理员时是否接收账户成员作业的清理提醒"`
+				}
+				type Alert struct {
+					Status  model.AlertStatus `gorm:"type:varchar(32);not null;default:sent;comment:通知处理结果 (sent, skipped)"`
+					Message string            `gorm:"type:text;comment:跳过发送的原因"`
+				}
+				if err := tx.Table("notification_preferences").Migrator().CreateTable(&NotificationPreference{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().AddColumn(&Alert{}, "Status"); err != nil {
+					return err
+				}
+				return tx.Migrator().AddColumn(&Alert{}, "Message")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				type Alert struct {
+					Status  model.AlertStatus `gorm:"type:varchar(32);not null;default:sent;comment:通知处理结果 (sent, skipped)"`
+					Message string            `gorm:"type:text;comment:跳过发送的原因"`
+				}
+				if err := tx.Migrator().DropColumn(&Alert{}, "Message"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&Alert{}, "Status"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable("notification_preferences")
+			},
+		},
+		{
+			ID: "202511081030",
+			Migrate: func(tx *gorm.DB) error {
+				type InboxMessage struct {
+					gorm.Model
+					UserID    uint                `gorm:"index;not null;comment:接收者ID"`
+					Category  model.InboxCategory `gorm:"type:varchar(32);not null;index;comment:消息类别 (job, approval)"`
+					Title     string              `gorm:"type:varchar(256);not null;comment:消息标题"`
+					Content   string              `gorm:"type:text;comment:消息内容"`
+					Link      string              `gorm:"type:varchar(512);comment:相关页面链接"`
+					JobName   string              `gorm:"type:varchar(256);index;comment:相关作业名"`
+					AlertType string              `gorm:"type:varchar(255);comment:通知类型"`
+					ReadAt    *time.Time          `gorm:"index;comment:已读时间"`
+				}
+				return tx.Table("inbox_messages").Migrator().CreateTable(&InboxMessage{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("inbox_messages")
+			},
+		},
+		{
+			ID: "202511101600",
+			Migrate: func(tx *gorm.DB) error {
+				type OutboxMessage struct {
+					gorm.Model
+					DedupeKey string                                          `gorm:"type:varchar(512);index;comment:去重键，同一键在未发送完成前不会重复入队"`
+					UserID    uint                                            `gorm:"index;comment:接收者ID"`
+					Receiver  datatypes.JSONType[model.UserAttribute]         `gorm:"comment:接收者信息快照"`
+					Channels  datatypes.JSONType[[]model.NotificationChannel] `gorm:"comment:投递渠道，为空表示所有可用渠道"`
+					Subject   string                                          `gorm:"type:varchar(512);not null;comment:通知标题"`
+					Body      string                                          `gorm:"type:text;comment:通知内容 (HTML)"`
+
+					Status        model.OutboxStatus                         `gorm:"type:varchar(32);not null;index;default:pending;comment:投递状态 (pending, sending, sent, failed)"`
+					Attempts      int                                        `gorm:"not null;default:0;comment:已尝试次数"`
+					MaxAttempts   int                                        `gorm:"not null;comment:最大尝试次数"`
+					NextAttemptAt time.Time                                  `gorm:"index;comment:下次尝试时间"`
+					LastError     string                                     `gorm:"type:text;comment:最近一次失败原因"`
+					SentAt        *time.Time                                 `gorm:"comment:发送成功时间"`
+					Deliveries    datatypes.JSONType[[]model.OutboxDelivery] `gorm:"comment:投递记录"`
+				}
+				return tx.Table("outbox_messages").Migrator().CreateTable(&OutboxMessage{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("outbox_messages")
+			},
+		},
+		{
+			ID: "202511121000",
+			Migrate: func(tx *gorm.DB) error {
+				type NotificationPreference struct {
+					DailyDigestChannels  datatypes.JSONType[[]model.NotificationChannel] `gorm:"comment:订阅每日作业摘要的渠道，为空表示不订阅"`
+					WeeklyDigestChannels datatypes.JSONType[[]model.NotificationChannel] `gorm:"comment:订阅每周作业摘要的渠道，为空表示不订阅"`
+				}
+				type CronJobConfig struct {
+					gorm.Model
+					Name    string            `gorm:"type:varchar(128);not null;index;unique;comment:Cronjob配置名称" json:"name"`
+					Type    model.CronJobType `gorm:"type:varchar(128);not null;index;comment:Cronjob类型" json:"type"`
+					Spec    string            `gorm:"type:varchar(128);not null;index;comment:Cron调度规范" json:"spec"`
+					Suspend bool              `gorm:"not null;default:false;comment:是否暂停执行" json:"suspend"`
+					Config  datatypes.JSON    `gorm:"type:jsonb;comment:Cronjob配置数据" json:"config"`
+					EntryID int               `gorm:"type:int;comment:Cronjob标识ID" json:"entry_id"`
+				}
+				migrator := tx.Table("notification_preferences").Migrator()
+				if err := migrator.AddColumn(&NotificationPreference{}, "DailyDigestChannels"); err != nil {
+					return err
+				}
+				if err := migrator.AddColumn(&NotificationPreference{}, "WeeklyDigestChannels"); err != nil {
+					return err
+				}
+
+				digestConfigs := []*CronJobConfig{
+					{
+						Name:    "send-daily-digest",
+						Type:    model.CronJobTypeDigestFunc,
+						Spec:    "0 9 * * *",
+						Suspend: true,
+						Config:  datatypes.JSON(`{"period": "daily"}`),
+						EntryID: -1,
+					},
+					{
+						Name:    "send-weekly-digest",
+						Type:    model.CronJobTypeDigestFunc,
+						Spec:    "0 9 * * 1",
+						Suspend: true,
+						Config:  datatypes.JSON(`{"period": "weekly"}`),
+						EntryID: -1,
+					},
+				}
+				for _, config := range digestConfigs {
+					if err := tx.Table("cron_job_configs").Where("name = ?", config.Name).FirstOrCreate(config).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				type NotificationPreference struct {
+					DailyDigestChannels  datatypes.JSONType[[]model.NotificationChannel] `gorm:"comment:订阅每日作业摘要的渠道，为空表示不订阅"`
+					WeeklyDigestChannels datatypes.JSONType[[]model.NotificationChannel] `gorm:"comment:订阅每周作业摘要的渠道，为空表示不订阅"`
+				}
+				if err := tx.Exec("DELETE FROM cron_job_configs WHERE name IN ?",
+					[]string{"send-daily-digest", "send-weekly-digest"}).Error; err != nil {
+					return err
+				}
+				migrator := tx.Table("notification_preferences").Migrator()
+				if err := migrator.DropColumn(&NotificationPreference{}, "WeeklyDigestChannels"); err != nil {
+					return err
+				}
+				return migrator.DropColumn(&NotificationPreference{}, "DailyDigestChannels")
+			},
+		},
+		{
+			ID: "202511141500",
+			Migrate: func(tx *gorm.DB) error {
+				type ClusterIncident struct {
+					gorm.Model
+					Kind        model.IncidentKind   `gorm:"type:varchar(64);not null;index:idx_cluster_incident_target;comment:异常类型"`
+					Target      string               `gorm:"type:varchar(256);not null;index:idx_cluster_incident_target;comment:异常对象 (节点名、资源名等)"`
+					Status      model.IncidentStatus `gorm:"type:varchar(32);not null;index;default:open;comment:异常状态 (open, resolved)"`
+					Message     string               `gorm:"type:text;comment:最近一次检查到的异常详情"`
+					Occurrences int                  `gorm:"not null;default:1;comment:检查到异常的次数"`
+					OpenedAt    time.Time            `gorm:"not null;index;comment:异常出现时间"`
+					LastSeenAt  time.Time            `gorm:"comment:最近一次检查到异常的时间"`
+					ResolvedAt  *time.Time           `gorm:"comment:异常恢复时间"`
+				}
+				return tx.Table("cluster_incidents").Migrator().CreateTable(&ClusterIncident{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("cluster_incidents")
+			},
+		},
+		{
+			ID: "202511161000",
+			Migrate: func(tx *gorm.DB) error {
+				type JobWebhook struct {
+					gorm.Model
+					UserID     uint                         `gorm:"not null;index;comment:创建者ID"`
+					AccountID  uint                         `gorm:"not null;index;comment:所属账户ID"`
+					JobName    string                       `gorm:"type:varchar(256);index;comment:作业名，为空表示账户级 Webhook"`
+					AllMembers bool                         `gorm:"not null;default:false;comment:账户级 Webhook 是否包含账户中其他成员的作业"`
+					URL        string                       `gorm:"type:varchar(1024);not null;comment:接收事件的 URL"`
+					Secret     string                       `gorm:"type:varchar(256);comment:签名密钥"`
+					Events     datatypes.JSONType[[]string] `gorm:"comment:订阅的作业状态，为空表示所有状态"`
+					Enabled    bool                         `gorm:"not null;default:true;comment:是否启用"`
+				}
+				type JobWebhookDelivery struct {
+					gorm.Model
+					WebhookID uint   `gorm:"not null;index;comment:Webhook ID"`
+					JobName   string `gorm:"type:varchar(256);not null;index;comment:作业名"`
+					Event     string `gorm:"type:varchar(64);not null;comment:事件 (作业状态)"`
+					Payload   string `gorm:"type:text;not null;comment:请求体 (JSON)"`
+
+					Status        model.OutboxStatus                         `gorm:"type:varchar(32);not null;index;default:pending;comment:投递状态 (pending, sending, sent, failed)"`
+					Attempts      int                                        `gorm:"not null;default:0;comment:已尝试次数"`
+					MaxAttempts   int                                        `gorm:"not null;comment:最大尝试次数"`
+					NextAttemptAt time.Time                                  `gorm:"index;comment:下次尝试时间"`
+					LastError     string                                     `gorm:"type:text;comment:最近一次失败原因"`
+					SentAt        *time.Time                                 `gorm:"comment:发送成功时间"`
+					Deliveries    datatypes.JSONType[[]model.OutboxDelivery] `gorm:"comment:投递记录"`
+				}
+				if err := tx.Table("job_webhooks").Migrator().CreateTable(&JobWebhook{}); err != nil {
+					return err
+				}
+				return tx.Table("job_webhook_deliveries").Migrator().CreateTable(&JobWebhookDelivery{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("job_webhook_deliveries", "job_webhooks")
+			},
+		},
+		{
+			ID: "202511181000",
+			Migrate: func(tx *gorm.DB) error {
+				type APIToken struct {
+					gorm.Model
+					UserID     uint       `gorm:"not null;index;comment:所属用户ID"`
+					AccountID  uint       `gorm:"not null;index;comment:绑定的账户ID"`
+					Name       string     `gorm:"type:varchar(128);not null;comment:令牌名称"`
+					Prefix     string     `gorm:"type:varchar(32);not null;comment:令牌前缀，用于辨认令牌"`
+					TokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex;comment:令牌的 SHA-256 摘要"`
+					Scope      string     `gorm:"type:varchar(32);not null;comment:权限范围 (read-only, submit, admin)"`
+					ExpiresAt  *time.Time `gorm:"comment:过期时间，为空表示永不过期"`
+					LastUsedAt *time.Time `gorm:"comment:最近使用时间"`
+				}
+				return tx.Table("api_tokens").Migrator().CreateTable(&APIToken{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("api_tokens")
+			},
+		},
+		{
+			ID: "202511191000",
+			Migrate: func(tx *gorm.DB) error {
+				type UserSession struct {
+					gorm.Model
+					SessionID   string     `gorm:"type:varchar(64);not null;uniqueIndex;comment:会话ID (刷新令牌ID)"`
+					UserID      uint       `gorm:"not null;index;comment:用户ID"`
+					IP          string     `gorm:"type:varchar(64);comment:登录IP"`
+					UserAgent   string     `gorm:"type:varchar(512);comment:登录客户端"`
+					ExpiresAt   time.Time  `gorm:"not null;index;comment:过期时间，刷新令牌时延长"`
+					RefreshedAt *time.Time `gorm:"comment:最近刷新时间"`
+					RevokedAt   *time.Time `gorm:"comment:撤销时间"`
+				}
+				return tx.Table("user_sessions").Migrator().CreateTable(&UserSession{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("user_sessions")
+			},
+		},
+		{
+			ID: "202511201000",
+			Migrate: func(tx *gorm.DB) error {
+				type PermissionRole struct {
+					gorm.Model
+					Name        string                       `gorm:"type:varchar(64);not null;uniqueIndex;comment:角色名"`
+					Description string                       `gorm:"type:varchar(256);comment:角色描述"`
+					Permissions datatypes.JSONType[[]string] `gorm:"comment:角色包含的权限"`
+				}
+				type UserPermissionRole struct {
+					gorm.Model
+					UserID uint `gorm:"not null;uniqueIndex:idx_user_permission_role;comment:用户ID"`
+					RoleID uint `gorm:"not null;uniqueIndex:idx_user_permission_role;index;comment:权限角色ID"`
+				}
+				if err := tx.Table("permission_roles").Migrator().CreateTable(&PermissionRole{}); err != nil {
+					return err
+				}
+				return tx.Table("user_permission_roles").Migrator().CreateTable(&UserPermissionRole{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("user_permission_roles", "permission_roles")
+			},
+		},
+		{
+			ID: "202511211000",
+			Migrate: func(tx *gorm.DB) error {
+				type AuditLog struct {
+					gorm.Model
+					UserID      uint                                  `gorm:"index;comment:操作者ID"`
+					Username    string                                `gorm:"type:varchar(128);index;comment:操作者用户名"`
+					AccountID   uint                                  `gorm:"index;comment:操作时所在的账户ID"`
+					AccountName string                                `gorm:"type:varchar(128);comment:操作时所在的账户名"`
+					APITokenID  uint                                  `gorm:"comment:使用的个人访问令牌ID，为 0 表示使用登录令牌"`
+					ClientIP    string                                `gorm:"type:varchar(64);comment:客户端IP"`
+					Method      string                                `gorm:"type:varchar(16);not null;comment:请求方法"`
+					Route       string                                `gorm:"type:varchar(256);not null;index;comment:路由模板"`
+					Path        string                                `gorm:"type:varchar(1024);not null;comment:请求路径"`
+					Params      datatypes.JSONType[map[string]string] `gorm:"comment:路径参数"`
+					Body        string                                `gorm:"type:text;comment:脱敏后的请求体"`
+					StatusCode  int                                   `gorm:"not null;comment:HTTP 状态码"`
+					ResultCode  int                                   `gorm:"not null;index;comment:响应中的业务错误码，0 表示成功"`
+					Message     string                                `gorm:"type:varchar(1024);comment:响应中的错误信息"`
+					LatencyMs   int64                                 `gorm:"not null;comment:处理耗时 (毫秒)"`
+				}
+				return tx.Table("audit_logs").Migrator().CreateTable(&AuditLog{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("audit_logs")
+			},
+		},
+		{
+			ID: "202511221000",
+			Migrate: func(tx *gorm.DB) error {
+				type PasswordHistory struct {
+					gorm.Model
+					UserID   uint   `gorm:"not null;index;comment:用户ID"`
+					Password string `gorm:"type:varchar(128);not null;comment:bcrypt 密码哈希"`
+				}
+				type PasswordReset struct {
+					gorm.Model
+					UserID    uint       `gorm:"not null;index;comment:用户ID"`
+					CodeHash  string     `gorm:"type:varchar(64);not null;comment:验证码的 SHA-256 哈希"`
+					ExpiresAt time.Time  `gorm:"not null;comment:过期时间"`
+					Attempts  int        `gorm:"not null;default:0;comment:验证失败次数"`
+					UsedAt    *time.Time `gorm:"comment:使用时间"`
+				}
+				if err := tx.Table("password_histories").Migrator().CreateTable(&PasswordHistory{}); err != nil {
+					return err
+				}
+				return tx.Table("password_resets").Migrator().CreateTable(&PasswordReset{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("password_resets", "password_histories")
+			},
+		},
+		{
+			ID: "202511231000",
+			Migrate: func(tx *gorm.DB) error {
+				type UserMFA struct {
+					gorm.Model
+					UserID         uint                         `gorm:"not null;uniqueIndex;comment:用户ID"`
+					Secret         string                       `gorm:"type:varchar(64);not null;comment:TOTP 密钥 (Base32)"`
+					Enabled        bool                         `gorm:"not null;default:false;comment:是否已启用"`
+					EnabledAt      *time.Time                   `gorm:"comment:启用时间"`
+					LastUsedStep   int64                        `gorm:"not null;default:0;comment:最近使用的时间步"`
+					RecoveryCodes  datatypes.JSONType[[]string] `gorm:"comment:恢复码哈希"`
+					FailedAttempts int                          `gorm:"not null;default:0;comment:连续验证失败次数"`
+					LockedUntil    *time.Time                   `gorm:"comment:验证失败过多时锁定到该时间"`
+				}
+				return tx.Table("user_mfas").Migrator().CreateTable(&UserMFA{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("user_mfas")
+			},
+		},
+		{
+			ID: "202511241000",
+			Migrate: func(tx *gorm.DB) error {
+				type CronJobConfig struct {
+					gorm.Model
+					Name    string            `gorm:"type:varchar(128);not null;index;unique;comment:Cronjob配置名称" json:"name"`
+					Type    model.CronJobType `gorm:"type:varchar(128);not null;index;comment:Cronjob类型" json:"type"`
+					Spec    string            `gorm:"type:varchar(128);not null;index;comment:Cron调度规范" json:"spec"`
+					Suspend bool              `gorm:"not null;default:false;comment:是否暂停执行" json:"suspend"`
+					Config  datatypes.JSON    `gorm:"type:jsonb;comment:Cronjob配置数据" json:"config"`
+					EntryID int               `gorm:"type:int;comment:Cronjob标识ID" json:"entry_id"`
+				}
+				// 默认暂停且只试运行，管理员配置组映射并确认差异报告后再关闭 dryRun
+				ldapSyncConfig := &CronJobConfig{
+					Name:    "sync-ldap-groups",
+					Type:    model.CronJobTypeLDAPSyncFunc,
+					Spec:    "0 * * * *",
+					Suspend: true,
+					Config:  datatypes.JSON(`{"dryRun": true, "defaultRole": "user", "defaultAccessMode": "rw", "groups": []}`),
+					EntryID: -1,
+				}
+				return tx.Table("cron_job_configs").Where("name = ?", ldapSyncConfig.Name).FirstOrCreate(ldapSyncConfig).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Exec("DELETE FROM cron_job_configs WHERE name = ?", "sync-ldap-groups").Error
+			},
+		},
+		{
+			ID: "202511251000",
+			Migrate: func(tx *gorm.DB) error {
+				type TerminalRecording struct {
+					gorm.Model
+					UserID        uint       `gorm:"index;comment:打开终端的用户ID"`
+					Username      string     `gorm:"type:varchar(128);index;comment:打开终端的用户名"`
+					IsAdmin       bool       `gorm:"not null;default:false;comment:是否为平台管理员会话"`
+					Namespace     string     `gorm:"type:varchar(128);not null;comment:Pod 命名空间"`
+					PodName       string     `gorm:"type:varchar(256);not null;index;comment:Pod 名称"`
+					ContainerName string     `gorm:"type:varchar(256);not null;comment:容器名称"`
+					StartedAt     time.Time  `gorm:"not null;index;comment:会话开始时间"`
+					EndedAt       *time.Time `gorm:"comment:会话结束时间，为空表示会话未结束或异常中断"`
+					Duration      float64    `gorm:"not null;default:0;comment:会话时长 (秒)"`
+					Size          int64      `gorm:"not null;default:0;comment:录像大小 (字节)"`
+					Truncated     bool       `gorm:"not null;default:false;comment:是否因超过大小上限被截断"`
+					Path          string     `gorm:"type:varchar(1024);comment:录像文件在共享存储中的路径"`
+					Data          []byte     `gorm:"comment:录像内容，未配置录像目录时使用"`
+				}
+				return tx.Table("terminal_recordings").Migrator().CreateTable(&TerminalRecording{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("terminal_recordings")
+			},
+		},
+		{
+			ID: "202511261000",
+			Migrate: func(tx *gorm.DB) error {
+				type SSHKey struct {
+					gorm.Model
+					UserID      uint       `gorm:"not null;index;comment:用户ID"`
+					Name        string     `gorm:"type:varchar(128);not null;comment:公钥名称"`
+					KeyType     string     `gorm:"type:varchar(64);not null;comment:公钥类型"`
+					PublicKey   string     `gorm:"type:text;not null;comment:公钥 (authorized_keys 格式，不含选项和注释)"`
+					Fingerprint string     `gorm:"type:varchar(128);not null;index;comment:SHA256 指纹"`
+					RevokedAt   *time.Time `gorm:"comment:撤销时间"`
+					RevokedBy   string     `gorm:"type:varchar(128);comment:撤销者用户名"`
+				}
+				return tx.Table("ssh_keys").Migrator().CreateTable(&SSHKey{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("ssh_keys")
+			},
+		},
+		{
+			ID: "202511271000",
+			Migrate: func(tx *gorm.DB) error {
+				type User struct {
+					OIDCIssuer  *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 身份提供方"`
+					OIDCSubject *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 用户标识 (sub)"`
+				}
+				if err := tx.Migrator().AddColumn(&User{}, "OIDCIssuer"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().AddColumn(&User{}, "OIDCSubject"); err != nil {
+					return err
+				}
+				return tx.Migrator().CreateIndex(&User{}, "idx_users_oidc_identity")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				type User struct {
+					OIDCIssuer  *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 身份提供方"`
+					OIDCSubject *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 用户标识 (sub)"`
+				}
+				if err := tx.Migrator().DropIndex(&User{}, "idx_users_oidc_identity"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&User{}, "OIDCIssuer"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&User{}, "OIDCSubject")
+			},
+		},
 	})
 
 	m.InitSchema(func(tx *gorm.DB) error {
@@ -650,6 +1109,22 @@ func main() {
 			&model.ApprovalOrder{},
 			&model.ResourceNetwork{},
 			&model.ResourceVGPU{},
+			&model.NotificationPreference{},
+			&model.InboxMessage{},
+			&model.OutboxMessage{},
+			&model.ClusterIncident{},
+			&model.JobWebhook{},
+			&model.JobWebhookDelivery{},
+			&model.APIToken{},
+			&model.UserSession{},
+			&model.PermissionRole{},
+			&model.UserPermissionRole{},
+			&model.AuditLog{},
+			&model.PasswordHistory{},
+			&model.PasswordReset{},
+			&model.UserMFA{},
+			&model.TerminalRecording{},
+			&model.SSHKey{},
 		)
 		if err != nil {
 			return err
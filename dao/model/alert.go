@@ -17,4 +17,30 @@ type Alert struct {
 	AlertTimestamp time.Time `gorm:"comment:邮件发送时间"`
 	AllowRepeat    bool      `gorm:"type:boolean;default:false;comment:是否允许重复发送"`
 	SendCount      int       `gorm:"not null;comment:邮件发送次数"`
+
+	Status  AlertStatus `gorm:"type:varchar(32);not null;default:sent;comment:通知处理结果 (sent, skipped, deferred)" json:"status"`
+	Message string      `gorm:"type:text;comment:跳过发送的原因" json:"message"`
+}
+
+// NotificationChannel 通知渠道
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail    NotificationChannel = "email"    // SMTP 邮件
+	NotificationChannelWebhook  NotificationChannel = "webhook"  // 通用 JSON Webhook
+	NotificationChannelFeishu   NotificationChannel = "feishu"   // 飞书机器人
+	NotificationChannelDingTalk NotificationChannel = "dingtalk" // 钉钉机器人
+	NotificationChannelWeCom    NotificationChannel = "wecom"    // 企业微信机器人
+	NotificationChannelWPS      NotificationChannel = "wps"      // WPS 机器人
+)
+
+func GetAllNotificationChannels() []NotificationChannel {
+	return []NotificationChannel{
+		NotificationChannelEmail,
+		NotificationChannelWebhook,
+		NotificationChannelFeishu,
+		NotificationChannelDingTalk,
+		NotificationChannelWeCom,
+		NotificationChannelWPS,
+	}
 }
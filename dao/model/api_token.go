@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APITokenScope 个人访问令牌的权限范围
+type APITokenScope string
+
+const (
+	// APITokenScopeReadOnly 只能发起只读请求
+	APITokenScopeReadOnly APITokenScope = "read-only"
+	// APITokenScopeSubmit 可以提交和管理自己的作业，不具有账户管理员和平台管理员权限
+	APITokenScopeSubmit APITokenScope = "submit"
+	// APITokenScopeAdmin 具有用户在账户和平台中的全部权限
+	APITokenScopeAdmin APITokenScope = "admin"
+)
+
+// APIToken 用户创建的个人访问令牌，用于命令行、CI 和脚本访问，绑定到一个账户。
+// 数据库中只保存令牌的 SHA-256 摘要，明文只在创建时返回一次
+type APIToken struct {
+	gorm.Model
+	UserID     uint          `gorm:"not null;index;comment:所属用户ID" json:"userID"`
+	AccountID  uint          `gorm:"not null;index;comment:绑定的账户ID" json:"accountID"`
+	Name       string        `gorm:"type:varchar(128);not null;comment:令牌名称" json:"name"`
+	Prefix     string        `gorm:"type:varchar(32);not null;comment:令牌前缀，用于辨认令牌" json:"prefix"`
+	TokenHash  string        `gorm:"type:varchar(64);not null;uniqueIndex;comment:令牌的 SHA-256 摘要" json:"-"`
+	Scope      APITokenScope `gorm:"type:varchar(32);not null;comment:权限范围 (read-only, submit, admin)" json:"scope"`
+	ExpiresAt  *time.Time    `gorm:"comment:过期时间，为空表示永不过期" json:"expiresAt"`
+	LastUsedAt *time.Time    `gorm:"comment:最近使用时间" json:"lastUsedAt"`
+}
+
+// Expired 判断令牌是否已过期
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
+}
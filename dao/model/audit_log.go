@@ -0,0 +1,26 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AuditLog 一次修改类 API 操作的审计记录
+type AuditLog struct {
+	gorm.Model
+	UserID      uint                                  `gorm:"index;comment:操作者ID" json:"userID"`
+	Username    string                                `gorm:"type:varchar(128);index;comment:操作者用户名" json:"username"`
+	AccountID   uint                                  `gorm:"index;comment:操作时所在的账户ID" json:"accountID"`
+	AccountName string                                `gorm:"type:varchar(128);comment:操作时所在的账户名" json:"accountName"`
+	APITokenID  uint                                  `gorm:"comment:使用的个人访问令牌ID，为 0 表示使用登录令牌" json:"apiTokenID"`
+	ClientIP    string                                `gorm:"type:varchar(64);comment:客户端IP" json:"clientIP"`
+	Method      string                                `gorm:"type:varchar(16);not null;comment:请求方法" json:"method"`
+	Route       string                                `gorm:"type:varchar(256);not null;index;comment:路由模板" json:"route"`
+	Path        string                                `gorm:"type:varchar(1024);not null;comment:请求路径" json:"path"`
+	Params      datatypes.JSONType[map[string]string] `gorm:"comment:路径参数" json:"params"`
+	Body        string                                `gorm:"type:text;comment:脱敏后的请求体" json:"body"`
+	StatusCode  int                                   `gorm:"not null;comment:HTTP 状态码" json:"statusCode"`
+	ResultCode  int                                   `gorm:"not null;index;comment:响应中的业务错误码，0 表示成功" json:"resultCode"`
+	Message     string                                `gorm:"type:varchar(1024);comment:响应中的错误信息" json:"message"`
+	LatencyMs   int64                                 `gorm:"not null;comment:处理耗时 (毫秒)" json:"latencyMs"`
+}
@@ -22,6 +22,7 @@ type CronJobRecord struct {
 	Status      CronJobRecordStatus `gorm:"type:varchar(128);not null;index;default:unknown;comment:执行状态" json:"status"`
 	Message     string              `gorm:"type:text;comment:执行消息或错误信息" json:"message"`
 	JobData     datatypes.JSON      `gorm:"type:jsonb;comment:任务数据(包含提醒和删除的任务列表)" json:"jobData"`
+	Replica     string              `gorm:"type:varchar(256);index;comment:执行该任务的后端副本" json:"replica"`
 }
 
 // TableName 指定表名
@@ -37,11 +38,16 @@ func (c CronJobType) String() string {
 
 const (
 	CronJobTypeCleanerFunc CronJobType = "cleaner_function"
+	CronJobTypeDigestFunc  CronJobType = "digest_function"
+	// CronJobTypeLDAPSyncFunc 将 LDAP 组成员同步到账户
+	CronJobTypeLDAPSyncFunc CronJobType = "ldap_sync_function"
 )
 
 func GetAllCronJobTypes() []CronJobType {
 	return []CronJobType{
 		CronJobTypeCleanerFunc,
+		CronJobTypeDigestFunc,
+		CronJobTypeLDAPSyncFunc,
 	}
 }
 
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InboxCategory 站内消息类别
+type InboxCategory string
+
+const (
+	InboxCategoryJob      InboxCategory = "job"      // 作业通知
+	InboxCategoryApproval InboxCategory = "approval" // 审批结果通知
+)
+
+// InboxMessage 站内消息，所有通知都会写入接收者的收件箱，与外部通知渠道是否启用无关
+type InboxMessage struct {
+	gorm.Model
+	UserID    uint          `gorm:"index;not null;comment:接收者ID" json:"userID"`
+	Category  InboxCategory `gorm:"type:varchar(32);not null;index;comment:消息类别 (job, approval)" json:"category"`
+	Title     string        `gorm:"type:varchar(256);not null;comment:消息标题" json:"title"`
+	Content   string        `gorm:"type:text;comment:消息内容" json:"content"`
+	Link      string        `gorm:"type:varchar(512);comment:相关页面链接" json:"link"`
+	JobName   string        `gorm:"type:varchar(256);index;comment:相关作业名" json:"jobName"`
+	AlertType string        `gorm:"type:varchar(255);comment:通知类型" json:"alertType"`
+	ReadAt    *time.Time    `gorm:"index;comment:已读时间" json:"readAt"`
+}
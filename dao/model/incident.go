@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IncidentKind 集群异常的类型
+type IncidentKind string
+
+const (
+	IncidentKindNodeNotReady          IncidentKind = "node_not_ready"         // 节点 NotReady
+	IncidentKindGPUMissing            IncidentKind = "gpu_missing"            // 节点可分配的 GPU 少于实际数量
+	IncidentKindResourceDropped       IncidentKind = "resource_dropped"       // 集群资源总量低于上次同步的数量
+	IncidentKindBuildFailures         IncidentKind = "build_failures"         // 镜像构建作业连续失败
+	IncidentKindPrometheusUnreachable IncidentKind = "prometheus_unreachable" // Prometheus API 无法访问
+)
+
+// IncidentStatus 集群异常的状态
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen     IncidentStatus = "open"     // 异常尚未恢复
+	IncidentStatusResolved IncidentStatus = "resolved" // 异常已恢复
+)
+
+// ClusterIncident 集群异常记录，同一对象的同类异常在恢复前只有一条 open 记录，
+// 只在异常出现和恢复时通知管理员
+type ClusterIncident struct {
+	gorm.Model
+	Kind        IncidentKind   `gorm:"type:varchar(64);not null;index:idx_cluster_incident_target;comment:异常类型" json:"kind"`
+	Target      string         `gorm:"type:varchar(256);not null;index:idx_cluster_incident_target;comment:异常对象 (节点名、资源名等)" json:"target"`
+	Status      IncidentStatus `gorm:"type:varchar(32);not null;index;default:open;comment:异常状态 (open, resolved)" json:"status"`
+	Message     string         `gorm:"type:text;comment:最近一次检查到的异常详情" json:"message"`
+	Occurrences int            `gorm:"not null;default:1;comment:检查到异常的次数" json:"occurrences"`
+	OpenedAt    time.Time      `gorm:"not null;index;comment:异常出现时间" json:"openedAt"`
+	LastSeenAt  time.Time      `gorm:"comment:最近一次检查到异常的时间" json:"lastSeenAt"`
+	ResolvedAt  *time.Time     `gorm:"comment:异常恢复时间" json:"resolvedAt"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// JobWebhook 用户注册的作业生命周期 Webhook，作业状态变化时向 URL 发送签名的 JSON 请求。
+// JobName 为空时对账户中该用户的所有作业生效，AllMembers 为 true 时（仅账户管理员可设置）对账户中所有成员的作业生效
+type JobWebhook struct {
+	gorm.Model
+	UserID     uint                         `gorm:"not null;index;comment:创建者ID" json:"userID"`
+	AccountID  uint                         `gorm:"not null;index;comment:所属账户ID" json:"accountID"`
+	JobName    string                       `gorm:"type:varchar(256);index;comment:作业名，为空表示账户级 Webhook" json:"jobName"`
+	AllMembers bool                         `gorm:"not null;default:false;comment:账户级 Webhook 是否包含账户中其他成员的作业" json:"allMembers"`
+	URL        string                       `gorm:"type:varchar(1024);not null;comment:接收事件的 URL" json:"url"`
+	Secret     string                       `gorm:"type:varchar(256);comment:签名密钥" json:"-"`
+	Events     datatypes.JSONType[[]string] `gorm:"comment:订阅的作业状态，为空表示所有状态" json:"events"`
+	Enabled    bool                         `gorm:"not null;default:true;comment:是否启用" json:"enabled"`
+}
+
+// GetAllJobWebhookEvents 返回 Webhook 可以订阅的作业状态
+func GetAllJobWebhookEvents() []string {
+	phases := []batch.JobPhase{
+		batch.Pending, batch.Running, batch.Restarting, batch.Completing, batch.Completed,
+		batch.Aborting, batch.Aborted, batch.Terminating, batch.Terminated, batch.Failed,
+		Deleted, Freed,
+	}
+	events := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		events = append(events, string(phase))
+	}
+	return events
+}
+
+// Subscribed 判断 Webhook 是否订阅了作业进入 phase 状态的事件
+func (w *JobWebhook) Subscribed(phase string) bool {
+	events := w.Events.Data()
+	if len(events) == 0 {
+		return true
+	}
+	for _, event := range events {
+		if event == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// JobWebhookDelivery 作业事件的投递记录，投递失败时按退避策略重试，状态与发件箱一致
+type JobWebhookDelivery struct {
+	gorm.Model
+	WebhookID uint   `gorm:"not null;index;comment:Webhook ID" json:"webhookID"`
+	JobName   string `gorm:"type:varchar(256);not null;index;comment:作业名" json:"jobName"`
+	Event     string `gorm:"type:varchar(64);not null;comment:事件 (作业状态)" json:"event"`
+	Payload   string `gorm:"type:text;not null;comment:请求体 (JSON)" json:"payload"`
+
+	Status        OutboxStatus                         `gorm:"type:varchar(32);not null;index;default:pending;comment:投递状态 (pending, sending, sent, failed)" json:"status"`
+	Attempts      int                                  `gorm:"not null;default:0;comment:已尝试次数" json:"attempts"`
+	MaxAttempts   int                                  `gorm:"not null;comment:最大尝试次数" json:"maxAttempts"`
+	NextAttemptAt time.Time                            `gorm:"index;comment:下次尝试时间" json:"nextAttemptAt"`
+	LastError     string                               `gorm:"type:text;comment:最近一次失败原因" json:"lastError"`
+	SentAt        *time.Time                           `gorm:"comment:发送成功时间" json:"sentAt"`
+	Deliveries    datatypes.JSONType[[]OutboxDelivery] `gorm:"comment:投递记录" json:"deliveries"`
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AlertTypePreference 某一类通知的接收设置
+type AlertTypePreference struct {
+	AlertType AlertType             `json:"alertType"` // 通知类型
+	Enabled   bool                  `json:"enabled"`   // 是否接收
+	Channels  []NotificationChannel `json:"channels"`  // 接收渠道，为空表示所有可用渠道
+}
+
+// NotificationPreference 用户的通知偏好设置，未设置时使用默认值（接收所有通知）
+type NotificationPreference struct {
+	gorm.Model
+	UserID uint `gorm:"uniqueIndex;not null;comment:用户ID"`
+
+	AlertTypes datatypes.JSONType[[]AlertTypePreference] `gorm:"comment:各类型通知的接收设置"`
+
+	QuietHoursEnabled bool   `gorm:"type:boolean;default:false;comment:是否启用免打扰时段"`
+	QuietHoursStart   string `gorm:"type:varchar(5);comment:免打扰开始时间 (HH:MM)"`
+	QuietHoursEnd     string `gorm:"type:varchar(5);comment:免打扰结束时间 (HH:MM)"`
+
+	ReceiveAccountReminders bool `gorm:"type:boolean;default:false;comment:作为账户管理员时是否接收账户成员作业的清理提醒"`
+
+	DailyDigestChannels  datatypes.JSONType[[]NotificationChannel] `gorm:"comment:订阅每日作业摘要的渠道，为空表示不订阅"`
+	WeeklyDigestChannels datatypes.JSONType[[]NotificationChannel] `gorm:"comment:订阅每周作业摘要的渠道，为空表示不订阅"`
+}
+
+// DigestPeriod 作业摘要的统计周期
+type DigestPeriod string
+
+const (
+	DigestPeriodDaily  DigestPeriod = "daily"  // 每日摘要
+	DigestPeriodWeekly DigestPeriod = "weekly" // 每周摘要
+)
+
+// Duration 返回统计周期的时长
+func (p DigestPeriod) Duration() time.Duration {
+	if p == DigestPeriodWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// AlertStatus 通知的处理结果
+type AlertStatus string
+
+const (
+	AlertStatusSent     AlertStatus = "sent"     // 已发送
+	AlertStatusSkipped  AlertStatus = "skipped"  // 根据用户偏好跳过
+	AlertStatusDeferred AlertStatus = "deferred" // 处于免打扰时段，推迟到时段结束后发送
+)
+
+// NotificationLanguage 通知模板的语言
+type NotificationLanguage string
+
+const (
+	NotificationLanguageZh NotificationLanguage = "zh" // 中文
+	NotificationLanguageEn NotificationLanguage = "en" // 英文
+)
+
+// GetAllNotificationLanguages 返回所有支持的通知语言
+func GetAllNotificationLanguages() []NotificationLanguage {
+	return []NotificationLanguage{NotificationLanguageZh, NotificationLanguageEn}
+}
+
+// GetAllAlertTypes 返回所有作业通知类型
+func GetAllAlertTypes() []AlertType {
+	return []AlertType{
+		JobRunningAlert,
+		JobFailedAlert,
+		JobCompletedAlert,
+		LowGPUJobRemindedAlert,
+		LowGPUJobDeletedAlert,
+		LongTimeJobRemindedAlert,
+		LongTimeJobDeletedAlert,
+	}
+}
+
+// IsCleanerAlert 判断是否为作业清理相关的通知，这类通知与作业即将或已经被删除有关，
+// 不受免打扰时段限制，并且可以抄送给账户管理员
+func (t AlertType) IsCleanerAlert() bool {
+	switch t {
+	case LowGPUJobRemindedAlert, LowGPUJobDeletedAlert, LongTimeJobRemindedAlert, LongTimeJobDeletedAlert:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetAlertTypePreference 返回指定类型通知的接收设置，未设置时默认通过所有渠道接收
+func (p *NotificationPreference) GetAlertTypePreference(alertType AlertType) AlertTypePreference {
+	for _, pref := range p.AlertTypes.Data() {
+		if pref.AlertType == alertType {
+			return pref
+		}
+	}
+	return AlertTypePreference{AlertType: alertType, Enabled: true}
+}
+
+// InQuietHours 判断给定时间是否处于免打扰时段，结束时间早于开始时间表示跨越午夜
+func (p *NotificationPreference) InQuietHours(now time.Time) bool {
+	_, ok := p.QuietHoursEndAt(now)
+	return ok
+}
+
+// QuietHoursEndAt 给定时间处于免打扰时段时，返回本次免打扰时段的结束时间
+func (p *NotificationPreference) QuietHoursEndAt(now time.Time) (time.Time, bool) {
+	if !p.QuietHoursEnabled {
+		return time.Time{}, false
+	}
+	start, err := ParseClock(p.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := ParseClock(p.QuietHoursEnd)
+	if err != nil {
+		return time.Time{}, false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	var in bool
+	if start <= end {
+		in = cur >= start && cur < end
+	} else {
+		in = cur >= start || cur < end
+	}
+	if !in {
+		return time.Time{}, false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endAt := midnight.Add(time.Duration(end) * time.Minute)
+	if !endAt.After(now) {
+		// 跨越午夜的时段在当天开始，次日结束
+		endAt = endAt.AddDate(0, 0, 1)
+	}
+	return endAt, true
+}
+
+// GetDigestChannels 返回用户订阅该周期作业摘要的渠道，为空表示不订阅
+func (p *NotificationPreference) GetDigestChannels(period DigestPeriod) []NotificationChannel {
+	switch period {
+	case DigestPeriodDaily:
+		return p.DailyDigestChannels.Data()
+	case DigestPeriodWeekly:
+		return p.WeeklyDigestChannels.Data()
+	default:
+		return nil
+	}
+}
+
+// ParseClock 将 HH:MM 格式的时间解析为从零点开始的分钟数
+func ParseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// OutboxStatus 待发送通知的状态
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending" // 等待发送或等待重试
+	OutboxStatusSending OutboxStatus = "sending" // 正在发送
+	OutboxStatusSent    OutboxStatus = "sent"    // 发送成功
+	OutboxStatusFailed  OutboxStatus = "failed"  // 重试次数耗尽，发送失败
+)
+
+// OutboxDelivery 一次投递尝试的记录
+type OutboxDelivery struct {
+	Attempt   int       `json:"attempt"`         // 第几次尝试
+	Timestamp time.Time `json:"timestamp"`       // 尝试时间
+	Replica   string    `json:"replica"`         // 执行投递的后端副本
+	Error     string    `json:"error,omitempty"` // 失败原因，成功时为空
+}
+
+// OutboxMessage 通知发件箱，通知先写入发件箱，再由后台任务异步投递并在失败时按退避策略重试
+type OutboxMessage struct {
+	gorm.Model
+	DedupeKey string                                    `gorm:"type:varchar(512);index;comment:去重键，同一键在未发送完成前不会重复入队" json:"dedupeKey"`
+	UserID    uint                                      `gorm:"index;comment:接收者ID" json:"userID"`
+	Receiver  datatypes.JSONType[UserAttribute]         `gorm:"comment:接收者信息快照" json:"receiver"`
+	Channels  datatypes.JSONType[[]NotificationChannel] `gorm:"comment:投递渠道，为空表示所有可用渠道" json:"channels"`
+	Subject   string                                    `gorm:"type:varchar(512);not null;comment:通知标题" json:"subject"`
+	Body      string                                    `gorm:"type:text;comment:通知内容 (HTML)" json:"body"`
+
+	Status        OutboxStatus                         `gorm:"type:varchar(32);not null;index;default:pending;comment:投递状态 (pending, sending, sent, failed)" json:"status"`
+	Attempts      int                                  `gorm:"not null;default:0;comment:已尝试次数" json:"attempts"`
+	MaxAttempts   int                                  `gorm:"not null;comment:最大尝试次数" json:"maxAttempts"`
+	NextAttemptAt time.Time                            `gorm:"index;comment:下次尝试时间" json:"nextAttemptAt"`
+	LastError     string                               `gorm:"type:text;comment:最近一次失败原因" json:"lastError"`
+	SentAt        *time.Time                           `gorm:"comment:发送成功时间" json:"sentAt"`
+	Deliveries    datatypes.JSONType[[]OutboxDelivery] `gorm:"comment:投递记录" json:"deliveries"`
+}
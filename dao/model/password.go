@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordHistory 普通认证用户使用过的密码哈希，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index;comment:用户ID"`
+	Password string `gorm:"type:varchar(128);not null;comment:bcrypt 密码哈希"`
+}
+
+// PasswordReset 忘记密码时通过邮件发送的一次性验证码，只保存验证码的哈希
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index;comment:用户ID"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;comment:验证码的 SHA-256 哈希"`
+	ExpiresAt time.Time  `gorm:"not null;comment:过期时间"`
+	Attempts  int        `gorm:"not null;default:0;comment:验证失败次数"`
+	UsedAt    *time.Time `gorm:"comment:使用时间"`
+}
+
+// Usable 判断验证码是否未使用、未过期且未超过允许的失败次数
+func (r *PasswordReset) Usable(now time.Time, maxAttempts int) bool {
+	return r.UsedAt == nil && now.Before(r.ExpiresAt) && r.Attempts < maxAttempts
+}
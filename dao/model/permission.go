@@ -0,0 +1,71 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Permission 管理接口的细粒度权限，格式为 <资源>:<操作>
+type Permission string
+
+const (
+	PermissionNodesRead       Permission = "nodes:read"       // 查看节点及节点上的 Pod
+	PermissionNodesWrite      Permission = "nodes:write"      // 修改节点标签、注解和污点
+	PermissionResourcesManage Permission = "resources:manage" // 管理资源类型、RDMA 与 vGPU 关联
+	PermissionImagesAdmin     Permission = "images:admin"     // 管理所有用户的镜像和构建任务
+	PermissionJobsReadAny     Permission = "jobs:read-any"    // 查看所有用户的作业
+	PermissionJobsEditAny     Permission = "jobs:edit-any"    // 修改任意作业的资源、锁定时间和保留白名单
+	PermissionJobsDeleteAny   Permission = "jobs:delete-any"  // 删除任意作业
+	PermissionAccountsManage  Permission = "accounts:manage"  // 管理账户及账户成员
+	PermissionUsersManage     Permission = "users:manage"     // 管理用户角色、属性和登录会话
+	PermissionDatasetsManage  Permission = "datasets:manage"  // 管理数据集共享
+	PermissionApprovalsManage Permission = "approvals:manage" // 处理审批工单
+	PermissionCronjobsEdit    Permission = "cronjobs:edit"    // 查看和修改定时任务
+	PermissionAlertsManage    Permission = "alerts:manage"    // 查看集群事件和通知投递，重发通知
+	PermissionRolesManage     Permission = "roles:manage"     // 管理权限角色及其分配
+	PermissionAuditRead       Permission = "audit:read"       // 查询和导出审计日志及终端录像
+)
+
+// GetAllPermissions 返回所有可分配的权限
+func GetAllPermissions() []Permission {
+	return []Permission{
+		PermissionNodesRead,
+		PermissionNodesWrite,
+		PermissionResourcesManage,
+		PermissionImagesAdmin,
+		PermissionJobsReadAny,
+		PermissionJobsEditAny,
+		PermissionJobsDeleteAny,
+		PermissionAccountsManage,
+		PermissionUsersManage,
+		PermissionDatasetsManage,
+		PermissionApprovalsManage,
+		PermissionCronjobsEdit,
+		PermissionAlertsManage,
+		PermissionRolesManage,
+		PermissionAuditRead,
+	}
+}
+
+// DefaultPermissions 平台角色默认拥有的权限：平台管理员拥有所有权限，其他角色没有管理权限
+func DefaultPermissions(role Role) []Permission {
+	if role == RoleAdmin {
+		return GetAllPermissions()
+	}
+	return nil
+}
+
+// PermissionRole 一组可分配给用户的权限，例如让助教管理节点而不授予完整的管理员权限
+type PermissionRole struct {
+	gorm.Model
+	Name        string                           `gorm:"type:varchar(64);not null;uniqueIndex;comment:角色名" json:"name"`
+	Description string                           `gorm:"type:varchar(256);comment:角色描述" json:"description"`
+	Permissions datatypes.JSONType[[]Permission] `gorm:"comment:角色包含的权限" json:"permissions"`
+}
+
+// UserPermissionRole 用户被分配的权限角色
+type UserPermissionRole struct {
+	gorm.Model
+	UserID uint `gorm:"not null;uniqueIndex:idx_user_permission_role;comment:用户ID" json:"userID"`
+	RoleID uint `gorm:"not null;uniqueIndex:idx_user_permission_role;index;comment:权限角色ID" json:"roleID"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SSHKey 用户登记的 SSH 公钥，未撤销的公钥会同步到作业容器的 authorized_keys 中。
+// 撤销时只记录撤销时间和撤销者，保留记录以便审计
+type SSHKey struct {
+	gorm.Model
+	UserID      uint       `gorm:"not null;index;comment:用户ID" json:"userID"`
+	Name        string     `gorm:"type:varchar(128);not null;comment:公钥名称" json:"name"`
+	KeyType     string     `gorm:"type:varchar(64);not null;comment:公钥类型" json:"keyType"`
+	PublicKey   string     `gorm:"type:text;not null;comment:公钥 (authorized_keys 格式，不含选项和注释)" json:"publicKey"`
+	Fingerprint string     `gorm:"type:varchar(128);not null;index;comment:SHA256 指纹" json:"fingerprint"`
+	RevokedAt   *time.Time `gorm:"comment:撤销时间" json:"revokedAt"`
+	RevokedBy   string     `gorm:"type:varchar(128);comment:撤销者用户名" json:"revokedBy"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TerminalRecording 一次 Web 终端会话的录像，内容为 asciinema v2 格式 (.cast)。
+// 配置了录像目录时内容保存在共享存储的 Path 文件中，否则保存在 Data 字段
+type TerminalRecording struct {
+	gorm.Model
+	UserID        uint       `gorm:"index;comment:打开终端的用户ID" json:"userID"`
+	Username      string     `gorm:"type:varchar(128);index;comment:打开终端的用户名" json:"username"`
+	IsAdmin       bool       `gorm:"not null;default:false;comment:是否为平台管理员会话" json:"isAdmin"`
+	Namespace     string     `gorm:"type:varchar(128);not null;comment:Pod 命名空间" json:"namespace"`
+	PodName       string     `gorm:"type:varchar(256);not null;index;comment:Pod 名称" json:"podName"`
+	ContainerName string     `gorm:"type:varchar(256);not null;comment:容器名称" json:"containerName"`
+	StartedAt     time.Time  `gorm:"not null;index;comment:会话开始时间" json:"startedAt"`
+	EndedAt       *time.Time `gorm:"comment:会话结束时间，为空表示会话未结束或异常中断" json:"endedAt"`
+	Duration      float64    `gorm:"not null;default:0;comment:会话时长 (秒)" json:"duration"`
+	Size          int64      `gorm:"not null;default:0;comment:录像大小 (字节)" json:"size"`
+	// Truncated 录像超过大小上限后不再记录后续内容
+	Truncated bool   `gorm:"not null;default:false;comment:是否因超过大小上限被截断" json:"truncated"`
+	Path      string `gorm:"type:varchar(1024);comment:录像文件在共享存储中的路径" json:"-"`
+	Data      []byte `gorm:"comment:录像内容，未配置录像目录时使用" json:"-"`
+}
@@ -38,6 +38,14 @@ type UserAttribute struct {
 	Phone  *string `json:"phone,omitempty"`  // 电话
 	Avatar *string `json:"avatar,omitempty"` // 头像
 
+	// 个人 IM 机器人 Webhook，配置后通知将优先发送到个人机器人
+	FeishuWebhook   *string `json:"feishuWebhook,omitempty"`   // 飞书机器人
+	DingTalkWebhook *string `json:"dingtalkWebhook,omitempty"` // 钉钉机器人
+	WeComWebhook    *string `json:"wecomWebhook,omitempty"`    // 企业微信机器人
+	WPSWebhook      *string `json:"wpsWebhook,omitempty"`      // WPS 机器人
+
+	Language *string `json:"language,omitempty"` // 通知语言 (zh, en)，未设置时使用平台默认语言
+
 	// UID and GID are used for Filesystem
 	UID *string `json:"uid,omitempty"` // UID
 	GID *string `json:"gid,omitempty"` // GID
@@ -55,6 +63,10 @@ type User struct {
 	ImageQuota          int64      `gorm:"type:bigint;default:-1;comment:用户在镜像仓库的配额"`
 	LastEmailVerifiedAt *time.Time `gorm:"comment:最后一次邮箱验证时间"`
 
+	// OIDC 登录只按 issuer + subject 匹配用户，用户名声明可能被用户修改
+	OIDCIssuer  *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 身份提供方"`
+	OIDCSubject *string `gorm:"type:varchar(256);uniqueIndex:idx_users_oidc_identity;comment:绑定的 OIDC 用户标识 (sub)"`
+
 	Attributes   datatypes.JSONType[UserAttribute] `gorm:"comment:用户的额外属性 (昵称、邮箱、电话、头像等)"`
 	UserAccounts []UserAccount
 	UserDatasets []UserDataset
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// UserMFA 用户的 TOTP 两步验证配置。
+// 绑定时先生成密钥 (Enabled 为 false)，用户使用验证器应用输入一次正确的验证码后才启用
+type UserMFA struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;uniqueIndex;comment:用户ID"`
+	Secret    string     `gorm:"type:varchar(64);not null;comment:TOTP 密钥 (Base32)"`
+	Enabled   bool       `gorm:"not null;default:false;comment:是否已启用"`
+	EnabledAt *time.Time `gorm:"comment:启用时间"`
+	// LastUsedStep 最近一次验证通过的时间步，同一验证码不能重复使用
+	LastUsedStep int64 `gorm:"not null;default:0;comment:最近使用的时间步"`
+	// RecoveryCodes 未使用的恢复码的 SHA-256 哈希，每个恢复码只能使用一次
+	RecoveryCodes  datatypes.JSONType[[]string] `gorm:"comment:恢复码哈希"`
+	FailedAttempts int                          `gorm:"not null;default:0;comment:连续验证失败次数"`
+	LockedUntil    *time.Time                   `gorm:"comment:验证失败过多时锁定到该时间"`
+}
+
+// Locked 判断是否因为连续验证失败而被暂时锁定
+func (m *UserMFA) Locked(now time.Time) bool {
+	return m.LockedUntil != nil && now.Before(*m.LockedUntil)
+}
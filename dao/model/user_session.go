@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserSession 用户的一次登录会话，以刷新令牌中的会话 ID 为键。
+// 同一会话中签发的访问令牌和刷新令牌都带有会话 ID，会话被撤销后这些令牌立即失效
+type UserSession struct {
+	gorm.Model
+	SessionID   string     `gorm:"type:varchar(64);not null;uniqueIndex;comment:会话ID (刷新令牌ID)" json:"sessionID"`
+	UserID      uint       `gorm:"not null;index;comment:用户ID" json:"userID"`
+	IP          string     `gorm:"type:varchar(64);comment:登录IP" json:"ip"`
+	UserAgent   string     `gorm:"type:varchar(512);comment:登录客户端" json:"userAgent"`
+	ExpiresAt   time.Time  `gorm:"not null;index;comment:过期时间，刷新令牌时延长" json:"expiresAt"`
+	RefreshedAt *time.Time `gorm:"comment:最近刷新时间" json:"refreshedAt"`
+	RevokedAt   *time.Time `gorm:"comment:撤销时间" json:"revokedAt"`
+}
+
+// Active 判断会话是否未撤销且未过期
+func (s *UserSession) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -37,6 +37,8 @@ func newAlert(db *gorm.DB, opts ...gen.DOOption) alert {
 	_alert.AlertTimestamp = field.NewTime(tableName, "alert_timestamp")
 	_alert.AllowRepeat = field.NewBool(tableName, "allow_repeat")
 	_alert.SendCount = field.NewInt(tableName, "send_count")
+	_alert.Status = field.NewString(tableName, "status")
+	_alert.Message = field.NewString(tableName, "message")
 
 	_alert.fillFieldMap()
 
@@ -56,6 +58,8 @@ type alert struct {
 	AlertTimestamp field.Time   // 邮件发送时间
 	AllowRepeat    field.Bool   // 是否允许重复发送
 	SendCount      field.Int    // 邮件发送次数
+	Status         field.String // 通知处理结果 (sent, skipped, deferred)
+	Message        field.String // 跳过发送的原因
 
 	fieldMap map[string]field.Expr
 }
@@ -81,6 +85,8 @@ func (a *alert) updateTableName(table string) *alert {
 	a.AlertTimestamp = field.NewTime(table, "alert_timestamp")
 	a.AllowRepeat = field.NewBool(table, "allow_repeat")
 	a.SendCount = field.NewInt(table, "send_count")
+	a.Status = field.NewString(table, "status")
+	a.Message = field.NewString(table, "message")
 
 	a.fillFieldMap()
 
@@ -105,7 +111,7 @@ func (a *alert) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *alert) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 9)
+	a.fieldMap = make(map[string]field.Expr, 11)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
@@ -115,6 +121,8 @@ func (a *alert) fillFieldMap() {
 	a.fieldMap["alert_timestamp"] = a.AlertTimestamp
 	a.fieldMap["allow_repeat"] = a.AllowRepeat
 	a.fieldMap["send_count"] = a.SendCount
+	a.fieldMap["status"] = a.Status
+	a.fieldMap["message"] = a.Message
 }
 
 func (a alert) clone(db *gorm.DB) alert {
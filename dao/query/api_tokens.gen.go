@@ -0,0 +1,435 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newAPIToken(db *gorm.DB, opts ...gen.DOOption) aPIToken {
+	_aPIToken := aPIToken{}
+
+	_aPIToken.aPITokenDo.UseDB(db, opts...)
+	_aPIToken.aPITokenDo.UseModel(&model.APIToken{})
+
+	tableName := _aPIToken.aPITokenDo.TableName()
+	_aPIToken.ALL = field.NewAsterisk(tableName)
+	_aPIToken.ID = field.NewUint(tableName, "id")
+	_aPIToken.CreatedAt = field.NewTime(tableName, "created_at")
+	_aPIToken.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_aPIToken.DeletedAt = field.NewField(tableName, "deleted_at")
+	_aPIToken.UserID = field.NewUint(tableName, "user_id")
+	_aPIToken.AccountID = field.NewUint(tableName, "account_id")
+	_aPIToken.Name = field.NewString(tableName, "name")
+	_aPIToken.Prefix = field.NewString(tableName, "prefix")
+	_aPIToken.TokenHash = field.NewString(tableName, "token_hash")
+	_aPIToken.Scope = field.NewString(tableName, "scope")
+	_aPIToken.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_aPIToken.LastUsedAt = field.NewTime(tableName, "last_used_at")
+
+	_aPIToken.fillFieldMap()
+
+	return _aPIToken
+}
+
+type aPIToken struct {
+	aPITokenDo aPITokenDo
+
+	ALL        field.Asterisk
+	ID         field.Uint
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field
+	UserID     field.Uint   // 所属用户ID
+	AccountID  field.Uint   // 绑定的账户ID
+	Name       field.String // 令牌名称
+	Prefix     field.String // 令牌前缀，用于辨认令牌
+	TokenHash  field.String // 令牌的 SHA-256 摘要
+	Scope      field.String // 权限范围 (read-only, submit, admin)
+	ExpiresAt  field.Time   // 过期时间，为空表示永不过期
+	LastUsedAt field.Time   // 最近使用时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a aPIToken) Table(newTableName string) *aPIToken {
+	a.aPITokenDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a aPIToken) As(alias string) *aPIToken {
+	a.aPITokenDo.DO = *(a.aPITokenDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *aPIToken) updateTableName(table string) *aPIToken {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewUint(table, "id")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+	a.UserID = field.NewUint(table, "user_id")
+	a.AccountID = field.NewUint(table, "account_id")
+	a.Name = field.NewString(table, "name")
+	a.Prefix = field.NewString(table, "prefix")
+	a.TokenHash = field.NewString(table, "token_hash")
+	a.Scope = field.NewString(table, "scope")
+	a.ExpiresAt = field.NewTime(table, "expires_at")
+	a.LastUsedAt = field.NewTime(table, "last_used_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *aPIToken) WithContext(ctx context.Context) IAPITokenDo { return a.aPITokenDo.WithContext(ctx) }
+
+func (a aPIToken) TableName() string { return a.aPITokenDo.TableName() }
+
+func (a aPIToken) Alias() string { return a.aPITokenDo.Alias() }
+
+func (a aPIToken) Columns(cols ...field.Expr) gen.Columns { return a.aPITokenDo.Columns(cols...) }
+
+func (a *aPIToken) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *aPIToken) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 12)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+	a.fieldMap["user_id"] = a.UserID
+	a.fieldMap["account_id"] = a.AccountID
+	a.fieldMap["name"] = a.Name
+	a.fieldMap["prefix"] = a.Prefix
+	a.fieldMap["token_hash"] = a.TokenHash
+	a.fieldMap["scope"] = a.Scope
+	a.fieldMap["expires_at"] = a.ExpiresAt
+	a.fieldMap["last_used_at"] = a.LastUsedAt
+}
+
+func (a aPIToken) clone(db *gorm.DB) aPIToken {
+	a.aPITokenDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a aPIToken) replaceDB(db *gorm.DB) aPIToken {
+	a.aPITokenDo.ReplaceDB(db)
+	return a
+}
+
+type aPITokenDo struct{ gen.DO }
+
+type IAPITokenDo interface {
+	gen.SubQuery
+	Debug() IAPITokenDo
+	WithContext(ctx context.Context) IAPITokenDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAPITokenDo
+	WriteDB() IAPITokenDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAPITokenDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAPITokenDo
+	Not(conds ...gen.Condition) IAPITokenDo
+	Or(conds ...gen.Condition) IAPITokenDo
+	Select(conds ...field.Expr) IAPITokenDo
+	Where(conds ...gen.Condition) IAPITokenDo
+	Order(conds ...field.Expr) IAPITokenDo
+	Distinct(cols ...field.Expr) IAPITokenDo
+	Omit(cols ...field.Expr) IAPITokenDo
+	Join(table schema.Tabler, on ...field.Expr) IAPITokenDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAPITokenDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAPITokenDo
+	Group(cols ...field.Expr) IAPITokenDo
+	Having(conds ...gen.Condition) IAPITokenDo
+	Limit(limit int) IAPITokenDo
+	Offset(offset int) IAPITokenDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAPITokenDo
+	Unscoped() IAPITokenDo
+	Create(values ...*model.APIToken) error
+	CreateInBatches(values []*model.APIToken, batchSize int) error
+	Save(values ...*model.APIToken) error
+	First() (*model.APIToken, error)
+	Take() (*model.APIToken, error)
+	Last() (*model.APIToken, error)
+	Find() ([]*model.APIToken, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.APIToken, err error)
+	FindInBatches(result *[]*model.APIToken, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.APIToken) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAPITokenDo
+	Assign(attrs ...field.AssignExpr) IAPITokenDo
+	Joins(fields ...field.RelationField) IAPITokenDo
+	Preload(fields ...field.RelationField) IAPITokenDo
+	FirstOrInit() (*model.APIToken, error)
+	FirstOrCreate() (*model.APIToken, error)
+	FindByPage(offset int, limit int) (result []*model.APIToken, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAPITokenDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a aPITokenDo) Debug() IAPITokenDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a aPITokenDo) WithContext(ctx context.Context) IAPITokenDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a aPITokenDo) ReadDB() IAPITokenDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a aPITokenDo) WriteDB() IAPITokenDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a aPITokenDo) Session(config *gorm.Session) IAPITokenDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a aPITokenDo) Clauses(conds ...clause.Expression) IAPITokenDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a aPITokenDo) Returning(value interface{}, columns ...string) IAPITokenDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a aPITokenDo) Not(conds ...gen.Condition) IAPITokenDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a aPITokenDo) Or(conds ...gen.Condition) IAPITokenDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a aPITokenDo) Select(conds ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a aPITokenDo) Where(conds ...gen.Condition) IAPITokenDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a aPITokenDo) Order(conds ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a aPITokenDo) Distinct(cols ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a aPITokenDo) Omit(cols ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a aPITokenDo) Join(table schema.Tabler, on ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a aPITokenDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a aPITokenDo) RightJoin(table schema.Tabler, on ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a aPITokenDo) Group(cols ...field.Expr) IAPITokenDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a aPITokenDo) Having(conds ...gen.Condition) IAPITokenDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a aPITokenDo) Limit(limit int) IAPITokenDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a aPITokenDo) Offset(offset int) IAPITokenDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a aPITokenDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAPITokenDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a aPITokenDo) Unscoped() IAPITokenDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a aPITokenDo) Create(values ...*model.APIToken) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a aPITokenDo) CreateInBatches(values []*model.APIToken, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a aPITokenDo) Save(values ...*model.APIToken) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a aPITokenDo) First() (*model.APIToken, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.APIToken), nil
+	}
+}
+
+func (a aPITokenDo) Take() (*model.APIToken, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.APIToken), nil
+	}
+}
+
+func (a aPITokenDo) Last() (*model.APIToken, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.APIToken), nil
+	}
+}
+
+func (a aPITokenDo) Find() ([]*model.APIToken, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.APIToken), err
+}
+
+func (a aPITokenDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.APIToken, err error) {
+	buf := make([]*model.APIToken, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a aPITokenDo) FindInBatches(result *[]*model.APIToken, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a aPITokenDo) Attrs(attrs ...field.AssignExpr) IAPITokenDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a aPITokenDo) Assign(attrs ...field.AssignExpr) IAPITokenDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a aPITokenDo) Joins(fields ...field.RelationField) IAPITokenDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a aPITokenDo) Preload(fields ...field.RelationField) IAPITokenDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a aPITokenDo) FirstOrInit() (*model.APIToken, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.APIToken), nil
+	}
+}
+
+func (a aPITokenDo) FirstOrCreate() (*model.APIToken, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.APIToken), nil
+	}
+}
+
+func (a aPITokenDo) FindByPage(offset int, limit int) (result []*model.APIToken, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a aPITokenDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a aPITokenDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a aPITokenDo) Delete(models ...*model.APIToken) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *aPITokenDo) withDO(do gen.Dao) *aPITokenDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
@@ -0,0 +1,439 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newClusterIncident(db *gorm.DB, opts ...gen.DOOption) clusterIncident {
+	_clusterIncident := clusterIncident{}
+
+	_clusterIncident.clusterIncidentDo.UseDB(db, opts...)
+	_clusterIncident.clusterIncidentDo.UseModel(&model.ClusterIncident{})
+
+	tableName := _clusterIncident.clusterIncidentDo.TableName()
+	_clusterIncident.ALL = field.NewAsterisk(tableName)
+	_clusterIncident.ID = field.NewUint(tableName, "id")
+	_clusterIncident.CreatedAt = field.NewTime(tableName, "created_at")
+	_clusterIncident.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_clusterIncident.DeletedAt = field.NewField(tableName, "deleted_at")
+	_clusterIncident.Kind = field.NewString(tableName, "kind")
+	_clusterIncident.Target = field.NewString(tableName, "target")
+	_clusterIncident.Status = field.NewString(tableName, "status")
+	_clusterIncident.Message = field.NewString(tableName, "message")
+	_clusterIncident.Occurrences = field.NewInt(tableName, "occurrences")
+	_clusterIncident.OpenedAt = field.NewTime(tableName, "opened_at")
+	_clusterIncident.LastSeenAt = field.NewTime(tableName, "last_seen_at")
+	_clusterIncident.ResolvedAt = field.NewTime(tableName, "resolved_at")
+
+	_clusterIncident.fillFieldMap()
+
+	return _clusterIncident
+}
+
+type clusterIncident struct {
+	clusterIncidentDo clusterIncidentDo
+
+	ALL         field.Asterisk
+	ID          field.Uint
+	CreatedAt   field.Time
+	UpdatedAt   field.Time
+	DeletedAt   field.Field
+	Kind        field.String // 异常类型
+	Target      field.String // 异常对象 (节点名、资源名等)
+	Status      field.String // 异常状态 (open, resolved)
+	Message     field.String // 最近一次检查到的异常详情
+	Occurrences field.Int    // 检查到异常的次数
+	OpenedAt    field.Time   // 异常出现时间
+	LastSeenAt  field.Time   // 最近一次检查到异常的时间
+	ResolvedAt  field.Time   // 异常恢复时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (c clusterIncident) Table(newTableName string) *clusterIncident {
+	c.clusterIncidentDo.UseTable(newTableName)
+	return c.updateTableName(newTableName)
+}
+
+func (c clusterIncident) As(alias string) *clusterIncident {
+	c.clusterIncidentDo.DO = *(c.clusterIncidentDo.As(alias).(*gen.DO))
+	return c.updateTableName(alias)
+}
+
+func (c *clusterIncident) updateTableName(table string) *clusterIncident {
+	c.ALL = field.NewAsterisk(table)
+	c.ID = field.NewUint(table, "id")
+	c.CreatedAt = field.NewTime(table, "created_at")
+	c.UpdatedAt = field.NewTime(table, "updated_at")
+	c.DeletedAt = field.NewField(table, "deleted_at")
+	c.Kind = field.NewString(table, "kind")
+	c.Target = field.NewString(table, "target")
+	c.Status = field.NewString(table, "status")
+	c.Message = field.NewString(table, "message")
+	c.Occurrences = field.NewInt(table, "occurrences")
+	c.OpenedAt = field.NewTime(table, "opened_at")
+	c.LastSeenAt = field.NewTime(table, "last_seen_at")
+	c.ResolvedAt = field.NewTime(table, "resolved_at")
+
+	c.fillFieldMap()
+
+	return c
+}
+
+func (c *clusterIncident) WithContext(ctx context.Context) IClusterIncidentDo {
+	return c.clusterIncidentDo.WithContext(ctx)
+}
+
+func (c clusterIncident) TableName() string { return c.clusterIncidentDo.TableName() }
+
+func (c clusterIncident) Alias() string { return c.clusterIncidentDo.Alias() }
+
+func (c clusterIncident) Columns(cols ...field.Expr) gen.Columns {
+	return c.clusterIncidentDo.Columns(cols...)
+}
+
+func (c *clusterIncident) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := c.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (c *clusterIncident) fillFieldMap() {
+	c.fieldMap = make(map[string]field.Expr, 12)
+	c.fieldMap["id"] = c.ID
+	c.fieldMap["created_at"] = c.CreatedAt
+	c.fieldMap["updated_at"] = c.UpdatedAt
+	c.fieldMap["deleted_at"] = c.DeletedAt
+	c.fieldMap["kind"] = c.Kind
+	c.fieldMap["target"] = c.Target
+	c.fieldMap["status"] = c.Status
+	c.fieldMap["message"] = c.Message
+	c.fieldMap["occurrences"] = c.Occurrences
+	c.fieldMap["opened_at"] = c.OpenedAt
+	c.fieldMap["last_seen_at"] = c.LastSeenAt
+	c.fieldMap["resolved_at"] = c.ResolvedAt
+}
+
+func (c clusterIncident) clone(db *gorm.DB) clusterIncident {
+	c.clusterIncidentDo.ReplaceConnPool(db.Statement.ConnPool)
+	return c
+}
+
+func (c clusterIncident) replaceDB(db *gorm.DB) clusterIncident {
+	c.clusterIncidentDo.ReplaceDB(db)
+	return c
+}
+
+type clusterIncidentDo struct{ gen.DO }
+
+type IClusterIncidentDo interface {
+	gen.SubQuery
+	Debug() IClusterIncidentDo
+	WithContext(ctx context.Context) IClusterIncidentDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IClusterIncidentDo
+	WriteDB() IClusterIncidentDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IClusterIncidentDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IClusterIncidentDo
+	Not(conds ...gen.Condition) IClusterIncidentDo
+	Or(conds ...gen.Condition) IClusterIncidentDo
+	Select(conds ...field.Expr) IClusterIncidentDo
+	Where(conds ...gen.Condition) IClusterIncidentDo
+	Order(conds ...field.Expr) IClusterIncidentDo
+	Distinct(cols ...field.Expr) IClusterIncidentDo
+	Omit(cols ...field.Expr) IClusterIncidentDo
+	Join(table schema.Tabler, on ...field.Expr) IClusterIncidentDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IClusterIncidentDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IClusterIncidentDo
+	Group(cols ...field.Expr) IClusterIncidentDo
+	Having(conds ...gen.Condition) IClusterIncidentDo
+	Limit(limit int) IClusterIncidentDo
+	Offset(offset int) IClusterIncidentDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IClusterIncidentDo
+	Unscoped() IClusterIncidentDo
+	Create(values ...*model.ClusterIncident) error
+	CreateInBatches(values []*model.ClusterIncident, batchSize int) error
+	Save(values ...*model.ClusterIncident) error
+	First() (*model.ClusterIncident, error)
+	Take() (*model.ClusterIncident, error)
+	Last() (*model.ClusterIncident, error)
+	Find() ([]*model.ClusterIncident, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.ClusterIncident, err error)
+	FindInBatches(result *[]*model.ClusterIncident, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.ClusterIncident) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IClusterIncidentDo
+	Assign(attrs ...field.AssignExpr) IClusterIncidentDo
+	Joins(fields ...field.RelationField) IClusterIncidentDo
+	Preload(fields ...field.RelationField) IClusterIncidentDo
+	FirstOrInit() (*model.ClusterIncident, error)
+	FirstOrCreate() (*model.ClusterIncident, error)
+	FindByPage(offset int, limit int) (result []*model.ClusterIncident, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IClusterIncidentDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (c clusterIncidentDo) Debug() IClusterIncidentDo {
+	return c.withDO(c.DO.Debug())
+}
+
+func (c clusterIncidentDo) WithContext(ctx context.Context) IClusterIncidentDo {
+	return c.withDO(c.DO.WithContext(ctx))
+}
+
+func (c clusterIncidentDo) ReadDB() IClusterIncidentDo {
+	return c.Clauses(dbresolver.Read)
+}
+
+func (c clusterIncidentDo) WriteDB() IClusterIncidentDo {
+	return c.Clauses(dbresolver.Write)
+}
+
+func (c clusterIncidentDo) Session(config *gorm.Session) IClusterIncidentDo {
+	return c.withDO(c.DO.Session(config))
+}
+
+func (c clusterIncidentDo) Clauses(conds ...clause.Expression) IClusterIncidentDo {
+	return c.withDO(c.DO.Clauses(conds...))
+}
+
+func (c clusterIncidentDo) Returning(value interface{}, columns ...string) IClusterIncidentDo {
+	return c.withDO(c.DO.Returning(value, columns...))
+}
+
+func (c clusterIncidentDo) Not(conds ...gen.Condition) IClusterIncidentDo {
+	return c.withDO(c.DO.Not(conds...))
+}
+
+func (c clusterIncidentDo) Or(conds ...gen.Condition) IClusterIncidentDo {
+	return c.withDO(c.DO.Or(conds...))
+}
+
+func (c clusterIncidentDo) Select(conds ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Select(conds...))
+}
+
+func (c clusterIncidentDo) Where(conds ...gen.Condition) IClusterIncidentDo {
+	return c.withDO(c.DO.Where(conds...))
+}
+
+func (c clusterIncidentDo) Order(conds ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Order(conds...))
+}
+
+func (c clusterIncidentDo) Distinct(cols ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Distinct(cols...))
+}
+
+func (c clusterIncidentDo) Omit(cols ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Omit(cols...))
+}
+
+func (c clusterIncidentDo) Join(table schema.Tabler, on ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Join(table, on...))
+}
+
+func (c clusterIncidentDo) LeftJoin(table schema.Tabler, on ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.LeftJoin(table, on...))
+}
+
+func (c clusterIncidentDo) RightJoin(table schema.Tabler, on ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.RightJoin(table, on...))
+}
+
+func (c clusterIncidentDo) Group(cols ...field.Expr) IClusterIncidentDo {
+	return c.withDO(c.DO.Group(cols...))
+}
+
+func (c clusterIncidentDo) Having(conds ...gen.Condition) IClusterIncidentDo {
+	return c.withDO(c.DO.Having(conds...))
+}
+
+func (c clusterIncidentDo) Limit(limit int) IClusterIncidentDo {
+	return c.withDO(c.DO.Limit(limit))
+}
+
+func (c clusterIncidentDo) Offset(offset int) IClusterIncidentDo {
+	return c.withDO(c.DO.Offset(offset))
+}
+
+func (c clusterIncidentDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IClusterIncidentDo {
+	return c.withDO(c.DO.Scopes(funcs...))
+}
+
+func (c clusterIncidentDo) Unscoped() IClusterIncidentDo {
+	return c.withDO(c.DO.Unscoped())
+}
+
+func (c clusterIncidentDo) Create(values ...*model.ClusterIncident) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Create(values)
+}
+
+func (c clusterIncidentDo) CreateInBatches(values []*model.ClusterIncident, batchSize int) error {
+	return c.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (c clusterIncidentDo) Save(values ...*model.ClusterIncident) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Save(values)
+}
+
+func (c clusterIncidentDo) First() (*model.ClusterIncident, error) {
+	if result, err := c.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ClusterIncident), nil
+	}
+}
+
+func (c clusterIncidentDo) Take() (*model.ClusterIncident, error) {
+	if result, err := c.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ClusterIncident), nil
+	}
+}
+
+func (c clusterIncidentDo) Last() (*model.ClusterIncident, error) {
+	if result, err := c.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ClusterIncident), nil
+	}
+}
+
+func (c clusterIncidentDo) Find() ([]*model.ClusterIncident, error) {
+	result, err := c.DO.Find()
+	return result.([]*model.ClusterIncident), err
+}
+
+func (c clusterIncidentDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.ClusterIncident, err error) {
+	buf := make([]*model.ClusterIncident, 0, batchSize)
+	err = c.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (c clusterIncidentDo) FindInBatches(result *[]*model.ClusterIncident, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return c.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (c clusterIncidentDo) Attrs(attrs ...field.AssignExpr) IClusterIncidentDo {
+	return c.withDO(c.DO.Attrs(attrs...))
+}
+
+func (c clusterIncidentDo) Assign(attrs ...field.AssignExpr) IClusterIncidentDo {
+	return c.withDO(c.DO.Assign(attrs...))
+}
+
+func (c clusterIncidentDo) Joins(fields ...field.RelationField) IClusterIncidentDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Joins(_f))
+	}
+	return &c
+}
+
+func (c clusterIncidentDo) Preload(fields ...field.RelationField) IClusterIncidentDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Preload(_f))
+	}
+	return &c
+}
+
+func (c clusterIncidentDo) FirstOrInit() (*model.ClusterIncident, error) {
+	if result, err := c.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ClusterIncident), nil
+	}
+}
+
+func (c clusterIncidentDo) FirstOrCreate() (*model.ClusterIncident, error) {
+	if result, err := c.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ClusterIncident), nil
+	}
+}
+
+func (c clusterIncidentDo) FindByPage(offset int, limit int) (result []*model.ClusterIncident, count int64, err error) {
+	result, err = c.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = c.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (c clusterIncidentDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = c.Count()
+	if err != nil {
+		return
+	}
+
+	err = c.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (c clusterIncidentDo) Scan(result interface{}) (err error) {
+	return c.DO.Scan(result)
+}
+
+func (c clusterIncidentDo) Delete(models ...*model.ClusterIncident) (result gen.ResultInfo, err error) {
+	return c.DO.Delete(models)
+}
+
+func (c *clusterIncidentDo) withDO(do gen.Dao) *clusterIncidentDo {
+	c.DO = *do.(*gen.DO)
+	return c
+}
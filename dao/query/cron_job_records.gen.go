@@ -37,6 +37,7 @@ func newCronJobRecord(db *gorm.DB, opts ...gen.DOOption) cronJobRecord {
 	_cronJobRecord.Status = field.NewString(tableName, "status")
 	_cronJobRecord.Message = field.NewString(tableName, "message")
 	_cronJobRecord.JobData = field.NewField(tableName, "job_data")
+	_cronJobRecord.Replica = field.NewString(tableName, "replica")
 
 	_cronJobRecord.fillFieldMap()
 
@@ -56,6 +57,7 @@ type cronJobRecord struct {
 	Status      field.String // 执行状态
 	Message     field.String // 执行消息或错误信息
 	JobData     field.Field  // 任务数据(包含提醒和删除的任务列表)
+	Replica     field.String // 执行该任务的后端副本
 
 	fieldMap map[string]field.Expr
 }
@@ -81,6 +83,7 @@ func (c *cronJobRecord) updateTableName(table string) *cronJobRecord {
 	c.Status = field.NewString(table, "status")
 	c.Message = field.NewString(table, "message")
 	c.JobData = field.NewField(table, "job_data")
+	c.Replica = field.NewString(table, "replica")
 
 	c.fillFieldMap()
 
@@ -109,7 +112,7 @@ func (c *cronJobRecord) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (c *cronJobRecord) fillFieldMap() {
-	c.fieldMap = make(map[string]field.Expr, 9)
+	c.fieldMap = make(map[string]field.Expr, 10)
 	c.fieldMap["id"] = c.ID
 	c.fieldMap["created_at"] = c.CreatedAt
 	c.fieldMap["updated_at"] = c.UpdatedAt
@@ -119,6 +122,7 @@ func (c *cronJobRecord) fillFieldMap() {
 	c.fieldMap["status"] = c.Status
 	c.fieldMap["message"] = c.Message
 	c.fieldMap["job_data"] = c.JobData
+	c.fieldMap["replica"] = c.Replica
 }
 
 func (c cronJobRecord) clone(db *gorm.DB) cronJobRecord {
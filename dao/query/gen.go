@@ -16,37 +16,56 @@ import (
 )
 
 var (
-	Q               = new(Query)
-	AITask          *aITask
-	Account         *account
-	AccountDataset  *accountDataset
-	Alert           *alert
-	ApprovalOrder   *approvalOrder
-	CronJobConfig   *cronJobConfig
-	CronJobRecord   *cronJobRecord
-	CudaBaseImage   *cudaBaseImage
-	Dataset         *dataset
-	Image           *image
-	ImageAccount    *imageAccount
-	ImageUser       *imageUser
-	Job             *job
-	Jobtemplate     *jobtemplate
-	Kaniko          *kaniko
-	Resource        *resource
-	ResourceNetwork *resourceNetwork
-	ResourceVGPU    *resourceVGPU
-	User            *user
-	UserAccount     *userAccount
-	UserDataset     *userDataset
+	Q                      = new(Query)
+	AITask                 *aITask
+	APIToken               *aPIToken
+	Account                *account
+	AccountDataset         *accountDataset
+	Alert                  *alert
+	ApprovalOrder          *approvalOrder
+	AuditLog               *auditLog
+	ClusterIncident        *clusterIncident
+	CronJobConfig          *cronJobConfig
+	CronJobRecord          *cronJobRecord
+	CudaBaseImage          *cudaBaseImage
+	Dataset                *dataset
+	Image                  *image
+	ImageAccount           *imageAccount
+	ImageUser              *imageUser
+	InboxMessage           *inboxMessage
+	Job                    *job
+	JobWebhook             *jobWebhook
+	JobWebhookDelivery     *jobWebhookDelivery
+	Jobtemplate            *jobtemplate
+	Kaniko                 *kaniko
+	NotificationPreference *notificationPreference
+	OutboxMessage          *outboxMessage
+	PasswordHistory        *passwordHistory
+	PasswordReset          *passwordReset
+	PermissionRole         *permissionRole
+	Resource               *resource
+	ResourceNetwork        *resourceNetwork
+	ResourceVGPU           *resourceVGPU
+	SSHKey                 *sSHKey
+	TerminalRecording      *terminalRecording
+	User                   *user
+	UserAccount            *userAccount
+	UserDataset            *userDataset
+	UserMFA                *userMFA
+	UserPermissionRole     *userPermissionRole
+	UserSession            *userSession
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	AITask = &Q.AITask
+	APIToken = &Q.APIToken
 	Account = &Q.Account
 	AccountDataset = &Q.AccountDataset
 	Alert = &Q.Alert
 	ApprovalOrder = &Q.ApprovalOrder
+	AuditLog = &Q.AuditLog
+	ClusterIncident = &Q.ClusterIncident
 	CronJobConfig = &Q.CronJobConfig
 	CronJobRecord = &Q.CronJobRecord
 	CudaBaseImage = &Q.CudaBaseImage
@@ -54,96 +73,157 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	Image = &Q.Image
 	ImageAccount = &Q.ImageAccount
 	ImageUser = &Q.ImageUser
+	InboxMessage = &Q.InboxMessage
 	Job = &Q.Job
+	JobWebhook = &Q.JobWebhook
+	JobWebhookDelivery = &Q.JobWebhookDelivery
 	Jobtemplate = &Q.Jobtemplate
 	Kaniko = &Q.Kaniko
+	NotificationPreference = &Q.NotificationPreference
+	OutboxMessage = &Q.OutboxMessage
+	PasswordHistory = &Q.PasswordHistory
+	PasswordReset = &Q.PasswordReset
+	PermissionRole = &Q.PermissionRole
 	Resource = &Q.Resource
 	ResourceNetwork = &Q.ResourceNetwork
 	ResourceVGPU = &Q.ResourceVGPU
+	SSHKey = &Q.SSHKey
+	TerminalRecording = &Q.TerminalRecording
 	User = &Q.User
 	UserAccount = &Q.UserAccount
 	UserDataset = &Q.UserDataset
+	UserMFA = &Q.UserMFA
+	UserPermissionRole = &Q.UserPermissionRole
+	UserSession = &Q.UserSession
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:              db,
-		AITask:          newAITask(db, opts...),
-		Account:         newAccount(db, opts...),
-		AccountDataset:  newAccountDataset(db, opts...),
-		Alert:           newAlert(db, opts...),
-		ApprovalOrder:   newApprovalOrder(db, opts...),
-		CronJobConfig:   newCronJobConfig(db, opts...),
-		CronJobRecord:   newCronJobRecord(db, opts...),
-		CudaBaseImage:   newCudaBaseImage(db, opts...),
-		Dataset:         newDataset(db, opts...),
-		Image:           newImage(db, opts...),
-		ImageAccount:    newImageAccount(db, opts...),
-		ImageUser:       newImageUser(db, opts...),
-		Job:             newJob(db, opts...),
-		Jobtemplate:     newJobtemplate(db, opts...),
-		Kaniko:          newKaniko(db, opts...),
-		Resource:        newResource(db, opts...),
-		ResourceNetwork: newResourceNetwork(db, opts...),
-		ResourceVGPU:    newResourceVGPU(db, opts...),
-		User:            newUser(db, opts...),
-		UserAccount:     newUserAccount(db, opts...),
-		UserDataset:     newUserDataset(db, opts...),
+		db:                     db,
+		AITask:                 newAITask(db, opts...),
+		APIToken:               newAPIToken(db, opts...),
+		Account:                newAccount(db, opts...),
+		AccountDataset:         newAccountDataset(db, opts...),
+		Alert:                  newAlert(db, opts...),
+		ApprovalOrder:          newApprovalOrder(db, opts...),
+		AuditLog:               newAuditLog(db, opts...),
+		ClusterIncident:        newClusterIncident(db, opts...),
+		CronJobConfig:          newCronJobConfig(db, opts...),
+		CronJobRecord:          newCronJobRecord(db, opts...),
+		CudaBaseImage:          newCudaBaseImage(db, opts...),
+		Dataset:                newDataset(db, opts...),
+		Image:                  newImage(db, opts...),
+		ImageAccount:           newImageAccount(db, opts...),
+		ImageUser:              newImageUser(db, opts...),
+		InboxMessage:           newInboxMessage(db, opts...),
+		Job:                    newJob(db, opts...),
+		JobWebhook:             newJobWebhook(db, opts...),
+		JobWebhookDelivery:     newJobWebhookDelivery(db, opts...),
+		Jobtemplate:            newJobtemplate(db, opts...),
+		Kaniko:                 newKaniko(db, opts...),
+		NotificationPreference: newNotificationPreference(db, opts...),
+		OutboxMessage:          newOutboxMessage(db, opts...),
+		PasswordHistory:        newPasswordHistory(db, opts...),
+		PasswordReset:          newPasswordReset(db, opts...),
+		PermissionRole:         newPermissionRole(db, opts...),
+		Resource:               newResource(db, opts...),
+		ResourceNetwork:        newResourceNetwork(db, opts...),
+		ResourceVGPU:           newResourceVGPU(db, opts...),
+		SSHKey:                 newSSHKey(db, opts...),
+		TerminalRecording:      newTerminalRecording(db, opts...),
+		User:                   newUser(db, opts...),
+		UserAccount:            newUserAccount(db, opts...),
+		UserDataset:            newUserDataset(db, opts...),
+		UserMFA:                newUserMFA(db, opts...),
+		UserPermissionRole:     newUserPermissionRole(db, opts...),
+		UserSession:            newUserSession(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	AITask          aITask
-	Account         account
-	AccountDataset  accountDataset
-	Alert           alert
-	ApprovalOrder   approvalOrder
-	CronJobConfig   cronJobConfig
-	CronJobRecord   cronJobRecord
-	CudaBaseImage   cudaBaseImage
-	Dataset         dataset
-	Image           image
-	ImageAccount    imageAccount
-	ImageUser       imageUser
-	Job             job
-	Jobtemplate     jobtemplate
-	Kaniko          kaniko
-	Resource        resource
-	ResourceNetwork resourceNetwork
-	ResourceVGPU    resourceVGPU
-	User            user
-	UserAccount     userAccount
-	UserDataset     userDataset
+	AITask                 aITask
+	APIToken               aPIToken
+	Account                account
+	AccountDataset         accountDataset
+	Alert                  alert
+	ApprovalOrder          approvalOrder
+	AuditLog               auditLog
+	ClusterIncident        clusterIncident
+	CronJobConfig          cronJobConfig
+	CronJobRecord          cronJobRecord
+	CudaBaseImage          cudaBaseImage
+	Dataset                dataset
+	Image                  image
+	ImageAccount           imageAccount
+	ImageUser              imageUser
+	InboxMessage           inboxMessage
+	Job                    job
+	JobWebhook             jobWebhook
+	JobWebhookDelivery     jobWebhookDelivery
+	Jobtemplate            jobtemplate
+	Kaniko                 kaniko
+	NotificationPreference notificationPreference
+	OutboxMessage          outboxMessage
+	PasswordHistory        passwordHistory
+	PasswordReset          passwordReset
+	PermissionRole         permissionRole
+	Resource               resource
+	ResourceNetwork        resourceNetwork
+	ResourceVGPU           resourceVGPU
+	SSHKey                 sSHKey
+	TerminalRecording      terminalRecording
+	User                   user
+	UserAccount            userAccount
+	UserDataset            userDataset
+	UserMFA                userMFA
+	UserPermissionRole     userPermissionRole
+	UserSession            userSession
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:              db,
-		AITask:          q.AITask.clone(db),
-		Account:         q.Account.clone(db),
-		AccountDataset:  q.AccountDataset.clone(db),
-		Alert:           q.Alert.clone(db),
-		ApprovalOrder:   q.ApprovalOrder.clone(db),
-		CronJobConfig:   q.CronJobConfig.clone(db),
-		CronJobRecord:   q.CronJobRecord.clone(db),
-		CudaBaseImage:   q.CudaBaseImage.clone(db),
-		Dataset:         q.Dataset.clone(db),
-		Image:           q.Image.clone(db),
-		ImageAccount:    q.ImageAccount.clone(db),
-		ImageUser:       q.ImageUser.clone(db),
-		Job:             q.Job.clone(db),
-		Jobtemplate:     q.Jobtemplate.clone(db),
-		Kaniko:          q.Kaniko.clone(db),
-		Resource:        q.Resource.clone(db),
-		ResourceNetwork: q.ResourceNetwork.clone(db),
-		ResourceVGPU:    q.ResourceVGPU.clone(db),
-		User:            q.User.clone(db),
-		UserAccount:     q.UserAccount.clone(db),
-		UserDataset:     q.UserDataset.clone(db),
+		db:                     db,
+		AITask:                 q.AITask.clone(db),
+		APIToken:               q.APIToken.clone(db),
+		Account:                q.Account.clone(db),
+		AccountDataset:         q.AccountDataset.clone(db),
+		Alert:                  q.Alert.clone(db),
+		ApprovalOrder:          q.ApprovalOrder.clone(db),
+		AuditLog:               q.AuditLog.clone(db),
+		ClusterIncident:        q.ClusterIncident.clone(db),
+		CronJobConfig:          q.CronJobConfig.clone(db),
+		CronJobRecord:          q.CronJobRecord.clone(db),
+		CudaBaseImage:          q.CudaBaseImage.clone(db),
+		Dataset:                q.Dataset.clone(db),
+		Image:                  q.Image.clone(db),
+		ImageAccount:           q.ImageAccount.clone(db),
+		ImageUser:              q.ImageUser.clone(db),
+		InboxMessage:           q.InboxMessage.clone(db),
+		Job:                    q.Job.clone(db),
+		JobWebhook:             q.JobWebhook.clone(db),
+		JobWebhookDelivery:     q.JobWebhookDelivery.clone(db),
+		Jobtemplate:            q.Jobtemplate.clone(db),
+		Kaniko:                 q.Kaniko.clone(db),
+		NotificationPreference: q.NotificationPreference.clone(db),
+		OutboxMessage:          q.OutboxMessage.clone(db),
+		PasswordHistory:        q.PasswordHistory.clone(db),
+		PasswordReset:          q.PasswordReset.clone(db),
+		PermissionRole:         q.PermissionRole.clone(db),
+		Resource:               q.Resource.clone(db),
+		ResourceNetwork:        q.ResourceNetwork.clone(db),
+		ResourceVGPU:           q.ResourceVGPU.clone(db),
+		SSHKey:                 q.SSHKey.clone(db),
+		TerminalRecording:      q.TerminalRecording.clone(db),
+		User:                   q.User.clone(db),
+		UserAccount:            q.UserAccount.clone(db),
+		UserDataset:            q.UserDataset.clone(db),
+		UserMFA:                q.UserMFA.clone(db),
+		UserPermissionRole:     q.UserPermissionRole.clone(db),
+		UserSession:            q.UserSession.clone(db),
 	}
 }
 
@@ -157,78 +237,126 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:              db,
-		AITask:          q.AITask.replaceDB(db),
-		Account:         q.Account.replaceDB(db),
-		AccountDataset:  q.AccountDataset.replaceDB(db),
-		Alert:           q.Alert.replaceDB(db),
-		ApprovalOrder:   q.ApprovalOrder.replaceDB(db),
-		CronJobConfig:   q.CronJobConfig.replaceDB(db),
-		CronJobRecord:   q.CronJobRecord.replaceDB(db),
-		CudaBaseImage:   q.CudaBaseImage.replaceDB(db),
-		Dataset:         q.Dataset.replaceDB(db),
-		Image:           q.Image.replaceDB(db),
-		ImageAccount:    q.ImageAccount.replaceDB(db),
-		ImageUser:       q.ImageUser.replaceDB(db),
-		Job:             q.Job.replaceDB(db),
-		Jobtemplate:     q.Jobtemplate.replaceDB(db),
-		Kaniko:          q.Kaniko.replaceDB(db),
-		Resource:        q.Resource.replaceDB(db),
-		ResourceNetwork: q.ResourceNetwork.replaceDB(db),
-		ResourceVGPU:    q.ResourceVGPU.replaceDB(db),
-		User:            q.User.replaceDB(db),
-		UserAccount:     q.UserAccount.replaceDB(db),
-		UserDataset:     q.UserDataset.replaceDB(db),
+		db:                     db,
+		AITask:                 q.AITask.replaceDB(db),
+		APIToken:               q.APIToken.replaceDB(db),
+		Account:                q.Account.replaceDB(db),
+		AccountDataset:         q.AccountDataset.replaceDB(db),
+		Alert:                  q.Alert.replaceDB(db),
+		ApprovalOrder:          q.ApprovalOrder.replaceDB(db),
+		AuditLog:               q.AuditLog.replaceDB(db),
+		ClusterIncident:        q.ClusterIncident.replaceDB(db),
+		CronJobConfig:          q.CronJobConfig.replaceDB(db),
+		CronJobRecord:          q.CronJobRecord.replaceDB(db),
+		CudaBaseImage:          q.CudaBaseImage.replaceDB(db),
+		Dataset:                q.Dataset.replaceDB(db),
+		Image:                  q.Image.replaceDB(db),
+		ImageAccount:           q.ImageAccount.replaceDB(db),
+		ImageUser:              q.ImageUser.replaceDB(db),
+		InboxMessage:           q.InboxMessage.replaceDB(db),
+		Job:                    q.Job.replaceDB(db),
+		JobWebhook:             q.JobWebhook.replaceDB(db),
+		JobWebhookDelivery:     q.JobWebhookDelivery.replaceDB(db),
+		Jobtemplate:            q.Jobtemplate.replaceDB(db),
+		Kaniko:                 q.Kaniko.replaceDB(db),
+		NotificationPreference: q.NotificationPreference.replaceDB(db),
+		OutboxMessage:          q.OutboxMessage.replaceDB(db),
+		PasswordHistory:        q.PasswordHistory.replaceDB(db),
+		PasswordReset:          q.PasswordReset.replaceDB(db),
+		PermissionRole:         q.PermissionRole.replaceDB(db),
+		Resource:               q.Resource.replaceDB(db),
+		ResourceNetwork:        q.ResourceNetwork.replaceDB(db),
+		ResourceVGPU:           q.ResourceVGPU.replaceDB(db),
+		SSHKey:                 q.SSHKey.replaceDB(db),
+		TerminalRecording:      q.TerminalRecording.replaceDB(db),
+		User:                   q.User.replaceDB(db),
+		UserAccount:            q.UserAccount.replaceDB(db),
+		UserDataset:            q.UserDataset.replaceDB(db),
+		UserMFA:                q.UserMFA.replaceDB(db),
+		UserPermissionRole:     q.UserPermissionRole.replaceDB(db),
+		UserSession:            q.UserSession.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	AITask          IAITaskDo
-	Account         IAccountDo
-	AccountDataset  IAccountDatasetDo
-	Alert           IAlertDo
-	ApprovalOrder   IApprovalOrderDo
-	CronJobConfig   ICronJobConfigDo
-	CronJobRecord   ICronJobRecordDo
-	CudaBaseImage   ICudaBaseImageDo
-	Dataset         IDatasetDo
-	Image           IImageDo
-	ImageAccount    IImageAccountDo
-	ImageUser       IImageUserDo
-	Job             IJobDo
-	Jobtemplate     IJobtemplateDo
-	Kaniko          IKanikoDo
-	Resource        IResourceDo
-	ResourceNetwork IResourceNetworkDo
-	ResourceVGPU    IResourceVGPUDo
-	User            IUserDo
-	UserAccount     IUserAccountDo
-	UserDataset     IUserDatasetDo
+	AITask                 IAITaskDo
+	APIToken               IAPITokenDo
+	Account                IAccountDo
+	AccountDataset         IAccountDatasetDo
+	Alert                  IAlertDo
+	ApprovalOrder          IApprovalOrderDo
+	AuditLog               IAuditLogDo
+	ClusterIncident        IClusterIncidentDo
+	CronJobConfig          ICronJobConfigDo
+	CronJobRecord          ICronJobRecordDo
+	CudaBaseImage          ICudaBaseImageDo
+	Dataset                IDatasetDo
+	Image                  IImageDo
+	ImageAccount           IImageAccountDo
+	ImageUser              IImageUserDo
+	InboxMessage           IInboxMessageDo
+	Job                    IJobDo
+	JobWebhook             IJobWebhookDo
+	JobWebhookDelivery     IJobWebhookDeliveryDo
+	Jobtemplate            IJobtemplateDo
+	Kaniko                 IKanikoDo
+	NotificationPreference INotificationPreferenceDo
+	OutboxMessage          IOutboxMessageDo
+	PasswordHistory        IPasswordHistoryDo
+	PasswordReset          IPasswordResetDo
+	PermissionRole         IPermissionRoleDo
+	Resource               IResourceDo
+	ResourceNetwork        IResourceNetworkDo
+	ResourceVGPU           IResourceVGPUDo
+	SSHKey                 ISSHKeyDo
+	TerminalRecording      ITerminalRecordingDo
+	User                   IUserDo
+	UserAccount            IUserAccountDo
+	UserDataset            IUserDatasetDo
+	UserMFA                IUserMFADo
+	UserPermissionRole     IUserPermissionRoleDo
+	UserSession            IUserSessionDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		AITask:          q.AITask.WithContext(ctx),
-		Account:         q.Account.WithContext(ctx),
-		AccountDataset:  q.AccountDataset.WithContext(ctx),
-		Alert:           q.Alert.WithContext(ctx),
-		ApprovalOrder:   q.ApprovalOrder.WithContext(ctx),
-		CronJobConfig:   q.CronJobConfig.WithContext(ctx),
-		CronJobRecord:   q.CronJobRecord.WithContext(ctx),
-		CudaBaseImage:   q.CudaBaseImage.WithContext(ctx),
-		Dataset:         q.Dataset.WithContext(ctx),
-		Image:           q.Image.WithContext(ctx),
-		ImageAccount:    q.ImageAccount.WithContext(ctx),
-		ImageUser:       q.ImageUser.WithContext(ctx),
-		Job:             q.Job.WithContext(ctx),
-		Jobtemplate:     q.Jobtemplate.WithContext(ctx),
-		Kaniko:          q.Kaniko.WithContext(ctx),
-		Resource:        q.Resource.WithContext(ctx),
-		ResourceNetwork: q.ResourceNetwork.WithContext(ctx),
-		ResourceVGPU:    q.ResourceVGPU.WithContext(ctx),
-		User:            q.User.WithContext(ctx),
-		UserAccount:     q.UserAccount.WithContext(ctx),
-		UserDataset:     q.UserDataset.WithContext(ctx),
+		AITask:                 q.AITask.WithContext(ctx),
+		APIToken:               q.APIToken.WithContext(ctx),
+		Account:                q.Account.WithContext(ctx),
+		AccountDataset:         q.AccountDataset.WithContext(ctx),
+		Alert:                  q.Alert.WithContext(ctx),
+		ApprovalOrder:          q.ApprovalOrder.WithContext(ctx),
+		AuditLog:               q.AuditLog.WithContext(ctx),
+		ClusterIncident:        q.ClusterIncident.WithContext(ctx),
+		CronJobConfig:          q.CronJobConfig.WithContext(ctx),
+		CronJobRecord:          q.CronJobRecord.WithContext(ctx),
+		CudaBaseImage:          q.CudaBaseImage.WithContext(ctx),
+		Dataset:                q.Dataset.WithContext(ctx),
+		Image:                  q.Image.WithContext(ctx),
+		ImageAccount:           q.ImageAccount.WithContext(ctx),
+		ImageUser:              q.ImageUser.WithContext(ctx),
+		InboxMessage:           q.InboxMessage.WithContext(ctx),
+		Job:                    q.Job.WithContext(ctx),
+		JobWebhook:             q.JobWebhook.WithContext(ctx),
+		JobWebhookDelivery:     q.JobWebhookDelivery.WithContext(ctx),
+		Jobtemplate:            q.Jobtemplate.WithContext(ctx),
+		Kaniko:                 q.Kaniko.WithContext(ctx),
+		NotificationPreference: q.NotificationPreference.WithContext(ctx),
+		OutboxMessage:          q.OutboxMessage.WithContext(ctx),
+		PasswordHistory:        q.PasswordHistory.WithContext(ctx),
+		PasswordReset:          q.PasswordReset.WithContext(ctx),
+		PermissionRole:         q.PermissionRole.WithContext(ctx),
+		Resource:               q.Resource.WithContext(ctx),
+		ResourceNetwork:        q.ResourceNetwork.WithContext(ctx),
+		ResourceVGPU:           q.ResourceVGPU.WithContext(ctx),
+		SSHKey:                 q.SSHKey.WithContext(ctx),
+		TerminalRecording:      q.TerminalRecording.WithContext(ctx),
+		User:                   q.User.WithContext(ctx),
+		UserAccount:            q.UserAccount.WithContext(ctx),
+		UserDataset:            q.UserDataset.WithContext(ctx),
+		UserMFA:                q.UserMFA.WithContext(ctx),
+		UserPermissionRole:     q.UserPermissionRole.WithContext(ctx),
+		UserSession:            q.UserSession.WithContext(ctx),
 	}
 }
 
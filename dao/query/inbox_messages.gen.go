@@ -0,0 +1,439 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newInboxMessage(db *gorm.DB, opts ...gen.DOOption) inboxMessage {
+	_inboxMessage := inboxMessage{}
+
+	_inboxMessage.inboxMessageDo.UseDB(db, opts...)
+	_inboxMessage.inboxMessageDo.UseModel(&model.InboxMessage{})
+
+	tableName := _inboxMessage.inboxMessageDo.TableName()
+	_inboxMessage.ALL = field.NewAsterisk(tableName)
+	_inboxMessage.ID = field.NewUint(tableName, "id")
+	_inboxMessage.CreatedAt = field.NewTime(tableName, "created_at")
+	_inboxMessage.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_inboxMessage.DeletedAt = field.NewField(tableName, "deleted_at")
+	_inboxMessage.UserID = field.NewUint(tableName, "user_id")
+	_inboxMessage.Category = field.NewString(tableName, "category")
+	_inboxMessage.Title = field.NewString(tableName, "title")
+	_inboxMessage.Content = field.NewString(tableName, "content")
+	_inboxMessage.Link = field.NewString(tableName, "link")
+	_inboxMessage.JobName = field.NewString(tableName, "job_name")
+	_inboxMessage.AlertType = field.NewString(tableName, "alert_type")
+	_inboxMessage.ReadAt = field.NewTime(tableName, "read_at")
+
+	_inboxMessage.fillFieldMap()
+
+	return _inboxMessage
+}
+
+type inboxMessage struct {
+	inboxMessageDo inboxMessageDo
+
+	ALL       field.Asterisk
+	ID        field.Uint
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	DeletedAt field.Field
+	UserID    field.Uint   // 接收者ID
+	Category  field.String // 消息类别 (job, approval)
+	Title     field.String // 消息标题
+	Content   field.String // 消息内容
+	Link      field.String // 相关页面链接
+	JobName   field.String // 相关作业名
+	AlertType field.String // 通知类型
+	ReadAt    field.Time   // 已读时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (i inboxMessage) Table(newTableName string) *inboxMessage {
+	i.inboxMessageDo.UseTable(newTableName)
+	return i.updateTableName(newTableName)
+}
+
+func (i inboxMessage) As(alias string) *inboxMessage {
+	i.inboxMessageDo.DO = *(i.inboxMessageDo.As(alias).(*gen.DO))
+	return i.updateTableName(alias)
+}
+
+func (i *inboxMessage) updateTableName(table string) *inboxMessage {
+	i.ALL = field.NewAsterisk(table)
+	i.ID = field.NewUint(table, "id")
+	i.CreatedAt = field.NewTime(table, "created_at")
+	i.UpdatedAt = field.NewTime(table, "updated_at")
+	i.DeletedAt = field.NewField(table, "deleted_at")
+	i.UserID = field.NewUint(table, "user_id")
+	i.Category = field.NewString(table, "category")
+	i.Title = field.NewString(table, "title")
+	i.Content = field.NewString(table, "content")
+	i.Link = field.NewString(table, "link")
+	i.JobName = field.NewString(table, "job_name")
+	i.AlertType = field.NewString(table, "alert_type")
+	i.ReadAt = field.NewTime(table, "read_at")
+
+	i.fillFieldMap()
+
+	return i
+}
+
+func (i *inboxMessage) WithContext(ctx context.Context) IInboxMessageDo {
+	return i.inboxMessageDo.WithContext(ctx)
+}
+
+func (i inboxMessage) TableName() string { return i.inboxMessageDo.TableName() }
+
+func (i inboxMessage) Alias() string { return i.inboxMessageDo.Alias() }
+
+func (i inboxMessage) Columns(cols ...field.Expr) gen.Columns {
+	return i.inboxMessageDo.Columns(cols...)
+}
+
+func (i *inboxMessage) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := i.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (i *inboxMessage) fillFieldMap() {
+	i.fieldMap = make(map[string]field.Expr, 12)
+	i.fieldMap["id"] = i.ID
+	i.fieldMap["created_at"] = i.CreatedAt
+	i.fieldMap["updated_at"] = i.UpdatedAt
+	i.fieldMap["deleted_at"] = i.DeletedAt
+	i.fieldMap["user_id"] = i.UserID
+	i.fieldMap["category"] = i.Category
+	i.fieldMap["title"] = i.Title
+	i.fieldMap["content"] = i.Content
+	i.fieldMap["link"] = i.Link
+	i.fieldMap["job_name"] = i.JobName
+	i.fieldMap["alert_type"] = i.AlertType
+	i.fieldMap["read_at"] = i.ReadAt
+}
+
+func (i inboxMessage) clone(db *gorm.DB) inboxMessage {
+	i.inboxMessageDo.ReplaceConnPool(db.Statement.ConnPool)
+	return i
+}
+
+func (i inboxMessage) replaceDB(db *gorm.DB) inboxMessage {
+	i.inboxMessageDo.ReplaceDB(db)
+	return i
+}
+
+type inboxMessageDo struct{ gen.DO }
+
+type IInboxMessageDo interface {
+	gen.SubQuery
+	Debug() IInboxMessageDo
+	WithContext(ctx context.Context) IInboxMessageDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IInboxMessageDo
+	WriteDB() IInboxMessageDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IInboxMessageDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IInboxMessageDo
+	Not(conds ...gen.Condition) IInboxMessageDo
+	Or(conds ...gen.Condition) IInboxMessageDo
+	Select(conds ...field.Expr) IInboxMessageDo
+	Where(conds ...gen.Condition) IInboxMessageDo
+	Order(conds ...field.Expr) IInboxMessageDo
+	Distinct(cols ...field.Expr) IInboxMessageDo
+	Omit(cols ...field.Expr) IInboxMessageDo
+	Join(table schema.Tabler, on ...field.Expr) IInboxMessageDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IInboxMessageDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IInboxMessageDo
+	Group(cols ...field.Expr) IInboxMessageDo
+	Having(conds ...gen.Condition) IInboxMessageDo
+	Limit(limit int) IInboxMessageDo
+	Offset(offset int) IInboxMessageDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IInboxMessageDo
+	Unscoped() IInboxMessageDo
+	Create(values ...*model.InboxMessage) error
+	CreateInBatches(values []*model.InboxMessage, batchSize int) error
+	Save(values ...*model.InboxMessage) error
+	First() (*model.InboxMessage, error)
+	Take() (*model.InboxMessage, error)
+	Last() (*model.InboxMessage, error)
+	Find() ([]*model.InboxMessage, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.InboxMessage, err error)
+	FindInBatches(result *[]*model.InboxMessage, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.InboxMessage) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IInboxMessageDo
+	Assign(attrs ...field.AssignExpr) IInboxMessageDo
+	Joins(fields ...field.RelationField) IInboxMessageDo
+	Preload(fields ...field.RelationField) IInboxMessageDo
+	FirstOrInit() (*model.InboxMessage, error)
+	FirstOrCreate() (*model.InboxMessage, error)
+	FindByPage(offset int, limit int) (result []*model.InboxMessage, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IInboxMessageDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (i inboxMessageDo) Debug() IInboxMessageDo {
+	return i.withDO(i.DO.Debug())
+}
+
+func (i inboxMessageDo) WithContext(ctx context.Context) IInboxMessageDo {
+	return i.withDO(i.DO.WithContext(ctx))
+}
+
+func (i inboxMessageDo) ReadDB() IInboxMessageDo {
+	return i.Clauses(dbresolver.Read)
+}
+
+func (i inboxMessageDo) WriteDB() IInboxMessageDo {
+	return i.Clauses(dbresolver.Write)
+}
+
+func (i inboxMessageDo) Session(config *gorm.Session) IInboxMessageDo {
+	return i.withDO(i.DO.Session(config))
+}
+
+func (i inboxMessageDo) Clauses(conds ...clause.Expression) IInboxMessageDo {
+	return i.withDO(i.DO.Clauses(conds...))
+}
+
+func (i inboxMessageDo) Returning(value interface{}, columns ...string) IInboxMessageDo {
+	return i.withDO(i.DO.Returning(value, columns...))
+}
+
+func (i inboxMessageDo) Not(conds ...gen.Condition) IInboxMessageDo {
+	return i.withDO(i.DO.Not(conds...))
+}
+
+func (i inboxMessageDo) Or(conds ...gen.Condition) IInboxMessageDo {
+	return i.withDO(i.DO.Or(conds...))
+}
+
+func (i inboxMessageDo) Select(conds ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Select(conds...))
+}
+
+func (i inboxMessageDo) Where(conds ...gen.Condition) IInboxMessageDo {
+	return i.withDO(i.DO.Where(conds...))
+}
+
+func (i inboxMessageDo) Order(conds ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Order(conds...))
+}
+
+func (i inboxMessageDo) Distinct(cols ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Distinct(cols...))
+}
+
+func (i inboxMessageDo) Omit(cols ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Omit(cols...))
+}
+
+func (i inboxMessageDo) Join(table schema.Tabler, on ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Join(table, on...))
+}
+
+func (i inboxMessageDo) LeftJoin(table schema.Tabler, on ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.LeftJoin(table, on...))
+}
+
+func (i inboxMessageDo) RightJoin(table schema.Tabler, on ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.RightJoin(table, on...))
+}
+
+func (i inboxMessageDo) Group(cols ...field.Expr) IInboxMessageDo {
+	return i.withDO(i.DO.Group(cols...))
+}
+
+func (i inboxMessageDo) Having(conds ...gen.Condition) IInboxMessageDo {
+	return i.withDO(i.DO.Having(conds...))
+}
+
+func (i inboxMessageDo) Limit(limit int) IInboxMessageDo {
+	return i.withDO(i.DO.Limit(limit))
+}
+
+func (i inboxMessageDo) Offset(offset int) IInboxMessageDo {
+	return i.withDO(i.DO.Offset(offset))
+}
+
+func (i inboxMessageDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IInboxMessageDo {
+	return i.withDO(i.DO.Scopes(funcs...))
+}
+
+func (i inboxMessageDo) Unscoped() IInboxMessageDo {
+	return i.withDO(i.DO.Unscoped())
+}
+
+func (i inboxMessageDo) Create(values ...*model.InboxMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return i.DO.Create(values)
+}
+
+func (i inboxMessageDo) CreateInBatches(values []*model.InboxMessage, batchSize int) error {
+	return i.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (i inboxMessageDo) Save(values ...*model.InboxMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return i.DO.Save(values)
+}
+
+func (i inboxMessageDo) First() (*model.InboxMessage, error) {
+	if result, err := i.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.InboxMessage), nil
+	}
+}
+
+func (i inboxMessageDo) Take() (*model.InboxMessage, error) {
+	if result, err := i.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.InboxMessage), nil
+	}
+}
+
+func (i inboxMessageDo) Last() (*model.InboxMessage, error) {
+	if result, err := i.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.InboxMessage), nil
+	}
+}
+
+func (i inboxMessageDo) Find() ([]*model.InboxMessage, error) {
+	result, err := i.DO.Find()
+	return result.([]*model.InboxMessage), err
+}
+
+func (i inboxMessageDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.InboxMessage, err error) {
+	buf := make([]*model.InboxMessage, 0, batchSize)
+	err = i.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (i inboxMessageDo) FindInBatches(result *[]*model.InboxMessage, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return i.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (i inboxMessageDo) Attrs(attrs ...field.AssignExpr) IInboxMessageDo {
+	return i.withDO(i.DO.Attrs(attrs...))
+}
+
+func (i inboxMessageDo) Assign(attrs ...field.AssignExpr) IInboxMessageDo {
+	return i.withDO(i.DO.Assign(attrs...))
+}
+
+func (i inboxMessageDo) Joins(fields ...field.RelationField) IInboxMessageDo {
+	for _, _f := range fields {
+		i = *i.withDO(i.DO.Joins(_f))
+	}
+	return &i
+}
+
+func (i inboxMessageDo) Preload(fields ...field.RelationField) IInboxMessageDo {
+	for _, _f := range fields {
+		i = *i.withDO(i.DO.Preload(_f))
+	}
+	return &i
+}
+
+func (i inboxMessageDo) FirstOrInit() (*model.InboxMessage, error) {
+	if result, err := i.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.InboxMessage), nil
+	}
+}
+
+func (i inboxMessageDo) FirstOrCreate() (*model.InboxMessage, error) {
+	if result, err := i.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.InboxMessage), nil
+	}
+}
+
+func (i inboxMessageDo) FindByPage(offset int, limit int) (result []*model.InboxMessage, count int64, err error) {
+	result, err = i.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = i.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (i inboxMessageDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = i.Count()
+	if err != nil {
+		return
+	}
+
+	err = i.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (i inboxMessageDo) Scan(result interface{}) (err error) {
+	return i.DO.Scan(result)
+}
+
+func (i inboxMessageDo) Delete(models ...*model.InboxMessage) (result gen.ResultInfo, err error) {
+	return i.DO.Delete(models)
+}
+
+func (i *inboxMessageDo) withDO(do gen.Dao) *inboxMessageDo {
+	i.DO = *do.(*gen.DO)
+	return i
+}
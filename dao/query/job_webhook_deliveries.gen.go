@@ -0,0 +1,451 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newJobWebhookDelivery(db *gorm.DB, opts ...gen.DOOption) jobWebhookDelivery {
+	_jobWebhookDelivery := jobWebhookDelivery{}
+
+	_jobWebhookDelivery.jobWebhookDeliveryDo.UseDB(db, opts...)
+	_jobWebhookDelivery.jobWebhookDeliveryDo.UseModel(&model.JobWebhookDelivery{})
+
+	tableName := _jobWebhookDelivery.jobWebhookDeliveryDo.TableName()
+	_jobWebhookDelivery.ALL = field.NewAsterisk(tableName)
+	_jobWebhookDelivery.ID = field.NewUint(tableName, "id")
+	_jobWebhookDelivery.CreatedAt = field.NewTime(tableName, "created_at")
+	_jobWebhookDelivery.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_jobWebhookDelivery.DeletedAt = field.NewField(tableName, "deleted_at")
+	_jobWebhookDelivery.WebhookID = field.NewUint(tableName, "webhook_id")
+	_jobWebhookDelivery.JobName = field.NewString(tableName, "job_name")
+	_jobWebhookDelivery.Event = field.NewString(tableName, "event")
+	_jobWebhookDelivery.Payload = field.NewString(tableName, "payload")
+	_jobWebhookDelivery.Status = field.NewString(tableName, "status")
+	_jobWebhookDelivery.Attempts = field.NewInt(tableName, "attempts")
+	_jobWebhookDelivery.MaxAttempts = field.NewInt(tableName, "max_attempts")
+	_jobWebhookDelivery.NextAttemptAt = field.NewTime(tableName, "next_attempt_at")
+	_jobWebhookDelivery.LastError = field.NewString(tableName, "last_error")
+	_jobWebhookDelivery.SentAt = field.NewTime(tableName, "sent_at")
+	_jobWebhookDelivery.Deliveries = field.NewField(tableName, "deliveries")
+
+	_jobWebhookDelivery.fillFieldMap()
+
+	return _jobWebhookDelivery
+}
+
+type jobWebhookDelivery struct {
+	jobWebhookDeliveryDo jobWebhookDeliveryDo
+
+	ALL           field.Asterisk
+	ID            field.Uint
+	CreatedAt     field.Time
+	UpdatedAt     field.Time
+	DeletedAt     field.Field
+	WebhookID     field.Uint   // Webhook ID
+	JobName       field.String // 作业名
+	Event         field.String // 事件 (作业状态)
+	Payload       field.String // 请求体 (JSON)
+	Status        field.String // 投递状态 (pending, sending, sent, failed)
+	Attempts      field.Int    // 已尝试次数
+	MaxAttempts   field.Int    // 最大尝试次数
+	NextAttemptAt field.Time   // 下次尝试时间
+	LastError     field.String // 最近一次失败原因
+	SentAt        field.Time   // 发送成功时间
+	Deliveries    field.Field  // 投递记录
+
+	fieldMap map[string]field.Expr
+}
+
+func (j jobWebhookDelivery) Table(newTableName string) *jobWebhookDelivery {
+	j.jobWebhookDeliveryDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j jobWebhookDelivery) As(alias string) *jobWebhookDelivery {
+	j.jobWebhookDeliveryDo.DO = *(j.jobWebhookDeliveryDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *jobWebhookDelivery) updateTableName(table string) *jobWebhookDelivery {
+	j.ALL = field.NewAsterisk(table)
+	j.ID = field.NewUint(table, "id")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.WebhookID = field.NewUint(table, "webhook_id")
+	j.JobName = field.NewString(table, "job_name")
+	j.Event = field.NewString(table, "event")
+	j.Payload = field.NewString(table, "payload")
+	j.Status = field.NewString(table, "status")
+	j.Attempts = field.NewInt(table, "attempts")
+	j.MaxAttempts = field.NewInt(table, "max_attempts")
+	j.NextAttemptAt = field.NewTime(table, "next_attempt_at")
+	j.LastError = field.NewString(table, "last_error")
+	j.SentAt = field.NewTime(table, "sent_at")
+	j.Deliveries = field.NewField(table, "deliveries")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *jobWebhookDelivery) WithContext(ctx context.Context) IJobWebhookDeliveryDo {
+	return j.jobWebhookDeliveryDo.WithContext(ctx)
+}
+
+func (j jobWebhookDelivery) TableName() string { return j.jobWebhookDeliveryDo.TableName() }
+
+func (j jobWebhookDelivery) Alias() string { return j.jobWebhookDeliveryDo.Alias() }
+
+func (j jobWebhookDelivery) Columns(cols ...field.Expr) gen.Columns {
+	return j.jobWebhookDeliveryDo.Columns(cols...)
+}
+
+func (j *jobWebhookDelivery) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *jobWebhookDelivery) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 15)
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["webhook_id"] = j.WebhookID
+	j.fieldMap["job_name"] = j.JobName
+	j.fieldMap["event"] = j.Event
+	j.fieldMap["payload"] = j.Payload
+	j.fieldMap["status"] = j.Status
+	j.fieldMap["attempts"] = j.Attempts
+	j.fieldMap["max_attempts"] = j.MaxAttempts
+	j.fieldMap["next_attempt_at"] = j.NextAttemptAt
+	j.fieldMap["last_error"] = j.LastError
+	j.fieldMap["sent_at"] = j.SentAt
+	j.fieldMap["deliveries"] = j.Deliveries
+}
+
+func (j jobWebhookDelivery) clone(db *gorm.DB) jobWebhookDelivery {
+	j.jobWebhookDeliveryDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j jobWebhookDelivery) replaceDB(db *gorm.DB) jobWebhookDelivery {
+	j.jobWebhookDeliveryDo.ReplaceDB(db)
+	return j
+}
+
+type jobWebhookDeliveryDo struct{ gen.DO }
+
+type IJobWebhookDeliveryDo interface {
+	gen.SubQuery
+	Debug() IJobWebhookDeliveryDo
+	WithContext(ctx context.Context) IJobWebhookDeliveryDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJobWebhookDeliveryDo
+	WriteDB() IJobWebhookDeliveryDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJobWebhookDeliveryDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJobWebhookDeliveryDo
+	Not(conds ...gen.Condition) IJobWebhookDeliveryDo
+	Or(conds ...gen.Condition) IJobWebhookDeliveryDo
+	Select(conds ...field.Expr) IJobWebhookDeliveryDo
+	Where(conds ...gen.Condition) IJobWebhookDeliveryDo
+	Order(conds ...field.Expr) IJobWebhookDeliveryDo
+	Distinct(cols ...field.Expr) IJobWebhookDeliveryDo
+	Omit(cols ...field.Expr) IJobWebhookDeliveryDo
+	Join(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo
+	Group(cols ...field.Expr) IJobWebhookDeliveryDo
+	Having(conds ...gen.Condition) IJobWebhookDeliveryDo
+	Limit(limit int) IJobWebhookDeliveryDo
+	Offset(offset int) IJobWebhookDeliveryDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJobWebhookDeliveryDo
+	Unscoped() IJobWebhookDeliveryDo
+	Create(values ...*model.JobWebhookDelivery) error
+	CreateInBatches(values []*model.JobWebhookDelivery, batchSize int) error
+	Save(values ...*model.JobWebhookDelivery) error
+	First() (*model.JobWebhookDelivery, error)
+	Take() (*model.JobWebhookDelivery, error)
+	Last() (*model.JobWebhookDelivery, error)
+	Find() ([]*model.JobWebhookDelivery, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JobWebhookDelivery, err error)
+	FindInBatches(result *[]*model.JobWebhookDelivery, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JobWebhookDelivery) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJobWebhookDeliveryDo
+	Assign(attrs ...field.AssignExpr) IJobWebhookDeliveryDo
+	Joins(fields ...field.RelationField) IJobWebhookDeliveryDo
+	Preload(fields ...field.RelationField) IJobWebhookDeliveryDo
+	FirstOrInit() (*model.JobWebhookDelivery, error)
+	FirstOrCreate() (*model.JobWebhookDelivery, error)
+	FindByPage(offset int, limit int) (result []*model.JobWebhookDelivery, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJobWebhookDeliveryDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j jobWebhookDeliveryDo) Debug() IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j jobWebhookDeliveryDo) WithContext(ctx context.Context) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j jobWebhookDeliveryDo) ReadDB() IJobWebhookDeliveryDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j jobWebhookDeliveryDo) WriteDB() IJobWebhookDeliveryDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j jobWebhookDeliveryDo) Session(config *gorm.Session) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j jobWebhookDeliveryDo) Clauses(conds ...clause.Expression) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Returning(value interface{}, columns ...string) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j jobWebhookDeliveryDo) Not(conds ...gen.Condition) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Or(conds ...gen.Condition) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Select(conds ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Where(conds ...gen.Condition) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Order(conds ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Distinct(cols ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j jobWebhookDeliveryDo) Omit(cols ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j jobWebhookDeliveryDo) Join(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j jobWebhookDeliveryDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j jobWebhookDeliveryDo) RightJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j jobWebhookDeliveryDo) Group(cols ...field.Expr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j jobWebhookDeliveryDo) Having(conds ...gen.Condition) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j jobWebhookDeliveryDo) Limit(limit int) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j jobWebhookDeliveryDo) Offset(offset int) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j jobWebhookDeliveryDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j jobWebhookDeliveryDo) Unscoped() IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j jobWebhookDeliveryDo) Create(values ...*model.JobWebhookDelivery) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j jobWebhookDeliveryDo) CreateInBatches(values []*model.JobWebhookDelivery, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j jobWebhookDeliveryDo) Save(values ...*model.JobWebhookDelivery) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j jobWebhookDeliveryDo) First() (*model.JobWebhookDelivery, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhookDelivery), nil
+	}
+}
+
+func (j jobWebhookDeliveryDo) Take() (*model.JobWebhookDelivery, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhookDelivery), nil
+	}
+}
+
+func (j jobWebhookDeliveryDo) Last() (*model.JobWebhookDelivery, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhookDelivery), nil
+	}
+}
+
+func (j jobWebhookDeliveryDo) Find() ([]*model.JobWebhookDelivery, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JobWebhookDelivery), err
+}
+
+func (j jobWebhookDeliveryDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JobWebhookDelivery, err error) {
+	buf := make([]*model.JobWebhookDelivery, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j jobWebhookDeliveryDo) FindInBatches(result *[]*model.JobWebhookDelivery, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j jobWebhookDeliveryDo) Attrs(attrs ...field.AssignExpr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j jobWebhookDeliveryDo) Assign(attrs ...field.AssignExpr) IJobWebhookDeliveryDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j jobWebhookDeliveryDo) Joins(fields ...field.RelationField) IJobWebhookDeliveryDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j jobWebhookDeliveryDo) Preload(fields ...field.RelationField) IJobWebhookDeliveryDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j jobWebhookDeliveryDo) FirstOrInit() (*model.JobWebhookDelivery, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhookDelivery), nil
+	}
+}
+
+func (j jobWebhookDeliveryDo) FirstOrCreate() (*model.JobWebhookDelivery, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhookDelivery), nil
+	}
+}
+
+func (j jobWebhookDeliveryDo) FindByPage(offset int, limit int) (result []*model.JobWebhookDelivery, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j jobWebhookDeliveryDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j jobWebhookDeliveryDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j jobWebhookDeliveryDo) Delete(models ...*model.JobWebhookDelivery) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *jobWebhookDeliveryDo) withDO(do gen.Dao) *jobWebhookDeliveryDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
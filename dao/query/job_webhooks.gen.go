@@ -0,0 +1,437 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newJobWebhook(db *gorm.DB, opts ...gen.DOOption) jobWebhook {
+	_jobWebhook := jobWebhook{}
+
+	_jobWebhook.jobWebhookDo.UseDB(db, opts...)
+	_jobWebhook.jobWebhookDo.UseModel(&model.JobWebhook{})
+
+	tableName := _jobWebhook.jobWebhookDo.TableName()
+	_jobWebhook.ALL = field.NewAsterisk(tableName)
+	_jobWebhook.ID = field.NewUint(tableName, "id")
+	_jobWebhook.CreatedAt = field.NewTime(tableName, "created_at")
+	_jobWebhook.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_jobWebhook.DeletedAt = field.NewField(tableName, "deleted_at")
+	_jobWebhook.UserID = field.NewUint(tableName, "user_id")
+	_jobWebhook.AccountID = field.NewUint(tableName, "account_id")
+	_jobWebhook.JobName = field.NewString(tableName, "job_name")
+	_jobWebhook.AllMembers = field.NewBool(tableName, "all_members")
+	_jobWebhook.URL = field.NewString(tableName, "url")
+	_jobWebhook.Secret = field.NewString(tableName, "secret")
+	_jobWebhook.Events = field.NewField(tableName, "events")
+	_jobWebhook.Enabled = field.NewBool(tableName, "enabled")
+
+	_jobWebhook.fillFieldMap()
+
+	return _jobWebhook
+}
+
+type jobWebhook struct {
+	jobWebhookDo jobWebhookDo
+
+	ALL        field.Asterisk
+	ID         field.Uint
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field
+	UserID     field.Uint   // 创建者ID
+	AccountID  field.Uint   // 所属账户ID
+	JobName    field.String // 作业名，为空表示账户级 Webhook
+	AllMembers field.Bool   // 账户级 Webhook 是否包含账户中其他成员的作业
+	URL        field.String // 接收事件的 URL
+	Secret     field.String // 签名密钥
+	Events     field.Field  // 订阅的作业状态，为空表示所有状态
+	Enabled    field.Bool   // 是否启用
+
+	fieldMap map[string]field.Expr
+}
+
+func (j jobWebhook) Table(newTableName string) *jobWebhook {
+	j.jobWebhookDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j jobWebhook) As(alias string) *jobWebhook {
+	j.jobWebhookDo.DO = *(j.jobWebhookDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *jobWebhook) updateTableName(table string) *jobWebhook {
+	j.ALL = field.NewAsterisk(table)
+	j.ID = field.NewUint(table, "id")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.UserID = field.NewUint(table, "user_id")
+	j.AccountID = field.NewUint(table, "account_id")
+	j.JobName = field.NewString(table, "job_name")
+	j.AllMembers = field.NewBool(table, "all_members")
+	j.URL = field.NewString(table, "url")
+	j.Secret = field.NewString(table, "secret")
+	j.Events = field.NewField(table, "events")
+	j.Enabled = field.NewBool(table, "enabled")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *jobWebhook) WithContext(ctx context.Context) IJobWebhookDo {
+	return j.jobWebhookDo.WithContext(ctx)
+}
+
+func (j jobWebhook) TableName() string { return j.jobWebhookDo.TableName() }
+
+func (j jobWebhook) Alias() string { return j.jobWebhookDo.Alias() }
+
+func (j jobWebhook) Columns(cols ...field.Expr) gen.Columns { return j.jobWebhookDo.Columns(cols...) }
+
+func (j *jobWebhook) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *jobWebhook) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 12)
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["user_id"] = j.UserID
+	j.fieldMap["account_id"] = j.AccountID
+	j.fieldMap["job_name"] = j.JobName
+	j.fieldMap["all_members"] = j.AllMembers
+	j.fieldMap["url"] = j.URL
+	j.fieldMap["secret"] = j.Secret
+	j.fieldMap["events"] = j.Events
+	j.fieldMap["enabled"] = j.Enabled
+}
+
+func (j jobWebhook) clone(db *gorm.DB) jobWebhook {
+	j.jobWebhookDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j jobWebhook) replaceDB(db *gorm.DB) jobWebhook {
+	j.jobWebhookDo.ReplaceDB(db)
+	return j
+}
+
+type jobWebhookDo struct{ gen.DO }
+
+type IJobWebhookDo interface {
+	gen.SubQuery
+	Debug() IJobWebhookDo
+	WithContext(ctx context.Context) IJobWebhookDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJobWebhookDo
+	WriteDB() IJobWebhookDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJobWebhookDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJobWebhookDo
+	Not(conds ...gen.Condition) IJobWebhookDo
+	Or(conds ...gen.Condition) IJobWebhookDo
+	Select(conds ...field.Expr) IJobWebhookDo
+	Where(conds ...gen.Condition) IJobWebhookDo
+	Order(conds ...field.Expr) IJobWebhookDo
+	Distinct(cols ...field.Expr) IJobWebhookDo
+	Omit(cols ...field.Expr) IJobWebhookDo
+	Join(table schema.Tabler, on ...field.Expr) IJobWebhookDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDo
+	Group(cols ...field.Expr) IJobWebhookDo
+	Having(conds ...gen.Condition) IJobWebhookDo
+	Limit(limit int) IJobWebhookDo
+	Offset(offset int) IJobWebhookDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJobWebhookDo
+	Unscoped() IJobWebhookDo
+	Create(values ...*model.JobWebhook) error
+	CreateInBatches(values []*model.JobWebhook, batchSize int) error
+	Save(values ...*model.JobWebhook) error
+	First() (*model.JobWebhook, error)
+	Take() (*model.JobWebhook, error)
+	Last() (*model.JobWebhook, error)
+	Find() ([]*model.JobWebhook, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JobWebhook, err error)
+	FindInBatches(result *[]*model.JobWebhook, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JobWebhook) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJobWebhookDo
+	Assign(attrs ...field.AssignExpr) IJobWebhookDo
+	Joins(fields ...field.RelationField) IJobWebhookDo
+	Preload(fields ...field.RelationField) IJobWebhookDo
+	FirstOrInit() (*model.JobWebhook, error)
+	FirstOrCreate() (*model.JobWebhook, error)
+	FindByPage(offset int, limit int) (result []*model.JobWebhook, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJobWebhookDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j jobWebhookDo) Debug() IJobWebhookDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j jobWebhookDo) WithContext(ctx context.Context) IJobWebhookDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j jobWebhookDo) ReadDB() IJobWebhookDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j jobWebhookDo) WriteDB() IJobWebhookDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j jobWebhookDo) Session(config *gorm.Session) IJobWebhookDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j jobWebhookDo) Clauses(conds ...clause.Expression) IJobWebhookDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j jobWebhookDo) Returning(value interface{}, columns ...string) IJobWebhookDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j jobWebhookDo) Not(conds ...gen.Condition) IJobWebhookDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j jobWebhookDo) Or(conds ...gen.Condition) IJobWebhookDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j jobWebhookDo) Select(conds ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j jobWebhookDo) Where(conds ...gen.Condition) IJobWebhookDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j jobWebhookDo) Order(conds ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j jobWebhookDo) Distinct(cols ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j jobWebhookDo) Omit(cols ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j jobWebhookDo) Join(table schema.Tabler, on ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j jobWebhookDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j jobWebhookDo) RightJoin(table schema.Tabler, on ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j jobWebhookDo) Group(cols ...field.Expr) IJobWebhookDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j jobWebhookDo) Having(conds ...gen.Condition) IJobWebhookDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j jobWebhookDo) Limit(limit int) IJobWebhookDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j jobWebhookDo) Offset(offset int) IJobWebhookDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j jobWebhookDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJobWebhookDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j jobWebhookDo) Unscoped() IJobWebhookDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j jobWebhookDo) Create(values ...*model.JobWebhook) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j jobWebhookDo) CreateInBatches(values []*model.JobWebhook, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j jobWebhookDo) Save(values ...*model.JobWebhook) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j jobWebhookDo) First() (*model.JobWebhook, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhook), nil
+	}
+}
+
+func (j jobWebhookDo) Take() (*model.JobWebhook, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhook), nil
+	}
+}
+
+func (j jobWebhookDo) Last() (*model.JobWebhook, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhook), nil
+	}
+}
+
+func (j jobWebhookDo) Find() ([]*model.JobWebhook, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JobWebhook), err
+}
+
+func (j jobWebhookDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JobWebhook, err error) {
+	buf := make([]*model.JobWebhook, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j jobWebhookDo) FindInBatches(result *[]*model.JobWebhook, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j jobWebhookDo) Attrs(attrs ...field.AssignExpr) IJobWebhookDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j jobWebhookDo) Assign(attrs ...field.AssignExpr) IJobWebhookDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j jobWebhookDo) Joins(fields ...field.RelationField) IJobWebhookDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j jobWebhookDo) Preload(fields ...field.RelationField) IJobWebhookDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j jobWebhookDo) FirstOrInit() (*model.JobWebhook, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhook), nil
+	}
+}
+
+func (j jobWebhookDo) FirstOrCreate() (*model.JobWebhook, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JobWebhook), nil
+	}
+}
+
+func (j jobWebhookDo) FindByPage(offset int, limit int) (result []*model.JobWebhook, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j jobWebhookDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j jobWebhookDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j jobWebhookDo) Delete(models ...*model.JobWebhook) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *jobWebhookDo) withDO(do gen.Dao) *jobWebhookDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
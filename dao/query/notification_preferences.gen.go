@@ -0,0 +1,439 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newNotificationPreference(db *gorm.DB, opts ...gen.DOOption) notificationPreference {
+	_notificationPreference := notificationPreference{}
+
+	_notificationPreference.notificationPreferenceDo.UseDB(db, opts...)
+	_notificationPreference.notificationPreferenceDo.UseModel(&model.NotificationPreference{})
+
+	tableName := _notificationPreference.notificationPreferenceDo.TableName()
+	_notificationPreference.ALL = field.NewAsterisk(tableName)
+	_notificationPreference.ID = field.NewUint(tableName, "id")
+	_notificationPreference.CreatedAt = field.NewTime(tableName, "created_at")
+	_notificationPreference.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_notificationPreference.DeletedAt = field.NewField(tableName, "deleted_at")
+	_notificationPreference.UserID = field.NewUint(tableName, "user_id")
+	_notificationPreference.AlertTypes = field.NewField(tableName, "alert_types")
+	_notificationPreference.QuietHoursEnabled = field.NewBool(tableName, "quiet_hours_enabled")
+	_notificationPreference.QuietHoursStart = field.NewString(tableName, "quiet_hours_start")
+	_notificationPreference.QuietHoursEnd = field.NewString(tableName, "quiet_hours_end")
+	_notificationPreference.ReceiveAccountReminders = field.NewBool(tableName, "receive_account_reminders")
+	_notificationPreference.DailyDigestChannels = field.NewField(tableName, "daily_digest_channels")
+	_notificationPreference.WeeklyDigestChannels = field.NewField(tableName, "weekly_digest_channels")
+
+	_notificationPreference.fillFieldMap()
+
+	return _notificationPreference
+}
+
+type notificationPreference struct {
+	notificationPreferenceDo notificationPreferenceDo
+
+	ALL                     field.Asterisk
+	ID                      field.Uint
+	CreatedAt               field.Time
+	UpdatedAt               field.Time
+	DeletedAt               field.Field
+	UserID                  field.Uint   // 用户ID
+	AlertTypes              field.Field  // 各类型通知的接收设置
+	QuietHoursEnabled       field.Bool   // 是否启用免打扰时段
+	QuietHoursStart         field.String // 免打扰开始时间 (HH:MM)
+	QuietHoursEnd           field.String // 免打扰结束时间 (HH:MM)
+	ReceiveAccountReminders field.Bool   // 作为账户管理员时是否接收账户成员作业的清理提醒
+	DailyDigestChannels     field.Field  // 订阅每日作业摘要的渠道，为空表示不订阅
+	WeeklyDigestChannels    field.Field  // 订阅每周作业摘要的渠道，为空表示不订阅
+
+	fieldMap map[string]field.Expr
+}
+
+func (n notificationPreference) Table(newTableName string) *notificationPreference {
+	n.notificationPreferenceDo.UseTable(newTableName)
+	return n.updateTableName(newTableName)
+}
+
+func (n notificationPreference) As(alias string) *notificationPreference {
+	n.notificationPreferenceDo.DO = *(n.notificationPreferenceDo.As(alias).(*gen.DO))
+	return n.updateTableName(alias)
+}
+
+func (n *notificationPreference) updateTableName(table string) *notificationPreference {
+	n.ALL = field.NewAsterisk(table)
+	n.ID = field.NewUint(table, "id")
+	n.CreatedAt = field.NewTime(table, "created_at")
+	n.UpdatedAt = field.NewTime(table, "updated_at")
+	n.DeletedAt = field.NewField(table, "deleted_at")
+	n.UserID = field.NewUint(table, "user_id")
+	n.AlertTypes = field.NewField(table, "alert_types")
+	n.QuietHoursEnabled = field.NewBool(table, "quiet_hours_enabled")
+	n.QuietHoursStart = field.NewString(table, "quiet_hours_start")
+	n.QuietHoursEnd = field.NewString(table, "quiet_hours_end")
+	n.ReceiveAccountReminders = field.NewBool(table, "receive_account_reminders")
+	n.DailyDigestChannels = field.NewField(table, "daily_digest_channels")
+	n.WeeklyDigestChannels = field.NewField(table, "weekly_digest_channels")
+
+	n.fillFieldMap()
+
+	return n
+}
+
+func (n *notificationPreference) WithContext(ctx context.Context) INotificationPreferenceDo {
+	return n.notificationPreferenceDo.WithContext(ctx)
+}
+
+func (n notificationPreference) TableName() string { return n.notificationPreferenceDo.TableName() }
+
+func (n notificationPreference) Alias() string { return n.notificationPreferenceDo.Alias() }
+
+func (n notificationPreference) Columns(cols ...field.Expr) gen.Columns {
+	return n.notificationPreferenceDo.Columns(cols...)
+}
+
+func (n *notificationPreference) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := n.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (n *notificationPreference) fillFieldMap() {
+	n.fieldMap = make(map[string]field.Expr, 12)
+	n.fieldMap["id"] = n.ID
+	n.fieldMap["created_at"] = n.CreatedAt
+	n.fieldMap["updated_at"] = n.UpdatedAt
+	n.fieldMap["deleted_at"] = n.DeletedAt
+	n.fieldMap["user_id"] = n.UserID
+	n.fieldMap["alert_types"] = n.AlertTypes
+	n.fieldMap["quiet_hours_enabled"] = n.QuietHoursEnabled
+	n.fieldMap["quiet_hours_start"] = n.QuietHoursStart
+	n.fieldMap["quiet_hours_end"] = n.QuietHoursEnd
+	n.fieldMap["receive_account_reminders"] = n.ReceiveAccountReminders
+	n.fieldMap["daily_digest_channels"] = n.DailyDigestChannels
+	n.fieldMap["weekly_digest_channels"] = n.WeeklyDigestChannels
+}
+
+func (n notificationPreference) clone(db *gorm.DB) notificationPreference {
+	n.notificationPreferenceDo.ReplaceConnPool(db.Statement.ConnPool)
+	return n
+}
+
+func (n notificationPreference) replaceDB(db *gorm.DB) notificationPreference {
+	n.notificationPreferenceDo.ReplaceDB(db)
+	return n
+}
+
+type notificationPreferenceDo struct{ gen.DO }
+
+type INotificationPreferenceDo interface {
+	gen.SubQuery
+	Debug() INotificationPreferenceDo
+	WithContext(ctx context.Context) INotificationPreferenceDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() INotificationPreferenceDo
+	WriteDB() INotificationPreferenceDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) INotificationPreferenceDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) INotificationPreferenceDo
+	Not(conds ...gen.Condition) INotificationPreferenceDo
+	Or(conds ...gen.Condition) INotificationPreferenceDo
+	Select(conds ...field.Expr) INotificationPreferenceDo
+	Where(conds ...gen.Condition) INotificationPreferenceDo
+	Order(conds ...field.Expr) INotificationPreferenceDo
+	Distinct(cols ...field.Expr) INotificationPreferenceDo
+	Omit(cols ...field.Expr) INotificationPreferenceDo
+	Join(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo
+	RightJoin(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo
+	Group(cols ...field.Expr) INotificationPreferenceDo
+	Having(conds ...gen.Condition) INotificationPreferenceDo
+	Limit(limit int) INotificationPreferenceDo
+	Offset(offset int) INotificationPreferenceDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) INotificationPreferenceDo
+	Unscoped() INotificationPreferenceDo
+	Create(values ...*model.NotificationPreference) error
+	CreateInBatches(values []*model.NotificationPreference, batchSize int) error
+	Save(values ...*model.NotificationPreference) error
+	First() (*model.NotificationPreference, error)
+	Take() (*model.NotificationPreference, error)
+	Last() (*model.NotificationPreference, error)
+	Find() ([]*model.NotificationPreference, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.NotificationPreference, err error)
+	FindInBatches(result *[]*model.NotificationPreference, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.NotificationPreference) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) INotificationPreferenceDo
+	Assign(attrs ...field.AssignExpr) INotificationPreferenceDo
+	Joins(fields ...field.RelationField) INotificationPreferenceDo
+	Preload(fields ...field.RelationField) INotificationPreferenceDo
+	FirstOrInit() (*model.NotificationPreference, error)
+	FirstOrCreate() (*model.NotificationPreference, error)
+	FindByPage(offset int, limit int) (result []*model.NotificationPreference, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) INotificationPreferenceDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (n notificationPreferenceDo) Debug() INotificationPreferenceDo {
+	return n.withDO(n.DO.Debug())
+}
+
+func (n notificationPreferenceDo) WithContext(ctx context.Context) INotificationPreferenceDo {
+	return n.withDO(n.DO.WithContext(ctx))
+}
+
+func (n notificationPreferenceDo) ReadDB() INotificationPreferenceDo {
+	return n.Clauses(dbresolver.Read)
+}
+
+func (n notificationPreferenceDo) WriteDB() INotificationPreferenceDo {
+	return n.Clauses(dbresolver.Write)
+}
+
+func (n notificationPreferenceDo) Session(config *gorm.Session) INotificationPreferenceDo {
+	return n.withDO(n.DO.Session(config))
+}
+
+func (n notificationPreferenceDo) Clauses(conds ...clause.Expression) INotificationPreferenceDo {
+	return n.withDO(n.DO.Clauses(conds...))
+}
+
+func (n notificationPreferenceDo) Returning(value interface{}, columns ...string) INotificationPreferenceDo {
+	return n.withDO(n.DO.Returning(value, columns...))
+}
+
+func (n notificationPreferenceDo) Not(conds ...gen.Condition) INotificationPreferenceDo {
+	return n.withDO(n.DO.Not(conds...))
+}
+
+func (n notificationPreferenceDo) Or(conds ...gen.Condition) INotificationPreferenceDo {
+	return n.withDO(n.DO.Or(conds...))
+}
+
+func (n notificationPreferenceDo) Select(conds ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Select(conds...))
+}
+
+func (n notificationPreferenceDo) Where(conds ...gen.Condition) INotificationPreferenceDo {
+	return n.withDO(n.DO.Where(conds...))
+}
+
+func (n notificationPreferenceDo) Order(conds ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Order(conds...))
+}
+
+func (n notificationPreferenceDo) Distinct(cols ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Distinct(cols...))
+}
+
+func (n notificationPreferenceDo) Omit(cols ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Omit(cols...))
+}
+
+func (n notificationPreferenceDo) Join(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Join(table, on...))
+}
+
+func (n notificationPreferenceDo) LeftJoin(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.LeftJoin(table, on...))
+}
+
+func (n notificationPreferenceDo) RightJoin(table schema.Tabler, on ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.RightJoin(table, on...))
+}
+
+func (n notificationPreferenceDo) Group(cols ...field.Expr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Group(cols...))
+}
+
+func (n notificationPreferenceDo) Having(conds ...gen.Condition) INotificationPreferenceDo {
+	return n.withDO(n.DO.Having(conds...))
+}
+
+func (n notificationPreferenceDo) Limit(limit int) INotificationPreferenceDo {
+	return n.withDO(n.DO.Limit(limit))
+}
+
+func (n notificationPreferenceDo) Offset(offset int) INotificationPreferenceDo {
+	return n.withDO(n.DO.Offset(offset))
+}
+
+func (n notificationPreferenceDo) Scopes(funcs ...func(gen.Dao) gen.Dao) INotificationPreferenceDo {
+	return n.withDO(n.DO.Scopes(funcs...))
+}
+
+func (n notificationPreferenceDo) Unscoped() INotificationPreferenceDo {
+	return n.withDO(n.DO.Unscoped())
+}
+
+func (n notificationPreferenceDo) Create(values ...*model.NotificationPreference) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return n.DO.Create(values)
+}
+
+func (n notificationPreferenceDo) CreateInBatches(values []*model.NotificationPreference, batchSize int) error {
+	return n.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (n notificationPreferenceDo) Save(values ...*model.NotificationPreference) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return n.DO.Save(values)
+}
+
+func (n notificationPreferenceDo) First() (*model.NotificationPreference, error) {
+	if result, err := n.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NotificationPreference), nil
+	}
+}
+
+func (n notificationPreferenceDo) Take() (*model.NotificationPreference, error) {
+	if result, err := n.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NotificationPreference), nil
+	}
+}
+
+func (n notificationPreferenceDo) Last() (*model.NotificationPreference, error) {
+	if result, err := n.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NotificationPreference), nil
+	}
+}
+
+func (n notificationPreferenceDo) Find() ([]*model.NotificationPreference, error) {
+	result, err := n.DO.Find()
+	return result.([]*model.NotificationPreference), err
+}
+
+func (n notificationPreferenceDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.NotificationPreference, err error) {
+	buf := make([]*model.NotificationPreference, 0, batchSize)
+	err = n.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (n notificationPreferenceDo) FindInBatches(result *[]*model.NotificationPreference, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return n.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (n notificationPreferenceDo) Attrs(attrs ...field.AssignExpr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Attrs(attrs...))
+}
+
+func (n notificationPreferenceDo) Assign(attrs ...field.AssignExpr) INotificationPreferenceDo {
+	return n.withDO(n.DO.Assign(attrs...))
+}
+
+func (n notificationPreferenceDo) Joins(fields ...field.RelationField) INotificationPreferenceDo {
+	for _, _f := range fields {
+		n = *n.withDO(n.DO.Joins(_f))
+	}
+	return &n
+}
+
+func (n notificationPreferenceDo) Preload(fields ...field.RelationField) INotificationPreferenceDo {
+	for _, _f := range fields {
+		n = *n.withDO(n.DO.Preload(_f))
+	}
+	return &n
+}
+
+func (n notificationPreferenceDo) FirstOrInit() (*model.NotificationPreference, error) {
+	if result, err := n.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NotificationPreference), nil
+	}
+}
+
+func (n notificationPreferenceDo) FirstOrCreate() (*model.NotificationPreference, error) {
+	if result, err := n.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NotificationPreference), nil
+	}
+}
+
+func (n notificationPreferenceDo) FindByPage(offset int, limit int) (result []*model.NotificationPreference, count int64, err error) {
+	result, err = n.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = n.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (n notificationPreferenceDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = n.Count()
+	if err != nil {
+		return
+	}
+
+	err = n.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (n notificationPreferenceDo) Scan(result interface{}) (err error) {
+	return n.DO.Scan(result)
+}
+
+func (n notificationPreferenceDo) Delete(models ...*model.NotificationPreference) (result gen.ResultInfo, err error) {
+	return n.DO.Delete(models)
+}
+
+func (n *notificationPreferenceDo) withDO(do gen.Dao) *notificationPreferenceDo {
+	n.DO = *do.(*gen.DO)
+	return n
+}
@@ -0,0 +1,459 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newOutboxMessage(db *gorm.DB, opts ...gen.DOOption) outboxMessage {
+	_outboxMessage := outboxMessage{}
+
+	_outboxMessage.outboxMessageDo.UseDB(db, opts...)
+	_outboxMessage.outboxMessageDo.UseModel(&model.OutboxMessage{})
+
+	tableName := _outboxMessage.outboxMessageDo.TableName()
+	_outboxMessage.ALL = field.NewAsterisk(tableName)
+	_outboxMessage.ID = field.NewUint(tableName, "id")
+	_outboxMessage.CreatedAt = field.NewTime(tableName, "created_at")
+	_outboxMessage.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_outboxMessage.DeletedAt = field.NewField(tableName, "deleted_at")
+	_outboxMessage.DedupeKey = field.NewString(tableName, "dedupe_key")
+	_outboxMessage.UserID = field.NewUint(tableName, "user_id")
+	_outboxMessage.Receiver = field.NewField(tableName, "receiver")
+	_outboxMessage.Channels = field.NewField(tableName, "channels")
+	_outboxMessage.Subject = field.NewString(tableName, "subject")
+	_outboxMessage.Body = field.NewString(tableName, "body")
+	_outboxMessage.Status = field.NewString(tableName, "status")
+	_outboxMessage.Attempts = field.NewInt(tableName, "attempts")
+	_outboxMessage.MaxAttempts = field.NewInt(tableName, "max_attempts")
+	_outboxMessage.NextAttemptAt = field.NewTime(tableName, "next_attempt_at")
+	_outboxMessage.LastError = field.NewString(tableName, "last_error")
+	_outboxMessage.SentAt = field.NewTime(tableName, "sent_at")
+	_outboxMessage.Deliveries = field.NewField(tableName, "deliveries")
+
+	_outboxMessage.fillFieldMap()
+
+	return _outboxMessage
+}
+
+type outboxMessage struct {
+	outboxMessageDo outboxMessageDo
+
+	ALL           field.Asterisk
+	ID            field.Uint
+	CreatedAt     field.Time
+	UpdatedAt     field.Time
+	DeletedAt     field.Field
+	DedupeKey     field.String // 去重键，同一键在未发送完成前不会重复入队
+	UserID        field.Uint   // 接收者ID
+	Receiver      field.Field  // 接收者信息快照
+	Channels      field.Field  // 投递渠道，为空表示所有可用渠道
+	Subject       field.String // 通知标题
+	Body          field.String // 通知内容 (HTML)
+	Status        field.String // 投递状态 (pending, sending, sent, failed)
+	Attempts      field.Int    // 已尝试次数
+	MaxAttempts   field.Int    // 最大尝试次数
+	NextAttemptAt field.Time   // 下次尝试时间
+	LastError     field.String // 最近一次失败原因
+	SentAt        field.Time   // 发送成功时间
+	Deliveries    field.Field  // 投递记录
+
+	fieldMap map[string]field.Expr
+}
+
+func (o outboxMessage) Table(newTableName string) *outboxMessage {
+	o.outboxMessageDo.UseTable(newTableName)
+	return o.updateTableName(newTableName)
+}
+
+func (o outboxMessage) As(alias string) *outboxMessage {
+	o.outboxMessageDo.DO = *(o.outboxMessageDo.As(alias).(*gen.DO))
+	return o.updateTableName(alias)
+}
+
+func (o *outboxMessage) updateTableName(table string) *outboxMessage {
+	o.ALL = field.NewAsterisk(table)
+	o.ID = field.NewUint(table, "id")
+	o.CreatedAt = field.NewTime(table, "created_at")
+	o.UpdatedAt = field.NewTime(table, "updated_at")
+	o.DeletedAt = field.NewField(table, "deleted_at")
+	o.DedupeKey = field.NewString(table, "dedupe_key")
+	o.UserID = field.NewUint(table, "user_id")
+	o.Receiver = field.NewField(table, "receiver")
+	o.Channels = field.NewField(table, "channels")
+	o.Subject = field.NewString(table, "subject")
+	o.Body = field.NewString(table, "body")
+	o.Status = field.NewString(table, "status")
+	o.Attempts = field.NewInt(table, "attempts")
+	o.MaxAttempts = field.NewInt(table, "max_attempts")
+	o.NextAttemptAt = field.NewTime(table, "next_attempt_at")
+	o.LastError = field.NewString(table, "last_error")
+	o.SentAt = field.NewTime(table, "sent_at")
+	o.Deliveries = field.NewField(table, "deliveries")
+
+	o.fillFieldMap()
+
+	return o
+}
+
+func (o *outboxMessage) WithContext(ctx context.Context) IOutboxMessageDo {
+	return o.outboxMessageDo.WithContext(ctx)
+}
+
+func (o outboxMessage) TableName() string { return o.outboxMessageDo.TableName() }
+
+func (o outboxMessage) Alias() string { return o.outboxMessageDo.Alias() }
+
+func (o outboxMessage) Columns(cols ...field.Expr) gen.Columns {
+	return o.outboxMessageDo.Columns(cols...)
+}
+
+func (o *outboxMessage) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := o.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (o *outboxMessage) fillFieldMap() {
+	o.fieldMap = make(map[string]field.Expr, 17)
+	o.fieldMap["id"] = o.ID
+	o.fieldMap["created_at"] = o.CreatedAt
+	o.fieldMap["updated_at"] = o.UpdatedAt
+	o.fieldMap["deleted_at"] = o.DeletedAt
+	o.fieldMap["dedupe_key"] = o.DedupeKey
+	o.fieldMap["user_id"] = o.UserID
+	o.fieldMap["receiver"] = o.Receiver
+	o.fieldMap["channels"] = o.Channels
+	o.fieldMap["subject"] = o.Subject
+	o.fieldMap["body"] = o.Body
+	o.fieldMap["status"] = o.Status
+	o.fieldMap["attempts"] = o.Attempts
+	o.fieldMap["max_attempts"] = o.MaxAttempts
+	o.fieldMap["next_attempt_at"] = o.NextAttemptAt
+	o.fieldMap["last_error"] = o.LastError
+	o.fieldMap["sent_at"] = o.SentAt
+	o.fieldMap["deliveries"] = o.Deliveries
+}
+
+func (o outboxMessage) clone(db *gorm.DB) outboxMessage {
+	o.outboxMessageDo.ReplaceConnPool(db.Statement.ConnPool)
+	return o
+}
+
+func (o outboxMessage) replaceDB(db *gorm.DB) outboxMessage {
+	o.outboxMessageDo.ReplaceDB(db)
+	return o
+}
+
+type outboxMessageDo struct{ gen.DO }
+
+type IOutboxMessageDo interface {
+	gen.SubQuery
+	Debug() IOutboxMessageDo
+	WithContext(ctx context.Context) IOutboxMessageDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IOutboxMessageDo
+	WriteDB() IOutboxMessageDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IOutboxMessageDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IOutboxMessageDo
+	Not(conds ...gen.Condition) IOutboxMessageDo
+	Or(conds ...gen.Condition) IOutboxMessageDo
+	Select(conds ...field.Expr) IOutboxMessageDo
+	Where(conds ...gen.Condition) IOutboxMessageDo
+	Order(conds ...field.Expr) IOutboxMessageDo
+	Distinct(cols ...field.Expr) IOutboxMessageDo
+	Omit(cols ...field.Expr) IOutboxMessageDo
+	Join(table schema.Tabler, on ...field.Expr) IOutboxMessageDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IOutboxMessageDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IOutboxMessageDo
+	Group(cols ...field.Expr) IOutboxMessageDo
+	Having(conds ...gen.Condition) IOutboxMessageDo
+	Limit(limit int) IOutboxMessageDo
+	Offset(offset int) IOutboxMessageDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IOutboxMessageDo
+	Unscoped() IOutboxMessageDo
+	Create(values ...*model.OutboxMessage) error
+	CreateInBatches(values []*model.OutboxMessage, batchSize int) error
+	Save(values ...*model.OutboxMessage) error
+	First() (*model.OutboxMessage, error)
+	Take() (*model.OutboxMessage, error)
+	Last() (*model.OutboxMessage, error)
+	Find() ([]*model.OutboxMessage, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OutboxMessage, err error)
+	FindInBatches(result *[]*model.OutboxMessage, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.OutboxMessage) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IOutboxMessageDo
+	Assign(attrs ...field.AssignExpr) IOutboxMessageDo
+	Joins(fields ...field.RelationField) IOutboxMessageDo
+	Preload(fields ...field.RelationField) IOutboxMessageDo
+	FirstOrInit() (*model.OutboxMessage, error)
+	FirstOrCreate() (*model.OutboxMessage, error)
+	FindByPage(offset int, limit int) (result []*model.OutboxMessage, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IOutboxMessageDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (o outboxMessageDo) Debug() IOutboxMessageDo {
+	return o.withDO(o.DO.Debug())
+}
+
+func (o outboxMessageDo) WithContext(ctx context.Context) IOutboxMessageDo {
+	return o.withDO(o.DO.WithContext(ctx))
+}
+
+func (o outboxMessageDo) ReadDB() IOutboxMessageDo {
+	return o.Clauses(dbresolver.Read)
+}
+
+func (o outboxMessageDo) WriteDB() IOutboxMessageDo {
+	return o.Clauses(dbresolver.Write)
+}
+
+func (o outboxMessageDo) Session(config *gorm.Session) IOutboxMessageDo {
+	return o.withDO(o.DO.Session(config))
+}
+
+func (o outboxMessageDo) Clauses(conds ...clause.Expression) IOutboxMessageDo {
+	return o.withDO(o.DO.Clauses(conds...))
+}
+
+func (o outboxMessageDo) Returning(value interface{}, columns ...string) IOutboxMessageDo {
+	return o.withDO(o.DO.Returning(value, columns...))
+}
+
+func (o outboxMessageDo) Not(conds ...gen.Condition) IOutboxMessageDo {
+	return o.withDO(o.DO.Not(conds...))
+}
+
+func (o outboxMessageDo) Or(conds ...gen.Condition) IOutboxMessageDo {
+	return o.withDO(o.DO.Or(conds...))
+}
+
+func (o outboxMessageDo) Select(conds ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Select(conds...))
+}
+
+func (o outboxMessageDo) Where(conds ...gen.Condition) IOutboxMessageDo {
+	return o.withDO(o.DO.Where(conds...))
+}
+
+func (o outboxMessageDo) Order(conds ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Order(conds...))
+}
+
+func (o outboxMessageDo) Distinct(cols ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Distinct(cols...))
+}
+
+func (o outboxMessageDo) Omit(cols ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Omit(cols...))
+}
+
+func (o outboxMessageDo) Join(table schema.Tabler, on ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Join(table, on...))
+}
+
+func (o outboxMessageDo) LeftJoin(table schema.Tabler, on ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.LeftJoin(table, on...))
+}
+
+func (o outboxMessageDo) RightJoin(table schema.Tabler, on ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.RightJoin(table, on...))
+}
+
+func (o outboxMessageDo) Group(cols ...field.Expr) IOutboxMessageDo {
+	return o.withDO(o.DO.Group(cols...))
+}
+
+func (o outboxMessageDo) Having(conds ...gen.Condition) IOutboxMessageDo {
+	return o.withDO(o.DO.Having(conds...))
+}
+
+func (o outboxMessageDo) Limit(limit int) IOutboxMessageDo {
+	return o.withDO(o.DO.Limit(limit))
+}
+
+func (o outboxMessageDo) Offset(offset int) IOutboxMessageDo {
+	return o.withDO(o.DO.Offset(offset))
+}
+
+func (o outboxMessageDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IOutboxMessageDo {
+	return o.withDO(o.DO.Scopes(funcs...))
+}
+
+func (o outboxMessageDo) Unscoped() IOutboxMessageDo {
+	return o.withDO(o.DO.Unscoped())
+}
+
+func (o outboxMessageDo) Create(values ...*model.OutboxMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Create(values)
+}
+
+func (o outboxMessageDo) CreateInBatches(values []*model.OutboxMessage, batchSize int) error {
+	return o.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (o outboxMessageDo) Save(values ...*model.OutboxMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return o.DO.Save(values)
+}
+
+func (o outboxMessageDo) First() (*model.OutboxMessage, error) {
+	if result, err := o.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OutboxMessage), nil
+	}
+}
+
+func (o outboxMessageDo) Take() (*model.OutboxMessage, error) {
+	if result, err := o.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OutboxMessage), nil
+	}
+}
+
+func (o outboxMessageDo) Last() (*model.OutboxMessage, error) {
+	if result, err := o.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OutboxMessage), nil
+	}
+}
+
+func (o outboxMessageDo) Find() ([]*model.OutboxMessage, error) {
+	result, err := o.DO.Find()
+	return result.([]*model.OutboxMessage), err
+}
+
+func (o outboxMessageDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.OutboxMessage, err error) {
+	buf := make([]*model.OutboxMessage, 0, batchSize)
+	err = o.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (o outboxMessageDo) FindInBatches(result *[]*model.OutboxMessage, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return o.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (o outboxMessageDo) Attrs(attrs ...field.AssignExpr) IOutboxMessageDo {
+	return o.withDO(o.DO.Attrs(attrs...))
+}
+
+func (o outboxMessageDo) Assign(attrs ...field.AssignExpr) IOutboxMessageDo {
+	return o.withDO(o.DO.Assign(attrs...))
+}
+
+func (o outboxMessageDo) Joins(fields ...field.RelationField) IOutboxMessageDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Joins(_f))
+	}
+	return &o
+}
+
+func (o outboxMessageDo) Preload(fields ...field.RelationField) IOutboxMessageDo {
+	for _, _f := range fields {
+		o = *o.withDO(o.DO.Preload(_f))
+	}
+	return &o
+}
+
+func (o outboxMessageDo) FirstOrInit() (*model.OutboxMessage, error) {
+	if result, err := o.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OutboxMessage), nil
+	}
+}
+
+func (o outboxMessageDo) FirstOrCreate() (*model.OutboxMessage, error) {
+	if result, err := o.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.OutboxMessage), nil
+	}
+}
+
+func (o outboxMessageDo) FindByPage(offset int, limit int) (result []*model.OutboxMessage, count int64, err error) {
+	result, err = o.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = o.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (o outboxMessageDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = o.Count()
+	if err != nil {
+		return
+	}
+
+	err = o.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (o outboxMessageDo) Scan(result interface{}) (err error) {
+	return o.DO.Scan(result)
+}
+
+func (o outboxMessageDo) Delete(models ...*model.OutboxMessage) (result gen.ResultInfo, err error) {
+	return o.DO.Delete(models)
+}
+
+func (o *outboxMessageDo) withDO(do gen.Dao) *outboxMessageDo {
+	o.DO = *do.(*gen.DO)
+	return o
+}
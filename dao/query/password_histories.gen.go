@@ -0,0 +1,415 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newPasswordHistory(db *gorm.DB, opts ...gen.DOOption) passwordHistory {
+	_passwordHistory := passwordHistory{}
+
+	_passwordHistory.passwordHistoryDo.UseDB(db, opts...)
+	_passwordHistory.passwordHistoryDo.UseModel(&model.PasswordHistory{})
+
+	tableName := _passwordHistory.passwordHistoryDo.TableName()
+	_passwordHistory.ALL = field.NewAsterisk(tableName)
+	_passwordHistory.ID = field.NewUint(tableName, "id")
+	_passwordHistory.CreatedAt = field.NewTime(tableName, "created_at")
+	_passwordHistory.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_passwordHistory.DeletedAt = field.NewField(tableName, "deleted_at")
+	_passwordHistory.UserID = field.NewUint(tableName, "user_id")
+	_passwordHistory.Password = field.NewString(tableName, "password")
+
+	_passwordHistory.fillFieldMap()
+
+	return _passwordHistory
+}
+
+type passwordHistory struct {
+	passwordHistoryDo passwordHistoryDo
+
+	ALL       field.Asterisk
+	ID        field.Uint
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	DeletedAt field.Field
+	UserID    field.Uint   // 用户ID
+	Password  field.String // bcrypt 密码哈希
+
+	fieldMap map[string]field.Expr
+}
+
+func (p passwordHistory) Table(newTableName string) *passwordHistory {
+	p.passwordHistoryDo.UseTable(newTableName)
+	return p.updateTableName(newTableName)
+}
+
+func (p passwordHistory) As(alias string) *passwordHistory {
+	p.passwordHistoryDo.DO = *(p.passwordHistoryDo.As(alias).(*gen.DO))
+	return p.updateTableName(alias)
+}
+
+func (p *passwordHistory) updateTableName(table string) *passwordHistory {
+	p.ALL = field.NewAsterisk(table)
+	p.ID = field.NewUint(table, "id")
+	p.CreatedAt = field.NewTime(table, "created_at")
+	p.UpdatedAt = field.NewTime(table, "updated_at")
+	p.DeletedAt = field.NewField(table, "deleted_at")
+	p.UserID = field.NewUint(table, "user_id")
+	p.Password = field.NewString(table, "password")
+
+	p.fillFieldMap()
+
+	return p
+}
+
+func (p *passwordHistory) WithContext(ctx context.Context) IPasswordHistoryDo {
+	return p.passwordHistoryDo.WithContext(ctx)
+}
+
+func (p passwordHistory) TableName() string { return p.passwordHistoryDo.TableName() }
+
+func (p passwordHistory) Alias() string { return p.passwordHistoryDo.Alias() }
+
+func (p passwordHistory) Columns(cols ...field.Expr) gen.Columns {
+	return p.passwordHistoryDo.Columns(cols...)
+}
+
+func (p *passwordHistory) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := p.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (p *passwordHistory) fillFieldMap() {
+	p.fieldMap = make(map[string]field.Expr, 6)
+	p.fieldMap["id"] = p.ID
+	p.fieldMap["created_at"] = p.CreatedAt
+	p.fieldMap["updated_at"] = p.UpdatedAt
+	p.fieldMap["deleted_at"] = p.DeletedAt
+	p.fieldMap["user_id"] = p.UserID
+	p.fieldMap["password"] = p.Password
+}
+
+func (p passwordHistory) clone(db *gorm.DB) passwordHistory {
+	p.passwordHistoryDo.ReplaceConnPool(db.Statement.ConnPool)
+	return p
+}
+
+func (p passwordHistory) replaceDB(db *gorm.DB) passwordHistory {
+	p.passwordHistoryDo.ReplaceDB(db)
+	return p
+}
+
+type passwordHistoryDo struct{ gen.DO }
+
+type IPasswordHistoryDo interface {
+	gen.SubQuery
+	Debug() IPasswordHistoryDo
+	WithContext(ctx context.Context) IPasswordHistoryDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IPasswordHistoryDo
+	WriteDB() IPasswordHistoryDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IPasswordHistoryDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IPasswordHistoryDo
+	Not(conds ...gen.Condition) IPasswordHistoryDo
+	Or(conds ...gen.Condition) IPasswordHistoryDo
+	Select(conds ...field.Expr) IPasswordHistoryDo
+	Where(conds ...gen.Condition) IPasswordHistoryDo
+	Order(conds ...field.Expr) IPasswordHistoryDo
+	Distinct(cols ...field.Expr) IPasswordHistoryDo
+	Omit(cols ...field.Expr) IPasswordHistoryDo
+	Join(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo
+	Group(cols ...field.Expr) IPasswordHistoryDo
+	Having(conds ...gen.Condition) IPasswordHistoryDo
+	Limit(limit int) IPasswordHistoryDo
+	Offset(offset int) IPasswordHistoryDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IPasswordHistoryDo
+	Unscoped() IPasswordHistoryDo
+	Create(values ...*model.PasswordHistory) error
+	CreateInBatches(values []*model.PasswordHistory, batchSize int) error
+	Save(values ...*model.PasswordHistory) error
+	First() (*model.PasswordHistory, error)
+	Take() (*model.PasswordHistory, error)
+	Last() (*model.PasswordHistory, error)
+	Find() ([]*model.PasswordHistory, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.PasswordHistory, err error)
+	FindInBatches(result *[]*model.PasswordHistory, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.PasswordHistory) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IPasswordHistoryDo
+	Assign(attrs ...field.AssignExpr) IPasswordHistoryDo
+	Joins(fields ...field.RelationField) IPasswordHistoryDo
+	Preload(fields ...field.RelationField) IPasswordHistoryDo
+	FirstOrInit() (*model.PasswordHistory, error)
+	FirstOrCreate() (*model.PasswordHistory, error)
+	FindByPage(offset int, limit int) (result []*model.PasswordHistory, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IPasswordHistoryDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (p passwordHistoryDo) Debug() IPasswordHistoryDo {
+	return p.withDO(p.DO.Debug())
+}
+
+func (p passwordHistoryDo) WithContext(ctx context.Context) IPasswordHistoryDo {
+	return p.withDO(p.DO.WithContext(ctx))
+}
+
+func (p passwordHistoryDo) ReadDB() IPasswordHistoryDo {
+	return p.Clauses(dbresolver.Read)
+}
+
+func (p passwordHistoryDo) WriteDB() IPasswordHistoryDo {
+	return p.Clauses(dbresolver.Write)
+}
+
+func (p passwordHistoryDo) Session(config *gorm.Session) IPasswordHistoryDo {
+	return p.withDO(p.DO.Session(config))
+}
+
+func (p passwordHistoryDo) Clauses(conds ...clause.Expression) IPasswordHistoryDo {
+	return p.withDO(p.DO.Clauses(conds...))
+}
+
+func (p passwordHistoryDo) Returning(value interface{}, columns ...string) IPasswordHistoryDo {
+	return p.withDO(p.DO.Returning(value, columns...))
+}
+
+func (p passwordHistoryDo) Not(conds ...gen.Condition) IPasswordHistoryDo {
+	return p.withDO(p.DO.Not(conds...))
+}
+
+func (p passwordHistoryDo) Or(conds ...gen.Condition) IPasswordHistoryDo {
+	return p.withDO(p.DO.Or(conds...))
+}
+
+func (p passwordHistoryDo) Select(conds ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Select(conds...))
+}
+
+func (p passwordHistoryDo) Where(conds ...gen.Condition) IPasswordHistoryDo {
+	return p.withDO(p.DO.Where(conds...))
+}
+
+func (p passwordHistoryDo) Order(conds ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Order(conds...))
+}
+
+func (p passwordHistoryDo) Distinct(cols ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Distinct(cols...))
+}
+
+func (p passwordHistoryDo) Omit(cols ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Omit(cols...))
+}
+
+func (p passwordHistoryDo) Join(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Join(table, on...))
+}
+
+func (p passwordHistoryDo) LeftJoin(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.LeftJoin(table, on...))
+}
+
+func (p passwordHistoryDo) RightJoin(table schema.Tabler, on ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.RightJoin(table, on...))
+}
+
+func (p passwordHistoryDo) Group(cols ...field.Expr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Group(cols...))
+}
+
+func (p passwordHistoryDo) Having(conds ...gen.Condition) IPasswordHistoryDo {
+	return p.withDO(p.DO.Having(conds...))
+}
+
+func (p passwordHistoryDo) Limit(limit int) IPasswordHistoryDo {
+	return p.withDO(p.DO.Limit(limit))
+}
+
+func (p passwordHistoryDo) Offset(offset int) IPasswordHistoryDo {
+	return p.withDO(p.DO.Offset(offset))
+}
+
+func (p passwordHistoryDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IPasswordHistoryDo {
+	return p.withDO(p.DO.Scopes(funcs...))
+}
+
+func (p passwordHistoryDo) Unscoped() IPasswordHistoryDo {
+	return p.withDO(p.DO.Unscoped())
+}
+
+func (p passwordHistoryDo) Create(values ...*model.PasswordHistory) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return p.DO.Create(values)
+}
+
+func (p passwordHistoryDo) CreateInBatches(values []*model.PasswordHistory, batchSize int) error {
+	return p.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (p passwordHistoryDo) Save(values ...*model.PasswordHistory) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return p.DO.Save(values)
+}
+
+func (p passwordHistoryDo) First() (*model.PasswordHistory, error) {
+	if result, err := p.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PasswordHistory), nil
+	}
+}
+
+func (p passwordHistoryDo) Take() (*model.PasswordHistory, error) {
+	if result, err := p.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PasswordHistory), nil
+	}
+}
+
+func (p passwordHistoryDo) Last() (*model.PasswordHistory, error) {
+	if result, err := p.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PasswordHistory), nil
+	}
+}
+
+func (p passwordHistoryDo) Find() ([]*model.PasswordHistory, error) {
+	result, err := p.DO.Find()
+	return result.([]*model.PasswordHistory), err
+}
+
+func (p passwordHistoryDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.PasswordHistory, err error) {
+	buf := make([]*model.PasswordHistory, 0, batchSize)
+	err = p.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (p passwordHistoryDo) FindInBatches(result *[]*model.PasswordHistory, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return p.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (p passwordHistoryDo) Attrs(attrs ...field.AssignExpr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Attrs(attrs...))
+}
+
+func (p passwordHistoryDo) Assign(attrs ...field.AssignExpr) IPasswordHistoryDo {
+	return p.withDO(p.DO.Assign(attrs...))
+}
+
+func (p passwordHistoryDo) Joins(fields ...field.RelationField) IPasswordHistoryDo {
+	for _, _f := range fields {
+		p = *p.withDO(p.DO.Joins(_f))
+	}
+	return &p
+}
+
+func (p passwordHistoryDo) Preload(fields ...field.RelationField) IPasswordHistoryDo {
+	for _, _f := range fields {
+		p = *p.withDO(p.DO.Preload(_f))
+	}
+	return &p
+}
+
+func (p passwordHistoryDo) FirstOrInit() (*model.PasswordHistory, error) {
+	if result, err := p.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PasswordHistory), nil
+	}
+}
+
+func (p passwordHistoryDo) FirstOrCreate() (*model.PasswordHistory, error) {
+	if result, err := p.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PasswordHistory), nil
+	}
+}
+
+func (p passwordHistoryDo) FindByPage(offset int, limit int) (result []*model.PasswordHistory, count int64, err error) {
+	result, err = p.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = p.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (p passwordHistoryDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = p.Count()
+	if err != nil {
+		return
+	}
+
+	err = p.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (p passwordHistoryDo) Scan(result interface{}) (err error) {
+	return p.DO.Scan(result)
+}
+
+func (p passwordHistoryDo) Delete(models ...*model.PasswordHistory) (result gen.ResultInfo, err error) {
+	return p.DO.Delete(models)
+}
+
+func (p *passwordHistoryDo) withDO(do gen.Dao) *passwordHistoryDo {
+	p.DO = *do.(*gen.DO)
+	return p
+}
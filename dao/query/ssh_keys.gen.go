@@ -0,0 +1,431 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newSSHKey(db *gorm.DB, opts ...gen.DOOption) sSHKey {
+	_sSHKey := sSHKey{}
+
+	_sSHKey.sSHKeyDo.UseDB(db, opts...)
+	_sSHKey.sSHKeyDo.UseModel(&model.SSHKey{})
+
+	tableName := _sSHKey.sSHKeyDo.TableName()
+	_sSHKey.ALL = field.NewAsterisk(tableName)
+	_sSHKey.ID = field.NewUint(tableName, "id")
+	_sSHKey.CreatedAt = field.NewTime(tableName, "created_at")
+	_sSHKey.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_sSHKey.DeletedAt = field.NewField(tableName, "deleted_at")
+	_sSHKey.UserID = field.NewUint(tableName, "user_id")
+	_sSHKey.Name = field.NewString(tableName, "name")
+	_sSHKey.KeyType = field.NewString(tableName, "key_type")
+	_sSHKey.PublicKey = field.NewString(tableName, "public_key")
+	_sSHKey.Fingerprint = field.NewString(tableName, "fingerprint")
+	_sSHKey.RevokedAt = field.NewTime(tableName, "revoked_at")
+	_sSHKey.RevokedBy = field.NewString(tableName, "revoked_by")
+
+	_sSHKey.fillFieldMap()
+
+	return _sSHKey
+}
+
+type sSHKey struct {
+	sSHKeyDo sSHKeyDo
+
+	ALL         field.Asterisk
+	ID          field.Uint
+	CreatedAt   field.Time
+	UpdatedAt   field.Time
+	DeletedAt   field.Field
+	UserID      field.Uint   // 用户ID
+	Name        field.String // 公钥名称
+	KeyType     field.String // 公钥类型
+	PublicKey   field.String // 公钥 (authorized_keys 格式，不含选项和注释)
+	Fingerprint field.String // SHA256 指纹
+	RevokedAt   field.Time   // 撤销时间
+	RevokedBy   field.String // 撤销者用户名
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sSHKey) Table(newTableName string) *sSHKey {
+	s.sSHKeyDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sSHKey) As(alias string) *sSHKey {
+	s.sSHKeyDo.DO = *(s.sSHKeyDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sSHKey) updateTableName(table string) *sSHKey {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewUint(table, "id")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+	s.DeletedAt = field.NewField(table, "deleted_at")
+	s.UserID = field.NewUint(table, "user_id")
+	s.Name = field.NewString(table, "name")
+	s.KeyType = field.NewString(table, "key_type")
+	s.PublicKey = field.NewString(table, "public_key")
+	s.Fingerprint = field.NewString(table, "fingerprint")
+	s.RevokedAt = field.NewTime(table, "revoked_at")
+	s.RevokedBy = field.NewString(table, "revoked_by")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sSHKey) WithContext(ctx context.Context) ISSHKeyDo { return s.sSHKeyDo.WithContext(ctx) }
+
+func (s sSHKey) TableName() string { return s.sSHKeyDo.TableName() }
+
+func (s sSHKey) Alias() string { return s.sSHKeyDo.Alias() }
+
+func (s sSHKey) Columns(cols ...field.Expr) gen.Columns { return s.sSHKeyDo.Columns(cols...) }
+
+func (s *sSHKey) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sSHKey) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 11)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+	s.fieldMap["deleted_at"] = s.DeletedAt
+	s.fieldMap["user_id"] = s.UserID
+	s.fieldMap["name"] = s.Name
+	s.fieldMap["key_type"] = s.KeyType
+	s.fieldMap["public_key"] = s.PublicKey
+	s.fieldMap["fingerprint"] = s.Fingerprint
+	s.fieldMap["revoked_at"] = s.RevokedAt
+	s.fieldMap["revoked_by"] = s.RevokedBy
+}
+
+func (s sSHKey) clone(db *gorm.DB) sSHKey {
+	s.sSHKeyDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sSHKey) replaceDB(db *gorm.DB) sSHKey {
+	s.sSHKeyDo.ReplaceDB(db)
+	return s
+}
+
+type sSHKeyDo struct{ gen.DO }
+
+type ISSHKeyDo interface {
+	gen.SubQuery
+	Debug() ISSHKeyDo
+	WithContext(ctx context.Context) ISSHKeyDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ISSHKeyDo
+	WriteDB() ISSHKeyDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ISSHKeyDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ISSHKeyDo
+	Not(conds ...gen.Condition) ISSHKeyDo
+	Or(conds ...gen.Condition) ISSHKeyDo
+	Select(conds ...field.Expr) ISSHKeyDo
+	Where(conds ...gen.Condition) ISSHKeyDo
+	Order(conds ...field.Expr) ISSHKeyDo
+	Distinct(cols ...field.Expr) ISSHKeyDo
+	Omit(cols ...field.Expr) ISSHKeyDo
+	Join(table schema.Tabler, on ...field.Expr) ISSHKeyDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ISSHKeyDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ISSHKeyDo
+	Group(cols ...field.Expr) ISSHKeyDo
+	Having(conds ...gen.Condition) ISSHKeyDo
+	Limit(limit int) ISSHKeyDo
+	Offset(offset int) ISSHKeyDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ISSHKeyDo
+	Unscoped() ISSHKeyDo
+	Create(values ...*model.SSHKey) error
+	CreateInBatches(values []*model.SSHKey, batchSize int) error
+	Save(values ...*model.SSHKey) error
+	First() (*model.SSHKey, error)
+	Take() (*model.SSHKey, error)
+	Last() (*model.SSHKey, error)
+	Find() ([]*model.SSHKey, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SSHKey, err error)
+	FindInBatches(result *[]*model.SSHKey, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.SSHKey) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ISSHKeyDo
+	Assign(attrs ...field.AssignExpr) ISSHKeyDo
+	Joins(fields ...field.RelationField) ISSHKeyDo
+	Preload(fields ...field.RelationField) ISSHKeyDo
+	FirstOrInit() (*model.SSHKey, error)
+	FirstOrCreate() (*model.SSHKey, error)
+	FindByPage(offset int, limit int) (result []*model.SSHKey, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ISSHKeyDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (s sSHKeyDo) Debug() ISSHKeyDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sSHKeyDo) WithContext(ctx context.Context) ISSHKeyDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sSHKeyDo) ReadDB() ISSHKeyDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sSHKeyDo) WriteDB() ISSHKeyDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sSHKeyDo) Session(config *gorm.Session) ISSHKeyDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sSHKeyDo) Clauses(conds ...clause.Expression) ISSHKeyDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sSHKeyDo) Returning(value interface{}, columns ...string) ISSHKeyDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sSHKeyDo) Not(conds ...gen.Condition) ISSHKeyDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sSHKeyDo) Or(conds ...gen.Condition) ISSHKeyDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sSHKeyDo) Select(conds ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sSHKeyDo) Where(conds ...gen.Condition) ISSHKeyDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sSHKeyDo) Order(conds ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sSHKeyDo) Distinct(cols ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sSHKeyDo) Omit(cols ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sSHKeyDo) Join(table schema.Tabler, on ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sSHKeyDo) LeftJoin(table schema.Tabler, on ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sSHKeyDo) RightJoin(table schema.Tabler, on ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sSHKeyDo) Group(cols ...field.Expr) ISSHKeyDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sSHKeyDo) Having(conds ...gen.Condition) ISSHKeyDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sSHKeyDo) Limit(limit int) ISSHKeyDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sSHKeyDo) Offset(offset int) ISSHKeyDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sSHKeyDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ISSHKeyDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sSHKeyDo) Unscoped() ISSHKeyDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sSHKeyDo) Create(values ...*model.SSHKey) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sSHKeyDo) CreateInBatches(values []*model.SSHKey, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sSHKeyDo) Save(values ...*model.SSHKey) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sSHKeyDo) First() (*model.SSHKey, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SSHKey), nil
+	}
+}
+
+func (s sSHKeyDo) Take() (*model.SSHKey, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SSHKey), nil
+	}
+}
+
+func (s sSHKeyDo) Last() (*model.SSHKey, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SSHKey), nil
+	}
+}
+
+func (s sSHKeyDo) Find() ([]*model.SSHKey, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SSHKey), err
+}
+
+func (s sSHKeyDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SSHKey, err error) {
+	buf := make([]*model.SSHKey, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sSHKeyDo) FindInBatches(result *[]*model.SSHKey, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sSHKeyDo) Attrs(attrs ...field.AssignExpr) ISSHKeyDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sSHKeyDo) Assign(attrs ...field.AssignExpr) ISSHKeyDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sSHKeyDo) Joins(fields ...field.RelationField) ISSHKeyDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sSHKeyDo) Preload(fields ...field.RelationField) ISSHKeyDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sSHKeyDo) FirstOrInit() (*model.SSHKey, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SSHKey), nil
+	}
+}
+
+func (s sSHKeyDo) FirstOrCreate() (*model.SSHKey, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SSHKey), nil
+	}
+}
+
+func (s sSHKeyDo) FindByPage(offset int, limit int) (result []*model.SSHKey, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sSHKeyDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sSHKeyDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sSHKeyDo) Delete(models ...*model.SSHKey) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sSHKeyDo) withDO(do gen.Dao) *sSHKeyDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
@@ -0,0 +1,459 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newTerminalRecording(db *gorm.DB, opts ...gen.DOOption) terminalRecording {
+	_terminalRecording := terminalRecording{}
+
+	_terminalRecording.terminalRecordingDo.UseDB(db, opts...)
+	_terminalRecording.terminalRecordingDo.UseModel(&model.TerminalRecording{})
+
+	tableName := _terminalRecording.terminalRecordingDo.TableName()
+	_terminalRecording.ALL = field.NewAsterisk(tableName)
+	_terminalRecording.ID = field.NewUint(tableName, "id")
+	_terminalRecording.CreatedAt = field.NewTime(tableName, "created_at")
+	_terminalRecording.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_terminalRecording.DeletedAt = field.NewField(tableName, "deleted_at")
+	_terminalRecording.UserID = field.NewUint(tableName, "user_id")
+	_terminalRecording.Username = field.NewString(tableName, "username")
+	_terminalRecording.IsAdmin = field.NewBool(tableName, "is_admin")
+	_terminalRecording.Namespace = field.NewString(tableName, "namespace")
+	_terminalRecording.PodName = field.NewString(tableName, "pod_name")
+	_terminalRecording.ContainerName = field.NewString(tableName, "container_name")
+	_terminalRecording.StartedAt = field.NewTime(tableName, "started_at")
+	_terminalRecording.EndedAt = field.NewTime(tableName, "ended_at")
+	_terminalRecording.Duration = field.NewFloat64(tableName, "duration")
+	_terminalRecording.Size = field.NewInt64(tableName, "size")
+	_terminalRecording.Truncated = field.NewBool(tableName, "truncated")
+	_terminalRecording.Path = field.NewString(tableName, "path")
+	_terminalRecording.Data = field.NewBytes(tableName, "data")
+
+	_terminalRecording.fillFieldMap()
+
+	return _terminalRecording
+}
+
+type terminalRecording struct {
+	terminalRecordingDo terminalRecordingDo
+
+	ALL           field.Asterisk
+	ID            field.Uint
+	CreatedAt     field.Time
+	UpdatedAt     field.Time
+	DeletedAt     field.Field
+	UserID        field.Uint    // 打开终端的用户ID
+	Username      field.String  // 打开终端的用户名
+	IsAdmin       field.Bool    // 是否为平台管理员会话
+	Namespace     field.String  // Pod 命名空间
+	PodName       field.String  // Pod 名称
+	ContainerName field.String  // 容器名称
+	StartedAt     field.Time    // 会话开始时间
+	EndedAt       field.Time    // 会话结束时间，为空表示会话未结束或异常中断
+	Duration      field.Float64 // 会话时长 (秒)
+	Size          field.Int64   // 录像大小 (字节)
+	Truncated     field.Bool    // 是否因超过大小上限被截断
+	Path          field.String  // 录像文件在共享存储中的路径
+	Data          field.Bytes   // 录像内容，未配置录像目录时使用
+
+	fieldMap map[string]field.Expr
+}
+
+func (t terminalRecording) Table(newTableName string) *terminalRecording {
+	t.terminalRecordingDo.UseTable(newTableName)
+	return t.updateTableName(newTableName)
+}
+
+func (t terminalRecording) As(alias string) *terminalRecording {
+	t.terminalRecordingDo.DO = *(t.terminalRecordingDo.As(alias).(*gen.DO))
+	return t.updateTableName(alias)
+}
+
+func (t *terminalRecording) updateTableName(table string) *terminalRecording {
+	t.ALL = field.NewAsterisk(table)
+	t.ID = field.NewUint(table, "id")
+	t.CreatedAt = field.NewTime(table, "created_at")
+	t.UpdatedAt = field.NewTime(table, "updated_at")
+	t.DeletedAt = field.NewField(table, "deleted_at")
+	t.UserID = field.NewUint(table, "user_id")
+	t.Username = field.NewString(table, "username")
+	t.IsAdmin = field.NewBool(table, "is_admin")
+	t.Namespace = field.NewString(table, "namespace")
+	t.PodName = field.NewString(table, "pod_name")
+	t.ContainerName = field.NewString(table, "container_name")
+	t.StartedAt = field.NewTime(table, "started_at")
+	t.EndedAt = field.NewTime(table, "ended_at")
+	t.Duration = field.NewFloat64(table, "duration")
+	t.Size = field.NewInt64(table, "size")
+	t.Truncated = field.NewBool(table, "truncated")
+	t.Path = field.NewString(table, "path")
+	t.Data = field.NewBytes(table, "data")
+
+	t.fillFieldMap()
+
+	return t
+}
+
+func (t *terminalRecording) WithContext(ctx context.Context) ITerminalRecordingDo {
+	return t.terminalRecordingDo.WithContext(ctx)
+}
+
+func (t terminalRecording) TableName() string { return t.terminalRecordingDo.TableName() }
+
+func (t terminalRecording) Alias() string { return t.terminalRecordingDo.Alias() }
+
+func (t terminalRecording) Columns(cols ...field.Expr) gen.Columns {
+	return t.terminalRecordingDo.Columns(cols...)
+}
+
+func (t *terminalRecording) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := t.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (t *terminalRecording) fillFieldMap() {
+	t.fieldMap = make(map[string]field.Expr, 17)
+	t.fieldMap["id"] = t.ID
+	t.fieldMap["created_at"] = t.CreatedAt
+	t.fieldMap["updated_at"] = t.UpdatedAt
+	t.fieldMap["deleted_at"] = t.DeletedAt
+	t.fieldMap["user_id"] = t.UserID
+	t.fieldMap["username"] = t.Username
+	t.fieldMap["is_admin"] = t.IsAdmin
+	t.fieldMap["namespace"] = t.Namespace
+	t.fieldMap["pod_name"] = t.PodName
+	t.fieldMap["container_name"] = t.ContainerName
+	t.fieldMap["started_at"] = t.StartedAt
+	t.fieldMap["ended_at"] = t.EndedAt
+	t.fieldMap["duration"] = t.Duration
+	t.fieldMap["size"] = t.Size
+	t.fieldMap["truncated"] = t.Truncated
+	t.fieldMap["path"] = t.Path
+	t.fieldMap["data"] = t.Data
+}
+
+func (t terminalRecording) clone(db *gorm.DB) terminalRecording {
+	t.terminalRecordingDo.ReplaceConnPool(db.Statement.ConnPool)
+	return t
+}
+
+func (t terminalRecording) replaceDB(db *gorm.DB) terminalRecording {
+	t.terminalRecordingDo.ReplaceDB(db)
+	return t
+}
+
+type terminalRecordingDo struct{ gen.DO }
+
+type ITerminalRecordingDo interface {
+	gen.SubQuery
+	Debug() ITerminalRecordingDo
+	WithContext(ctx context.Context) ITerminalRecordingDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ITerminalRecordingDo
+	WriteDB() ITerminalRecordingDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ITerminalRecordingDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ITerminalRecordingDo
+	Not(conds ...gen.Condition) ITerminalRecordingDo
+	Or(conds ...gen.Condition) ITerminalRecordingDo
+	Select(conds ...field.Expr) ITerminalRecordingDo
+	Where(conds ...gen.Condition) ITerminalRecordingDo
+	Order(conds ...field.Expr) ITerminalRecordingDo
+	Distinct(cols ...field.Expr) ITerminalRecordingDo
+	Omit(cols ...field.Expr) ITerminalRecordingDo
+	Join(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo
+	Group(cols ...field.Expr) ITerminalRecordingDo
+	Having(conds ...gen.Condition) ITerminalRecordingDo
+	Limit(limit int) ITerminalRecordingDo
+	Offset(offset int) ITerminalRecordingDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ITerminalRecordingDo
+	Unscoped() ITerminalRecordingDo
+	Create(values ...*model.TerminalRecording) error
+	CreateInBatches(values []*model.TerminalRecording, batchSize int) error
+	Save(values ...*model.TerminalRecording) error
+	First() (*model.TerminalRecording, error)
+	Take() (*model.TerminalRecording, error)
+	Last() (*model.TerminalRecording, error)
+	Find() ([]*model.TerminalRecording, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.TerminalRecording, err error)
+	FindInBatches(result *[]*model.TerminalRecording, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.TerminalRecording) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ITerminalRecordingDo
+	Assign(attrs ...field.AssignExpr) ITerminalRecordingDo
+	Joins(fields ...field.RelationField) ITerminalRecordingDo
+	Preload(fields ...field.RelationField) ITerminalRecordingDo
+	FirstOrInit() (*model.TerminalRecording, error)
+	FirstOrCreate() (*model.TerminalRecording, error)
+	FindByPage(offset int, limit int) (result []*model.TerminalRecording, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ITerminalRecordingDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (t terminalRecordingDo) Debug() ITerminalRecordingDo {
+	return t.withDO(t.DO.Debug())
+}
+
+func (t terminalRecordingDo) WithContext(ctx context.Context) ITerminalRecordingDo {
+	return t.withDO(t.DO.WithContext(ctx))
+}
+
+func (t terminalRecordingDo) ReadDB() ITerminalRecordingDo {
+	return t.Clauses(dbresolver.Read)
+}
+
+func (t terminalRecordingDo) WriteDB() ITerminalRecordingDo {
+	return t.Clauses(dbresolver.Write)
+}
+
+func (t terminalRecordingDo) Session(config *gorm.Session) ITerminalRecordingDo {
+	return t.withDO(t.DO.Session(config))
+}
+
+func (t terminalRecordingDo) Clauses(conds ...clause.Expression) ITerminalRecordingDo {
+	return t.withDO(t.DO.Clauses(conds...))
+}
+
+func (t terminalRecordingDo) Returning(value interface{}, columns ...string) ITerminalRecordingDo {
+	return t.withDO(t.DO.Returning(value, columns...))
+}
+
+func (t terminalRecordingDo) Not(conds ...gen.Condition) ITerminalRecordingDo {
+	return t.withDO(t.DO.Not(conds...))
+}
+
+func (t terminalRecordingDo) Or(conds ...gen.Condition) ITerminalRecordingDo {
+	return t.withDO(t.DO.Or(conds...))
+}
+
+func (t terminalRecordingDo) Select(conds ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Select(conds...))
+}
+
+func (t terminalRecordingDo) Where(conds ...gen.Condition) ITerminalRecordingDo {
+	return t.withDO(t.DO.Where(conds...))
+}
+
+func (t terminalRecordingDo) Order(conds ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Order(conds...))
+}
+
+func (t terminalRecordingDo) Distinct(cols ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Distinct(cols...))
+}
+
+func (t terminalRecordingDo) Omit(cols ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Omit(cols...))
+}
+
+func (t terminalRecordingDo) Join(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Join(table, on...))
+}
+
+func (t terminalRecordingDo) LeftJoin(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.LeftJoin(table, on...))
+}
+
+func (t terminalRecordingDo) RightJoin(table schema.Tabler, on ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.RightJoin(table, on...))
+}
+
+func (t terminalRecordingDo) Group(cols ...field.Expr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Group(cols...))
+}
+
+func (t terminalRecordingDo) Having(conds ...gen.Condition) ITerminalRecordingDo {
+	return t.withDO(t.DO.Having(conds...))
+}
+
+func (t terminalRecordingDo) Limit(limit int) ITerminalRecordingDo {
+	return t.withDO(t.DO.Limit(limit))
+}
+
+func (t terminalRecordingDo) Offset(offset int) ITerminalRecordingDo {
+	return t.withDO(t.DO.Offset(offset))
+}
+
+func (t terminalRecordingDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ITerminalRecordingDo {
+	return t.withDO(t.DO.Scopes(funcs...))
+}
+
+func (t terminalRecordingDo) Unscoped() ITerminalRecordingDo {
+	return t.withDO(t.DO.Unscoped())
+}
+
+func (t terminalRecordingDo) Create(values ...*model.TerminalRecording) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return t.DO.Create(values)
+}
+
+func (t terminalRecordingDo) CreateInBatches(values []*model.TerminalRecording, batchSize int) error {
+	return t.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (t terminalRecordingDo) Save(values ...*model.TerminalRecording) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return t.DO.Save(values)
+}
+
+func (t terminalRecordingDo) First() (*model.TerminalRecording, error) {
+	if result, err := t.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TerminalRecording), nil
+	}
+}
+
+func (t terminalRecordingDo) Take() (*model.TerminalRecording, error) {
+	if result, err := t.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TerminalRecording), nil
+	}
+}
+
+func (t terminalRecordingDo) Last() (*model.TerminalRecording, error) {
+	if result, err := t.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TerminalRecording), nil
+	}
+}
+
+func (t terminalRecordingDo) Find() ([]*model.TerminalRecording, error) {
+	result, err := t.DO.Find()
+	return result.([]*model.TerminalRecording), err
+}
+
+func (t terminalRecordingDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.TerminalRecording, err error) {
+	buf := make([]*model.TerminalRecording, 0, batchSize)
+	err = t.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (t terminalRecordingDo) FindInBatches(result *[]*model.TerminalRecording, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return t.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (t terminalRecordingDo) Attrs(attrs ...field.AssignExpr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Attrs(attrs...))
+}
+
+func (t terminalRecordingDo) Assign(attrs ...field.AssignExpr) ITerminalRecordingDo {
+	return t.withDO(t.DO.Assign(attrs...))
+}
+
+func (t terminalRecordingDo) Joins(fields ...field.RelationField) ITerminalRecordingDo {
+	for _, _f := range fields {
+		t = *t.withDO(t.DO.Joins(_f))
+	}
+	return &t
+}
+
+func (t terminalRecordingDo) Preload(fields ...field.RelationField) ITerminalRecordingDo {
+	for _, _f := range fields {
+		t = *t.withDO(t.DO.Preload(_f))
+	}
+	return &t
+}
+
+func (t terminalRecordingDo) FirstOrInit() (*model.TerminalRecording, error) {
+	if result, err := t.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TerminalRecording), nil
+	}
+}
+
+func (t terminalRecordingDo) FirstOrCreate() (*model.TerminalRecording, error) {
+	if result, err := t.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.TerminalRecording), nil
+	}
+}
+
+func (t terminalRecordingDo) FindByPage(offset int, limit int) (result []*model.TerminalRecording, count int64, err error) {
+	result, err = t.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = t.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (t terminalRecordingDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = t.Count()
+	if err != nil {
+		return
+	}
+
+	err = t.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (t terminalRecordingDo) Scan(result interface{}) (err error) {
+	return t.DO.Scan(result)
+}
+
+func (t terminalRecordingDo) Delete(models ...*model.TerminalRecording) (result gen.ResultInfo, err error) {
+	return t.DO.Delete(models)
+}
+
+func (t *terminalRecordingDo) withDO(do gen.Dao) *terminalRecordingDo {
+	t.DO = *do.(*gen.DO)
+	return t
+}
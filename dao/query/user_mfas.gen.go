@@ -0,0 +1,435 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newUserMFA(db *gorm.DB, opts ...gen.DOOption) userMFA {
+	_userMFA := userMFA{}
+
+	_userMFA.userMFADo.UseDB(db, opts...)
+	_userMFA.userMFADo.UseModel(&model.UserMFA{})
+
+	tableName := _userMFA.userMFADo.TableName()
+	_userMFA.ALL = field.NewAsterisk(tableName)
+	_userMFA.ID = field.NewUint(tableName, "id")
+	_userMFA.CreatedAt = field.NewTime(tableName, "created_at")
+	_userMFA.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_userMFA.DeletedAt = field.NewField(tableName, "deleted_at")
+	_userMFA.UserID = field.NewUint(tableName, "user_id")
+	_userMFA.Secret = field.NewString(tableName, "secret")
+	_userMFA.Enabled = field.NewBool(tableName, "enabled")
+	_userMFA.EnabledAt = field.NewTime(tableName, "enabled_at")
+	_userMFA.LastUsedStep = field.NewInt64(tableName, "last_used_step")
+	_userMFA.RecoveryCodes = field.NewField(tableName, "recovery_codes")
+	_userMFA.FailedAttempts = field.NewInt(tableName, "failed_attempts")
+	_userMFA.LockedUntil = field.NewTime(tableName, "locked_until")
+
+	_userMFA.fillFieldMap()
+
+	return _userMFA
+}
+
+type userMFA struct {
+	userMFADo userMFADo
+
+	ALL            field.Asterisk
+	ID             field.Uint
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	DeletedAt      field.Field
+	UserID         field.Uint   // 用户ID
+	Secret         field.String // TOTP 密钥 (Base32)
+	Enabled        field.Bool   // 是否已启用
+	EnabledAt      field.Time   // 启用时间
+	LastUsedStep   field.Int64  // 最近使用的时间步
+	RecoveryCodes  field.Field  // 恢复码哈希
+	FailedAttempts field.Int    // 连续验证失败次数
+	LockedUntil    field.Time   // 验证失败过多时锁定到该时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (u userMFA) Table(newTableName string) *userMFA {
+	u.userMFADo.UseTable(newTableName)
+	return u.updateTableName(newTableName)
+}
+
+func (u userMFA) As(alias string) *userMFA {
+	u.userMFADo.DO = *(u.userMFADo.As(alias).(*gen.DO))
+	return u.updateTableName(alias)
+}
+
+func (u *userMFA) updateTableName(table string) *userMFA {
+	u.ALL = field.NewAsterisk(table)
+	u.ID = field.NewUint(table, "id")
+	u.CreatedAt = field.NewTime(table, "created_at")
+	u.UpdatedAt = field.NewTime(table, "updated_at")
+	u.DeletedAt = field.NewField(table, "deleted_at")
+	u.UserID = field.NewUint(table, "user_id")
+	u.Secret = field.NewString(table, "secret")
+	u.Enabled = field.NewBool(table, "enabled")
+	u.EnabledAt = field.NewTime(table, "enabled_at")
+	u.LastUsedStep = field.NewInt64(table, "last_used_step")
+	u.RecoveryCodes = field.NewField(table, "recovery_codes")
+	u.FailedAttempts = field.NewInt(table, "failed_attempts")
+	u.LockedUntil = field.NewTime(table, "locked_until")
+
+	u.fillFieldMap()
+
+	return u
+}
+
+func (u *userMFA) WithContext(ctx context.Context) IUserMFADo { return u.userMFADo.WithContext(ctx) }
+
+func (u userMFA) TableName() string { return u.userMFADo.TableName() }
+
+func (u userMFA) Alias() string { return u.userMFADo.Alias() }
+
+func (u userMFA) Columns(cols ...field.Expr) gen.Columns { return u.userMFADo.Columns(cols...) }
+
+func (u *userMFA) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := u.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (u *userMFA) fillFieldMap() {
+	u.fieldMap = make(map[string]field.Expr, 12)
+	u.fieldMap["id"] = u.ID
+	u.fieldMap["created_at"] = u.CreatedAt
+	u.fieldMap["updated_at"] = u.UpdatedAt
+	u.fieldMap["deleted_at"] = u.DeletedAt
+	u.fieldMap["user_id"] = u.UserID
+	u.fieldMap["secret"] = u.Secret
+	u.fieldMap["enabled"] = u.Enabled
+	u.fieldMap["enabled_at"] = u.EnabledAt
+	u.fieldMap["last_used_step"] = u.LastUsedStep
+	u.fieldMap["recovery_codes"] = u.RecoveryCodes
+	u.fieldMap["failed_attempts"] = u.FailedAttempts
+	u.fieldMap["locked_until"] = u.LockedUntil
+}
+
+func (u userMFA) clone(db *gorm.DB) userMFA {
+	u.userMFADo.ReplaceConnPool(db.Statement.ConnPool)
+	return u
+}
+
+func (u userMFA) replaceDB(db *gorm.DB) userMFA {
+	u.userMFADo.ReplaceDB(db)
+	return u
+}
+
+type userMFADo struct{ gen.DO }
+
+type IUserMFADo interface {
+	gen.SubQuery
+	Debug() IUserMFADo
+	WithContext(ctx context.Context) IUserMFADo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IUserMFADo
+	WriteDB() IUserMFADo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IUserMFADo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IUserMFADo
+	Not(conds ...gen.Condition) IUserMFADo
+	Or(conds ...gen.Condition) IUserMFADo
+	Select(conds ...field.Expr) IUserMFADo
+	Where(conds ...gen.Condition) IUserMFADo
+	Order(conds ...field.Expr) IUserMFADo
+	Distinct(cols ...field.Expr) IUserMFADo
+	Omit(cols ...field.Expr) IUserMFADo
+	Join(table schema.Tabler, on ...field.Expr) IUserMFADo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IUserMFADo
+	RightJoin(table schema.Tabler, on ...field.Expr) IUserMFADo
+	Group(cols ...field.Expr) IUserMFADo
+	Having(conds ...gen.Condition) IUserMFADo
+	Limit(limit int) IUserMFADo
+	Offset(offset int) IUserMFADo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IUserMFADo
+	Unscoped() IUserMFADo
+	Create(values ...*model.UserMFA) error
+	CreateInBatches(values []*model.UserMFA, batchSize int) error
+	Save(values ...*model.UserMFA) error
+	First() (*model.UserMFA, error)
+	Take() (*model.UserMFA, error)
+	Last() (*model.UserMFA, error)
+	Find() ([]*model.UserMFA, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserMFA, err error)
+	FindInBatches(result *[]*model.UserMFA, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.UserMFA) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IUserMFADo
+	Assign(attrs ...field.AssignExpr) IUserMFADo
+	Joins(fields ...field.RelationField) IUserMFADo
+	Preload(fields ...field.RelationField) IUserMFADo
+	FirstOrInit() (*model.UserMFA, error)
+	FirstOrCreate() (*model.UserMFA, error)
+	FindByPage(offset int, limit int) (result []*model.UserMFA, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IUserMFADo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (u userMFADo) Debug() IUserMFADo {
+	return u.withDO(u.DO.Debug())
+}
+
+func (u userMFADo) WithContext(ctx context.Context) IUserMFADo {
+	return u.withDO(u.DO.WithContext(ctx))
+}
+
+func (u userMFADo) ReadDB() IUserMFADo {
+	return u.Clauses(dbresolver.Read)
+}
+
+func (u userMFADo) WriteDB() IUserMFADo {
+	return u.Clauses(dbresolver.Write)
+}
+
+func (u userMFADo) Session(config *gorm.Session) IUserMFADo {
+	return u.withDO(u.DO.Session(config))
+}
+
+func (u userMFADo) Clauses(conds ...clause.Expression) IUserMFADo {
+	return u.withDO(u.DO.Clauses(conds...))
+}
+
+func (u userMFADo) Returning(value interface{}, columns ...string) IUserMFADo {
+	return u.withDO(u.DO.Returning(value, columns...))
+}
+
+func (u userMFADo) Not(conds ...gen.Condition) IUserMFADo {
+	return u.withDO(u.DO.Not(conds...))
+}
+
+func (u userMFADo) Or(conds ...gen.Condition) IUserMFADo {
+	return u.withDO(u.DO.Or(conds...))
+}
+
+func (u userMFADo) Select(conds ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Select(conds...))
+}
+
+func (u userMFADo) Where(conds ...gen.Condition) IUserMFADo {
+	return u.withDO(u.DO.Where(conds...))
+}
+
+func (u userMFADo) Order(conds ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Order(conds...))
+}
+
+func (u userMFADo) Distinct(cols ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Distinct(cols...))
+}
+
+func (u userMFADo) Omit(cols ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Omit(cols...))
+}
+
+func (u userMFADo) Join(table schema.Tabler, on ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Join(table, on...))
+}
+
+func (u userMFADo) LeftJoin(table schema.Tabler, on ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.LeftJoin(table, on...))
+}
+
+func (u userMFADo) RightJoin(table schema.Tabler, on ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.RightJoin(table, on...))
+}
+
+func (u userMFADo) Group(cols ...field.Expr) IUserMFADo {
+	return u.withDO(u.DO.Group(cols...))
+}
+
+func (u userMFADo) Having(conds ...gen.Condition) IUserMFADo {
+	return u.withDO(u.DO.Having(conds...))
+}
+
+func (u userMFADo) Limit(limit int) IUserMFADo {
+	return u.withDO(u.DO.Limit(limit))
+}
+
+func (u userMFADo) Offset(offset int) IUserMFADo {
+	return u.withDO(u.DO.Offset(offset))
+}
+
+func (u userMFADo) Scopes(funcs ...func(gen.Dao) gen.Dao) IUserMFADo {
+	return u.withDO(u.DO.Scopes(funcs...))
+}
+
+func (u userMFADo) Unscoped() IUserMFADo {
+	return u.withDO(u.DO.Unscoped())
+}
+
+func (u userMFADo) Create(values ...*model.UserMFA) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Create(values)
+}
+
+func (u userMFADo) CreateInBatches(values []*model.UserMFA, batchSize int) error {
+	return u.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (u userMFADo) Save(values ...*model.UserMFA) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Save(values)
+}
+
+func (u userMFADo) First() (*model.UserMFA, error) {
+	if result, err := u.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserMFA), nil
+	}
+}
+
+func (u userMFADo) Take() (*model.UserMFA, error) {
+	if result, err := u.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserMFA), nil
+	}
+}
+
+func (u userMFADo) Last() (*model.UserMFA, error) {
+	if result, err := u.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserMFA), nil
+	}
+}
+
+func (u userMFADo) Find() ([]*model.UserMFA, error) {
+	result, err := u.DO.Find()
+	return result.([]*model.UserMFA), err
+}
+
+func (u userMFADo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserMFA, err error) {
+	buf := make([]*model.UserMFA, 0, batchSize)
+	err = u.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (u userMFADo) FindInBatches(result *[]*model.UserMFA, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return u.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (u userMFADo) Attrs(attrs ...field.AssignExpr) IUserMFADo {
+	return u.withDO(u.DO.Attrs(attrs...))
+}
+
+func (u userMFADo) Assign(attrs ...field.AssignExpr) IUserMFADo {
+	return u.withDO(u.DO.Assign(attrs...))
+}
+
+func (u userMFADo) Joins(fields ...field.RelationField) IUserMFADo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Joins(_f))
+	}
+	return &u
+}
+
+func (u userMFADo) Preload(fields ...field.RelationField) IUserMFADo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Preload(_f))
+	}
+	return &u
+}
+
+func (u userMFADo) FirstOrInit() (*model.UserMFA, error) {
+	if result, err := u.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserMFA), nil
+	}
+}
+
+func (u userMFADo) FirstOrCreate() (*model.UserMFA, error) {
+	if result, err := u.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserMFA), nil
+	}
+}
+
+func (u userMFADo) FindByPage(offset int, limit int) (result []*model.UserMFA, count int64, err error) {
+	result, err = u.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = u.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (u userMFADo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = u.Count()
+	if err != nil {
+		return
+	}
+
+	err = u.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (u userMFADo) Scan(result interface{}) (err error) {
+	return u.DO.Scan(result)
+}
+
+func (u userMFADo) Delete(models ...*model.UserMFA) (result gen.ResultInfo, err error) {
+	return u.DO.Delete(models)
+}
+
+func (u *userMFADo) withDO(do gen.Dao) *userMFADo {
+	u.DO = *do.(*gen.DO)
+	return u
+}
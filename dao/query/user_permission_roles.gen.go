@@ -0,0 +1,415 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newUserPermissionRole(db *gorm.DB, opts ...gen.DOOption) userPermissionRole {
+	_userPermissionRole := userPermissionRole{}
+
+	_userPermissionRole.userPermissionRoleDo.UseDB(db, opts...)
+	_userPermissionRole.userPermissionRoleDo.UseModel(&model.UserPermissionRole{})
+
+	tableName := _userPermissionRole.userPermissionRoleDo.TableName()
+	_userPermissionRole.ALL = field.NewAsterisk(tableName)
+	_userPermissionRole.ID = field.NewUint(tableName, "id")
+	_userPermissionRole.CreatedAt = field.NewTime(tableName, "created_at")
+	_userPermissionRole.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_userPermissionRole.DeletedAt = field.NewField(tableName, "deleted_at")
+	_userPermissionRole.UserID = field.NewUint(tableName, "user_id")
+	_userPermissionRole.RoleID = field.NewUint(tableName, "role_id")
+
+	_userPermissionRole.fillFieldMap()
+
+	return _userPermissionRole
+}
+
+type userPermissionRole struct {
+	userPermissionRoleDo userPermissionRoleDo
+
+	ALL       field.Asterisk
+	ID        field.Uint
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	DeletedAt field.Field
+	UserID    field.Uint // 用户ID
+	RoleID    field.Uint // 权限角色ID
+
+	fieldMap map[string]field.Expr
+}
+
+func (u userPermissionRole) Table(newTableName string) *userPermissionRole {
+	u.userPermissionRoleDo.UseTable(newTableName)
+	return u.updateTableName(newTableName)
+}
+
+func (u userPermissionRole) As(alias string) *userPermissionRole {
+	u.userPermissionRoleDo.DO = *(u.userPermissionRoleDo.As(alias).(*gen.DO))
+	return u.updateTableName(alias)
+}
+
+func (u *userPermissionRole) updateTableName(table string) *userPermissionRole {
+	u.ALL = field.NewAsterisk(table)
+	u.ID = field.NewUint(table, "id")
+	u.CreatedAt = field.NewTime(table, "created_at")
+	u.UpdatedAt = field.NewTime(table, "updated_at")
+	u.DeletedAt = field.NewField(table, "deleted_at")
+	u.UserID = field.NewUint(table, "user_id")
+	u.RoleID = field.NewUint(table, "role_id")
+
+	u.fillFieldMap()
+
+	return u
+}
+
+func (u *userPermissionRole) WithContext(ctx context.Context) IUserPermissionRoleDo {
+	return u.userPermissionRoleDo.WithContext(ctx)
+}
+
+func (u userPermissionRole) TableName() string { return u.userPermissionRoleDo.TableName() }
+
+func (u userPermissionRole) Alias() string { return u.userPermissionRoleDo.Alias() }
+
+func (u userPermissionRole) Columns(cols ...field.Expr) gen.Columns {
+	return u.userPermissionRoleDo.Columns(cols...)
+}
+
+func (u *userPermissionRole) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := u.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (u *userPermissionRole) fillFieldMap() {
+	u.fieldMap = make(map[string]field.Expr, 6)
+	u.fieldMap["id"] = u.ID
+	u.fieldMap["created_at"] = u.CreatedAt
+	u.fieldMap["updated_at"] = u.UpdatedAt
+	u.fieldMap["deleted_at"] = u.DeletedAt
+	u.fieldMap["user_id"] = u.UserID
+	u.fieldMap["role_id"] = u.RoleID
+}
+
+func (u userPermissionRole) clone(db *gorm.DB) userPermissionRole {
+	u.userPermissionRoleDo.ReplaceConnPool(db.Statement.ConnPool)
+	return u
+}
+
+func (u userPermissionRole) replaceDB(db *gorm.DB) userPermissionRole {
+	u.userPermissionRoleDo.ReplaceDB(db)
+	return u
+}
+
+type userPermissionRoleDo struct{ gen.DO }
+
+type IUserPermissionRoleDo interface {
+	gen.SubQuery
+	Debug() IUserPermissionRoleDo
+	WithContext(ctx context.Context) IUserPermissionRoleDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IUserPermissionRoleDo
+	WriteDB() IUserPermissionRoleDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IUserPermissionRoleDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IUserPermissionRoleDo
+	Not(conds ...gen.Condition) IUserPermissionRoleDo
+	Or(conds ...gen.Condition) IUserPermissionRoleDo
+	Select(conds ...field.Expr) IUserPermissionRoleDo
+	Where(conds ...gen.Condition) IUserPermissionRoleDo
+	Order(conds ...field.Expr) IUserPermissionRoleDo
+	Distinct(cols ...field.Expr) IUserPermissionRoleDo
+	Omit(cols ...field.Expr) IUserPermissionRoleDo
+	Join(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo
+	Group(cols ...field.Expr) IUserPermissionRoleDo
+	Having(conds ...gen.Condition) IUserPermissionRoleDo
+	Limit(limit int) IUserPermissionRoleDo
+	Offset(offset int) IUserPermissionRoleDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IUserPermissionRoleDo
+	Unscoped() IUserPermissionRoleDo
+	Create(values ...*model.UserPermissionRole) error
+	CreateInBatches(values []*model.UserPermissionRole, batchSize int) error
+	Save(values ...*model.UserPermissionRole) error
+	First() (*model.UserPermissionRole, error)
+	Take() (*model.UserPermissionRole, error)
+	Last() (*model.UserPermissionRole, error)
+	Find() ([]*model.UserPermissionRole, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserPermissionRole, err error)
+	FindInBatches(result *[]*model.UserPermissionRole, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.UserPermissionRole) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IUserPermissionRoleDo
+	Assign(attrs ...field.AssignExpr) IUserPermissionRoleDo
+	Joins(fields ...field.RelationField) IUserPermissionRoleDo
+	Preload(fields ...field.RelationField) IUserPermissionRoleDo
+	FirstOrInit() (*model.UserPermissionRole, error)
+	FirstOrCreate() (*model.UserPermissionRole, error)
+	FindByPage(offset int, limit int) (result []*model.UserPermissionRole, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IUserPermissionRoleDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (u userPermissionRoleDo) Debug() IUserPermissionRoleDo {
+	return u.withDO(u.DO.Debug())
+}
+
+func (u userPermissionRoleDo) WithContext(ctx context.Context) IUserPermissionRoleDo {
+	return u.withDO(u.DO.WithContext(ctx))
+}
+
+func (u userPermissionRoleDo) ReadDB() IUserPermissionRoleDo {
+	return u.Clauses(dbresolver.Read)
+}
+
+func (u userPermissionRoleDo) WriteDB() IUserPermissionRoleDo {
+	return u.Clauses(dbresolver.Write)
+}
+
+func (u userPermissionRoleDo) Session(config *gorm.Session) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Session(config))
+}
+
+func (u userPermissionRoleDo) Clauses(conds ...clause.Expression) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Clauses(conds...))
+}
+
+func (u userPermissionRoleDo) Returning(value interface{}, columns ...string) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Returning(value, columns...))
+}
+
+func (u userPermissionRoleDo) Not(conds ...gen.Condition) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Not(conds...))
+}
+
+func (u userPermissionRoleDo) Or(conds ...gen.Condition) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Or(conds...))
+}
+
+func (u userPermissionRoleDo) Select(conds ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Select(conds...))
+}
+
+func (u userPermissionRoleDo) Where(conds ...gen.Condition) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Where(conds...))
+}
+
+func (u userPermissionRoleDo) Order(conds ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Order(conds...))
+}
+
+func (u userPermissionRoleDo) Distinct(cols ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Distinct(cols...))
+}
+
+func (u userPermissionRoleDo) Omit(cols ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Omit(cols...))
+}
+
+func (u userPermissionRoleDo) Join(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Join(table, on...))
+}
+
+func (u userPermissionRoleDo) LeftJoin(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.LeftJoin(table, on...))
+}
+
+func (u userPermissionRoleDo) RightJoin(table schema.Tabler, on ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.RightJoin(table, on...))
+}
+
+func (u userPermissionRoleDo) Group(cols ...field.Expr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Group(cols...))
+}
+
+func (u userPermissionRoleDo) Having(conds ...gen.Condition) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Having(conds...))
+}
+
+func (u userPermissionRoleDo) Limit(limit int) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Limit(limit))
+}
+
+func (u userPermissionRoleDo) Offset(offset int) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Offset(offset))
+}
+
+func (u userPermissionRoleDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Scopes(funcs...))
+}
+
+func (u userPermissionRoleDo) Unscoped() IUserPermissionRoleDo {
+	return u.withDO(u.DO.Unscoped())
+}
+
+func (u userPermissionRoleDo) Create(values ...*model.UserPermissionRole) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Create(values)
+}
+
+func (u userPermissionRoleDo) CreateInBatches(values []*model.UserPermissionRole, batchSize int) error {
+	return u.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (u userPermissionRoleDo) Save(values ...*model.UserPermissionRole) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return u.DO.Save(values)
+}
+
+func (u userPermissionRoleDo) First() (*model.UserPermissionRole, error) {
+	if result, err := u.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserPermissionRole), nil
+	}
+}
+
+func (u userPermissionRoleDo) Take() (*model.UserPermissionRole, error) {
+	if result, err := u.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserPermissionRole), nil
+	}
+}
+
+func (u userPermissionRoleDo) Last() (*model.UserPermissionRole, error) {
+	if result, err := u.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserPermissionRole), nil
+	}
+}
+
+func (u userPermissionRoleDo) Find() ([]*model.UserPermissionRole, error) {
+	result, err := u.DO.Find()
+	return result.([]*model.UserPermissionRole), err
+}
+
+func (u userPermissionRoleDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.UserPermissionRole, err error) {
+	buf := make([]*model.UserPermissionRole, 0, batchSize)
+	err = u.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (u userPermissionRoleDo) FindInBatches(result *[]*model.UserPermissionRole, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return u.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (u userPermissionRoleDo) Attrs(attrs ...field.AssignExpr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Attrs(attrs...))
+}
+
+func (u userPermissionRoleDo) Assign(attrs ...field.AssignExpr) IUserPermissionRoleDo {
+	return u.withDO(u.DO.Assign(attrs...))
+}
+
+func (u userPermissionRoleDo) Joins(fields ...field.RelationField) IUserPermissionRoleDo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Joins(_f))
+	}
+	return &u
+}
+
+func (u userPermissionRoleDo) Preload(fields ...field.RelationField) IUserPermissionRoleDo {
+	for _, _f := range fields {
+		u = *u.withDO(u.DO.Preload(_f))
+	}
+	return &u
+}
+
+func (u userPermissionRoleDo) FirstOrInit() (*model.UserPermissionRole, error) {
+	if result, err := u.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserPermissionRole), nil
+	}
+}
+
+func (u userPermissionRoleDo) FirstOrCreate() (*model.UserPermissionRole, error) {
+	if result, err := u.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.UserPermissionRole), nil
+	}
+}
+
+func (u userPermissionRoleDo) FindByPage(offset int, limit int) (result []*model.UserPermissionRole, count int64, err error) {
+	result, err = u.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = u.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (u userPermissionRoleDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = u.Count()
+	if err != nil {
+		return
+	}
+
+	err = u.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (u userPermissionRoleDo) Scan(result interface{}) (err error) {
+	return u.DO.Scan(result)
+}
+
+func (u userPermissionRoleDo) Delete(models ...*model.UserPermissionRole) (result gen.ResultInfo, err error) {
+	return u.DO.Delete(models)
+}
+
+func (u *userPermissionRoleDo) withDO(do gen.Dao) *userPermissionRoleDo {
+	u.DO = *do.(*gen.DO)
+	return u
+}
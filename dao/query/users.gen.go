@@ -40,6 +40,8 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.Space = field.NewString(tableName, "space")
 	_user.ImageQuota = field.NewInt64(tableName, "image_quota")
 	_user.LastEmailVerifiedAt = field.NewTime(tableName, "last_email_verified_at")
+	_user.OIDCIssuer = field.NewString(tableName, "o_id_c_issuer")
+	_user.OIDCSubject = field.NewString(tableName, "o_id_c_subject")
 	_user.Attributes = field.NewField(tableName, "attributes")
 	_user.UserAccounts = userHasManyUserAccounts{
 		db: db.Session(&gorm.Session{}),
@@ -74,6 +76,8 @@ type user struct {
 	Space               field.String // 用户空间绝对路径
 	ImageQuota          field.Int64  // 用户在镜像仓库的配额
 	LastEmailVerifiedAt field.Time   // 最后一次邮箱验证时间
+	OIDCIssuer          field.String // 绑定的 OIDC 身份提供方
+	OIDCSubject         field.String // 绑定的 OIDC 用户标识 (sub)
 	Attributes          field.Field  // 用户的额外属性 (昵称、邮箱、电话、头像等)
 	UserAccounts        userHasManyUserAccounts
 
@@ -106,6 +110,8 @@ func (u *user) updateTableName(table string) *user {
 	u.Space = field.NewString(table, "space")
 	u.ImageQuota = field.NewInt64(table, "image_quota")
 	u.LastEmailVerifiedAt = field.NewTime(table, "last_email_verified_at")
+	u.OIDCIssuer = field.NewString(table, "o_id_c_issuer")
+	u.OIDCSubject = field.NewString(table, "o_id_c_subject")
 	u.Attributes = field.NewField(table, "attributes")
 
 	u.fillFieldMap()
@@ -131,7 +137,7 @@ func (u *user) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *user) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 15)
+	u.fieldMap = make(map[string]field.Expr, 17)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
@@ -144,6 +150,8 @@ func (u *user) fillFieldMap() {
 	u.fieldMap["space"] = u.Space
 	u.fieldMap["image_quota"] = u.ImageQuota
 	u.fieldMap["last_email_verified_at"] = u.LastEmailVerifiedAt
+	u.fieldMap["o_id_c_issuer"] = u.OIDCIssuer
+	u.fieldMap["o_id_c_subject"] = u.OIDCSubject
 	u.fieldMap["attributes"] = u.Attributes
 
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/payload"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
@@ -51,9 +53,13 @@ func (mgr *AccountMgr) RegisterPublic(_ *gin.RouterGroup) {
 func (mgr *AccountMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("", mgr.ListForUser)           // 获取当前用户可访问的账户
 	g.GET(":name", mgr.GetAccountByName) // 获取指定账户
+
+	// 账户管理员管理自己的账户
+	mgr.registerManage(g.Group(AccountManagePath, middleware.AuthAccountAdmin(AccountManageParam)))
 }
 
 func (mgr *AccountMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionAccountsManage))
 	g.GET("", mgr.ListForAdmin)
 	g.POST("", mgr.CreateAccount)
 	g.GET(":aid", mgr.GetAccountByID)
@@ -740,62 +746,19 @@ func (mgr *AccountMgr) AddUserProject(c *gin.Context) {
 		resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
 		return
 	}
-
-	q := query.Account
-	queue, err := q.WithContext(c).Where(q.ID.Eq(req.QueueID)).First()
-	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
+	var reqBody UpdateUserProjectReq
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
 		return
 	}
-	u := query.User
-	user, err := u.WithContext(c).Where(u.ID.Eq(req.UserID)).First()
+
+	user, queue, err := addUserProject(c, req.QueueID, req.UserID, &reqBody)
 	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
-	uq := query.UserAccount
 
-	if _, err := uq.WithContext(c).Where(uq.AccountID.Eq(req.QueueID), uq.UserID.Eq(req.UserID)).Find(); err != nil {
-		resputil.Error(c, fmt.Sprintf("Get UserProject failed, detail: %v", err), resputil.NotSpecified)
-		return
-	} else {
-		var reqBody UpdateUserProjectReq
-		if err = c.ShouldBindJSON(&reqBody); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
-
-		var role, access uint64
-
-		if role, err = strconv.ParseUint(reqBody.Role, 10, 64); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
-
-		if access, err = strconv.ParseUint(reqBody.AccessMode, 10, 64); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
-
-		userQueue := model.UserAccount{
-			UserID:    req.UserID,
-			AccountID: req.QueueID,
-			//nolint:gosec // TODO(wanggz): refactor this
-			Role: model.Role(role),
-			//nolint:gosec // TODO(wanggz): refactor this
-			AccessMode: model.AccessMode(access),
-		}
-		userQueue.Quota = datatypes.NewJSONType(model.QueueQuota{
-			Capability: reqBody.Quota,
-		})
-
-		if err := uq.WithContext(c).Create(&userQueue); err != nil {
-			resputil.Error(c, fmt.Sprintf("create UserProject failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
-
-		resputil.Success(c, fmt.Sprintf("Add User %s for %s", user.Name, queue.Nickname))
-	}
+	resputil.Success(c, fmt.Sprintf("Add User %s for %s", user.Name, queue.Nickname))
 }
 
 // / UpdateUserProject godoc
@@ -819,58 +782,126 @@ func (mgr *AccountMgr) UpdateUserProject(c *gin.Context) {
 		resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
 		return
 	}
+	var reqBody UpdateUserProjectReq
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
 
-	q := query.Account
-	queue, err := q.WithContext(c).Where(q.ID.Eq(req.QueueID)).First()
+	user, queue, err := updateUserProject(c, req.QueueID, req.UserID, &reqBody)
 	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
-	u := query.User
-	user, err := u.WithContext(c).Where(u.ID.Eq(req.UserID)).First()
+
+	resputil.Success(c, fmt.Sprintf("Update User %s for %s", user.Name, queue.Nickname))
+}
+
+// parseUserProjectReq 解析账户成员的角色和访问模式
+func parseUserProjectReq(reqBody *UpdateUserProjectReq) (model.Role, model.AccessMode, error) {
+	role, err := strconv.ParseUint(reqBody.Role, 10, 8)
 	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
-		return
+		return 0, 0, fmt.Errorf("validate UserProject parameters failed, detail: %w", err)
 	}
-	uq := query.UserAccount
-	userQueue, err := uq.WithContext(c).Where(uq.AccountID.Eq(req.QueueID), uq.UserID.Eq(req.UserID)).First()
+	access, err := strconv.ParseUint(reqBody.AccessMode, 10, 8)
 	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get UserProject failed, detail: %v", err), resputil.NotSpecified)
-		return
-	} else {
-		var reqBody UpdateUserProjectReq
-		if err = c.ShouldBindJSON(&reqBody); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
+		return 0, 0, fmt.Errorf("validate UserProject parameters failed, detail: %w", err)
+	}
+	return model.Role(uint8(role)), model.AccessMode(uint8(access)), nil
+}
 
-		var role, access uint64
+// getUserAndAccount 获取账户成员操作涉及的用户和账户
+func getUserAndAccount(c context.Context, accountID, userID uint) (*model.User, *model.Account, error) {
+	q := query.Account
+	queue, err := q.WithContext(c).Where(q.ID.Eq(accountID)).First()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get project failed, detail: %w", err)
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(userID)).First()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user failed, detail: %w", err)
+	}
+	return user, queue, nil
+}
 
-		if role, err = strconv.ParseUint(reqBody.Role, 10, 64); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
+// addUserProject 将用户加入账户，平台管理员和账户管理员共用
+func addUserProject(c context.Context, accountID, userID uint, reqBody *UpdateUserProjectReq) (*model.User, *model.Account, error) {
+	user, queue, err := getUserAndAccount(c, accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	role, access, err := parseUserProjectReq(reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		if access, err = strconv.ParseUint(reqBody.AccessMode, 10, 64); err != nil {
-			resputil.Error(c, fmt.Sprintf("validate UserProject parameters failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
+	uq := query.UserAccount
+	exists, err := uq.WithContext(c).Where(uq.AccountID.Eq(accountID), uq.UserID.Eq(userID)).Count()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get UserProject failed, detail: %w", err)
+	}
+	if exists > 0 {
+		return nil, nil, fmt.Errorf("user %s is already in %s", user.Name, queue.Nickname)
+	}
 
-		//nolint:gosec // TODO(wanggz): refactor this
-		userQueue.Role = model.Role(role)
-		//nolint:gosec // TODO(wanggz): refactor this
-		userQueue.AccessMode = model.AccessMode(access)
+	userQueue := model.UserAccount{
+		UserID:     userID,
+		AccountID:  accountID,
+		Role:       role,
+		AccessMode: access,
+	}
+	userQueue.Quota = datatypes.NewJSONType(model.QueueQuota{
+		Capability: reqBody.Quota,
+	})
+	if err := uq.WithContext(c).Create(&userQueue); err != nil {
+		return nil, nil, fmt.Errorf("create UserProject failed, detail: %w", err)
+	}
+	return user, queue, nil
+}
 
-		userQueue.Quota = datatypes.NewJSONType(model.QueueQuota{
-			Capability: reqBody.Quota,
-		})
-		if _, err := uq.WithContext(c).Where(uq.AccountID.Eq(req.QueueID), uq.UserID.Eq(req.UserID)).Updates(userQueue); err != nil {
-			resputil.Error(c, fmt.Sprintf("update UserProject failed, detail: %v", err), resputil.NotSpecified)
-			return
-		}
+// updateUserProject 更新用户在账户中的角色、访问模式和配额，平台管理员和账户管理员共用
+func updateUserProject(c context.Context, accountID, userID uint, reqBody *UpdateUserProjectReq) (*model.User, *model.Account, error) {
+	user, queue, err := getUserAndAccount(c, accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	role, access, err := parseUserProjectReq(reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		resputil.Success(c, fmt.Sprintf("Update User %s for %s", user.Name, queue.Nickname))
+	uq := query.UserAccount
+	userQueue, err := uq.WithContext(c).Where(uq.AccountID.Eq(accountID), uq.UserID.Eq(userID)).First()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get UserProject failed, detail: %w", err)
+	}
+	userQueue.Role = role
+	userQueue.AccessMode = access
+	userQueue.Quota = datatypes.NewJSONType(model.QueueQuota{
+		Capability: reqBody.Quota,
+	})
+	if _, err := uq.WithContext(c).Where(uq.AccountID.Eq(accountID), uq.UserID.Eq(userID)).Updates(userQueue); err != nil {
+		return nil, nil, fmt.Errorf("update UserProject failed, detail: %w", err)
 	}
+	return user, queue, nil
+}
+
+// deleteUserProject 将用户移出账户，平台管理员和账户管理员共用
+func deleteUserProject(c context.Context, accountID, userID uint) (*model.User, *model.Account, error) {
+	user, queue, err := getUserAndAccount(c, accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	uq := query.UserAccount
+	userQueue, err := uq.WithContext(c).Where(uq.AccountID.Eq(queue.ID), uq.UserID.Eq(user.ID)).First()
+	if err != nil {
+		return nil, nil, fmt.Errorf("delete UserProject failed, detail: %w", err)
+	}
+	if _, err := uq.WithContext(c).Delete(userQueue); err != nil {
+		return nil, nil, fmt.Errorf("delete UserProject failed, detail: %w", err)
+	}
+	return user, queue, nil
 }
 
 type ProjectGetReq struct {
@@ -913,18 +944,26 @@ func (mgr *AccountMgr) GetUserInProject(c *gin.Context) {
 		return
 	}
 
+	resp, err := listUserInProject(c, queue.ID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("Get UserProject failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	resputil.Success(c, resp)
+}
+
+func listUserInProject(c context.Context, accountID uint) ([]UserProjectGetResp, error) {
 	u := query.User
 	uq := query.UserAccount
 
 	var resp []UserProjectGetResp
 	exec := u.WithContext(c).Join(uq, uq.UserID.EqCol(u.ID)).Where(uq.DeletedAt.IsNull())
 	exec = exec.Select(u.ID, u.Name, uq.Role, uq.AccessMode, uq.AccountID, u.Attributes, uq.Quota)
-	if err := exec.Where(uq.AccountID.Eq(queue.ID)).Distinct().Scan(&resp); err != nil {
-		resputil.Error(c, fmt.Sprintf("Get UserProject failed, detail: %v", err), resputil.NotSpecified)
-		return
+	if err := exec.Where(uq.AccountID.Eq(accountID)).Distinct().Scan(&resp); err != nil {
+		return nil, err
 	}
-
-	resputil.Success(c, resp)
+	return resp, nil
 }
 
 type PutUserInProjectUriReq struct {
@@ -1080,26 +1119,9 @@ func (mgr *AccountMgr) DeleteUserProject(c *gin.Context) {
 		return
 	}
 
-	q := query.Account
-	queue, err := q.WithContext(c).Where(q.ID.Eq(req.QueueID)).First()
+	user, queue, err := deleteUserProject(c, req.QueueID, req.UserID)
 	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
-		return
-	}
-	u := query.User
-	user, err := u.WithContext(c).Where(u.ID.Eq(req.UserID)).First()
-	if err != nil {
-		resputil.Error(c, fmt.Sprintf("Get Project failed, detail: %v", err), resputil.NotSpecified)
-		return
-	}
-	uq := query.UserAccount
-	userQueue, err := uq.WithContext(c).Where(uq.AccountID.Eq(queue.ID), uq.UserID.Eq(user.ID)).First()
-	if err != nil {
-		resputil.Error(c, fmt.Sprintf("delete UserProject failed, detail: %v", err), resputil.NotSpecified)
-		return
-	}
-	if _, err := uq.WithContext(c).Delete(userQueue); err != nil {
-		resputil.Error(c, fmt.Sprintf("delete UserProject failed, detail: %v", err), resputil.NotSpecified)
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
 
@@ -0,0 +1,479 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+// AccountManageParam 账户管理接口中账户 ID 所在的路径参数。
+// gin 要求同一位置的路径参数同名，因此沿用 /v1/accounts/:name 的参数名，其值为账户 ID
+const AccountManageParam = "name"
+
+// AccountManagePath 账户管理员接口的路由前缀，对应 /v1/accounts/{aid}/manage
+const AccountManagePath = ":" + AccountManageParam + "/manage"
+
+// registerManage 注册账户管理员可以使用的接口，平台管理员同样可以访问
+func (mgr *AccountMgr) registerManage(g *gin.RouterGroup) {
+	g.GET("members", mgr.ListAccountMembers)
+	g.POST("members/:uid", mgr.AddAccountMember)
+	g.PUT("members/:uid", mgr.UpdateAccountMember)
+	g.DELETE("members/:uid", mgr.RemoveAccountMember)
+
+	g.GET("approvalorders", mgr.ListAccountApprovalOrders)
+	g.PUT("approvalorders/:id", mgr.ReviewAccountApprovalOrder)
+
+	g.GET("datasets", mgr.ListAccountDatasets)
+	g.POST("datasets/:did", mgr.ShareDatasetToAccount)
+	g.DELETE("datasets/:did", mgr.CancelShareDatasetToAccount)
+}
+
+type (
+	AccountMemberReq struct {
+		UserID uint `uri:"uid" binding:"required"`
+	}
+
+	AccountDatasetReq struct {
+		DatasetID uint `uri:"did" binding:"required"`
+	}
+
+	ReviewAccountApprovalOrderReq struct {
+		Status      model.ApprovalOrderStatus `json:"status" binding:"required"`
+		ReviewNotes string                    `json:"reviewNotes"`
+	}
+)
+
+// validateMemberUpdate 账户管理员只能设置普通用户或管理员角色，成员配额不能超过账户本身的配额，
+// 也不能修改自己在账户中的成员关系，避免管理员误操作后失去管理权限
+func validateMemberUpdate(c *gin.Context, accountID, userID uint, reqBody *UpdateUserProjectReq) error {
+	token := util.GetToken(c)
+	if userID == token.UserID && token.RolePlatform != model.RoleAdmin {
+		return fmt.Errorf("can not change your own membership")
+	}
+
+	role, access, err := parseUserProjectReq(reqBody)
+	if err != nil {
+		return err
+	}
+	if role != model.RoleUser && role != model.RoleAdmin {
+		return fmt.Errorf("invalid role %d", role)
+	}
+	if access != model.AccessModeNA && access != model.AccessModeRO &&
+		access != model.AccessModeRW && access != model.AccessModeAO {
+		return fmt.Errorf("invalid access mode %d", access)
+	}
+	if err := checkResource(c, reqBody.Quota); err != nil {
+		return err
+	}
+
+	q := query.Account
+	account, err := q.WithContext(c).Where(q.ID.Eq(accountID)).First()
+	if err != nil {
+		return err
+	}
+	return checkQuotaWithinAccount(reqBody.Quota, account.Quota.Data().Capability)
+}
+
+// checkQuotaWithinAccount 检查成员配额中的每一项资源都不超过账户配额，账户未限制的资源不做检查
+func checkQuotaWithinAccount(quota, capability v1.ResourceList) error {
+	for name, value := range quota {
+		limit, ok := capability[name]
+		if ok && value.Cmp(limit) > 0 {
+			return fmt.Errorf("quota of %s (%s) exceeds the account quota (%s)", name, value.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+// ListAccountMembers godoc
+//
+//	@Summary		账户管理员获取账户成员
+//	@Description	获取账户中的所有成员及其角色、访问模式和配额
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint										true	"account id"
+//	@Success		200	{object}	resputil.Response[[]UserProjectGetResp]	"账户成员"
+//	@Failure		403	{object}	resputil.Response[any]						"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]						"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/members [get]
+func (mgr *AccountMgr) ListAccountMembers(c *gin.Context) {
+	resp, err := listUserInProject(c, util.GetManagedAccountID(c))
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list account members failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, resp)
+}
+
+// AddAccountMember godoc
+//
+//	@Summary		账户管理员添加成员
+//	@Description	将用户加入账户，成员配额不能超过账户配额
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint						true	"account id"
+//	@Param			uid	path		uint						true	"user id"
+//	@Param			req	body		UpdateUserProjectReq		true	"角色、访问模式和配额"
+//	@Success		200	{object}	resputil.Response[string]	"添加成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403	{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/members/{uid} [post]
+func (mgr *AccountMgr) AddAccountMember(c *gin.Context) {
+	var uri AccountMemberReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var reqBody UpdateUserProjectReq
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	accountID := util.GetManagedAccountID(c)
+	if err := validateMemberUpdate(c, accountID, uri.UserID, &reqBody); err != nil {
+		resputil.Error(c, err.Error(), resputil.InvalidRequest)
+		return
+	}
+	user, queue, err := addUserProject(c, accountID, uri.UserID, &reqBody)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, fmt.Sprintf("Add User %s for %s", user.Name, queue.Nickname))
+}
+
+// UpdateAccountMember godoc
+//
+//	@Summary		账户管理员更新成员
+//	@Description	更新成员在账户中的角色、访问模式和配额，成员配额不能超过账户配额
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint						true	"account id"
+//	@Param			uid	path		uint						true	"user id"
+//	@Param			req	body		UpdateUserProjectReq		true	"角色、访问模式和配额"
+//	@Success		200	{object}	resputil.Response[string]	"更新成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403	{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/members/{uid} [put]
+func (mgr *AccountMgr) UpdateAccountMember(c *gin.Context) {
+	var uri AccountMemberReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var reqBody UpdateUserProjectReq
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	accountID := util.GetManagedAccountID(c)
+	if err := validateMemberUpdate(c, accountID, uri.UserID, &reqBody); err != nil {
+		resputil.Error(c, err.Error(), resputil.InvalidRequest)
+		return
+	}
+	user, queue, err := updateUserProject(c, accountID, uri.UserID, &reqBody)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, fmt.Sprintf("Update User %s for %s", user.Name, queue.Nickname))
+}
+
+// RemoveAccountMember godoc
+//
+//	@Summary		账户管理员移除成员
+//	@Description	将用户移出账户，不能移除自己
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint						true	"account id"
+//	@Param			uid	path		uint						true	"user id"
+//	@Success		200	{object}	resputil.Response[string]	"移除成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403	{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/members/{uid} [delete]
+func (mgr *AccountMgr) RemoveAccountMember(c *gin.Context) {
+	var uri AccountMemberReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	if uri.UserID == token.UserID && token.RolePlatform != model.RoleAdmin {
+		resputil.Error(c, "can not remove yourself from the account", resputil.InvalidRequest)
+		return
+	}
+
+	user, queue, err := deleteUserProject(c, util.GetManagedAccountID(c), uri.UserID)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, fmt.Sprintf("delete User %s for %s", user.Name, queue.Nickname))
+}
+
+// accountJobNames 返回账户中所有作业的名称，用于筛选与账户相关的作业锁定工单
+func accountJobNames(c *gin.Context, accountID uint) ([]string, error) {
+	j := query.Job
+	var names []string
+	if err := j.WithContext(c).Where(j.AccountID.Eq(accountID)).Pluck(j.JobName, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListAccountApprovalOrders godoc
+//
+//	@Summary		账户管理员获取作业锁定工单
+//	@Description	获取账户内作业的锁定延期工单
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint									true	"account id"
+//	@Success		200	{object}	resputil.Response[[]ApprovalOrderResp]	"工单列表"
+//	@Failure		403	{object}	resputil.Response[any]					"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]					"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/approvalorders [get]
+func (mgr *AccountMgr) ListAccountApprovalOrders(c *gin.Context) {
+	jobNames, err := accountJobNames(c, util.GetManagedAccountID(c))
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list account jobs failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if len(jobNames) == 0 {
+		resputil.Success(c, []ApprovalOrderResp{})
+		return
+	}
+
+	ao := query.ApprovalOrder
+	orders, err := ao.WithContext(c).
+		Preload(ao.Creator).
+		Preload(ao.Reviewer).
+		Where(ao.Type.Eq(string(model.ApprovalOrderTypeJob)), ao.Name.In(jobNames...)).
+		Order(ao.CreatedAt.Desc()).
+		Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list approval orders failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, convertToApprovalOrderResps(orders))
+}
+
+// ReviewAccountApprovalOrder godoc
+//
+//	@Summary		账户管理员审批作业锁定工单
+//	@Description	批准或拒绝账户内作业的锁定延期工单，批准时按工单中的延长时间锁定作业
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid		path		uint							true	"account id"
+//	@Param			id		path		uint							true	"approval order id"
+//	@Param			body	body		ReviewAccountApprovalOrderReq	true	"审批结果"
+//	@Success		200		{object}	resputil.Response[string]		"审批成功"
+//	@Failure		400		{object}	resputil.Response[any]			"请求参数错误"
+//	@Failure		403		{object}	resputil.Response[any]			"不是账户管理员"
+//	@Failure		500		{object}	resputil.Response[any]			"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/approvalorders/{id} [put]
+func (mgr *AccountMgr) ReviewAccountApprovalOrder(c *gin.Context) {
+	var uri ApprovalOrderIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var req ReviewAccountApprovalOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.Status != model.ApprovalOrderStatusApproved && req.Status != model.ApprovalOrderStatusRejected {
+		resputil.BadRequestError(c, fmt.Sprintf("invalid status %s", req.Status))
+		return
+	}
+
+	ao := query.ApprovalOrder
+	order, err := ao.WithContext(c).Where(ao.ID.Eq(uri.ID), ao.Type.Eq(string(model.ApprovalOrderTypeJob))).First()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("approval order %d not found", uri.ID), resputil.InvalidRequest)
+		return
+	}
+	if order.Status != model.ApprovalOrderStatusPending {
+		resputil.Error(c, fmt.Sprintf("approval order %d is already %s", uri.ID, order.Status), resputil.InvalidRequest)
+		return
+	}
+
+	// 工单对应的作业必须属于被管理的账户
+	j := query.Job
+	if _, err := j.WithContext(c).Where(j.JobName.Eq(order.Name), j.AccountID.Eq(util.GetManagedAccountID(c))).First(); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Error(c, "the job of this approval order is not in the account", resputil.UserNotAllowed)
+			return
+		}
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	if req.Status == model.ApprovalOrderStatusApproved {
+		if err := lockJobForApproval(c, order.Name, order.Content.Data().ApprovalOrderExtensionHours); err != nil {
+			resputil.Error(c, fmt.Sprintf("lock job failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+	}
+
+	token := util.GetToken(c)
+	if _, err := ao.WithContext(c).Where(ao.ID.Eq(order.ID)).Updates(model.ApprovalOrder{
+		Status:      req.Status,
+		ReviewerID:  token.UserID,
+		ReviewNotes: req.ReviewNotes,
+	}); err != nil {
+		resputil.Error(c, fmt.Sprintf("update approval order failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	if err := alert.GetAlertMgr().ApprovalOrderAlert(c, order.ID); err != nil {
+		klog.Errorf("failed to notify approval order result, orderID: %d, err: %v", order.ID, err)
+	}
+	resputil.Success(c, "review approvalorder successfully")
+}
+
+// ListAccountDatasets godoc
+//
+//	@Summary		账户管理员获取账户共享的数据集
+//	@Description	获取共享给账户的数据集
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint								true	"account id"
+//	@Success		200	{object}	resputil.Response[[]DatasetResp]	"数据集列表"
+//	@Failure		403	{object}	resputil.Response[any]				"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]				"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/datasets [get]
+func (mgr *AccountMgr) ListAccountDatasets(c *gin.Context) {
+	ad := query.AccountDataset
+	var datasetIDs []uint
+	if err := ad.WithContext(c).Where(ad.AccountID.Eq(util.GetManagedAccountID(c))).Pluck(ad.DatasetID, &datasetIDs); err != nil {
+		resputil.Error(c, fmt.Sprintf("list account datasets failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	resp := make([]DatasetResp, 0, len(datasetIDs))
+	if len(datasetIDs) > 0 {
+		d := query.Dataset
+		datasets, err := d.WithContext(c).Preload(d.User).Where(d.ID.In(datasetIDs...)).Find()
+		if err != nil {
+			resputil.Error(c, fmt.Sprintf("list account datasets failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+		for _, dataset := range datasets {
+			resp = append(resp, convertDataset(dataset))
+		}
+	}
+	resputil.Success(c, resp)
+}
+
+// canShareDataset 账户管理员可以将自己创建或共享给自己的数据集共享到账户，平台管理员不受限制
+func canShareDataset(c *gin.Context, datasetID uint) (bool, error) {
+	token := util.GetToken(c)
+	d := query.Dataset
+	dataset, err := d.WithContext(c).Where(d.ID.Eq(datasetID)).First()
+	if err != nil {
+		return false, err
+	}
+	if token.RolePlatform == model.RoleAdmin || dataset.UserID == token.UserID {
+		return true, nil
+	}
+	ud := query.UserDataset
+	count, err := ud.WithContext(c).Where(ud.UserID.Eq(token.UserID), ud.DatasetID.Eq(datasetID)).Count()
+	return count > 0, err
+}
+
+// ShareDatasetToAccount godoc
+//
+//	@Summary		账户管理员向账户共享数据集
+//	@Description	将自己创建或共享给自己的数据集共享给账户内的所有成员
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint						true	"account id"
+//	@Param			did	path		uint						true	"dataset id"
+//	@Success		200	{object}	resputil.Response[string]	"共享成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403	{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/datasets/{did} [post]
+func (mgr *AccountMgr) ShareDatasetToAccount(c *gin.Context) {
+	var uri AccountDatasetReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	allowed, err := canShareDataset(c, uri.DatasetID)
+	if err != nil || !allowed {
+		resputil.Error(c, "you has no permission or this dataset not exist", resputil.InvalidRequest)
+		return
+	}
+	if err := shareWithQueue(c, SharedQueueReq{
+		DatasetID: uri.DatasetID,
+		QueueIDs:  []uint{util.GetManagedAccountID(c)},
+	}); err != nil {
+		resputil.Error(c, err.Error(), resputil.InvalidRequest)
+		return
+	}
+	resputil.Success(c, "Shared successfully")
+}
+
+// CancelShareDatasetToAccount godoc
+//
+//	@Summary		账户管理员取消数据集共享
+//	@Description	取消数据集对账户的共享
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint						true	"account id"
+//	@Param			did	path		uint						true	"dataset id"
+//	@Success		200	{object}	resputil.Response[string]	"取消成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403	{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/datasets/{did} [delete]
+func (mgr *AccountMgr) CancelShareDatasetToAccount(c *gin.Context) {
+	var uri AccountDatasetReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := cancelShareWithQueue(c, cancelSharedQueueReq{
+		DatasetID: uri.DatasetID,
+		QueueID:   util.GetManagedAccountID(c),
+	}); err != nil {
+		resputil.Error(c, err.Error(), resputil.InvalidRequest)
+		return
+	}
+	resputil.Success(c, "cancel successfully")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/handler"
 	"github.com/raids-lab/crater/internal/handler/vcjob"
+	"github.com/raids-lab/crater/internal/middleware"
 	interpayload "github.com/raids-lab/crater/internal/payload"
 	"github.com/raids-lab/crater/internal/resputil"
 	interutil "github.com/raids-lab/crater/internal/util"
@@ -81,6 +82,7 @@ func (mgr *AIJobMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *AIJobMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionJobsReadAny))
 	g.GET("", mgr.ListUserJob)
 	g.GET(":id/detail", mgr.GetDetail)
 }
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewAPITokenMgr)
+}
+
+// maxAPITokensPerUser 每个用户最多持有的个人访问令牌数量
+const maxAPITokensPerUser = 20
+
+type APITokenMgr struct {
+	name string
+}
+
+func NewAPITokenMgr(_ *RegisterConfig) Manager {
+	return &APITokenMgr{
+		name: "tokens",
+	}
+}
+
+func (mgr *APITokenMgr) GetName() string { return mgr.name }
+
+func (mgr *APITokenMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *APITokenMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListAPITokens)
+	g.POST("", mgr.CreateAPIToken)
+	g.DELETE("/:id", mgr.RevokeAPIToken)
+}
+
+func (mgr *APITokenMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	CreateAPITokenReq struct {
+		Name      string              `json:"name" binding:"required"`
+		Scope     model.APITokenScope `json:"scope" binding:"required"` // [read-only, submit, admin]
+		ExpiresAt *time.Time          `json:"expiresAt"`                // 为空表示永不过期
+	}
+
+	CreateAPITokenResp struct {
+		Token    string          `json:"token"` // 令牌明文，只在创建时返回一次
+		APIToken *model.APIToken `json:"apiToken"`
+	}
+
+	APITokenIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+)
+
+// ListAPITokens godoc
+//
+//	@Summary		List personal access tokens
+//	@Description	List personal access tokens of the current user, including tokens bound to other accounts
+//	@Tags			APIToken
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.APIToken]	"Personal access tokens"
+//	@Failure		500	{object}	resputil.Response[any]				"Other errors"
+//	@Router			/v1/tokens [get]
+func (mgr *APITokenMgr) ListAPITokens(c *gin.Context) {
+	token := util.GetToken(c)
+	t := query.APIToken
+	tokens, err := t.WithContext(c).Where(t.UserID.Eq(token.UserID)).Order(t.ID.Desc()).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list api tokens failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, tokens)
+}
+
+// CreateAPIToken godoc
+//
+//	@Summary		Create personal access token
+//	@Description	Create a personal access token bound to the current account, the token is only returned once
+//	@Tags			APIToken
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		CreateAPITokenReq						true	"token"
+//	@Success		200		{object}	resputil.Response[CreateAPITokenResp]	"Created token"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/tokens [post]
+func (mgr *APITokenMgr) CreateAPIToken(c *gin.Context) {
+	var req CreateAPITokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	switch req.Scope {
+	case model.APITokenScopeReadOnly, model.APITokenScopeSubmit, model.APITokenScopeAdmin:
+	default:
+		resputil.BadRequestError(c, fmt.Sprintf("invalid scope %s", req.Scope))
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		resputil.BadRequestError(c, "expiresAt must be in the future")
+		return
+	}
+
+	// 个人访问令牌不能用于创建新的令牌
+	if _, ok := util.GetAPITokenID(c); ok {
+		resputil.Error(c, "can not create api token with an api token", resputil.UserNotAllowed)
+		return
+	}
+
+	token := util.GetToken(c)
+	t := query.APIToken
+	count, err := t.WithContext(c).Where(t.UserID.Eq(token.UserID)).Count()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("count api tokens failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if count >= maxAPITokensPerUser {
+		resputil.Error(c, fmt.Sprintf("a user can have at most %d api tokens", maxAPITokensPerUser), resputil.UserNotAllowed)
+		return
+	}
+
+	raw, prefix, hash, err := util.GenerateAPIToken()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("generate api token failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	apiToken := &model.APIToken{
+		UserID:    token.UserID,
+		AccountID: token.AccountID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		TokenHash: hash,
+		Scope:     req.Scope,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := t.WithContext(c).Create(apiToken); err != nil {
+		resputil.Error(c, fmt.Sprintf("create api token failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, CreateAPITokenResp{
+		Token:    raw,
+		APIToken: apiToken,
+	})
+}
+
+// RevokeAPIToken godoc
+//
+//	@Summary		Revoke personal access token
+//	@Description	Revoke a personal access token of the current user, requests using it are rejected immediately
+//	@Tags			APIToken
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"token id"
+//	@Success		200	{object}	resputil.Response[string]	"Revoked"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/tokens/{id} [delete]
+func (mgr *APITokenMgr) RevokeAPIToken(c *gin.Context) {
+	var uri APITokenIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	t := query.APIToken
+	if _, err := t.WithContext(c).Where(t.ID.Eq(uri.ID), t.UserID.Eq(token.UserID)).First(); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Error(c, "api token not found", resputil.UserNotAllowed)
+			return
+		}
+		resputil.Error(c, fmt.Sprintf("get api token failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if _, err := t.WithContext(c).Where(t.ID.Eq(uri.ID)).Delete(); err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke api token failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/utils"
 )
 
@@ -47,6 +50,7 @@ func (mgr *ApprovalOrderMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *ApprovalOrderMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionApprovalsManage))
 	// 管理员接口
 	g.GET("", mgr.ListAllApprovalOrders)                // 获取所有审批工单
 	g.GET("/:id", mgr.GetApprovalOrderAdmin)            // 管理员通过ID获取审批工单详情
@@ -357,7 +361,7 @@ func (mgr *ApprovalOrderMgr) CreateApprovalOrder(c *gin.Context) {
 
 	if canAutoApprove {
 		// 尝试锁定作业
-		if err := mgr.lockJobForApproval(c, req.Name, req.ExtensionHours); err != nil {
+		if err := lockJobForApproval(c, req.Name, req.ExtensionHours); err != nil {
 			klog.Errorf("failed to lock job for auto approval, jobName: %s, err: %v", req.Name, err)
 			// 锁定失败时不进行自动审批，但继续创建工单
 		} else {
@@ -477,6 +481,13 @@ func (mgr *ApprovalOrderMgr) UpdateApprovalOrder(c *gin.Context) {
 		return
 	}
 	klog.Infof("updated approval order successfully, affected rows: %d", info.RowsAffected)
+
+	// 3. 通知创建者审批结果
+	if req.Status == model.ApprovalOrderStatusApproved || req.Status == model.ApprovalOrderStatusRejected {
+		if err := alert.GetAlertMgr().ApprovalOrderAlert(c, orderID.ID); err != nil {
+			klog.Errorf("failed to notify approval order result, orderID: %d, err: %v", orderID.ID, err)
+		}
+	}
 	resputil.Success(c, "update approvalorder successfully")
 }
 
@@ -776,7 +787,7 @@ func (mgr *ApprovalOrderMgr) checkAutoApprovalEligibility(c *gin.Context, userID
 }
 
 // lockJobForApproval 为审批工单锁定作业
-func (mgr *ApprovalOrderMgr) lockJobForApproval(c *gin.Context, jobName string, extensionHours uint) error {
+func lockJobForApproval(c context.Context, jobName string, extensionHours uint) error {
 	jobDB := query.Job
 
 	// 查找作业
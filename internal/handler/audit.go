@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewAuditMgr)
+}
+
+const (
+	// defaultAuditLogPageSize 默认每页审计日志数
+	defaultAuditLogPageSize = 50
+	// maxAuditLogExportRows 单次导出的最大审计日志条数
+	maxAuditLogExportRows = 100000
+	// auditLogExportBatchSize 导出时每批查询的条数
+	auditLogExportBatchSize = 1000
+)
+
+type AuditMgr struct {
+	name string
+}
+
+func NewAuditMgr(_ *RegisterConfig) Manager {
+	return &AuditMgr{
+		name: "audit",
+	}
+}
+
+func (mgr *AuditMgr) GetName() string { return mgr.name }
+
+func (mgr *AuditMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *AuditMgr) RegisterProtected(_ *gin.RouterGroup) {}
+
+func (mgr *AuditMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionAuditRead))
+	g.GET("logs", mgr.ListAuditLogs)
+	g.GET("logs/export", mgr.ExportAuditLogs)
+}
+
+type (
+	AuditLogFilter struct {
+		Username    *string    `form:"username"`    // 按操作者过滤
+		AccountName *string    `form:"accountName"` // 按操作时所在的账户过滤
+		Method      *string    `form:"method"`      // 按请求方法过滤
+		Route       *string    `form:"route"`       // 按路由模板过滤，支持前缀匹配
+		Failed      *bool      `form:"failed"`      // true 只返回失败的操作，false 只返回成功的操作
+		From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	ListAuditLogsReq struct {
+		AuditLogFilter
+		Page     int `form:"page"`     // 页码，从 0 开始
+		PageSize int `form:"pageSize"` // 每页大小
+	}
+
+	ListAuditLogsResp struct {
+		Logs  []*model.AuditLog `json:"logs"`
+		Total int64             `json:"total"`
+	}
+)
+
+func (f *AuditLogFilter) conditions() []gen.Condition {
+	a := query.AuditLog
+	conds := []gen.Condition{}
+	if f.Username != nil {
+		conds = append(conds, a.Username.Eq(*f.Username))
+	}
+	if f.AccountName != nil {
+		conds = append(conds, a.AccountName.Eq(*f.AccountName))
+	}
+	if f.Method != nil {
+		conds = append(conds, a.Method.Eq(*f.Method))
+	}
+	if f.Route != nil {
+		conds = append(conds, a.Route.Like(*f.Route+"%"))
+	}
+	if f.Failed != nil {
+		if *f.Failed {
+			conds = append(conds, field.Or(a.ResultCode.Neq(int(resputil.OK)), a.StatusCode.Gte(400)))
+		} else {
+			conds = append(conds, a.ResultCode.Eq(int(resputil.OK)), a.StatusCode.Lt(400))
+		}
+	}
+	if f.From != nil {
+		conds = append(conds, a.CreatedAt.Gte(*f.From))
+	}
+	if f.To != nil {
+		conds = append(conds, a.CreatedAt.Lt(*f.To))
+	}
+	return conds
+}
+
+// ListAuditLogs godoc
+//
+//	@Summary		查询审计日志
+//	@Description	按操作者、账户、请求方法、路由、结果和时间范围查询修改类 API 操作的审计日志
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			username	query		string								false	"操作者"
+//	@Param			accountName	query		string								false	"账户名"
+//	@Param			method		query		string								false	"请求方法"
+//	@Param			route		query		string								false	"路由模板前缀"
+//	@Param			failed		query		bool								false	"是否只返回失败的操作"
+//	@Param			from		query		string								false	"开始时间 (RFC3339)"
+//	@Param			to			query		string								false	"结束时间 (RFC3339)"
+//	@Param			page		query		int									false	"页码，从 0 开始"
+//	@Param			pageSize	query		int									false	"每页大小"
+//	@Success		200			{object}	resputil.Response[ListAuditLogsResp]	"审计日志"
+//	@Failure		400			{object}	resputil.Response[any]				"请求参数错误"
+//	@Failure		500			{object}	resputil.Response[any]				"其他错误"
+//	@Router			/v1/admin/audit/logs [get]
+func (mgr *AuditMgr) ListAuditLogs(c *gin.Context) {
+	var req ListAuditLogsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultAuditLogPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+
+	a := query.AuditLog
+	logs, total, err := a.WithContext(c).Where(req.conditions()...).Order(a.ID.Desc()).
+		FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list audit logs failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, ListAuditLogsResp{Logs: logs, Total: total})
+}
+
+// ExportAuditLogs godoc
+//
+//	@Summary		导出审计日志
+//	@Description	以 CSV 格式导出符合条件的审计日志，过滤条件与查询接口相同
+//	@Tags			Audit
+//	@Produce		text/csv
+//	@Security		Bearer
+//	@Param			username	query		string					false	"操作者"
+//	@Param			accountName	query		string					false	"账户名"
+//	@Param			method		query		string					false	"请求方法"
+//	@Param			route		query		string					false	"路由模板前缀"
+//	@Param			failed		query		bool					false	"是否只返回失败的操作"
+//	@Param			from		query		string					false	"开始时间 (RFC3339)"
+//	@Param			to			query		string					false	"结束时间 (RFC3339)"
+//	@Success		200			{file}		file					"CSV 文件"
+//	@Failure		400			{object}	resputil.Response[any]	"请求参数错误"
+//	@Router			/v1/admin/audit/logs/export [get]
+func (mgr *AuditMgr) ExportAuditLogs(c *gin.Context) {
+	var req AuditLogFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"time", "username", "account", "api_token_id", "client_ip", "method", "route", "path",
+		"params", "body", "status_code", "result_code", "message", "latency_ms",
+	})
+
+	// 按 ID 倒序分批读取，避免一次加载全部日志
+	a := query.AuditLog
+	conds := req.conditions()
+	var lastID uint
+	for exported := 0; exported < maxAuditLogExportRows; {
+		batchConds := conds
+		if lastID != 0 {
+			batchConds = append(batchConds[:len(batchConds):len(batchConds)], a.ID.Lt(lastID))
+		}
+		logs, err := a.WithContext(c).Where(batchConds...).Order(a.ID.Desc()).Limit(auditLogExportBatchSize).Find()
+		if err != nil {
+			// 响应头已发出，只能在文件末尾标记导出失败
+			_ = w.Write([]string{"# export failed: " + err.Error()})
+			break
+		}
+		for _, log := range logs {
+			params, _ := log.Params.MarshalJSON()
+			_ = w.Write([]string{
+				log.CreatedAt.Format(time.RFC3339), log.Username, log.AccountName,
+				strconv.FormatUint(uint64(log.APITokenID), 10), log.ClientIP, log.Method, log.Route, log.Path,
+				string(params), log.Body, strconv.Itoa(log.StatusCode), strconv.Itoa(log.ResultCode),
+				log.Message, strconv.FormatInt(log.LatencyMs, 10),
+			})
+		}
+		w.Flush()
+		if len(logs) < auditLogExportBatchSize {
+			break
+		}
+		exported += len(logs)
+		lastID = logs[len(logs)-1].ID
+	}
+	w.Flush()
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -24,9 +25,11 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/oidc"
 )
 
 //nolint:gochecknoinits // This is the standard way to register a gin handler.
@@ -40,6 +43,9 @@ type AuthMgr struct {
 	req      *imrocreq.Client
 	openAPI  config.RaidsLabOpenAPI
 	tokenMgr *util.TokenManager
+
+	oidcMu       sync.Mutex
+	oidcProvider *oidc.Provider
 }
 
 func NewAuthMgr(_ *RegisterConfig) Manager {
@@ -60,20 +66,39 @@ func (mgr *AuthMgr) RegisterPublic(g *gin.RouterGroup) {
 	g.POST("signup", mgr.Signup)
 	g.POST("refresh", mgr.RefreshToken)
 	g.GET("mode", mgr.GetAuthMode)
+	g.GET("oidc/authorize", mgr.OIDCAuthorize)
+	passwordRateLimit := middleware.IPRateLimit("auth/password", passwordRateLimitInterval, passwordRateLimitBurst)
+	g.POST("password/forgot", passwordRateLimit, mgr.ForgotPassword)
+	g.POST("password/reset", passwordRateLimit, mgr.ResetPassword)
+	g.POST("mfa/verify", mgr.VerifyMFALogin)
+	g.POST("mfa/setup", mgr.SetupMFALogin)
+	g.POST("mfa/setup/confirm", mgr.ConfirmMFALoginSetup)
 }
 
 func (mgr *AuthMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.POST("switch", mgr.SwitchQueue) // 切换项目 /switch
+	g.POST("logout", mgr.Logout)
+	g.GET("sessions", mgr.ListSessions)
+	g.DELETE("sessions/:sid", mgr.RevokeSession)
+	g.POST("sessions/revoke-all", mgr.RevokeAllSessions)
+	g.GET("mfa", mgr.GetMFAStatus)
+	g.POST("mfa/enroll", mgr.EnrollMFA)
+	g.POST("mfa/enable", mgr.EnableMFA)
+	g.POST("mfa/disable", mgr.DisableMFA)
+	g.POST("mfa/recovery-codes", mgr.RegenerateRecoveryCodes)
+	g.POST("oidc/link", mgr.LinkOIDC)
 }
 
 func (mgr *AuthMgr) RegisterAdmin(_ *gin.RouterGroup) {}
 
 type (
 	LoginReq struct {
-		AuthMethod AuthMethod `json:"auth" binding:"required"` // [normal, act-ldap, act-api]
+		AuthMethod AuthMethod `json:"auth" binding:"required"` // [normal, act-ldap, act-api, oidc]
 		Username   *string    `json:"username"`                // (act-ldap, normal)
 		Password   *string    `json:"password"`                // (act-ldap, normal)
 		Token      *string    `json:"token"`                   // (act-api)
+		Code       *string    `json:"code"`                    // (oidc)
+		State      *string    `json:"state"`                   // (oidc)
 	}
 
 	LoginResp struct {
@@ -81,6 +106,11 @@ type (
 		RefreshToken string              `json:"refreshToken"`
 		Context      AccountContext      `json:"context"`
 		User         model.UserAttribute `json:"user"`
+		// 以下字段只在需要两步验证时返回，此时不返回令牌，前端使用 MFAToken 完成第二步
+		MFARequired      bool     `json:"mfaRequired,omitempty"`      // 已启用两步验证，需要输入验证码
+		MFASetupRequired bool     `json:"mfaSetupRequired,omitempty"` // 管理员必须先绑定验证器
+		MFAToken         string   `json:"mfaToken,omitempty"`         // 两步验证的挑战令牌，5 分钟内有效
+		RecoveryCodes    []string `json:"recoveryCodes,omitempty"`    // 登录时完成绑定返回的恢复码，只展示一次
 	}
 
 	CheckResp struct {
@@ -137,6 +167,7 @@ const (
 	AuthMethodNormal  AuthMethod = "normal"
 	AuthMethodACTLDAP AuthMethod = "act-ldap"
 	AuthMethodACTAPI  AuthMethod = "act-api"
+	AuthMethodOIDC    AuthMethod = "oidc"
 )
 
 // GetAuthMode godoc
@@ -187,6 +218,10 @@ func (mgr *AuthMgr) Check(c *gin.Context) {
 		resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenExpired)
 		return
 	}
+	if err = middleware.ValidateSession(c, &jwtMessage); err != nil {
+		resputil.Success(c, nil)
+		return
+	}
 
 	// 从数据库获取用户信息
 	u := query.User
@@ -246,7 +281,8 @@ func (mgr *AuthMgr) Check(c *gin.Context) {
 // Login godoc
 //
 //	@Summary		用户登录
-//	@Description	校验用户身份，生成包含当前用户和项目的 JWT Token
+//	@Description	校验用户身份，生成包含当前用户和项目的 JWT Token。
+//	@Description	需要两步验证时不返回 Token，而是返回 mfaToken，前端再调用 /auth/mfa/verify 或 /auth/mfa/setup 完成登录
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
@@ -273,6 +309,11 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 			return
 		}
 		token = *req.Token
+	case AuthMethodOIDC:
+		if req.Code == nil || req.State == nil {
+			resputil.BadRequestError(c, "Code or state not provided")
+			return
+		}
 	case AuthMethodACTLDAP, AuthMethodNormal:
 		if req.Username == nil || req.Password == nil {
 			resputil.BadRequestError(c, "Username or password not provided")
@@ -293,6 +334,7 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 
 	// Check if request auth method is valid
 	var attributes model.UserAttribute
+	var oidcInfo *oidc.UserInfo
 	allowRegister := false
 	switch req.AuthMethod {
 	case AuthMethodACTAPI:
@@ -301,6 +343,14 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 			return
 		}
 		allowRegister = true
+	case AuthMethodOIDC:
+		info, err := mgr.oidcAuth(c, *req.Code, *req.State, &attributes)
+		if err != nil {
+			klog.Errorf("oidc login failed: %v", err)
+			resputil.HTTPError(c, http.StatusUnauthorized, "Invalid OIDC login", resputil.InvalidCredentials)
+			return
+		}
+		oidcInfo = info
 	case AuthMethodACTLDAP:
 		if err := mgr.actLDAPAuth(c, username, password); err != nil {
 			resputil.HTTPError(c, http.StatusUnauthorized, "Invalid credentials", resputil.InvalidCredentials)
@@ -318,9 +368,18 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 	}
 
 	// Check if the user exists, and should create user or return error
-	user, err := mgr.getOrCreateUser(c, &req, &attributes, allowRegister)
+	var user *model.User
+	var err error
+	if oidcInfo != nil {
+		user, err = mgr.getOrCreateOIDCUser(c, oidcInfo, &attributes)
+	} else {
+		user, err = mgr.getOrCreateUser(c, &req, &attributes, allowRegister)
+	}
 	if err != nil {
-		if errors.Is(err, ErrorMustRegister) {
+		if errors.Is(err, ErrorOIDCNotLinked) {
+			resputil.Error(c, "User exists, login with the original method and link the OIDC identity first", resputil.OIDCNotLinked)
+			return
+		} else if errors.Is(err, ErrorMustRegister) {
 			resputil.Error(c, "User must register before login", resputil.MustRegister)
 			return
 		} else if errors.Is(err, ErrorUIDServerConnect) {
@@ -345,19 +404,39 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 		return
 	}
 
+	if oidcInfo != nil && len(oidcInfo.Groups) > 0 {
+		if err = syncOIDCGroupAccounts(c, user.ID, oidcInfo.Groups); err != nil {
+			klog.Errorf("failed to sync oidc group accounts of user %s: %v", user.Name, err)
+		}
+	}
+
+	// 启用了两步验证的用户需要再提交验证码，此时不签发令牌
+	if mgr.requireMFA(c, user) {
+		return
+	}
+
+	loginResponse, ok := mgr.issueLoginTokens(c, user)
+	if !ok {
+		return
+	}
+	resputil.Success(c, loginResponse)
+}
+
+// issueLoginTokens 创建登录会话，签发访问令牌和刷新令牌。出错时已写入响应
+func (mgr *AuthMgr) issueLoginTokens(c *gin.Context, user *model.User) (*LoginResp, bool) {
 	q := query.Account
 	uq := query.UserAccount
 
 	lastUserQueue, err := uq.WithContext(c).Where(uq.UserID.Eq(user.ID)).Last()
 	if err != nil {
 		resputil.Error(c, "User must has at least one queue", resputil.UserNotAllowed)
-		return
+		return nil, false
 	}
 
 	lastQueue, err := q.WithContext(c).Where(q.ID.Eq(lastUserQueue.AccountID)).First()
 	if err != nil {
 		resputil.Error(c, "User must has at least one queue", resputil.UserNotAllowed)
-		return
+		return nil, false
 	}
 
 	publicAccessMode := model.AccessModeNA
@@ -377,12 +456,16 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 		PublicAccessMode:  publicAccessMode,
 		RolePlatform:      user.Role,
 	}
+	if err = mgr.createSession(c, &jwtMessage); err != nil {
+		resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+		return nil, false
+	}
 	accessToken, refreshToken, err := mgr.tokenMgr.CreateTokens(&jwtMessage)
 	if err != nil {
 		resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
-		return
+		return nil, false
 	}
-	loginResponse := LoginResp{
+	return &LoginResp{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		Context: AccountContext{
@@ -394,8 +477,7 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 			Space:        user.Space,
 		},
 		User: user.Attributes.Data(),
-	}
-	resputil.Success(c, loginResponse)
+	}, true
 }
 
 var (
@@ -702,6 +784,11 @@ func (mgr *AuthMgr) Signup(c *gin.Context) {
 		return
 	}
 
+	if err := util.GetPasswordPolicy().Check(req.Username, req.Password); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
 	u := query.User
 
 	_, err := u.WithContext(c).Where(u.Name.Eq(req.Username)).First()
@@ -758,6 +845,10 @@ func (mgr *AuthMgr) RefreshToken(c *gin.Context) {
 		resputil.HTTPError(c, http.StatusUnauthorized, "User not found", resputil.NotSpecified)
 		return
 	}
+	if err = mgr.refreshSession(c, &chaims); err != nil {
+		resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenInvalid)
+		return
+	}
 
 	accessToken, refreshToken, err := mgr.tokenMgr.CreateTokens(&chaims)
 	if err != nil {
@@ -801,6 +892,12 @@ func (mgr *AuthMgr) SwitchQueue(c *gin.Context) {
 		return
 	}
 
+	// 个人访问令牌绑定到一个账户，不能切换账户
+	if _, ok := util.GetAPITokenID(c); ok {
+		resputil.Error(c, "Can't switch queue with an api token", resputil.UserNotAllowed)
+		return
+	}
+
 	token := util.GetToken(c)
 
 	// Check queue
@@ -829,6 +926,7 @@ func (mgr *AuthMgr) SwitchQueue(c *gin.Context) {
 		RolePlatform:      token.RolePlatform,
 		AccountAccessMode: userQueue.AccessMode,
 		PublicAccessMode:  token.PublicAccessMode,
+		SessionID:         token.SessionID,
 	}
 	accessToken, refreshToken, err := mgr.tokenMgr.CreateTokens(&jwtMessage)
 	if err != nil {
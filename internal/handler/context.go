@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	"golang.org/x/exp/rand"
 	"gorm.io/datatypes"
 	v1 "k8s.io/api/core/v1"
@@ -52,6 +55,9 @@ func (mgr *ContextMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.PUT("attributes", mgr.UpdateUserAttributes)
 	g.POST("email/code", mgr.SendUserVerificationCode)
 	g.POST("email/update", mgr.UpdateUserEmail)
+	g.PUT("password", mgr.ChangePassword)
+	g.GET("notification/preference", mgr.GetNotificationPreference)
+	g.PUT("notification/preference", mgr.UpdateNotificationPreference)
 }
 
 func (mgr *ContextMgr) RegisterAdmin(_ *gin.RouterGroup) {}
@@ -199,6 +205,11 @@ func (mgr *ContextMgr) UpdateUserAttributes(c *gin.Context) {
 		resputil.BadRequestError(c, "Invalid request body")
 		return
 	}
+	if attributes.Language != nil &&
+		!lo.Contains(model.GetAllNotificationLanguages(), model.NotificationLanguage(*attributes.Language)) {
+		resputil.BadRequestError(c, fmt.Sprintf("unsupported language %s", *attributes.Language))
+		return
+	}
 
 	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
 	if err != nil {
@@ -208,6 +219,10 @@ func (mgr *ContextMgr) UpdateUserAttributes(c *gin.Context) {
 
 	// Fix UID and GID are not allowed to be updated
 	oldAttributes := user.Attributes.Data()
+	if err := validateRobotWebhooks(c, &oldAttributes, &attributes); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
 	attributes.ID = oldAttributes.ID
 	attributes.UID = oldAttributes.UID
 	attributes.GID = oldAttributes.GID
@@ -221,6 +236,31 @@ func (mgr *ContextMgr) UpdateUserAttributes(c *gin.Context) {
 	resputil.Success(c, "User attributes updated successfully")
 }
 
+// validateRobotWebhooks 检查用户修改的个人机器人 Webhook，只允许 https 且不能指向本地或集群内部网络。
+// 未修改的 Webhook 不再检查，避免已保存地址的解析结果变化后无法修改其他属性
+func validateRobotWebhooks(ctx context.Context, oldAttributes, attributes *model.UserAttribute) error {
+	webhooks := [][2]*string{
+		{oldAttributes.FeishuWebhook, attributes.FeishuWebhook},
+		{oldAttributes.DingTalkWebhook, attributes.DingTalkWebhook},
+		{oldAttributes.WeComWebhook, attributes.WeComWebhook},
+		{oldAttributes.WPSWebhook, attributes.WPSWebhook},
+	}
+	for _, pair := range webhooks {
+		oldURL, newURL := ptr.Deref(pair[0], ""), ptr.Deref(pair[1], "")
+		if newURL == "" || newURL == oldURL {
+			continue
+		}
+		u, err := url.Parse(newURL)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			return fmt.Errorf("invalid robot webhook url %q, only https is supported", newURL)
+		}
+		if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SendUserVerificationCode godoc
 //
 //	@Summary		Send User Verification Code for email
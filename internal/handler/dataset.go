@@ -13,6 +13,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
@@ -54,6 +55,7 @@ func (mgr *DatasetMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *DatasetMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionDatasetsManage))
 	g.GET("/alldataset", mgr.GetAllDataset)
 	g.POST("/share/user", mgr.AdminShareDatasetWithUser)
 	g.POST("/share/queue", mgr.AdminShareDatasetWithQueue)
@@ -536,7 +538,7 @@ func (mgr *DatasetMgr) ShareDatasetWithQueue(c *gin.Context) {
 		resputil.Error(c, "you has no permission or this dataset not exist", resputil.InvalidRequest)
 		return
 	}
-	err = mgr.shareWithQueue(c, queueReq)
+	err = shareWithQueue(c, queueReq)
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.InvalidRequest)
 		return
@@ -569,7 +571,7 @@ func (mgr *DatasetMgr) AdminShareDatasetWithQueue(c *gin.Context) {
 		resputil.Error(c, "can't find this dataset", resputil.InvalidRequest)
 		return
 	}
-	err = mgr.shareWithQueue(c, queueReq)
+	err = shareWithQueue(c, queueReq)
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.InvalidRequest)
 		return
@@ -577,7 +579,7 @@ func (mgr *DatasetMgr) AdminShareDatasetWithQueue(c *gin.Context) {
 	resputil.Success(c, "Shared with queue successfully")
 }
 
-func (mgr *DatasetMgr) shareWithQueue(c *gin.Context, queueReq SharedQueueReq) error {
+func shareWithQueue(c *gin.Context, queueReq SharedQueueReq) error {
 	q := query.Account
 	qd := query.AccountDataset
 	if len(queueReq.QueueIDs) == 0 {
@@ -634,7 +636,7 @@ func (mgr *DatasetMgr) CancelShareDatasetWithQueue(c *gin.Context) {
 		resputil.Error(c, "you can't cancel share with queue", resputil.InvalidRequest)
 		return
 	}
-	err = mgr.cancelShareWithQueue(c, queueReq)
+	err = cancelShareWithQueue(c, queueReq)
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.InvalidRequest)
 		return
@@ -668,7 +670,7 @@ func (mgr *DatasetMgr) AdmincancelShareDatasetWithQueue(c *gin.Context) {
 		resputil.Error(c, "dataset does not exist", resputil.InvalidRequest)
 		return
 	}
-	err = mgr.cancelShareWithQueue(c, queueReq)
+	err = cancelShareWithQueue(c, queueReq)
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.InvalidRequest)
 		return
@@ -676,7 +678,7 @@ func (mgr *DatasetMgr) AdmincancelShareDatasetWithQueue(c *gin.Context) {
 	resputil.Success(c, "cancel successfully")
 }
 
-func (mgr *DatasetMgr) cancelShareWithQueue(c *gin.Context, cancelQueueReq cancelSharedQueueReq) error {
+func cancelShareWithQueue(c *gin.Context, cancelQueueReq cancelSharedQueueReq) error {
 	q := query.Account
 	qd := query.AccountDataset
 	_, err := q.WithContext(c).Where(q.ID.Eq(cancelQueueReq.QueueID)).First()
@@ -7,7 +7,9 @@ import (
 	"github.com/gin-gonic/gin"
 	imrocreq "github.com/imroc/req/v3"
 
+	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
 	"github.com/raids-lab/crater/pkg/imageregistry"
@@ -69,6 +71,7 @@ func (mgr *ImagePackMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *ImagePackMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionImagesAdmin))
 	g.GET("/kaniko", mgr.AdminListKaniko)
 	g.GET("/image", mgr.AdminListImage)
 	g.POST("/deleteimage", mgr.AdminDeleteImageByIDList)
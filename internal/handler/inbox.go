@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewInboxMgr)
+}
+
+const (
+	// inboxRefreshInterval 推送未读数时从数据库刷新的周期，覆盖其他副本写入的消息
+	inboxRefreshInterval = 30 * time.Second
+	// inboxWriteTimeout 推送未读数时的写超时
+	inboxWriteTimeout = 10 * time.Second
+	// defaultInboxPageSize 默认每页消息数
+	defaultInboxPageSize = 20
+)
+
+type InboxMgr struct {
+	name string
+}
+
+func NewInboxMgr(_ *RegisterConfig) Manager {
+	return &InboxMgr{
+		name: "inbox",
+	}
+}
+
+func (mgr *InboxMgr) GetName() string { return mgr.name }
+
+func (mgr *InboxMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *InboxMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListInboxMessages)
+	g.GET("/unread", mgr.GetUnreadCount)
+	g.GET("/stream", mgr.StreamUnreadCount)
+	g.PUT("/read", mgr.MarkAllInboxMessagesRead)
+	g.PUT("/:id/read", mgr.MarkInboxMessageRead)
+}
+
+func (mgr *InboxMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	ListInboxMessagesReq struct {
+		Page       int  `form:"page"`       // 页码，从 0 开始
+		PageSize   int  `form:"pageSize"`   // 每页大小
+		UnreadOnly bool `form:"unreadOnly"` // 是否只返回未读消息
+	}
+
+	InboxMessageResp struct {
+		ID        uint                `json:"id"`
+		Category  model.InboxCategory `json:"category"`
+		Title     string              `json:"title"`
+		Content   string              `json:"content"`
+		Link      string              `json:"link"`
+		JobName   string              `json:"jobName"`
+		AlertType string              `json:"alertType"`
+		CreatedAt time.Time           `json:"createdAt"`
+		ReadAt    *time.Time          `json:"readAt"`
+	}
+
+	ListInboxMessagesResp struct {
+		Messages []InboxMessageResp `json:"messages"`
+		Total    int64              `json:"total"`
+	}
+
+	UnreadCountResp struct {
+		Count int64 `json:"count"`
+	}
+
+	InboxMessageIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+)
+
+// ListInboxMessages godoc
+//
+//	@Summary		List inbox messages
+//	@Description	List inbox messages of the current user, newest first
+//	@Tags			Inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			page		query		int										false	"page number, starts from 0"
+//	@Param			pageSize	query		int										false	"page size"
+//	@Param			unreadOnly	query		bool									false	"only unread messages"
+//	@Success		200			{object}	resputil.Response[ListInboxMessagesResp]	"Inbox messages"
+//	@Failure		400			{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500			{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/inbox [get]
+func (mgr *InboxMgr) ListInboxMessages(c *gin.Context) {
+	var req ListInboxMessagesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultInboxPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+
+	token := util.GetToken(c)
+	m := query.InboxMessage
+	q := m.WithContext(c).Where(m.UserID.Eq(token.UserID))
+	if req.UnreadOnly {
+		q = q.Where(m.ReadAt.IsNull())
+	}
+	messages, total, err := q.Order(m.ID.Desc()).FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list inbox messages failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	resp := ListInboxMessagesResp{
+		Messages: make([]InboxMessageResp, 0, len(messages)),
+		Total:    total,
+	}
+	for _, msg := range messages {
+		resp.Messages = append(resp.Messages, InboxMessageResp{
+			ID:        msg.ID,
+			Category:  msg.Category,
+			Title:     msg.Title,
+			Content:   msg.Content,
+			Link:      msg.Link,
+			JobName:   msg.JobName,
+			AlertType: msg.AlertType,
+			CreatedAt: msg.CreatedAt,
+			ReadAt:    msg.ReadAt,
+		})
+	}
+	resputil.Success(c, resp)
+}
+
+// GetUnreadCount godoc
+//
+//	@Summary		Get unread count
+//	@Description	Get the number of unread inbox messages of the current user
+//	@Tags			Inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[UnreadCountResp]	"Unread count"
+//	@Failure		500	{object}	resputil.Response[any]				"Other errors"
+//	@Router			/v1/inbox/unread [get]
+func (mgr *InboxMgr) GetUnreadCount(c *gin.Context) {
+	token := util.GetToken(c)
+	count, err := countUnreadInboxMessages(c, token.UserID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("count unread messages failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, UnreadCountResp{Count: count})
+}
+
+// MarkInboxMessageRead godoc
+//
+//	@Summary		Mark message read
+//	@Description	Mark an inbox message of the current user as read
+//	@Tags			Inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"message id"
+//	@Success		200	{object}	resputil.Response[string]	"Marked as read"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/inbox/{id}/read [put]
+func (mgr *InboxMgr) MarkInboxMessageRead(c *gin.Context) {
+	var req InboxMessageIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	token := util.GetToken(c)
+	m := query.InboxMessage
+	_, err := m.WithContext(c).
+		Where(m.ID.Eq(req.ID), m.UserID.Eq(token.UserID), m.ReadAt.IsNull()).
+		Update(m.ReadAt, utils.GetLocalTime())
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("mark message read failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	alert.NotifyInboxChanged(token.UserID)
+	resputil.Success(c, "")
+}
+
+// MarkAllInboxMessagesRead godoc
+//
+//	@Summary		Mark all messages read
+//	@Description	Mark all inbox messages of the current user as read
+//	@Tags			Inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[string]	"Marked as read"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/inbox/read [put]
+func (mgr *InboxMgr) MarkAllInboxMessagesRead(c *gin.Context) {
+	token := util.GetToken(c)
+	m := query.InboxMessage
+	info, err := m.WithContext(c).
+		Where(m.UserID.Eq(token.UserID), m.ReadAt.IsNull()).
+		Update(m.ReadAt, utils.GetLocalTime())
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("mark messages read failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	klog.Infof("mark inbox messages read, user: %s, count: %d", token.Username, info.RowsAffected)
+	alert.NotifyInboxChanged(token.UserID)
+	resputil.Success(c, "")
+}
+
+// StreamUnreadCount godoc
+//
+//	@Summary		Stream unread count
+//	@Description	Push the unread count of the current user through websocket whenever it changes
+//	@Tags			Inbox
+//	@Security		Bearer
+//	@Param			token	query	string	true	"access token"
+//	@Router			/v1/inbox/stream [get]
+func (mgr *InboxMgr) StreamUnreadCount(c *gin.Context) {
+	token := util.GetToken(c)
+
+	var upgrade = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	// Allow all origins in debug mode
+	if config.IsDebugMode() {
+		upgrade.CheckOrigin = func(_ *http.Request) bool {
+			return true
+		}
+	}
+	ws, err := upgrade.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	defer ws.Close()
+
+	events, cancel := alert.SubscribeInbox(token.UserID)
+	defer cancel()
+
+	// 读取客户端消息以感知连接关闭
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(inboxRefreshInterval)
+	defer ticker.Stop()
+
+	last := int64(-1)
+	push := func() error {
+		count, err := countUnreadInboxMessages(c, token.UserID)
+		if err != nil {
+			return err
+		}
+		if count == last {
+			return nil
+		}
+		last = count
+		if err := ws.SetWriteDeadline(time.Now().Add(inboxWriteTimeout)); err != nil {
+			return err
+		}
+		return ws.WriteJSON(UnreadCountResp{Count: count})
+	}
+
+	for {
+		if err := push(); err != nil {
+			klog.Warningf("stop pushing unread count to user %s: %v", token.Username, err)
+			return
+		}
+		select {
+		case <-events:
+		case <-ticker.C:
+		case <-closed:
+			return
+		case <-c.Done():
+			return
+		}
+	}
+}
+
+func countUnreadInboxMessages(c *gin.Context, userID uint) (int64, error) {
+	m := query.InboxMessage
+	return m.WithContext(c).Where(m.UserID.Eq(userID), m.ReadAt.IsNull()).Count()
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewIncidentMgr)
+}
+
+// defaultIncidentPageSize 默认每页异常数
+const defaultIncidentPageSize = 20
+
+type IncidentMgr struct {
+	name string
+}
+
+func NewIncidentMgr(_ *RegisterConfig) Manager {
+	return &IncidentMgr{
+		name: "incidents",
+	}
+}
+
+func (mgr *IncidentMgr) GetName() string { return mgr.name }
+
+func (mgr *IncidentMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *IncidentMgr) RegisterProtected(_ *gin.RouterGroup) {}
+
+func (mgr *IncidentMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionAlertsManage))
+	g.GET("", mgr.ListIncidents)
+}
+
+type (
+	ListIncidentsReq struct {
+		Kind     *model.IncidentKind   `form:"kind"`     // 按异常类型过滤
+		Status   *model.IncidentStatus `form:"status"`   // 按异常状态过滤
+		Page     int                   `form:"page"`     // 页码，从 0 开始
+		PageSize int                   `form:"pageSize"` // 每页大小
+	}
+
+	ListIncidentsResp struct {
+		Incidents []*model.ClusterIncident `json:"incidents"`
+		Total     int64                    `json:"total"`
+	}
+)
+
+// ListIncidents godoc
+//
+//	@Summary		List cluster incidents
+//	@Description	List cluster incidents such as NotReady nodes and missing GPUs, newest first
+//	@Tags			Incident
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			kind		query		string									false	"node_not_ready, gpu_missing, resource_dropped, build_failures or prometheus_unreachable"
+//	@Param			status		query		string									false	"open or resolved"
+//	@Param			page		query		int										false	"page number, starts from 0"
+//	@Param			pageSize	query		int										false	"page size"
+//	@Success		200			{object}	resputil.Response[ListIncidentsResp]	"Cluster incidents"
+//	@Failure		400			{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500			{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/admin/incidents [get]
+func (mgr *IncidentMgr) ListIncidents(c *gin.Context) {
+	var req ListIncidentsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultIncidentPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+
+	ci := query.ClusterIncident
+	q := ci.WithContext(c)
+	if req.Kind != nil {
+		q = q.Where(ci.Kind.Eq(string(*req.Kind)))
+	}
+	if req.Status != nil {
+		q = q.Where(ci.Status.Eq(string(*req.Status)))
+	}
+	incidents, total, err := q.Order(ci.ID.Desc()).FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list incidents failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, ListIncidentsResp{Incidents: incidents, Total: total})
+}
@@ -0,0 +1,452 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewJobWebhookMgr)
+}
+
+// defaultJobWebhookDeliveryPageSize 默认每页投递记录数
+const defaultJobWebhookDeliveryPageSize = 20
+
+type JobWebhookMgr struct {
+	name string
+}
+
+func NewJobWebhookMgr(_ *RegisterConfig) Manager {
+	return &JobWebhookMgr{
+		name: "webhooks",
+	}
+}
+
+func (mgr *JobWebhookMgr) GetName() string { return mgr.name }
+
+func (mgr *JobWebhookMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *JobWebhookMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListJobWebhooks)
+	g.POST("", mgr.CreateJobWebhook)
+	g.PUT("/:id", mgr.UpdateJobWebhook)
+	g.DELETE("/:id", mgr.DeleteJobWebhook)
+	g.POST("/:id/ping", mgr.PingJobWebhook)
+	g.GET("/:id/deliveries", mgr.ListJobWebhookDeliveries)
+	g.POST("/:id/deliveries/:did/resend", mgr.ResendJobWebhookDelivery)
+}
+
+func (mgr *JobWebhookMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	ListJobWebhooksReq struct {
+		JobName *string `form:"jobName"` // 按作业过滤
+	}
+
+	CreateJobWebhookReq struct {
+		URL        string   `json:"url" binding:"required"`
+		Secret     string   `json:"secret"`
+		Events     []string `json:"events"`     // 订阅的作业状态，为空表示所有状态
+		JobName    string   `json:"jobName"`    // 作业名，为空表示账户级 Webhook
+		AllMembers bool     `json:"allMembers"` // 账户级 Webhook 是否包含其他成员的作业，仅账户管理员可设置
+	}
+
+	UpdateJobWebhookReq struct {
+		URL     *string   `json:"url"`
+		Secret  *string   `json:"secret"` // 为空字符串时取消签名
+		Events  *[]string `json:"events"`
+		Enabled *bool     `json:"enabled"`
+	}
+
+	JobWebhookIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	JobWebhookDeliveryIDReq struct {
+		ID         uint `uri:"id" binding:"required"`
+		DeliveryID uint `uri:"did" binding:"required"`
+	}
+
+	ListJobWebhookDeliveriesReq struct {
+		Status   *model.OutboxStatus `form:"status"`   // 按投递状态过滤
+		Page     int                 `form:"page"`     // 页码，从 0 开始
+		PageSize int                 `form:"pageSize"` // 每页大小
+	}
+
+	ListJobWebhookDeliveriesResp struct {
+		Deliveries []*model.JobWebhookDelivery `json:"deliveries"`
+		Total      int64                       `json:"total"`
+	}
+)
+
+// ListJobWebhooks godoc
+//
+//	@Summary		List job webhooks
+//	@Description	List job lifecycle webhooks of the current user in the current account
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			jobName	query		string									false	"job name"
+//	@Success		200		{object}	resputil.Response[[]model.JobWebhook]	"Job webhooks"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/webhooks [get]
+func (mgr *JobWebhookMgr) ListJobWebhooks(c *gin.Context) {
+	var req ListJobWebhooksReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	jw := query.JobWebhook
+	q := jw.WithContext(c).Where(jw.UserID.Eq(token.UserID), jw.AccountID.Eq(token.AccountID))
+	if req.JobName != nil {
+		q = q.Where(jw.JobName.Eq(*req.JobName))
+	}
+	webhooks, err := q.Order(jw.ID.Desc()).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list job webhooks failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, webhooks)
+}
+
+// CreateJobWebhook godoc
+//
+//	@Summary		Create job webhook
+//	@Description	Register a webhook that receives signed job lifecycle events of one job or of the current account
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		CreateJobWebhookReq						true	"webhook"
+//	@Success		200		{object}	resputil.Response[model.JobWebhook]		"Created webhook"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/webhooks [post]
+func (mgr *JobWebhookMgr) CreateJobWebhook(c *gin.Context) {
+	var req CreateJobWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := validateJobWebhook(c, req.URL, req.Events); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	token := util.GetToken(c)
+	if req.AllMembers && (req.JobName != "" || token.RoleAccount != model.RoleAdmin) {
+		resputil.Error(c, "only account admins can watch jobs of all members", resputil.UserNotAllowed)
+		return
+	}
+	if req.JobName != "" {
+		j := query.Job
+		conds := []gen.Condition{j.JobName.Eq(req.JobName), j.AccountID.Eq(token.AccountID)}
+		if token.RoleAccount != model.RoleAdmin {
+			conds = append(conds, j.UserID.Eq(token.UserID))
+		}
+		if _, err := j.WithContext(c).Where(conds...).First(); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				resputil.Error(c, fmt.Sprintf("job %s not found", req.JobName), resputil.UserNotAllowed)
+				return
+			}
+			resputil.Error(c, fmt.Sprintf("get job failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+	}
+
+	webhook := &model.JobWebhook{
+		UserID:     token.UserID,
+		AccountID:  token.AccountID,
+		JobName:    req.JobName,
+		AllMembers: req.AllMembers,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		Events:     datatypes.NewJSONType(lo.Uniq(req.Events)),
+		Enabled:    true,
+	}
+	if err := query.JobWebhook.WithContext(c).Create(webhook); err != nil {
+		resputil.Error(c, fmt.Sprintf("create job webhook failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, webhook)
+}
+
+// UpdateJobWebhook godoc
+//
+//	@Summary		Update job webhook
+//	@Description	Update the URL, secret, events or enabled state of a job webhook
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id		path		uint						true	"webhook id"
+//	@Param			data	body		UpdateJobWebhookReq			true	"fields to update"
+//	@Success		200		{object}	resputil.Response[string]	"Updated"
+//	@Failure		400		{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/webhooks/{id} [put]
+func (mgr *JobWebhookMgr) UpdateJobWebhook(c *gin.Context) {
+	var uri JobWebhookIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var req UpdateJobWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	webhook, ok := mgr.getOwnWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	jw := query.JobWebhook
+	columns := []field.AssignExpr{}
+	if req.URL != nil {
+		columns = append(columns, jw.URL.Value(*req.URL))
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		columns = append(columns, jw.Events.Value(datatypes.NewJSONType(lo.Uniq(*req.Events))))
+		webhook.Events = datatypes.NewJSONType(*req.Events)
+	}
+	if req.Secret != nil {
+		columns = append(columns, jw.Secret.Value(*req.Secret))
+	}
+	if req.Enabled != nil {
+		columns = append(columns, jw.Enabled.Value(*req.Enabled))
+	}
+	if len(columns) == 0 {
+		resputil.Success(c, "")
+		return
+	}
+	if err := validateJobWebhook(c, webhook.URL, webhook.Events.Data()); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if _, err := jw.WithContext(c).Where(jw.ID.Eq(webhook.ID)).UpdateSimple(columns...); err != nil {
+		resputil.Error(c, fmt.Sprintf("update job webhook failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// DeleteJobWebhook godoc
+//
+//	@Summary		Delete job webhook
+//	@Description	Delete a job webhook, pending deliveries of it will not be retried
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"webhook id"
+//	@Success		200	{object}	resputil.Response[string]	"Deleted"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/webhooks/{id} [delete]
+func (mgr *JobWebhookMgr) DeleteJobWebhook(c *gin.Context) {
+	var uri JobWebhookIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	webhook, ok := mgr.getOwnWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+	jw := query.JobWebhook
+	if _, err := jw.WithContext(c).Where(jw.ID.Eq(webhook.ID)).Delete(); err != nil {
+		resputil.Error(c, fmt.Sprintf("delete job webhook failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// PingJobWebhook godoc
+//
+//	@Summary		Ping job webhook
+//	@Description	Send a signed ping event to the webhook immediately and report whether it was accepted
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"webhook id"
+//	@Success		200	{object}	resputil.Response[string]	"Ping accepted"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/webhooks/{id}/ping [post]
+func (mgr *JobWebhookMgr) PingJobWebhook(c *gin.Context) {
+	var uri JobWebhookIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	webhook, ok := mgr.getOwnWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+	if err := alert.PingJobWebhook(c, webhook); err != nil {
+		resputil.Error(c, fmt.Sprintf("ping job webhook failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// ListJobWebhookDeliveries godoc
+//
+//	@Summary		List job webhook deliveries
+//	@Description	List events sent to a job webhook with their delivery history, newest first
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id			path		uint											true	"webhook id"
+//	@Param			status		query		string											false	"pending, sending, sent or failed"
+//	@Param			page		query		int												false	"page number, starts from 0"
+//	@Param			pageSize	query		int												false	"page size"
+//	@Success		200			{object}	resputil.Response[ListJobWebhookDeliveriesResp]	"Deliveries"
+//	@Failure		400			{object}	resputil.Response[any]							"Request parameter error"
+//	@Failure		500			{object}	resputil.Response[any]							"Other errors"
+//	@Router			/v1/webhooks/{id}/deliveries [get]
+func (mgr *JobWebhookMgr) ListJobWebhookDeliveries(c *gin.Context) {
+	var uri JobWebhookIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var req ListJobWebhookDeliveriesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultJobWebhookDeliveryPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+	webhook, ok := mgr.getOwnWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	d := query.JobWebhookDelivery
+	q := d.WithContext(c).Where(d.WebhookID.Eq(webhook.ID))
+	if req.Status != nil {
+		q = q.Where(d.Status.Eq(string(*req.Status)))
+	}
+	deliveries, total, err := q.Order(d.ID.Desc()).FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list job webhook deliveries failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, ListJobWebhookDeliveriesResp{Deliveries: deliveries, Total: total})
+}
+
+// ResendJobWebhookDelivery godoc
+//
+//	@Summary		Resend job webhook delivery
+//	@Description	Put a failed or sent job event back into the queue for delivery
+//	@Tags			JobWebhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"webhook id"
+//	@Param			did	path		uint						true	"delivery id"
+//	@Success		200	{object}	resputil.Response[string]	"Resend scheduled"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/webhooks/{id}/deliveries/{did}/resend [post]
+func (mgr *JobWebhookMgr) ResendJobWebhookDelivery(c *gin.Context) {
+	var uri JobWebhookDeliveryIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	webhook, ok := mgr.getOwnWebhook(c, uri.ID)
+	if !ok {
+		return
+	}
+	d := query.JobWebhookDelivery
+	if _, err := d.WithContext(c).Where(d.ID.Eq(uri.DeliveryID), d.WebhookID.Eq(webhook.ID)).First(); err != nil {
+		resputil.Error(c, fmt.Sprintf("get job webhook delivery failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if err := alert.ResendJobWebhookDelivery(c, uri.DeliveryID); err != nil {
+		resputil.Error(c, fmt.Sprintf("resend job webhook delivery failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// getOwnWebhook 获取当前用户在当前账户中创建的 Webhook，不存在时写入错误响应
+func (mgr *JobWebhookMgr) getOwnWebhook(c *gin.Context, id uint) (*model.JobWebhook, bool) {
+	token := util.GetToken(c)
+	jw := query.JobWebhook
+	webhook, err := jw.WithContext(c).Where(
+		jw.ID.Eq(id),
+		jw.UserID.Eq(token.UserID),
+		jw.AccountID.Eq(token.AccountID),
+	).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Error(c, fmt.Sprintf("webhook %d not found", id), resputil.UserNotAllowed)
+			return nil, false
+		}
+		resputil.Error(c, fmt.Sprintf("get job webhook failed, detail: %v", err), resputil.NotSpecified)
+		return nil, false
+	}
+	return webhook, true
+}
+
+// validateJobWebhook 检查 Webhook 的 URL 和订阅的作业状态。
+// 提前拒绝指向本地或集群内部网络的地址，投递时建立连接前还会再次检查解析结果
+func validateJobWebhook(ctx context.Context, rawURL string, events []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid webhook url %q", rawURL)
+	}
+	if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+	if invalid, _ := lo.Difference(events, model.GetAllJobWebhookEvents()); len(invalid) > 0 {
+		return fmt.Errorf("unsupported events %v", invalid)
+	}
+	return nil
+}
+
+// checkWebhookHost 解析用户配置的 Webhook 主机，拒绝解析到本地或集群内部网络的地址
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve webhook host %q", host)
+	}
+	denied := alert.JobWebhookDeniedPrefixes()
+	for _, addr := range addrs {
+		if err := alert.CheckJobWebhookAddress(addr.IP, denied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 )
 
@@ -61,6 +62,7 @@ func init() {
 	promHTTPHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
 	registry.MustRegister(completedJobsGauge)
 	registry.MustRegister(runningJobsGauge)
+	registry.MustRegister(middleware.RateLimitedRequests)
 }
 
 // GetMetrics godoc
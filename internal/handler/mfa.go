@@ -0,0 +1,622 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	defaultMFAIssuer = "Crater"
+	// mfaMaxFailedAttempts 连续验证失败达到该次数后暂时锁定
+	mfaMaxFailedAttempts = 5
+	mfaLockDuration      = 5 * time.Minute
+)
+
+var (
+	errMFALocked      = errors.New("too many failed attempts, please try again later")
+	errMFAInvalidCode = errors.New("invalid verification code")
+	errMFANotEnabled  = errors.New("two-factor authentication is not enabled")
+	errMFAEnabled     = errors.New("two-factor authentication is already enabled")
+)
+
+type (
+	MFAVerifyReq struct {
+		MFAToken     string `json:"mfaToken" binding:"required"`
+		Code         string `json:"code"`         // 验证器应用中的 6 位验证码
+		RecoveryCode string `json:"recoveryCode"` // 无法使用验证器时使用恢复码，每个恢复码只能使用一次
+	}
+
+	MFATokenReq struct {
+		MFAToken string `json:"mfaToken" binding:"required"`
+	}
+
+	MFAConfirmReq struct {
+		MFAToken string `json:"mfaToken" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
+	MFACodeReq struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recoveryCode"`
+	}
+
+	MFAEnrollResp struct {
+		Secret string `json:"secret"` // Base32 密钥，无法扫码时手动输入
+		URI    string `json:"uri"`    // otpauth:// 地址，前端渲染为二维码
+	}
+
+	MFAStatusResp struct {
+		Enabled           bool       `json:"enabled"`
+		EnabledAt         *time.Time `json:"enabledAt"`
+		RecoveryCodesLeft int        `json:"recoveryCodesLeft"`
+		Required          bool       `json:"required"` // 平台要求该用户启用两步验证，不能关闭
+	}
+
+	MFARecoveryCodesResp struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+)
+
+// mfaRequiredFor 开启 mfa.enforceForAdmins 时平台管理员必须启用两步验证
+func mfaRequiredFor(user *model.User) bool {
+	return config.GetConfig().MFA.EnforceForAdmins && user.Role == model.RoleAdmin
+}
+
+func mfaIssuer() string {
+	if issuer := config.GetConfig().MFA.Issuer; issuer != "" {
+		return issuer
+	}
+	return defaultMFAIssuer
+}
+
+func getUserMFA(c context.Context, userID uint) (*model.UserMFA, error) {
+	m := query.UserMFA
+	return m.WithContext(c).Where(m.UserID.Eq(userID)).First()
+}
+
+// requireMFA 登录第一步通过后检查是否需要两步验证，需要时返回挑战令牌并返回 true
+func (mgr *AuthMgr) requireMFA(c *gin.Context, user *model.User) bool {
+	purpose := util.MFAPurpose("")
+	mfa, err := getUserMFA(c, user.ID)
+	switch {
+	case err == nil && mfa.Enabled:
+		purpose = util.MFAPurposeVerify
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+		return true
+	case mfaRequiredFor(user):
+		purpose = util.MFAPurposeSetup
+	default:
+		return false
+	}
+
+	token, err := mgr.tokenMgr.CreateMFAToken(user.ID, purpose)
+	if err != nil {
+		resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+		return true
+	}
+	resputil.Success(c, LoginResp{
+		User:             user.Attributes.Data(),
+		MFARequired:      purpose == util.MFAPurposeVerify,
+		MFASetupRequired: purpose == util.MFAPurposeSetup,
+		MFAToken:         token,
+	})
+	return true
+}
+
+// userFromMFAToken 校验挑战令牌并返回对应的用户。出错时已写入响应
+func (mgr *AuthMgr) userFromMFAToken(c *gin.Context, token string, purpose util.MFAPurpose) (*model.User, bool) {
+	userID, tokenPurpose, err := mgr.tokenMgr.CheckMFAToken(token)
+	if err != nil || tokenPurpose != purpose {
+		resputil.HTTPError(c, http.StatusUnauthorized, "Invalid or expired MFA token, please login again", resputil.TokenInvalid)
+		return nil, false
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(userID)).First()
+	if err != nil || user.Status != model.StatusActive {
+		resputil.HTTPError(c, http.StatusUnauthorized, "User is not active", resputil.NotSpecified)
+		return nil, false
+	}
+	return user, true
+}
+
+// verifyMFACode 校验 TOTP 验证码或恢复码。验证码不能重复使用，恢复码使用后删除；
+// 连续失败过多时锁定一段时间，防止暴力破解
+func verifyMFACode(c context.Context, mfa *model.UserMFA, code, recoveryCode string) error {
+	now := time.Now()
+	if mfa.Locked(now) {
+		return errMFALocked
+	}
+
+	m := query.UserMFA
+	switch {
+	case code != "":
+		if step, ok := util.ValidateTOTP(mfa.Secret, code, now, mfa.LastUsedStep); ok {
+			info, err := m.WithContext(c).Where(m.ID.Eq(mfa.ID), m.LastUsedStep.Lt(step)).
+				UpdateSimple(m.LastUsedStep.Value(step), m.FailedAttempts.Value(0), m.LockedUntil.Null())
+			if err != nil {
+				return err
+			}
+			if info.RowsAffected == 1 {
+				mfa.LastUsedStep = step
+				return nil
+			}
+		}
+	case recoveryCode != "" && mfa.Enabled:
+		hash := util.HashRecoveryCode(recoveryCode)
+		codes := mfa.RecoveryCodes.Data()
+		if i := slices.Index(codes, hash); i >= 0 {
+			remaining := slices.Delete(slices.Clone(codes), i, i+1)
+			// 以更新时间作为乐观锁，避免同一恢复码被并发使用两次
+			info, err := m.WithContext(c).Where(m.ID.Eq(mfa.ID), m.UpdatedAt.Eq(mfa.UpdatedAt)).
+				UpdateSimple(m.RecoveryCodes.Value(datatypes.NewJSONType(remaining)),
+					m.FailedAttempts.Value(0), m.LockedUntil.Null())
+			if err != nil {
+				return err
+			}
+			if info.RowsAffected == 1 {
+				mfa.RecoveryCodes = datatypes.NewJSONType(remaining)
+				return nil
+			}
+		}
+	}
+
+	attempts := mfa.FailedAttempts + 1
+	assigns := []field.AssignExpr{m.FailedAttempts.Value(attempts)}
+	if attempts >= mfaMaxFailedAttempts {
+		assigns = []field.AssignExpr{m.FailedAttempts.Value(0), m.LockedUntil.Value(now.Add(mfaLockDuration))}
+	}
+	if _, err := m.WithContext(c).Where(m.ID.Eq(mfa.ID)).UpdateSimple(assigns...); err != nil {
+		return err
+	}
+	return errMFAInvalidCode
+}
+
+// respondMFAError 将两步验证的错误写入响应，invalidStatus 为验证码错误时的 HTTP 状态码
+func respondMFAError(c *gin.Context, err error, invalidStatus int) {
+	switch {
+	case errors.Is(err, errMFALocked):
+		resputil.HTTPError(c, http.StatusTooManyRequests, err.Error(), resputil.InvalidCredentials)
+	case errors.Is(err, errMFAInvalidCode):
+		resputil.HTTPError(c, invalidStatus, err.Error(), resputil.InvalidCredentials)
+	case errors.Is(err, errMFANotEnabled), errors.Is(err, errMFAEnabled):
+		resputil.BadRequestError(c, err.Error())
+	default:
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+	}
+}
+
+// enrollMFA 为用户生成新的 TOTP 密钥，启用前可以重复生成
+func enrollMFA(c context.Context, user *model.User) (*MFAEnrollResp, error) {
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	m := query.UserMFA
+	mfa, err := getUserMFA(c, user.ID)
+	switch {
+	case err == nil && mfa.Enabled:
+		return nil, errMFAEnabled
+	case err == nil:
+		if _, err = m.WithContext(c).Where(m.ID.Eq(mfa.ID)).
+			UpdateSimple(m.Secret.Value(secret), m.LastUsedStep.Value(0)); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err = m.WithContext(c).Create(&model.UserMFA{UserID: user.ID, Secret: secret}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return &MFAEnrollResp{
+		Secret: secret,
+		URI:    util.TOTPProvisioningURI(mfaIssuer(), user.Name, secret),
+	}, nil
+}
+
+// enableMFA 使用验证器中的验证码确认绑定，启用两步验证并返回恢复码
+func enableMFA(c context.Context, userID uint, code string) ([]string, error) {
+	mfa, err := getUserMFA(c, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("please enroll an authenticator first")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, errMFAEnabled
+	}
+	if err = verifyMFACode(c, mfa, code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := util.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	m := query.UserMFA
+	if _, err = m.WithContext(c).Where(m.ID.Eq(mfa.ID)).UpdateSimple(
+		m.Enabled.Value(true),
+		m.EnabledAt.Value(time.Now()),
+		m.RecoveryCodes.Value(datatypes.NewJSONType(hashes)),
+	); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// rejectAPIToken 两步验证的配置只能在登录会话中修改，不能使用访问令牌
+func rejectAPIToken(c *gin.Context) bool {
+	if _, ok := util.GetAPITokenID(c); ok {
+		resputil.HTTPError(c, http.StatusForbidden, "Two-factor authentication can not be changed with an API token",
+			resputil.UserNotAllowed)
+		return true
+	}
+	return false
+}
+
+// VerifyMFALogin godoc
+//
+//	@Summary		登录第二步：校验两步验证码
+//	@Description	使用登录返回的 mfaToken 和验证器中的验证码 (或恢复码) 完成登录
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			data	body		MFAVerifyReq					true	"挑战令牌和验证码"
+//	@Success		200		{object}	resputil.Response[LoginResp]	"登录成功"
+//	@Failure		400		{object}	resputil.Response[any]			"请求参数错误"
+//	@Failure		401		{object}	resputil.Response[any]			"验证码错误或挑战令牌无效"
+//	@Failure		429		{object}	resputil.Response[any]			"失败次数过多"
+//	@Router			/auth/mfa/verify [post]
+func (mgr *AuthMgr) VerifyMFALogin(c *gin.Context) {
+	var req MFAVerifyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		resputil.BadRequestError(c, "Code or recovery code not provided")
+		return
+	}
+
+	user, ok := mgr.userFromMFAToken(c, req.MFAToken, util.MFAPurposeVerify)
+	if !ok {
+		return
+	}
+	mfa, err := getUserMFA(c, user.ID)
+	if err != nil || !mfa.Enabled {
+		resputil.HTTPError(c, http.StatusUnauthorized, errMFANotEnabled.Error(), resputil.TokenInvalid)
+		return
+	}
+	if err = verifyMFACode(c, mfa, req.Code, req.RecoveryCode); err != nil {
+		respondMFAError(c, err, http.StatusUnauthorized)
+		return
+	}
+
+	resp, ok := mgr.issueLoginTokens(c, user)
+	if !ok {
+		return
+	}
+	resputil.Success(c, resp)
+}
+
+// SetupMFALogin godoc
+//
+//	@Summary		登录时绑定验证器
+//	@Description	平台要求启用两步验证但用户尚未绑定时，使用登录返回的 mfaToken 获取 TOTP 密钥
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			data	body		MFATokenReq							true	"挑战令牌"
+//	@Success		200		{object}	resputil.Response[MFAEnrollResp]	"TOTP 密钥和二维码地址"
+//	@Failure		401		{object}	resputil.Response[any]				"挑战令牌无效"
+//	@Router			/auth/mfa/setup [post]
+func (mgr *AuthMgr) SetupMFALogin(c *gin.Context) {
+	var req MFATokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	user, ok := mgr.userFromMFAToken(c, req.MFAToken, util.MFAPurposeSetup)
+	if !ok {
+		return
+	}
+	resp, err := enrollMFA(c, user)
+	if err != nil {
+		respondMFAError(c, err, http.StatusBadRequest)
+		return
+	}
+	resputil.Success(c, resp)
+}
+
+// ConfirmMFALoginSetup godoc
+//
+//	@Summary		登录时确认绑定验证器
+//	@Description	输入验证器中的验证码启用两步验证并完成登录，响应中包含只展示一次的恢复码
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			data	body		MFAConfirmReq					true	"挑战令牌和验证码"
+//	@Success		200		{object}	resputil.Response[LoginResp]	"登录成功"
+//	@Failure		401		{object}	resputil.Response[any]			"验证码错误或挑战令牌无效"
+//	@Failure		429		{object}	resputil.Response[any]			"失败次数过多"
+//	@Router			/auth/mfa/setup/confirm [post]
+func (mgr *AuthMgr) ConfirmMFALoginSetup(c *gin.Context) {
+	var req MFAConfirmReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	user, ok := mgr.userFromMFAToken(c, req.MFAToken, util.MFAPurposeSetup)
+	if !ok {
+		return
+	}
+	codes, err := enableMFA(c, user.ID, req.Code)
+	if err != nil {
+		respondMFAError(c, err, http.StatusUnauthorized)
+		return
+	}
+
+	resp, ok := mgr.issueLoginTokens(c, user)
+	if !ok {
+		return
+	}
+	resp.RecoveryCodes = codes
+	resputil.Success(c, resp)
+}
+
+// GetMFAStatus godoc
+//
+//	@Summary		获取两步验证状态
+//	@Description	返回当前用户是否启用了两步验证以及剩余的恢复码数量
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[MFAStatusResp]	"两步验证状态"
+//	@Failure		500	{object}	resputil.Response[any]				"其他错误"
+//	@Router			/v1/auth/mfa [get]
+func (mgr *AuthMgr) GetMFAStatus(c *gin.Context) {
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.Error(c, "User not found", resputil.NotSpecified)
+		return
+	}
+
+	resp := MFAStatusResp{Required: mfaRequiredFor(user)}
+	mfa, err := getUserMFA(c, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	if err == nil && mfa.Enabled {
+		resp.Enabled = true
+		resp.EnabledAt = mfa.EnabledAt
+		resp.RecoveryCodesLeft = len(mfa.RecoveryCodes.Data())
+	}
+	resputil.Success(c, resp)
+}
+
+// EnrollMFA godoc
+//
+//	@Summary		绑定验证器
+//	@Description	生成新的 TOTP 密钥，需要调用 /v1/auth/mfa/enable 输入验证码后才会启用
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[MFAEnrollResp]	"TOTP 密钥和二维码地址"
+//	@Failure		400	{object}	resputil.Response[any]				"已启用两步验证"
+//	@Failure		403	{object}	resputil.Response[any]				"不能使用访问令牌"
+//	@Router			/v1/auth/mfa/enroll [post]
+func (mgr *AuthMgr) EnrollMFA(c *gin.Context) {
+	if rejectAPIToken(c) {
+		return
+	}
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.Error(c, "User not found", resputil.NotSpecified)
+		return
+	}
+	resp, err := enrollMFA(c, user)
+	if err != nil {
+		respondMFAError(c, err, http.StatusBadRequest)
+		return
+	}
+	resputil.Success(c, resp)
+}
+
+// EnableMFA godoc
+//
+//	@Summary		启用两步验证
+//	@Description	输入验证器中的验证码确认绑定，返回只展示一次的恢复码
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		MFACodeReq								true	"验证码"
+//	@Success		200		{object}	resputil.Response[MFARecoveryCodesResp]	"恢复码"
+//	@Failure		400		{object}	resputil.Response[any]					"验证码错误"
+//	@Failure		429		{object}	resputil.Response[any]					"失败次数过多"
+//	@Router			/v1/auth/mfa/enable [post]
+func (mgr *AuthMgr) EnableMFA(c *gin.Context) {
+	if rejectAPIToken(c) {
+		return
+	}
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		resputil.BadRequestError(c, "Code not provided")
+		return
+	}
+
+	token := util.GetToken(c)
+	codes, err := enableMFA(c, token.UserID, req.Code)
+	if err != nil {
+		respondMFAError(c, err, http.StatusBadRequest)
+		return
+	}
+	resputil.Success(c, MFARecoveryCodesResp{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+//
+//	@Summary		关闭两步验证
+//	@Description	输入验证码或恢复码后关闭两步验证，平台要求启用两步验证的用户不能关闭
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		MFACodeReq					true	"验证码或恢复码"
+//	@Success		200		{object}	resputil.Response[string]	"关闭成功"
+//	@Failure		400		{object}	resputil.Response[any]		"验证码错误"
+//	@Failure		403		{object}	resputil.Response[any]		"平台要求启用两步验证"
+//	@Failure		429		{object}	resputil.Response[any]		"失败次数过多"
+//	@Router			/v1/auth/mfa/disable [post]
+func (mgr *AuthMgr) DisableMFA(c *gin.Context) {
+	if rejectAPIToken(c) {
+		return
+	}
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		resputil.BadRequestError(c, "Code or recovery code not provided")
+		return
+	}
+
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.Error(c, "User not found", resputil.NotSpecified)
+		return
+	}
+	if mfaRequiredFor(user) {
+		resputil.HTTPError(c, http.StatusForbidden, "Two-factor authentication is required for admins", resputil.UserNotAllowed)
+		return
+	}
+	mfa, err := getUserMFA(c, user.ID)
+	if err != nil || !mfa.Enabled {
+		respondMFAError(c, errMFANotEnabled, http.StatusBadRequest)
+		return
+	}
+	if err = verifyMFACode(c, mfa, req.Code, req.RecoveryCode); err != nil {
+		respondMFAError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	m := query.UserMFA
+	if _, err = m.WithContext(c).Unscoped().Where(m.ID.Eq(mfa.ID)).Delete(); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "Two-factor authentication disabled")
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		重新生成恢复码
+//	@Description	输入验证码后生成新的恢复码，之前的恢复码全部失效
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		MFACodeReq								true	"验证码"
+//	@Success		200		{object}	resputil.Response[MFARecoveryCodesResp]	"新的恢复码"
+//	@Failure		400		{object}	resputil.Response[any]					"验证码错误"
+//	@Failure		429		{object}	resputil.Response[any]					"失败次数过多"
+//	@Router			/v1/auth/mfa/recovery-codes [post]
+func (mgr *AuthMgr) RegenerateRecoveryCodes(c *gin.Context) {
+	if rejectAPIToken(c) {
+		return
+	}
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		resputil.BadRequestError(c, "Code not provided")
+		return
+	}
+
+	token := util.GetToken(c)
+	mfa, err := getUserMFA(c, token.UserID)
+	if err != nil || !mfa.Enabled {
+		respondMFAError(c, errMFANotEnabled, http.StatusBadRequest)
+		return
+	}
+	if err = verifyMFACode(c, mfa, req.Code, ""); err != nil {
+		respondMFAError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	codes, hashes, err := util.GenerateRecoveryCodes()
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	m := query.UserMFA
+	if _, err = m.WithContext(c).Where(m.ID.Eq(mfa.ID)).
+		UpdateSimple(m.RecoveryCodes.Value(datatypes.NewJSONType(hashes))); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, MFARecoveryCodesResp{RecoveryCodes: codes})
+}
+
+// ResetUserMFA godoc
+//
+//	@Summary		重置用户的两步验证
+//	@Description	用户丢失验证器和恢复码时，管理员删除其两步验证配置并撤销所有会话。被要求启用两步验证的用户下次登录时需要重新绑定
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string						true	"username"
+//	@Success		200		{object}	resputil.Response[string]	"重置成功"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/admin/users/{name}/mfa [delete]
+func (mgr *UserMgr) ResetUserMFA(c *gin.Context) {
+	var nameReq UserNameReq
+	if err := c.ShouldBindUri(&nameReq); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(nameReq.Name)).First()
+	if err != nil {
+		resputil.Error(c, "user not found", resputil.NotSpecified)
+		return
+	}
+	if !checkManagedUser(c, user) {
+		return
+	}
+
+	m := query.UserMFA
+	if _, err = m.WithContext(c).Unscoped().Where(m.UserID.Eq(user.ID)).Delete(); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	if _, err = revokeSessions(c, user.ID); err != nil {
+		klog.Errorf("revoke sessions of user %s after resetting mfa: %v", user.Name, err)
+	}
+	resputil.Success(c, "Two-factor authentication reset")
+}
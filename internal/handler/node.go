@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"k8s.io/klog/v2"
 
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/pkg/crclient"
 )
@@ -74,16 +76,18 @@ func (mgr *NodeMgr) RegisterProtected(g *gin.RouterGroup) {
 
 //nolint:dupl // ignore duplicate code
 func (mgr *NodeMgr) RegisterAdmin(g *gin.RouterGroup) {
-	g.GET("", mgr.ListNode)
-	g.GET("/:name/pods", mgr.GetPodsForNode)
-	g.GET("/:name/gpu", mgr.ListNodeGPUInfo)
-	g.GET("/:name/mark", mgr.GetNodeMarks)
-	g.POST("/:name/label", mgr.AddNodeLabel)
-	g.DELETE("/:name/label", mgr.DeleteNodeLabel)
-	g.POST("/:name/annotation", mgr.AddNodeAnnotation)
-	g.DELETE("/:name/annotation", mgr.DeleteNodeAnnotation)
-	g.POST("/:name/taint", mgr.AddNodeTaint)
-	g.DELETE("/:name/taint", mgr.DeleteNodeTaint)
+	read := middleware.RequirePermission(model.PermissionNodesRead)
+	write := middleware.RequirePermission(model.PermissionNodesWrite)
+	g.GET("", read, mgr.ListNode)
+	g.GET("/:name/pods", read, mgr.GetPodsForNode)
+	g.GET("/:name/gpu", read, mgr.ListNodeGPUInfo)
+	g.GET("/:name/mark", read, mgr.GetNodeMarks)
+	g.POST("/:name/label", write, mgr.AddNodeLabel)
+	g.DELETE("/:name/label", write, mgr.DeleteNodeLabel)
+	g.POST("/:name/annotation", write, mgr.AddNodeAnnotation)
+	g.DELETE("/:name/annotation", write, mgr.DeleteNodeAnnotation)
+	g.POST("/:name/taint", write, mgr.AddNodeTaint)
+	g.DELETE("/:name/taint", write, mgr.DeleteNodeTaint)
 }
 
 // ListNode godoc
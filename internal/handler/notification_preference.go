@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/clause"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+type (
+	NotificationPreferenceReq struct {
+		AlertTypes              []model.AlertTypePreference `json:"alertTypes" binding:"required"` // 各类型通知的接收设置
+		QuietHoursEnabled       bool                        `json:"quietHoursEnabled"`             // 是否启用免打扰时段
+		QuietHoursStart         string                      `json:"quietHoursStart"`               // 免打扰开始时间 (HH:MM)
+		QuietHoursEnd           string                      `json:"quietHoursEnd"`                 // 免打扰结束时间 (HH:MM)
+		ReceiveAccountReminders bool                        `json:"receiveAccountReminders"`       // 作为账户管理员时是否接收成员作业的清理提醒
+		DailyDigestChannels     []model.NotificationChannel `json:"dailyDigestChannels"`           // 订阅每日作业摘要的渠道，为空表示不订阅
+		WeeklyDigestChannels    []model.NotificationChannel `json:"weeklyDigestChannels"`          // 订阅每周作业摘要的渠道，为空表示不订阅
+	}
+
+	NotificationPreferenceResp struct {
+		NotificationPreferenceReq
+		EnabledChannels []model.NotificationChannel `json:"enabledChannels"` // 平台启用的通知渠道
+	}
+)
+
+// GetNotificationPreference godoc
+//
+//	@Summary		Get notification preference
+//	@Description	Get the notification preference of the current user, defaults are returned if not set
+//	@Tags			Context
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[NotificationPreferenceResp]	"Notification preference"
+//	@Failure		400	{object}	resputil.Response[any]							"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]							"Other errors"
+//	@Router			/v1/context/notification/preference [get]
+func (mgr *ContextMgr) GetNotificationPreference(c *gin.Context) {
+	token := util.GetToken(c)
+	pref, err := alert.GetNotificationPreference(c, token.UserID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("Failed to get notification preference: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	alertTypes := make([]model.AlertTypePreference, 0)
+	for _, alertType := range model.GetAllAlertTypes() {
+		alertTypes = append(alertTypes, pref.GetAlertTypePreference(alertType))
+	}
+
+	resputil.Success(c, NotificationPreferenceResp{
+		NotificationPreferenceReq: NotificationPreferenceReq{
+			AlertTypes:              alertTypes,
+			QuietHoursEnabled:       pref.QuietHoursEnabled,
+			QuietHoursStart:         pref.QuietHoursStart,
+			QuietHoursEnd:           pref.QuietHoursEnd,
+			ReceiveAccountReminders: pref.ReceiveAccountReminders,
+			DailyDigestChannels:     pref.DailyDigestChannels.Data(),
+			WeeklyDigestChannels:    pref.WeeklyDigestChannels.Data(),
+		},
+		EnabledChannels: alert.GetAlertMgr().GetEnabledChannels(),
+	})
+}
+
+// UpdateNotificationPreference godoc
+//
+//	@Summary		Update notification preference
+//	@Description	Update which alert types to receive, on which channels, quiet hours, account reminders and digests
+//	@Tags			Context
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		NotificationPreferenceReq	true	"Notification preference"
+//	@Success		200		{object}	resputil.Response[any]		"Notification preference updated"
+//	@Failure		400		{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/context/notification/preference [put]
+func (mgr *ContextMgr) UpdateNotificationPreference(c *gin.Context) {
+	token := util.GetToken(c)
+
+	var req NotificationPreferenceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if err := validateNotificationPreference(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	pref := &model.NotificationPreference{
+		UserID:                  token.UserID,
+		AlertTypes:              datatypes.NewJSONType(req.AlertTypes),
+		QuietHoursEnabled:       req.QuietHoursEnabled,
+		QuietHoursStart:         req.QuietHoursStart,
+		QuietHoursEnd:           req.QuietHoursEnd,
+		ReceiveAccountReminders: req.ReceiveAccountReminders,
+		DailyDigestChannels:     datatypes.NewJSONType(req.DailyDigestChannels),
+		WeeklyDigestChannels:    datatypes.NewJSONType(req.WeeklyDigestChannels),
+	}
+	p := query.NotificationPreference
+	err := p.WithContext(c).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "alert_types", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "receive_account_reminders",
+			"daily_digest_channels", "weekly_digest_channels",
+		}),
+	}).Create(pref)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("Failed to update notification preference: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	klog.Infof("update notification preference success, user: %s", token.Username)
+	resputil.Success(c, "Notification preference updated successfully")
+}
+
+func validateNotificationPreference(req *NotificationPreferenceReq) error {
+	allAlertTypes := model.GetAllAlertTypes()
+	allChannels := model.GetAllNotificationChannels()
+	for _, pref := range req.AlertTypes {
+		if !lo.Contains(allAlertTypes, pref.AlertType) {
+			return fmt.Errorf("unknown alert type: %d", pref.AlertType)
+		}
+		for _, channel := range pref.Channels {
+			if !lo.Contains(allChannels, channel) {
+				return fmt.Errorf("unknown notification channel: %s", channel)
+			}
+		}
+	}
+	for _, channel := range append(req.DailyDigestChannels, req.WeeklyDigestChannels...) {
+		if !lo.Contains(allChannels, channel) {
+			return fmt.Errorf("unknown notification channel: %s", channel)
+		}
+	}
+	if req.QuietHoursEnabled {
+		if _, err := model.ParseClock(req.QuietHoursStart); err != nil {
+			return err
+		}
+		if _, err := model.ParseClock(req.QuietHoursEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/oidc"
+)
+
+const (
+	// oidcStateCookie 保存登录状态的 Cookie，回调时用于校验 state 并取回 nonce
+	oidcStateCookie = "crater_oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+// getOIDCProvider 返回缓存的身份提供方，首次使用时通过 Discovery 创建，失败时下次请求重试
+func (mgr *AuthMgr) getOIDCProvider(c context.Context) (*oidc.Provider, error) {
+	mgr.oidcMu.Lock()
+	defer mgr.oidcMu.Unlock()
+	if mgr.oidcProvider != nil {
+		return mgr.oidcProvider, nil
+	}
+
+	cfg := config.GetConfig().OIDC
+	if !cfg.Enable {
+		return nil, errors.New("oidc login is not enabled")
+	}
+	provider, err := oidc.NewProvider(c, cfg.Issuer, cfg.ClientID, cfg.ClientSecret, cfg.RedirectURL, cfg.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	mgr.oidcProvider = provider
+	return provider, nil
+}
+
+// OIDCAuthorize godoc
+//
+//	@Summary		跳转到 OIDC 登录页
+//	@Description	生成 state 与 nonce 并写入 Cookie，重定向到身份提供方的授权页面，回调页面再以 oidc 方式调用登录接口
+//	@Tags			Auth
+//	@Success		302
+//	@Failure		400	{object}	resputil.Response[any]	"未启用 OIDC 登录"
+//	@Failure		500	{object}	resputil.Response[any]	"无法连接身份提供方"
+//	@Router			/auth/oidc/authorize [get]
+func (mgr *AuthMgr) OIDCAuthorize(c *gin.Context) {
+	if !config.GetConfig().OIDC.Enable {
+		resputil.BadRequestError(c, "OIDC login is not enabled")
+		return
+	}
+	provider, err := mgr.getOIDCProvider(c)
+	if err != nil {
+		klog.Errorf("failed to discover oidc provider: %v", err)
+		resputil.Error(c, "Can't connect to OIDC provider", resputil.NotSpecified)
+		return
+	}
+
+	state, nonce, cookie, err := oidc.NewState(config.GetConfig().Auth.AccessTokenSecret, oidcStateTTL)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, cookie, int(oidcStateTTL.Seconds()), "/", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, provider.AuthCodeURL(state, nonce))
+}
+
+// oidcAuth 校验回调的 state，用授权码换取用户声明并填充用户属性，返回身份提供方中的用户信息
+func (mgr *AuthMgr) oidcAuth(c *gin.Context, code, state string, attr *model.UserAttribute) (*oidc.UserInfo, error) {
+	cfg := config.GetConfig().OIDC
+	if !cfg.Enable {
+		return nil, errors.New("oidc login is not enabled")
+	}
+
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil {
+		return nil, fmt.Errorf("login state cookie not found: %w", err)
+	}
+	// state 只能使用一次
+	c.SetCookie(oidcStateCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+	nonce, err := oidc.VerifyState(config.GetConfig().Auth.AccessTokenSecret, cookie, state)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := mgr.getOIDCProvider(c)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := provider.Exchange(c, code, nonce)
+	if err != nil {
+		return nil, err
+	}
+	info, err := oidc.MapClaims(claims, oidcClaimMapping())
+	if err != nil {
+		return nil, err
+	}
+
+	attr.Name = info.Username
+	attr.Nickname = info.Nickname
+	if info.Email != "" {
+		attr.Email = ptr.To(info.Email)
+	}
+	return info, nil
+}
+
+// ErrorOIDCNotLinked 同名用户已存在但未绑定该 OIDC 身份
+var ErrorOIDCNotLinked = errors.New("a user with the same name exists and is not linked to this oidc identity")
+
+// getOrCreateOIDCUser 按 issuer + subject 查找绑定的用户，不存在时创建新用户并绑定。
+// 用户名声明通常可以由用户自行修改，不能用来匹配已有用户：同名用户已存在时拒绝登录，
+// 需要该用户先用原有方式登录，再通过 /v1/auth/oidc/link 显式绑定
+func (mgr *AuthMgr) getOrCreateOIDCUser(
+	c context.Context,
+	info *oidc.UserInfo,
+	attr *model.UserAttribute,
+) (*model.User, error) {
+	u := query.User
+	user, err := u.WithContext(c).Where(u.OIDCIssuer.Eq(info.Issuer), u.OIDCSubject.Eq(info.Subject)).First()
+	if err == nil {
+		// 用户名以平台中的记录为准
+		attr.Name = user.Name
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 用户名同时用于命名空间中的资源，需要满足 DNS-1123 规范
+	if errs := validation.IsDNS1123Label(attr.Name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid username %q: %v", attr.Name, errs)
+	}
+	if _, err = u.WithContext(c).Where(u.Name.Eq(attr.Name)).First(); err == nil {
+		return nil, ErrorOIDCNotLinked
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user, err = mgr.createUser(c, attr.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = u.WithContext(c).Where(u.ID.Eq(user.ID)).UpdateSimple(
+		u.OIDCIssuer.Value(info.Issuer),
+		u.OIDCSubject.Value(info.Subject),
+	); err != nil {
+		return nil, err
+	}
+	user.OIDCIssuer = ptr.To(info.Issuer)
+	user.OIDCSubject = ptr.To(info.Subject)
+	return user, nil
+}
+
+type OIDCLinkReq struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// LinkOIDC godoc
+//
+//	@Summary		绑定 OIDC 身份
+//	@Description	已登录用户跳转到 OIDC 授权页面，回调页面以授权码调用该接口，将身份提供方中的用户绑定到当前账号，之后可以使用 OIDC 登录
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		OIDCLinkReq					true	"授权码和 state"
+//	@Success		200		{object}	resputil.Response[string]	"绑定成功"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403		{object}	resputil.Response[any]		"该身份已绑定其他用户"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/auth/oidc/link [post]
+func (mgr *AuthMgr) LinkOIDC(c *gin.Context) {
+	var req OIDCLinkReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var attr model.UserAttribute
+	info, err := mgr.oidcAuth(c, req.Code, req.State, &attr)
+	if err != nil {
+		klog.Errorf("oidc link failed: %v", err)
+		resputil.Error(c, "Invalid OIDC login", resputil.InvalidCredentials)
+		return
+	}
+
+	token := util.GetToken(c)
+	u := query.User
+	linked, err := u.WithContext(c).Where(u.OIDCIssuer.Eq(info.Issuer), u.OIDCSubject.Eq(info.Subject)).First()
+	switch {
+	case err == nil && linked.ID != token.UserID:
+		resputil.Error(c, "OIDC identity is linked to another user", resputil.UserNotAllowed)
+		return
+	case err == nil:
+		resputil.Success(c, "")
+		return
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		resputil.Error(c, fmt.Sprintf("get user failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	if _, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).UpdateSimple(
+		u.OIDCIssuer.Value(info.Issuer),
+		u.OIDCSubject.Value(info.Subject),
+	); err != nil {
+		resputil.Error(c, fmt.Sprintf("link oidc identity failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	klog.Infof("user %s linked oidc identity %s of %s", token.Username, info.Subject, info.Issuer)
+	resputil.Success(c, "")
+}
+
+func oidcClaimMapping() oidc.ClaimMapping {
+	claims := config.GetConfig().OIDC.Claims
+	mapping := oidc.ClaimMapping{
+		Username: claims.Username,
+		Nickname: claims.Nickname,
+		Email:    claims.Email,
+		Groups:   claims.Groups,
+	}
+	if mapping.Username == "" {
+		mapping.Username = "preferred_username"
+	}
+	if mapping.Nickname == "" {
+		mapping.Nickname = "name"
+	}
+	if mapping.Email == "" {
+		mapping.Email = "email"
+	}
+	if mapping.Groups == "" {
+		mapping.Groups = "groups"
+	}
+	return mapping
+}
+
+// syncOIDCGroupAccounts 将用户加入其所属组映射的账户，已在账户中的用户保持原有角色与权限
+func syncOIDCGroupAccounts(c context.Context, userID uint, groups []string) error {
+	groupAccounts := config.GetConfig().OIDC.GroupAccounts
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if name, ok := groupAccounts[group]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	a := query.Account
+	uq := query.UserAccount
+	accounts, err := a.WithContext(c).Where(a.Name.In(names...)).Find()
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if _, err := uq.WithContext(c).Where(uq.UserID.Eq(userID), uq.AccountID.Eq(account.ID)).First(); err == nil {
+			continue
+		}
+		if err := uq.WithContext(c).Create(&model.UserAccount{
+			UserID:     userID,
+			AccountID:  account.ID,
+			Role:       model.RoleUser,
+			AccessMode: model.AccessModeRW,
+		}); err != nil {
+			return err
+		}
+		klog.Infof("user %d joined account %s by oidc group mapping", userID, account.Name)
+	}
+	return nil
+}
@@ -12,7 +12,9 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/pkg/ldapsync"
 )
 
 type CronjobConfigs struct {
@@ -191,3 +193,46 @@ func (cm *OperationsMgr) DeleteCronjobRecords(c *gin.Context) {
 		"deleted": fmt.Sprintf("%d", deleted),
 	})
 }
+
+type PreviewLDAPSyncReq struct {
+	Name string `json:"name" binding:"required"` // LDAP 组同步定时任务的名称
+}
+
+// PreviewLDAPSync godoc
+//
+//	@Summary		试运行 LDAP 组同步
+//	@Description	按定时任务当前的配置读取 LDAP 组并与账户成员比较，返回差异报告，不修改账户成员
+//	@Tags			Operations
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		PreviewLDAPSyncReq						true	"定时任务名称"
+//	@Success		200		{object}	resputil.Response[ldapsync.Report]	"差异报告"
+//	@Failure		400		{object}	resputil.Response[any]					"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]					"其他错误"
+//	@Router			/v1/operations/cronjob/ldap-sync/preview [post]
+func (mgr *OperationsMgr) PreviewLDAPSync(c *gin.Context) {
+	var req PreviewLDAPSyncReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	cj := query.CronJobConfig
+	job, err := cj.WithContext(c).Where(cj.Name.Eq(req.Name)).First()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("cronjob %s not found", req.Name), resputil.InvalidRequest)
+		return
+	}
+	if job.Type != model.CronJobTypeLDAPSyncFunc {
+		resputil.BadRequestError(c, fmt.Sprintf("cronjob %s is not a ldap sync job", req.Name))
+		return
+	}
+
+	report, err := ldapsync.Preview(c, job.Config)
+	if err != nil && report == nil {
+		resputil.Error(c, err.Error(), resputil.ServiceError)
+		return
+	}
+	resputil.Success(c, report)
+}
@@ -5,7 +5,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/pkg/aitaskctl"
 	"github.com/raids-lab/crater/pkg/cronjob"
 	"github.com/raids-lab/crater/pkg/monitor"
@@ -50,17 +52,20 @@ func (mgr *OperationsMgr) RegisterProtected(_ *gin.RouterGroup) {
 }
 
 func (mgr *OperationsMgr) RegisterAdmin(g *gin.RouterGroup) {
-	g.GET("/whitelist", mgr.GetWhiteList)
-	g.PUT("/keep/:name", mgr.SetKeepWhenLowResourceUsage)
-	g.GET("/cronjob", mgr.GetCronjobConfigs)
-	g.PUT("/cronjob", mgr.UpdateCronjobConfig)
-	g.PUT("/add/locktime", mgr.AddLockTime)
-	g.PUT("/clear/locktime", mgr.ClearLockTime)
+	editJobs := middleware.RequirePermission(model.PermissionJobsEditAny)
+	editCronjobs := middleware.RequirePermission(model.PermissionCronjobsEdit)
+	g.GET("/whitelist", editJobs, mgr.GetWhiteList)
+	g.PUT("/keep/:name", editJobs, mgr.SetKeepWhenLowResourceUsage)
+	g.GET("/cronjob", editCronjobs, mgr.GetCronjobConfigs)
+	g.PUT("/cronjob", editCronjobs, mgr.UpdateCronjobConfig)
+	g.PUT("/add/locktime", editJobs, mgr.AddLockTime)
+	g.PUT("/clear/locktime", editJobs, mgr.ClearLockTime)
 
-	g.POST("/cronjob/config/name", mgr.GetCronjobNames)
-	g.POST("/cronjob/record/time", mgr.GetCronjobRecordTimeRange)
-	g.POST("/cronjob/record/list", mgr.GetCronjobRecords)
-	g.POST("/cronjob/record/delete", mgr.DeleteCronjobRecords)
+	g.POST("/cronjob/config/name", editCronjobs, mgr.GetCronjobNames)
+	g.POST("/cronjob/record/time", editCronjobs, mgr.GetCronjobRecordTimeRange)
+	g.POST("/cronjob/record/list", editCronjobs, mgr.GetCronjobRecords)
+	g.POST("/cronjob/record/delete", editCronjobs, mgr.DeleteCronjobRecords)
+	g.POST("/cronjob/ldap-sync/preview", editCronjobs, mgr.PreviewLDAPSync)
 }
 
 func (cm *OperationsMgr) StopCron() {
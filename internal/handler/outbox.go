@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewOutboxMgr)
+}
+
+// defaultOutboxPageSize 默认每页通知数
+const defaultOutboxPageSize = 20
+
+type OutboxMgr struct {
+	name string
+}
+
+func NewOutboxMgr(_ *RegisterConfig) Manager {
+	return &OutboxMgr{
+		name: "notifications",
+	}
+}
+
+func (mgr *OutboxMgr) GetName() string { return mgr.name }
+
+func (mgr *OutboxMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *OutboxMgr) RegisterProtected(_ *gin.RouterGroup) {}
+
+func (mgr *OutboxMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionAlertsManage))
+	g.GET("/outbox", mgr.ListOutboxMessages)
+	g.GET("/outbox/:id", mgr.GetOutboxMessage)
+	g.POST("/outbox/:id/resend", mgr.ResendOutboxMessage)
+}
+
+type (
+	ListOutboxMessagesReq struct {
+		Status   *model.OutboxStatus `form:"status"`   // 按投递状态过滤
+		Username *string             `form:"username"` // 按接收者用户名过滤
+		Page     int                 `form:"page"`     // 页码，从 0 开始
+		PageSize int                 `form:"pageSize"` // 每页大小
+	}
+
+	ListOutboxMessagesResp struct {
+		Messages []*model.OutboxMessage `json:"messages"`
+		Total    int64                  `json:"total"`
+	}
+
+	OutboxMessageIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+)
+
+// ListOutboxMessages godoc
+//
+//	@Summary		List outbox messages
+//	@Description	List notifications in the outbox with their delivery status
+//	@Tags			Notification
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			status		query		string										false	"pending, sending, sent or failed"
+//	@Param			username	query		string										false	"receiver username"
+//	@Param			page		query		int											false	"page number, starts from 0"
+//	@Param			pageSize	query		int											false	"page size"
+//	@Success		200			{object}	resputil.Response[ListOutboxMessagesResp]	"Outbox messages"
+//	@Failure		400			{object}	resputil.Response[any]						"Request parameter error"
+//	@Failure		500			{object}	resputil.Response[any]						"Other errors"
+//	@Router			/v1/admin/notifications/outbox [get]
+func (mgr *OutboxMgr) ListOutboxMessages(c *gin.Context) {
+	var req ListOutboxMessagesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultOutboxPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+
+	o := query.OutboxMessage
+	q := o.WithContext(c)
+	if req.Status != nil {
+		q = q.Where(o.Status.Eq(string(*req.Status)))
+	}
+	if req.Username != nil {
+		u := query.User
+		user, err := u.WithContext(c).Where(u.Name.Eq(*req.Username)).First()
+		if err != nil {
+			resputil.Error(c, fmt.Sprintf("get user failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+		q = q.Where(o.UserID.Eq(user.ID))
+	}
+	messages, total, err := q.Order(o.ID.Desc()).FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list outbox messages failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, ListOutboxMessagesResp{Messages: messages, Total: total})
+}
+
+// GetOutboxMessage godoc
+//
+//	@Summary		Get outbox message
+//	@Description	Get a notification in the outbox with its delivery log
+//	@Tags			Notification
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint									true	"message id"
+//	@Success		200	{object}	resputil.Response[model.OutboxMessage]	"Outbox message"
+//	@Failure		400	{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/admin/notifications/outbox/{id} [get]
+func (mgr *OutboxMgr) GetOutboxMessage(c *gin.Context) {
+	var req OutboxMessageIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	o := query.OutboxMessage
+	msg, err := o.WithContext(c).Where(o.ID.Eq(req.ID)).First()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("get outbox message failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, msg)
+}
+
+// ResendOutboxMessage godoc
+//
+//	@Summary		Resend outbox message
+//	@Description	Put a failed or sent notification back into the outbox for delivery
+//	@Tags			Notification
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"message id"
+//	@Success		200	{object}	resputil.Response[string]	"Resend scheduled"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/admin/notifications/outbox/{id}/resend [post]
+func (mgr *OutboxMgr) ResendOutboxMessage(c *gin.Context) {
+	var req OutboxMessageIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := alert.ResendOutboxMessage(c, req.ID); err != nil {
+		resputil.Error(c, fmt.Sprintf("resend outbox message failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	klog.Infof("outbox message %d rescheduled by %s", req.ID, util.GetToken(c).Username)
+	resputil.Success(c, "")
+}
@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+const (
+	// passwordResetCodeTTL 忘记密码验证码的有效期
+	passwordResetCodeTTL = 15 * time.Minute
+	// passwordResetResendInterval 同一用户两次发送验证码的最小间隔
+	passwordResetResendInterval = time.Minute
+	// passwordResetHourlyLimit 同一用户每小时最多发送的验证码数量
+	passwordResetHourlyLimit = 5
+	// passwordResetMaxAttempts 每个验证码允许的最大失败次数，超过后验证码失效
+	passwordResetMaxAttempts = 5
+	// passwordResetConcurrency 后台同时处理的找回密码请求数上限，超出时丢弃请求
+	passwordResetConcurrency = 8
+	// passwordResetSendTimeout 后台查询用户并发送验证码的超时时间
+	passwordResetSendTimeout = time.Minute
+	// passwordRateLimitInterval 和 passwordRateLimitBurst 限制同一 IP 请求找回和重置密码的频率
+	passwordRateLimitInterval = 12 * time.Second
+	passwordRateLimitBurst    = 5
+)
+
+// passwordResetSlots 限制后台处理找回密码请求的并发数
+var passwordResetSlots = make(chan struct{}, passwordResetConcurrency)
+
+var errPasswordReused = errors.New("password was used recently, please choose a different one")
+
+type (
+	ForgotPasswordReq struct {
+		Username string `json:"username" binding:"required"`
+	}
+
+	ResetPasswordReq struct {
+		Username string `json:"username" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	ChangePasswordReq struct {
+		OldPassword string `json:"oldPassword" binding:"required"`
+		NewPassword string `json:"newPassword" binding:"required"`
+	}
+)
+
+// ForgotPassword godoc
+//
+//	@Summary		忘记密码
+//	@Description	向普通认证用户的邮箱发送重置密码的验证码，为避免泄露用户是否存在，总是返回成功
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			data	body		ForgotPasswordReq			true	"用户名"
+//	@Success		200		{object}	resputil.Response[string]	"如果用户存在且绑定了邮箱，验证码已发送"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Router			/auth/password/forgot [post]
+func (mgr *AuthMgr) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	// 查询用户和发送邮件在后台进行，响应时间不随用户是否存在而变化
+	select {
+	case passwordResetSlots <- struct{}{}:
+		go func(username string) {
+			defer func() { <-passwordResetSlots }()
+			ctx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+			defer cancel()
+			if err := sendPasswordResetCode(ctx, username); err != nil {
+				klog.Warningf("send password reset code to %s: %v", username, err)
+			}
+		}(req.Username)
+	default:
+		klog.Warningf("too many pending password reset requests, drop the request of %s", req.Username)
+	}
+	resputil.Success(c, "If the user exists and has a verified email, a verification code has been sent")
+}
+
+// sendPasswordResetCode 生成并发送重置密码的验证码，返回的错误只用于记录日志
+func sendPasswordResetCode(c context.Context, username string) error {
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(username)).First()
+	if err != nil {
+		return err
+	}
+	if user.Password == nil {
+		return errors.New("user does not use normal auth")
+	}
+	receiver := user.Attributes.Data()
+	if receiver.Email == nil || *receiver.Email == "" {
+		return errors.New("user has no email")
+	}
+
+	r := query.PasswordReset
+	now := time.Now()
+	recent, err := r.WithContext(c).Where(r.UserID.Eq(user.ID), r.CreatedAt.Gte(now.Add(-time.Hour))).
+		Order(r.CreatedAt.Desc()).Find()
+	if err != nil {
+		return err
+	}
+	if len(recent) >= passwordResetHourlyLimit {
+		return errors.New("too many reset codes requested in the last hour")
+	}
+	if len(recent) > 0 && now.Sub(recent[0].CreatedAt) < passwordResetResendInterval {
+		return errors.New("reset code requested too frequently")
+	}
+
+	code, hash, err := util.GenerateResetCode(user.ID)
+	if err != nil {
+		return err
+	}
+	// 新的验证码生成后，之前未使用的验证码全部作废
+	if _, err = r.WithContext(c).Where(r.UserID.Eq(user.ID), r.UsedAt.IsNull()).
+		UpdateSimple(r.UsedAt.Value(now)); err != nil {
+		return err
+	}
+	if err = r.WithContext(c).Create(&model.PasswordReset{
+		UserID:    user.ID,
+		CodeHash:  hash,
+		ExpiresAt: now.Add(passwordResetCodeTTL),
+	}); err != nil {
+		return err
+	}
+	return alert.GetAlertMgr().SendVerificationCode(c, code, &receiver)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		通过验证码重置密码
+//	@Description	校验邮件中的验证码并设置新密码，成功后用户的所有会话失效
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			data	body		ResetPasswordReq			true	"用户名、验证码和新密码"
+//	@Success		200		{object}	resputil.Response[string]	"重置成功"
+//	@Failure		400		{object}	resputil.Response[any]		"验证码无效或密码不符合要求"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/auth/password/reset [post]
+func (mgr *AuthMgr) ResetPassword(c *gin.Context) {
+	var req ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	const invalidCodeMsg = "Invalid or expired verification code"
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(req.Username)).First()
+	if err != nil || user.Password == nil {
+		resputil.Error(c, invalidCodeMsg, resputil.InvalidCredentials)
+		return
+	}
+
+	r := query.PasswordReset
+	now := time.Now()
+	reset, err := r.WithContext(c).Where(r.UserID.Eq(user.ID)).Order(r.CreatedAt.Desc()).First()
+	if err != nil || !reset.Usable(now, passwordResetMaxAttempts) {
+		resputil.Error(c, invalidCodeMsg, resputil.InvalidCredentials)
+		return
+	}
+	// 比较验证码前先用条件更新占用一次尝试次数，并发请求不能超过最大尝试次数
+	usable := r.WithContext(c).Where(
+		r.ID.Eq(reset.ID), r.UsedAt.IsNull(), r.ExpiresAt.Gt(now), r.Attempts.Lt(passwordResetMaxAttempts),
+	)
+	info, err := usable.UpdateSimple(r.Attempts.Add(1))
+	if err != nil || info.RowsAffected == 0 {
+		resputil.Error(c, invalidCodeMsg, resputil.InvalidCredentials)
+		return
+	}
+	hash := util.HashResetCode(user.ID, req.Code)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(reset.CodeHash)) != 1 {
+		resputil.Error(c, invalidCodeMsg, resputil.InvalidCredentials)
+		return
+	}
+	// 同一验证码只能使用一次，并发的正确请求中只有一个能标记成功
+	info, err = r.WithContext(c).Where(r.ID.Eq(reset.ID), r.UsedAt.IsNull()).UpdateSimple(r.UsedAt.Value(now))
+	if err != nil || info.RowsAffected == 0 {
+		resputil.Error(c, invalidCodeMsg, resputil.InvalidCredentials)
+		return
+	}
+
+	// 新密码不符合要求时不消耗验证码，退回本次占用的尝试次数，用户可以修改后重试
+	if err = setUserPassword(c, user, req.Password); err != nil {
+		if _, rollbackErr := r.WithContext(c).Where(r.ID.Eq(reset.ID)).
+			UpdateSimple(r.UsedAt.Null(), r.Attempts.Sub(1)); rollbackErr != nil {
+			klog.Errorf("restore password reset %d: %v", reset.ID, rollbackErr)
+		}
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if _, err = revokeSessions(c, user.ID); err != nil {
+		klog.Errorf("revoke sessions of user %s after password reset: %v", user.Name, err)
+	}
+
+	resputil.Success(c, "Password reset successfully")
+}
+
+// ChangePassword godoc
+//
+//	@Summary		修改密码
+//	@Description	校验旧密码后设置新密码，除当前会话外的其他会话全部失效
+//	@Tags			Context
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		ChangePasswordReq			true	"旧密码和新密码"
+//	@Success		200		{object}	resputil.Response[string]	"修改成功"
+//	@Failure		400		{object}	resputil.Response[any]		"旧密码错误或新密码不符合要求"
+//	@Failure		403		{object}	resputil.Response[any]		"不能使用访问令牌修改密码"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/context/password [put]
+func (mgr *ContextMgr) ChangePassword(c *gin.Context) {
+	if _, ok := util.GetAPITokenID(c); ok {
+		resputil.HTTPError(c, http.StatusForbidden, "Password can not be changed with an API token", resputil.UserNotAllowed)
+		return
+	}
+
+	var req ChangePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.Error(c, "User not found", resputil.NotSpecified)
+		return
+	}
+	if user.Password == nil {
+		resputil.Error(c, "User does not have a password", resputil.InvalidRequest)
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.OldPassword)) != nil {
+		resputil.Error(c, "Wrong old password", resputil.InvalidCredentials)
+		return
+	}
+
+	if err = setUserPassword(c, user, req.NewPassword); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	// 保留当前会话，撤销其他会话
+	s := query.UserSession
+	sessions, err := s.WithContext(c).Select(s.SessionID).
+		Where(s.UserID.Eq(user.ID), s.RevokedAt.IsNull(), s.SessionID.Neq(token.SessionID)).Find()
+	if err != nil {
+		klog.Errorf("list sessions of user %s: %v", user.Name, err)
+	}
+	if len(sessions) > 0 {
+		ids := make([]string, 0, len(sessions))
+		for _, session := range sessions {
+			ids = append(ids, session.SessionID)
+		}
+		if _, err = revokeSessions(c, user.ID, ids...); err != nil {
+			klog.Errorf("revoke sessions of user %s after password change: %v", user.Name, err)
+		}
+	}
+
+	resputil.Success(c, "Password changed successfully")
+}
+
+// checkPasswordHistory 新密码不能与当前密码及最近 History-1 个历史密码相同
+func checkPasswordHistory(c context.Context, user *model.User, password string, history int) error {
+	if history <= 0 {
+		return nil
+	}
+	if user.Password != nil && bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(password)) == nil {
+		return errPasswordReused
+	}
+	if history == 1 {
+		return nil
+	}
+
+	h := query.PasswordHistory
+	previous, err := h.WithContext(c).Where(h.UserID.Eq(user.ID)).Order(h.ID.Desc()).Limit(history - 1).Find()
+	if err != nil {
+		return err
+	}
+	for _, p := range previous {
+		if bcrypt.CompareHashAndPassword([]byte(p.Password), []byte(password)) == nil {
+			return errPasswordReused
+		}
+	}
+	return nil
+}
+
+// setUserPassword 按密码策略校验新密码并保存，旧密码哈希写入历史记录
+func setUserPassword(c context.Context, user *model.User, password string) error {
+	policy := util.GetPasswordPolicy()
+	if err := policy.Check(user.Name, password); err != nil {
+		return err
+	}
+	if err := checkPasswordHistory(c, user, password, policy.History); err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	db := query.Use(query.GetDB())
+	err = db.Transaction(func(tx *query.Query) error {
+		u := tx.User
+		if _, err := u.WithContext(c).Where(u.ID.Eq(user.ID)).Update(u.Password, string(hashed)); err != nil {
+			return err
+		}
+		if user.Password == nil || policy.History <= 1 {
+			return nil
+		}
+
+		h := tx.PasswordHistory
+		if err := h.WithContext(c).Create(&model.PasswordHistory{UserID: user.ID, Password: *user.Password}); err != nil {
+			return err
+		}
+		// 只保留检查所需的历史记录
+		stale, err := h.WithContext(c).Select(h.ID).Where(h.UserID.Eq(user.ID)).
+			Order(h.ID.Desc()).Offset(policy.History - 1).Find()
+		if err != nil || len(stale) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(stale))
+		for _, s := range stale {
+			ids = append(ids, s.ID)
+		}
+		_, err = h.WithContext(c).Unscoped().Where(h.ID.In(ids...)).Delete()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	user.Password = ptr.To(string(hashed))
+	return nil
+}
@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewPermissionMgr)
+}
+
+type PermissionMgr struct {
+	name string
+}
+
+func NewPermissionMgr(_ *RegisterConfig) Manager {
+	return &PermissionMgr{
+		name: "permissions",
+	}
+}
+
+func (mgr *PermissionMgr) GetName() string { return mgr.name }
+
+func (mgr *PermissionMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *PermissionMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("mine", mgr.GetMyPermissions)
+}
+
+func (mgr *PermissionMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionRolesManage))
+	g.GET("", mgr.ListPermissions)
+	g.GET("roles", mgr.ListPermissionRoles)
+	g.POST("roles", mgr.CreatePermissionRole)
+	g.PUT("roles/:rid", mgr.UpdatePermissionRole)
+	g.DELETE("roles/:rid", mgr.DeletePermissionRole)
+	g.GET("users/:name", mgr.GetUserPermissionRoles)
+	g.PUT("users/:name", mgr.SetUserPermissionRoles)
+}
+
+type (
+	PermissionRoleReq struct {
+		Name        string             `json:"name" binding:"required"`
+		Description string             `json:"description"`
+		Permissions []model.Permission `json:"permissions" binding:"required"`
+	}
+
+	PermissionRoleIDReq struct {
+		ID uint `uri:"rid" binding:"required"`
+	}
+
+	SetUserPermissionRolesReq struct {
+		RoleIDs []uint `json:"roleIDs"` // 用户被分配的全部权限角色，为空表示取消所有分配
+	}
+
+	UserPermissionsResp struct {
+		Roles       []*model.PermissionRole `json:"roles"`       // 被分配的权限角色
+		Permissions []model.Permission      `json:"permissions"` // 有效权限，包括平台角色的默认权限
+	}
+)
+
+// GetMyPermissions godoc
+//
+//	@Summary		获取当前用户的权限
+//	@Description	返回当前用户的有效管理权限，前端据此展示管理页面
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.Permission]	"有效权限"
+//	@Failure		500	{object}	resputil.Response[any]					"其他错误"
+//	@Router			/v1/permissions/mine [get]
+func (mgr *PermissionMgr) GetMyPermissions(c *gin.Context) {
+	token := util.GetToken(c)
+	apiTokenID, _ := util.GetAPITokenID(c)
+	permissions, err := middleware.LoadPermissions(c, token.UserID, token.RolePlatform, apiTokenID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("load permissions failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if permissions == nil {
+		permissions = []model.Permission{}
+	}
+	resputil.Success(c, permissions)
+}
+
+// ListPermissions godoc
+//
+//	@Summary		列出所有权限
+//	@Description	列出可以分配给权限角色的所有权限
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.Permission]	"所有权限"
+//	@Router			/v1/admin/permissions [get]
+func (mgr *PermissionMgr) ListPermissions(c *gin.Context) {
+	resputil.Success(c, model.GetAllPermissions())
+}
+
+// ListPermissionRoles godoc
+//
+//	@Summary		列出权限角色
+//	@Description	列出所有权限角色
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.PermissionRole]	"权限角色"
+//	@Failure		500	{object}	resputil.Response[any]						"其他错误"
+//	@Router			/v1/admin/permissions/roles [get]
+func (mgr *PermissionMgr) ListPermissionRoles(c *gin.Context) {
+	pr := query.PermissionRole
+	roles, err := pr.WithContext(c).Order(pr.ID).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list permission roles failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, roles)
+}
+
+// CreatePermissionRole godoc
+//
+//	@Summary		创建权限角色
+//	@Description	创建一组可分配给用户的权限
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		PermissionRoleReq							true	"权限角色"
+//	@Success		200		{object}	resputil.Response[model.PermissionRole]	"创建的权限角色"
+//	@Failure		400		{object}	resputil.Response[any]						"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]						"其他错误"
+//	@Router			/v1/admin/permissions/roles [post]
+func (mgr *PermissionMgr) CreatePermissionRole(c *gin.Context) {
+	var req PermissionRoleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	role := &model.PermissionRole{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: datatypes.NewJSONType(lo.Uniq(req.Permissions)),
+	}
+	if err := query.PermissionRole.WithContext(c).Create(role); err != nil {
+		resputil.Error(c, fmt.Sprintf("create permission role failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, role)
+}
+
+// UpdatePermissionRole godoc
+//
+//	@Summary		更新权限角色
+//	@Description	更新权限角色的名称、描述和权限，已分配该角色的用户立即生效
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			rid		path		uint						true	"权限角色ID"
+//	@Param			data	body		PermissionRoleReq			true	"权限角色"
+//	@Success		200		{object}	resputil.Response[string]	"更新成功"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/admin/permissions/roles/{rid} [put]
+func (mgr *PermissionMgr) UpdatePermissionRole(c *gin.Context) {
+	var uri PermissionRoleIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var req PermissionRoleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	pr := query.PermissionRole
+	info, err := pr.WithContext(c).Where(pr.ID.Eq(uri.ID)).UpdateSimple(
+		pr.Name.Value(req.Name),
+		pr.Description.Value(req.Description),
+		pr.Permissions.Value(datatypes.NewJSONType(lo.Uniq(req.Permissions))),
+	)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("update permission role failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if info.RowsAffected == 0 {
+		resputil.Error(c, "permission role not found", resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// DeletePermissionRole godoc
+//
+//	@Summary		删除权限角色
+//	@Description	删除权限角色并取消其在所有用户上的分配
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			rid	path		uint						true	"权限角色ID"
+//	@Success		200	{object}	resputil.Response[string]	"删除成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/admin/permissions/roles/{rid} [delete]
+func (mgr *PermissionMgr) DeletePermissionRole(c *gin.Context) {
+	var uri PermissionRoleIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	db := query.Use(query.GetDB())
+	err := db.Transaction(func(tx *query.Query) error {
+		upr := tx.UserPermissionRole
+		if _, err := upr.WithContext(c).Unscoped().Where(upr.RoleID.Eq(uri.ID)).Delete(); err != nil {
+			return err
+		}
+		pr := tx.PermissionRole
+		_, err := pr.WithContext(c).Where(pr.ID.Eq(uri.ID)).Delete()
+		return err
+	})
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("delete permission role failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// GetUserPermissionRoles godoc
+//
+//	@Summary		获取用户的权限角色
+//	@Description	返回用户被分配的权限角色及其有效权限
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string									true	"username"
+//	@Success		200		{object}	resputil.Response[UserPermissionsResp]	"用户的权限"
+//	@Failure		400		{object}	resputil.Response[any]					"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]					"其他错误"
+//	@Router			/v1/admin/permissions/users/{name} [get]
+func (mgr *PermissionMgr) GetUserPermissionRoles(c *gin.Context) {
+	user, ok := getUserByNameParam(c)
+	if !ok {
+		return
+	}
+	upr := query.UserPermissionRole
+	assigned, err := upr.WithContext(c).Where(upr.UserID.Eq(user.ID)).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("get user permission roles failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	roles := []*model.PermissionRole{}
+	if len(assigned) > 0 {
+		pr := query.PermissionRole
+		roles, err = pr.WithContext(c).Where(pr.ID.In(lo.Map(assigned, func(r *model.UserPermissionRole, _ int) uint {
+			return r.RoleID
+		})...)).Find()
+		if err != nil {
+			resputil.Error(c, fmt.Sprintf("get permission roles failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+	}
+	permissions, err := middleware.LoadPermissions(c, user.ID, user.Role, 0)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("load permissions failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, UserPermissionsResp{
+		Roles:       roles,
+		Permissions: permissions,
+	})
+}
+
+// SetUserPermissionRoles godoc
+//
+//	@Summary		分配权限角色
+//	@Description	设置用户被分配的全部权限角色
+//	@Tags			Permission
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string						true	"username"
+//	@Param			data	body		SetUserPermissionRolesReq	true	"权限角色ID"
+//	@Success		200		{object}	resputil.Response[string]	"分配成功"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/admin/permissions/users/{name} [put]
+func (mgr *PermissionMgr) SetUserPermissionRoles(c *gin.Context) {
+	var req SetUserPermissionRolesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	user, ok := getUserByNameParam(c)
+	if !ok {
+		return
+	}
+	roleIDs := lo.Uniq(req.RoleIDs)
+	if len(roleIDs) > 0 {
+		pr := query.PermissionRole
+		count, err := pr.WithContext(c).Where(pr.ID.In(roleIDs...)).Count()
+		if err != nil {
+			resputil.Error(c, fmt.Sprintf("get permission roles failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+		if int(count) != len(roleIDs) {
+			resputil.BadRequestError(c, "permission role not found")
+			return
+		}
+	}
+
+	db := query.Use(query.GetDB())
+	err := db.Transaction(func(tx *query.Query) error {
+		upr := tx.UserPermissionRole
+		if _, err := upr.WithContext(c).Unscoped().Where(upr.UserID.Eq(user.ID)).Delete(); err != nil {
+			return err
+		}
+		assigned := lo.Map(roleIDs, func(roleID uint, _ int) *model.UserPermissionRole {
+			return &model.UserPermissionRole{UserID: user.ID, RoleID: roleID}
+		})
+		if len(assigned) == 0 {
+			return nil
+		}
+		return upr.WithContext(c).Create(assigned...)
+	})
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("set user permission roles failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+func getUserByNameParam(c *gin.Context) (*model.User, bool) {
+	var nameReq UserNameReq
+	if err := c.ShouldBindUri(&nameReq); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return nil, false
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(nameReq.Name)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Error(c, fmt.Sprintf("user %s not found", nameReq.Name), resputil.NotSpecified)
+			return nil, false
+		}
+		resputil.Error(c, fmt.Sprintf("get user failed, detail: %v", err), resputil.NotSpecified)
+		return nil, false
+	}
+	return user, true
+}
+
+func validatePermissions(permissions []model.Permission) error {
+	if invalid, _ := lo.Difference(permissions, model.GetAllPermissions()); len(invalid) > 0 {
+		return fmt.Errorf("invalid permissions %v", invalid)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 )
 
@@ -43,6 +44,7 @@ func (mgr *ResourceMgr) RegisterProtected(g *gin.RouterGroup) {
 
 //nolint:dupl // ignore duplicate code
 func (mgr *ResourceMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionResourcesManage))
 	g.POST("/sync", mgr.SyncResource)
 	g.PUT("/:id", mgr.UpdateResource) // 注意这里改为新的方法名
 	g.DELETE("/:id", mgr.DeleteResource)
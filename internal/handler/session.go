@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+type (
+	SessionResp struct {
+		model.UserSession
+		Current bool `json:"current"` // 是否为当前请求所属的会话
+	}
+
+	SessionIDReq struct {
+		SessionID string `uri:"sid" binding:"required"`
+	}
+)
+
+// createSession 为一次登录创建会话，并将会话 ID 写入令牌信息
+func (mgr *AuthMgr) createSession(c *gin.Context, msg *util.JWTMessage) error {
+	s := query.UserSession
+	now := time.Now()
+	// 顺便清理该用户已过期的会话
+	if _, err := s.WithContext(c).Unscoped().Where(s.UserID.Eq(msg.UserID), s.ExpiresAt.Lt(now)).Delete(); err != nil {
+		return err
+	}
+
+	session := &model.UserSession{
+		SessionID: uuid.NewString(),
+		UserID:    msg.UserID,
+		IP:        c.ClientIP(),
+		UserAgent: truncate(c.Request.UserAgent(), 512),
+		ExpiresAt: now.Add(mgr.tokenMgr.RefreshTokenTTL()),
+	}
+	if err := s.WithContext(c).Create(session); err != nil {
+		return err
+	}
+	msg.SessionID = session.SessionID
+	return nil
+}
+
+// refreshSession 刷新令牌时延长会话有效期，已撤销或过期的会话不能刷新。
+// 会话表引入前签发的刷新令牌没有会话 ID，撤销所有会话和强制下线对其无效，因此不再允许刷新
+func (mgr *AuthMgr) refreshSession(c *gin.Context, msg *util.JWTMessage) error {
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(msg.UserID)).First()
+	if err != nil || user.Status != model.StatusActive {
+		return middleware.ErrUserInactive
+	}
+	if msg.SessionID == "" {
+		return middleware.ErrSessionRevoked
+	}
+
+	s := query.UserSession
+	now := time.Now()
+	session, err := s.WithContext(c).Where(s.SessionID.Eq(msg.SessionID), s.UserID.Eq(msg.UserID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return middleware.ErrSessionRevoked
+		}
+		return err
+	}
+	if !session.Active(now) {
+		return middleware.ErrSessionRevoked
+	}
+	_, err = s.WithContext(c).Where(s.ID.Eq(session.ID)).UpdateSimple(
+		s.ExpiresAt.Value(now.Add(mgr.tokenMgr.RefreshTokenTTL())),
+		s.RefreshedAt.Value(now),
+	)
+	return err
+}
+
+// revokeSessions 撤销用户的会话，sessionIDs 为空时撤销该用户的所有会话
+func revokeSessions(c context.Context, userID uint, sessionIDs ...string) (int64, error) {
+	s := query.UserSession
+	if len(sessionIDs) == 0 {
+		sessions, err := s.WithContext(c).Select(s.SessionID).Where(s.UserID.Eq(userID), s.RevokedAt.IsNull()).Find()
+		if err != nil {
+			return 0, err
+		}
+		for _, session := range sessions {
+			sessionIDs = append(sessionIDs, session.SessionID)
+		}
+		if len(sessionIDs) == 0 {
+			return 0, nil
+		}
+	}
+	info, err := s.WithContext(c).Where(s.UserID.Eq(userID), s.RevokedAt.IsNull(), s.SessionID.In(sessionIDs...)).
+		UpdateSimple(s.RevokedAt.Value(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	middleware.InvalidateSessions(sessionIDs...)
+	return info.RowsAffected, nil
+}
+
+// Logout godoc
+//
+//	@Summary		退出登录
+//	@Description	撤销当前会话，当前会话签发的访问令牌和刷新令牌立即失效
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[string]	"退出成功"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/auth/logout [post]
+func (mgr *AuthMgr) Logout(c *gin.Context) {
+	token := util.GetToken(c)
+	if token.SessionID == "" {
+		resputil.Success(c, "")
+		return
+	}
+	if _, err := revokeSessions(c, token.UserID, token.SessionID); err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke session failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// ListSessions godoc
+//
+//	@Summary		列出登录会话
+//	@Description	列出当前用户未过期且未撤销的登录会话
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]SessionResp]	"会话列表"
+//	@Failure		500	{object}	resputil.Response[any]				"其他错误"
+//	@Router			/v1/auth/sessions [get]
+func (mgr *AuthMgr) ListSessions(c *gin.Context) {
+	token := util.GetToken(c)
+	s := query.UserSession
+	sessions, err := s.WithContext(c).
+		Where(s.UserID.Eq(token.UserID), s.RevokedAt.IsNull(), s.ExpiresAt.Gt(time.Now())).
+		Order(s.ID.Desc()).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list sessions failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resp := make([]SessionResp, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, SessionResp{
+			UserSession: *session,
+			Current:     session.SessionID == token.SessionID,
+		})
+	}
+	resputil.Success(c, resp)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		撤销登录会话
+//	@Description	撤销当前用户的一个登录会话，例如在其他设备上的登录
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			sid	path		string						true	"session id"
+//	@Success		200	{object}	resputil.Response[string]	"撤销成功"
+//	@Failure		400	{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/auth/sessions/{sid} [delete]
+func (mgr *AuthMgr) RevokeSession(c *gin.Context) {
+	var uri SessionIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	revoked, err := revokeSessions(c, token.UserID, uri.SessionID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke session failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if revoked == 0 {
+		resputil.Error(c, "session not found", resputil.UserNotAllowed)
+		return
+	}
+	resputil.Success(c, "")
+}
+
+// RevokeAllSessions godoc
+//
+//	@Summary		撤销所有登录会话
+//	@Description	撤销当前用户的所有登录会话，包括当前会话
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[int64]	"撤销的会话数"
+//	@Failure		500	{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/auth/sessions/revoke-all [post]
+func (mgr *AuthMgr) RevokeAllSessions(c *gin.Context) {
+	token := util.GetToken(c)
+	revoked, err := revokeSessions(c, token.UserID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke sessions failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, revoked)
+}
+
+// ForceLogoutUser godoc
+//
+//	@Summary		强制用户下线
+//	@Description	管理员撤销指定用户的所有登录会话
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string						true	"username"
+//	@Success		200		{object}	resputil.Response[int64]	"撤销的会话数"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/admin/users/{name}/logout [post]
+func (mgr *UserMgr) ForceLogoutUser(c *gin.Context) {
+	var nameReq UserNameReq
+	if err := c.ShouldBindUri(&nameReq); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(nameReq.Name)).First()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("user %s not found", nameReq.Name), resputil.NotSpecified)
+		return
+	}
+	if !checkManagedUser(c, user) {
+		return
+	}
+	revoked, err := revokeSessions(c, user.ID)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke sessions failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	middleware.InvalidateUser(user.ID)
+	resputil.Success(c, revoked)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
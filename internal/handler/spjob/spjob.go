@@ -19,6 +19,7 @@ import (
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/handler"
 	"github.com/raids-lab/crater/internal/handler/vcjob"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
 	recommenddljobapi "github.com/raids-lab/crater/pkg/apis/recommenddljob/v1"
@@ -80,6 +81,7 @@ func (mgr *SparseJobMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *SparseJobMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionJobsReadAny))
 	g.GET("", mgr.List)
 	g.GET(":name/detail", mgr.GetByName)
 }
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/sshkey"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewSSHKeyMgr)
+}
+
+const (
+	// maxSSHKeysPerUser 每个用户最多登记的未撤销公钥数量
+	maxSSHKeysPerUser = 20
+	// maxSSHKeyNameLength 公钥名称的最大长度，使用公钥注释作为名称时截断
+	maxSSHKeyNameLength = 128
+)
+
+type SSHKeyMgr struct {
+	name       string
+	kubeClient kubernetes.Interface
+}
+
+func NewSSHKeyMgr(conf *RegisterConfig) Manager {
+	return &SSHKeyMgr{
+		name:       "ssh-keys",
+		kubeClient: conf.KubeClient,
+	}
+}
+
+func (mgr *SSHKeyMgr) GetName() string { return mgr.name }
+
+func (mgr *SSHKeyMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *SSHKeyMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListSSHKeys)
+	g.POST("", mgr.AddSSHKey)
+	g.DELETE("/:id", mgr.RevokeSSHKey)
+}
+
+func (mgr *SSHKeyMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionUsersManage))
+	g.GET("", mgr.AdminListSSHKeys)
+	g.DELETE("/:id", mgr.AdminRevokeSSHKey)
+}
+
+type (
+	AddSSHKeyReq struct {
+		Name      string `json:"name" binding:"max=128"`       // 为空时使用公钥中的注释
+		PublicKey string `json:"publicKey" binding:"required"` // authorized_keys 格式的公钥
+	}
+
+	SSHKeyIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	AdminListSSHKeysReq struct {
+		Username       *string `form:"username"`       // 按用户过滤
+		Fingerprint    *string `form:"fingerprint"`    // 按指纹过滤
+		IncludeRevoked bool    `form:"includeRevoked"` // 是否包含已撤销的公钥
+	}
+
+	SSHKeyResp struct {
+		*model.SSHKey
+		Username string `json:"username"`
+	}
+)
+
+// ListSSHKeys godoc
+//
+//	@Summary		List SSH public keys
+//	@Description	List SSH public keys of the current user, including revoked keys
+//	@Tags			SSHKey
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.SSHKey]	"SSH public keys"
+//	@Failure		500	{object}	resputil.Response[any]				"Other errors"
+//	@Router			/v1/ssh-keys [get]
+func (mgr *SSHKeyMgr) ListSSHKeys(c *gin.Context) {
+	token := util.GetToken(c)
+	k := query.SSHKey
+	keys, err := k.WithContext(c).Where(k.UserID.Eq(token.UserID)).Order(k.ID.Desc()).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list ssh keys failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, keys)
+}
+
+// AddSSHKey godoc
+//
+//	@Summary		Add SSH public key
+//	@Description	Add an SSH public key, it is injected into authorized_keys of the user's job containers
+//	@Tags			SSHKey
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		AddSSHKeyReq						true	"public key"
+//	@Success		200		{object}	resputil.Response[model.SSHKey]	"Added key"
+//	@Failure		400		{object}	resputil.Response[any]			"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]			"Other errors"
+//	@Router			/v1/ssh-keys [post]
+func (mgr *SSHKeyMgr) AddSSHKey(c *gin.Context) {
+	var req AddSSHKeyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	parsed, err := sshkey.Parse(req.PublicKey)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	name := req.Name
+	if name == "" {
+		name = parsed.Comment
+	}
+	if name == "" {
+		name = parsed.KeyType
+	}
+	if runes := []rune(name); len(runes) > maxSSHKeyNameLength {
+		name = string(runes[:maxSSHKeyNameLength])
+	}
+
+	token := util.GetToken(c)
+	k := query.SSHKey
+	count, err := k.WithContext(c).Where(k.UserID.Eq(token.UserID), k.RevokedAt.IsNull()).Count()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("count ssh keys failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if count >= maxSSHKeysPerUser {
+		resputil.Error(c, fmt.Sprintf("a user can have at most %d ssh keys", maxSSHKeysPerUser), resputil.UserNotAllowed)
+		return
+	}
+	exists, err := k.WithContext(c).
+		Where(k.UserID.Eq(token.UserID), k.RevokedAt.IsNull(), k.Fingerprint.Eq(parsed.Fingerprint)).
+		Count()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("check ssh key failed, detail: %v", err), resputil.NotSpecified)
+		return
+	} else if exists > 0 {
+		resputil.BadRequestError(c, fmt.Sprintf("ssh key %s already exists", parsed.Fingerprint))
+		return
+	}
+
+	key := &model.SSHKey{
+		UserID:      token.UserID,
+		Name:        name,
+		KeyType:     parsed.KeyType,
+		PublicKey:   parsed.PublicKey,
+		Fingerprint: parsed.Fingerprint,
+	}
+	if err := k.WithContext(c).Create(key); err != nil {
+		resputil.Error(c, fmt.Sprintf("add ssh key failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if err := sshkey.Sync(c, mgr.kubeClient, token.UserID); err != nil {
+		klog.Error(err)
+		resputil.Error(c, fmt.Sprintf("ssh key added but not synced to jobs, detail: %v", err), resputil.ServiceError)
+		return
+	}
+	resputil.Success(c, key)
+}
+
+// RevokeSSHKey godoc
+//
+//	@Summary		Revoke SSH public key
+//	@Description	Revoke an SSH public key of the current user, it is removed from running job containers within a minute
+//	@Tags			SSHKey
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"key id"
+//	@Success		200	{object}	resputil.Response[string]	"Revoked"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/ssh-keys/{id} [delete]
+func (mgr *SSHKeyMgr) RevokeSSHKey(c *gin.Context) {
+	var uri SSHKeyIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	k := query.SSHKey
+	mgr.revoke(c, k.ID.Eq(uri.ID), k.UserID.Eq(token.UserID))
+}
+
+// AdminListSSHKeys godoc
+//
+//	@Summary		List SSH public keys of all users
+//	@Description	List SSH public keys of all users for auditing, revoked keys are excluded unless includeRevoked is set
+//	@Tags			SSHKey
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			username		query		string							false	"username"
+//	@Param			fingerprint		query		string							false	"SHA256 fingerprint"
+//	@Param			includeRevoked	query		bool							false	"include revoked keys"
+//	@Success		200				{object}	resputil.Response[[]SSHKeyResp]	"SSH public keys"
+//	@Failure		400				{object}	resputil.Response[any]			"Request parameter error"
+//	@Failure		500				{object}	resputil.Response[any]			"Other errors"
+//	@Router			/v1/admin/ssh-keys [get]
+func (mgr *SSHKeyMgr) AdminListSSHKeys(c *gin.Context) {
+	var req AdminListSSHKeysReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	k := query.SSHKey
+	u := query.User
+	conds := []gen.Condition{}
+	if req.Username != nil {
+		user, err := u.WithContext(c).Where(u.Name.Eq(*req.Username)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Success(c, []SSHKeyResp{})
+			return
+		}
+		if err != nil {
+			resputil.Error(c, fmt.Sprintf("get user failed, detail: %v", err), resputil.NotSpecified)
+			return
+		}
+		conds = append(conds, k.UserID.Eq(user.ID))
+	}
+	if req.Fingerprint != nil {
+		conds = append(conds, k.Fingerprint.Eq(*req.Fingerprint))
+	}
+	if !req.IncludeRevoked {
+		conds = append(conds, k.RevokedAt.IsNull())
+	}
+	keys, err := k.WithContext(c).Where(conds...).Order(k.ID.Desc()).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list ssh keys failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	userIDs := make([]uint, 0, len(keys))
+	for _, key := range keys {
+		userIDs = append(userIDs, key.UserID)
+	}
+	users, err := u.WithContext(c).Where(u.ID.In(userIDs...)).Find()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list users failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	usernames := make(map[uint]string, len(users))
+	for _, user := range users {
+		usernames[user.ID] = user.Name
+	}
+
+	resp := make([]SSHKeyResp, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, SSHKeyResp{SSHKey: key, Username: usernames[key.UserID]})
+	}
+	resputil.Success(c, resp)
+}
+
+// AdminRevokeSSHKey godoc
+//
+//	@Summary		Revoke SSH public key of any user
+//	@Description	Revoke an SSH public key of any user, e.g. when the private key is leaked
+//	@Tags			SSHKey
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint						true	"key id"
+//	@Success		200	{object}	resputil.Response[string]	"Revoked"
+//	@Failure		400	{object}	resputil.Response[any]		"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]		"Other errors"
+//	@Router			/v1/admin/ssh-keys/{id} [delete]
+func (mgr *SSHKeyMgr) AdminRevokeSSHKey(c *gin.Context) {
+	var uri SSHKeyIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	k := query.SSHKey
+	mgr.revoke(c, k.ID.Eq(uri.ID))
+}
+
+// revoke 撤销符合条件的公钥并重新同步所属用户的 authorized_keys，撤销记录保留用于审计
+func (mgr *SSHKeyMgr) revoke(c *gin.Context, conds ...gen.Condition) {
+	k := query.SSHKey
+	key, err := k.WithContext(c).Where(conds...).Where(k.RevokedAt.IsNull()).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.Error(c, "ssh key not found or already revoked", resputil.UserNotAllowed)
+			return
+		}
+		resputil.Error(c, fmt.Sprintf("get ssh key failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	now := time.Now()
+	if _, err := k.WithContext(c).Where(k.ID.Eq(key.ID)).Updates(map[string]any{
+		"revoked_at": now,
+		"revoked_by": util.GetToken(c).Username,
+	}); err != nil {
+		resputil.Error(c, fmt.Sprintf("revoke ssh key failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	if err := sshkey.Sync(c, mgr.kubeClient, key.UserID); err != nil {
+		klog.Error(err)
+		resputil.Error(c, fmt.Sprintf("ssh key revoked but not synced to jobs, detail: %v", err), resputil.ServiceError)
+		return
+	}
+	resputil.Success(c, "")
+}
@@ -0,0 +1,191 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+// podAccess 对 Pod 的访问类型
+type podAccess int
+
+const (
+	// podAccessRead 查看容器状态、事件和日志
+	podAccessRead podAccess = iota
+	// podAccessWrite 打开终端、修改端口暴露规则和资源
+	podAccessWrite
+)
+
+// podGrant 授予 Pod 访问权限的规则
+type podGrant int
+
+const (
+	podGrantNone podGrant = iota
+	// podGrantOwner 作业或镜像构建的所有者
+	podGrantOwner
+	// podGrantPlatformAdmin 平台管理员
+	podGrantPlatformAdmin
+	// podGrantAccountAdmin 作业所在账户的管理员
+	podGrantAccountAdmin
+	// podGrantReadAny 拥有 jobs:read-any 权限
+	podGrantReadAny
+	// podGrantEditAny 拥有 jobs:edit-any 权限
+	podGrantEditAny
+)
+
+// onBehalfOfOwner 是否由所有者以外的用户访问 Pod
+func (g podGrant) onBehalfOfOwner() bool {
+	return g != podGrantOwner
+}
+
+// imagePackJobLabelKey 镜像构建 Pod 上由 batch/v1 Job 添加的标签，值为 ImagePack 名称
+const imagePackJobLabelKey = "job-name"
+
+var (
+	errPodOwnerNotFound    = errors.New("pod does not belong to any job or image build")
+	errPodNamespaceInvalid = errors.New("pod is not in the job or image namespace")
+)
+
+// podAuthorizer 集中处理 Pod 相关接口的访问控制：
+// 根据 Pod 找到所属的 Volcano 作业 (或镜像构建任务)，再根据数据库中的记录判断当前用户能否访问
+type podAuthorizer struct {
+	client     client.Client
+	kubeClient kubernetes.Interface
+}
+
+func newPodAuthorizer(cl client.Client, kubeClient kubernetes.Interface) *podAuthorizer {
+	return &podAuthorizer{client: cl, kubeClient: kubeClient}
+}
+
+// authorize 获取 Pod 并校验访问权限。校验失败时已写入响应，并将拒绝记录到审计日志，调用方直接返回即可
+func (a *podAuthorizer) authorize(c *gin.Context, namespace, podName string, access podAccess) (*v1.Pod, bool) {
+	pod, _, ok := a.authorizeGrant(c, namespace, podName, access)
+	return pod, ok
+}
+
+// authorizeGrant 与 authorize 相同，同时返回授予访问权限的规则
+func (a *podAuthorizer) authorizeGrant(c *gin.Context, namespace, podName string, access podAccess) (*v1.Pod, podGrant, bool) {
+	// 终端和文件下载是 GET 请求，只读令牌不能只按请求方法判断
+	if access == podAccessWrite && util.IsReadOnlyAPIToken(c) {
+		reason := fmt.Sprintf("read-only api token can not operate pod %s/%s", namespace, podName)
+		middleware.AuditDenied(c, reason)
+		resputil.HTTPError(c, http.StatusForbidden, reason, resputil.UserNotAllowed)
+		return nil, podGrantNone, false
+	}
+
+	pod, err := a.getPod(c, namespace, podName)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return nil, podGrantNone, false
+	}
+
+	grant, err := a.allowed(c, pod, access)
+	if err != nil && !errors.Is(err, errPodOwnerNotFound) && !errors.Is(err, errPodNamespaceInvalid) {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return nil, podGrantNone, false
+	}
+	if grant == podGrantNone {
+		reason := fmt.Sprintf("user not allowed to access pod %s/%s", namespace, podName)
+		if err != nil {
+			reason = fmt.Sprintf("%s: %v", reason, err)
+		}
+		middleware.AuditDenied(c, reason)
+		resputil.HTTPError(c, http.StatusForbidden, reason, resputil.UserNotAllowed)
+		return nil, podGrantNone, false
+	}
+	return pod, grant, true
+}
+
+func (a *podAuthorizer) getPod(c *gin.Context, namespace, podName string) (*v1.Pod, error) {
+	var pod v1.Pod
+	err := a.client.Get(c, client.ObjectKey{Namespace: namespace, Name: podName}, &pod)
+	if err == nil {
+		return &pod, nil
+	}
+	if !strings.Contains(err.Error(), "unknown namespace") {
+		return nil, err
+	}
+	// 缓存未覆盖的命名空间，直接从 API Server 获取
+	return a.kubeClient.CoreV1().Pods(namespace).Get(c, podName, metav1.GetOptions{})
+}
+
+// allowed 作业的所有者、作业所在账户的管理员和平台管理员可以访问作业的 Pod，
+// 拥有 jobs:read-any / jobs:edit-any 权限的用户分别可以查看、操作任意作业的 Pod；
+// 镜像构建 Pod 只有构建者和平台管理员可以访问。
+// 数据库中的作业和镜像构建记录只按名称查找，因此除平台管理员外只允许访问作业和镜像命名空间中的 Pod，
+// 避免其他命名空间中标签相同的 Pod 被当作用户的作业。返回授予访问权限的规则，不允许访问时返回 podGrantNone
+func (a *podAuthorizer) allowed(c *gin.Context, pod *v1.Pod, access podAccess) (podGrant, error) {
+	token := util.GetToken(c)
+	if token.RolePlatform == model.RoleAdmin {
+		return podGrantPlatformAdmin, nil
+	}
+
+	namespaces := config.GetConfig().Namespaces
+	if pod.Namespace != namespaces.Job && pod.Namespace != namespaces.Image {
+		return podGrantNone, errPodNamespaceInvalid
+	}
+
+	if jobName := jobNameOfPod(pod); jobName != "" && pod.Namespace == namespaces.Job {
+		j := query.Job
+		job, err := j.WithContext(c).Where(j.JobName.Eq(jobName)).First()
+		if err != nil {
+			klog.Warningf("get job %s of pod %s/%s failed: %v", jobName, pod.Namespace, pod.Name, err)
+			return podGrantNone, errPodOwnerNotFound
+		}
+		if attrs := job.Attributes.Data(); attrs == nil || attrs.Namespace != pod.Namespace {
+			return podGrantNone, errPodOwnerNotFound
+		}
+		switch {
+		case job.UserID == token.UserID:
+			return podGrantOwner, nil
+		case job.AccountID == token.AccountID && token.RoleAccount == model.RoleAdmin:
+			return podGrantAccountAdmin, nil
+		case middleware.HasPermission(c, model.PermissionJobsEditAny):
+			return podGrantEditAny, nil
+		case access == podAccessRead && middleware.HasPermission(c, model.PermissionJobsReadAny):
+			return podGrantReadAny, nil
+		}
+		return podGrantNone, nil
+	}
+
+	if imagePackName := pod.Labels[imagePackJobLabelKey]; imagePackName != "" && pod.Namespace == namespaces.Image {
+		k := query.Kaniko
+		kaniko, err := k.WithContext(c).Where(k.ImagePackName.Eq(imagePackName)).First()
+		if err != nil {
+			return podGrantNone, errPodOwnerNotFound
+		}
+		if kaniko.UserID == token.UserID {
+			return podGrantOwner, nil
+		}
+		return podGrantNone, nil
+	}
+
+	return podGrantNone, errPodOwnerNotFound
+}
+
+// jobNameOfPod 返回 Pod 所属的 Volcano 作业名称，优先使用 OwnerReference，其次使用 Volcano 添加的注解和标签
+func jobNameOfPod(pod *v1.Pod) string {
+	if isJobPod(pod) {
+		return pod.OwnerReferences[0].Name
+	}
+	if name := pod.Annotations[batch.JobNameKey]; name != "" {
+		return name
+	}
+	return pod.Labels[batch.JobNameKey]
+}
@@ -0,0 +1,538 @@
+package tool
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	// defaultFileTransferMaxSize 上传和下载的默认大小上限
+	defaultFileTransferMaxSize = 1 << 30
+	// fileTransferRetention 传输结束后保留进度的时间
+	fileTransferRetention = 10 * time.Minute
+	// FileTransferHeader 响应头中返回传输ID，可用于查询进度
+	FileTransferHeader = "X-Transfer-ID"
+
+	fileTransferUpload   = "upload"
+	fileTransferDownload = "download"
+)
+
+var errFileTooLarge = errors.New("file size exceeds the limit")
+
+type (
+	UploadContainerFileQuery struct {
+		Path       string `form:"path" binding:"required"` // 容器内的目标目录，不存在时自动创建
+		Filename   string `form:"filename"`                // 上传单个文件时的文件名
+		Extract    bool   `form:"extract"`                 // 请求体为 tar 或 tar.gz 包，解压到目标目录
+		TransferID string `form:"transferID"`              // 客户端生成的传输ID，用于查询进度
+	}
+
+	DownloadContainerFileQuery struct {
+		Path       string `form:"path" binding:"required"` // 容器内的文件或目录
+		TransferID string `form:"transferID"`              // 客户端生成的传输ID，用于查询进度
+	}
+
+	FileTransferURIReq struct {
+		// from uri
+		Namespace     string `uri:"namespace" binding:"required"`
+		PodName       string `uri:"name" binding:"required"`
+		ContainerName string `uri:"container" binding:"required"`
+		TransferID    string `uri:"tid" binding:"required"`
+	}
+
+	FileTransferResp struct {
+		ID          string     `json:"id"`
+		Direction   string     `json:"direction"`   // upload 或 download
+		Path        string     `json:"path"`        // 容器内的路径
+		Total       int64      `json:"total"`       // 总字节数，下载时为压缩前的估算值
+		Transferred int64      `json:"transferred"` // 已传输的字节数，下载时为压缩前的字节数
+		StartedAt   time.Time  `json:"startedAt"`
+		FinishedAt  *time.Time `json:"finishedAt"`
+		Error       string     `json:"error,omitempty"`
+	}
+)
+
+// fileTransfer 一次上传或下载的进度
+type fileTransfer struct {
+	id            string
+	direction     string
+	path          string
+	userID        uint
+	namespace     string
+	podName       string
+	containerName string
+	total         int64
+	startedAt     time.Time
+	transferred   atomic.Int64
+
+	mu         sync.Mutex
+	finishedAt *time.Time
+	err        string
+}
+
+func (t *fileTransfer) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.finishedAt = &now
+	if err != nil {
+		t.err = err.Error()
+	}
+}
+
+func (t *fileTransfer) info() FileTransferResp {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return FileTransferResp{
+		ID:          t.id,
+		Direction:   t.direction,
+		Path:        t.path,
+		Total:       t.total,
+		Transferred: t.transferred.Load(),
+		StartedAt:   t.startedAt,
+		FinishedAt:  t.finishedAt,
+		Error:       t.err,
+	}
+}
+
+// fileTransferStore 当前副本上的传输进度，按用户隔离
+type fileTransferStore struct {
+	mu        sync.Mutex
+	transfers map[string]*fileTransfer
+}
+
+func newFileTransferStore() *fileTransferStore {
+	return &fileTransferStore{transfers: make(map[string]*fileTransfer)}
+}
+
+func fileTransferKey(userID uint, id string) string {
+	return fmt.Sprintf("%d/%s", userID, id)
+}
+
+// start 登记传输，同时清理结束已久的传输
+func (st *fileTransferStore) start(t *fileTransfer) error {
+	if t.id == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		t.id = hex.EncodeToString(b)
+	}
+	t.startedAt = time.Now()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for key, old := range st.transfers {
+		old.mu.Lock()
+		expired := old.finishedAt != nil && time.Since(*old.finishedAt) > fileTransferRetention
+		old.mu.Unlock()
+		if expired {
+			delete(st.transfers, key)
+		}
+	}
+	st.transfers[fileTransferKey(t.userID, t.id)] = t
+	return nil
+}
+
+func (st *fileTransferStore) get(userID uint, id string) (*fileTransfer, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t, ok := st.transfers[fileTransferKey(userID, id)]
+	return t, ok
+}
+
+// progressReader 统计读取的字节数
+type progressReader struct {
+	r io.Reader
+	t *fileTransfer
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.t.transferred.Add(int64(n))
+	return n, err
+}
+
+// progressWriter 统计写入的字节数，超过上限时中止传输
+type progressWriter struct {
+	w     io.Writer
+	t     *fileTransfer
+	limit int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if p.t.transferred.Add(int64(len(b))) > p.limit {
+		return 0, errFileTooLarge
+	}
+	return p.w.Write(b)
+}
+
+func fileTransferLimits() (upload, download int64) {
+	conf := config.GetConfig().FileTransfer
+	upload, download = conf.MaxUploadSize, conf.MaxDownloadSize
+	if upload == 0 {
+		upload = defaultFileTransferMaxSize
+	}
+	if download == 0 {
+		download = defaultFileTransferMaxSize
+	}
+	return upload, download
+}
+
+// execInContainer 在容器中执行命令并等待结束，命令失败时返回的错误包含标准错误输出
+func (mgr *APIServerMgr) execInContainer(
+	ctx context.Context,
+	req *PodContainerLogURIReq,
+	command []string,
+	stdin io.Reader,
+	stdout io.Writer,
+) error {
+	request := mgr.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(req.PodName).
+		Namespace(req.Namespace).
+		SubResource("exec")
+	request.VersionedParams(&v1.PodExecOptions{
+		Command:   command,
+		Container: req.ContainerName,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(mgr.config, "POST", request.URL())
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// UploadContainerFile godoc
+//
+//	@Summary		上传文件到容器
+//	@Description	请求体为文件内容，通过 exec 执行 tar 写入容器的目标目录；extract 为 true 时请求体为 tar 或 tar.gz 包，解压到目标目录
+//	@Tags			Pod
+//	@Accept			application/octet-stream
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string								true	"命名空间"
+//	@Param			name		path		string								true	"Pod名称"
+//	@Param			container	path		string								true	"容器名称"
+//	@Param			path		query		string								true	"容器内的目标目录"
+//	@Param			filename	query		string								false	"上传单个文件时的文件名"
+//	@Param			extract		query		bool								false	"是否解压 tar 或 tar.gz 包"
+//	@Param			transferID	query		string								false	"传输ID，用于查询进度"
+//	@Success		200			{object}	resputil.Response[FileTransferResp]	"上传结果"
+//	@Failure		400			{object}	resputil.Response[any]				"请求参数错误"
+//	@Failure		403			{object}	resputil.Response[any]				"无权访问该 Pod"
+//	@Failure		413			{object}	resputil.Response[any]				"文件超过大小上限"
+//	@Failure		500			{object}	resputil.Response[any]				"其他错误"
+//	@Router			/v1/namespaces/{namespace}/pods/{name}/containers/{container}/files [post]
+func (mgr *APIServerMgr) UploadContainerFile(c *gin.Context) {
+	var req PodContainerLogURIReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var query UploadContainerFileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	dir := path.Clean(query.Path)
+	if !path.IsAbs(dir) {
+		resputil.BadRequestError(c, "path must be absolute")
+		return
+	}
+	if !query.Extract {
+		if query.Filename == "" || query.Filename == "." || query.Filename == ".." || strings.Contains(query.Filename, "/") {
+			resputil.BadRequestError(c, "filename is required and must not contain '/'")
+			return
+		}
+		// tar 头部需要文件大小，单个文件上传时必须提供 Content-Length
+		if c.Request.ContentLength < 0 {
+			resputil.HTTPError(c, http.StatusLengthRequired, "Content-Length is required", resputil.InvalidRequest)
+			return
+		}
+	}
+	uploadLimit, _ := fileTransferLimits()
+	if c.Request.ContentLength > uploadLimit {
+		resputil.HTTPError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file size exceeds the limit of %d bytes", uploadLimit), resputil.InvalidRequest)
+		return
+	}
+
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite); !ok {
+		return
+	}
+
+	transfer := &fileTransfer{
+		id:            query.TransferID,
+		direction:     fileTransferUpload,
+		path:          dir,
+		userID:        util.GetToken(c).UserID,
+		namespace:     req.Namespace,
+		podName:       req.PodName,
+		containerName: req.ContainerName,
+		total:         max(c.Request.ContentLength, 0),
+	}
+	if err := mgr.transfers.start(transfer); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	c.Header(FileTransferHeader, transfer.id)
+
+	body := &progressReader{r: c.Request.Body, t: transfer}
+	pr, pw := io.Pipe()
+	writeErr := make(chan error, 1)
+	go func() {
+		var err error
+		if query.Extract {
+			err = copyUploadArchive(pw, body, uploadLimit)
+		} else {
+			err = writeUploadFile(pw, body, query.Filename, c.Request.ContentLength)
+		}
+		_ = pw.CloseWithError(err)
+		writeErr <- err
+	}()
+
+	// 目标目录不存在时先创建，路径作为参数传入，不会被 Shell 解析
+	command := []string{"sh", "-c", `mkdir -p "$1" && exec tar -xf - -C "$1"`, "sh", dir}
+	err := mgr.execInContainer(c, &req, command, pr, io.Discard)
+	_ = pr.Close()
+	if wErr := <-writeErr; wErr != nil && !errors.Is(wErr, io.ErrClosedPipe) {
+		err = wErr
+	}
+	transfer.finish(err)
+
+	if errors.Is(err, errFileTooLarge) {
+		resputil.HTTPError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file size exceeds the limit of %d bytes", uploadLimit), resputil.InvalidRequest)
+		return
+	}
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("upload to %s failed: %v", dir, err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, transfer.info())
+}
+
+// writeUploadFile 将单个文件打包为只包含一项的 tar 流
+func writeUploadFile(w io.Writer, body io.Reader, filename string, size int64) error {
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filename,
+		Mode:     0o644,
+		Size:     size,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, body, size); err != nil {
+		return fmt.Errorf("read upload body: %w", err)
+	}
+	return tw.Close()
+}
+
+// copyUploadArchive 转发 tar 包，gzip 压缩的包在服务端解压，容器中只需要 tar 命令。
+// 大小上限按解压后的字节数计算
+func copyUploadArchive(w io.Writer, body io.Reader, limit int64) error {
+	br := bufio.NewReader(body)
+	var src io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		src = gz
+	}
+	n, err := io.Copy(w, io.LimitReader(src, limit+1))
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return errFileTooLarge
+	}
+	return nil
+}
+
+// DownloadContainerFile godoc
+//
+//	@Summary		从容器下载文件
+//	@Description	通过 exec 执行 tar 打包容器中的文件或目录，以 tar.gz 格式下载
+//	@Tags			Pod
+//	@Produce		application/gzip
+//	@Security		Bearer
+//	@Param			namespace	path		string					true	"命名空间"
+//	@Param			name		path		string					true	"Pod名称"
+//	@Param			container	path		string					true	"容器名称"
+//	@Param			path		query		string					true	"容器内的文件或目录"
+//	@Param			transferID	query		string					false	"传输ID，用于查询进度"
+//	@Success		200			{file}		file					"tar.gz 文件"
+//	@Failure		400			{object}	resputil.Response[any]	"请求参数错误"
+//	@Failure		403			{object}	resputil.Response[any]	"无权访问该 Pod"
+//	@Failure		413			{object}	resputil.Response[any]	"文件超过大小上限"
+//	@Failure		500			{object}	resputil.Response[any]	"其他错误"
+//	@Router			/v1/namespaces/{namespace}/pods/{name}/containers/{container}/files [get]
+func (mgr *APIServerMgr) DownloadContainerFile(c *gin.Context) {
+	var req PodContainerLogURIReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	var query DownloadContainerFileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	target := path.Clean(query.Path)
+	if !path.IsAbs(target) || target == "/" {
+		resputil.BadRequestError(c, "path must be absolute and not the root directory")
+		return
+	}
+
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite); !ok {
+		return
+	}
+
+	_, downloadLimit := fileTransferLimits()
+	size, err := mgr.containerPathSize(c, &req, target)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("stat %s failed: %v", target, err), resputil.NotSpecified)
+		return
+	}
+	if size > downloadLimit {
+		resputil.HTTPError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("%s is about %d bytes, exceeds the limit of %d bytes", target, size, downloadLimit), resputil.InvalidRequest)
+		return
+	}
+
+	transfer := &fileTransfer{
+		id:            query.TransferID,
+		direction:     fileTransferDownload,
+		path:          target,
+		userID:        util.GetToken(c).UserID,
+		namespace:     req.Namespace,
+		podName:       req.PodName,
+		containerName: req.ContainerName,
+		total:         size,
+	}
+	if err := mgr.transfers.start(transfer); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	filename := path.Base(target) + ".tar.gz"
+	c.Header(FileTransferHeader, transfer.id)
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// 在服务端压缩，容器中只需要 tar 命令，进度按压缩前的字节数统计
+	gz := gzip.NewWriter(c.Writer)
+	out := &progressWriter{w: gz, t: transfer, limit: downloadLimit}
+	command := []string{"tar", "-cf", "-", "-C", path.Dir(target), path.Base(target)}
+	err = mgr.execInContainer(c, &req, command, nil, out)
+	transfer.finish(err)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		resputil.Error(c, fmt.Sprintf("download %s failed: %v", target, err), resputil.NotSpecified)
+		return
+	}
+	// 响应已经开始发送，不关闭 gzip 流，客户端会得到不完整的压缩包而不是截断后看似正常的文件
+	klog.Warningf("download %s from %s/%s/%s failed: %v", target, req.Namespace, req.PodName, req.ContainerName, err)
+}
+
+// containerPathSize 使用 du 估算容器中文件或目录的大小
+func (mgr *APIServerMgr) containerPathSize(ctx context.Context, req *PodContainerLogURIReq, target string) (int64, error) {
+	var out bytes.Buffer
+	if err := mgr.execInContainer(ctx, req, []string{"du", "-sk", target}, nil, &out); err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(out.String())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected du output %q", out.String())
+	}
+	kib, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected du output %q", out.String())
+	}
+	return kib << 10, nil
+}
+
+// GetFileTransfer godoc
+//
+//	@Summary		查询文件传输进度
+//	@Description	查询当前用户的上传或下载进度，传输结束后保留 10 分钟
+//	@Tags			Pod
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string								true	"命名空间"
+//	@Param			name		path		string								true	"Pod名称"
+//	@Param			container	path		string								true	"容器名称"
+//	@Param			tid			path		string								true	"传输ID"
+//	@Success		200			{object}	resputil.Response[FileTransferResp]	"传输进度"
+//	@Failure		400			{object}	resputil.Response[any]				"请求参数错误"
+//	@Failure		404			{object}	resputil.Response[any]				"传输不存在"
+//	@Router			/v1/namespaces/{namespace}/pods/{name}/containers/{container}/files/transfers/{tid} [get]
+func (mgr *APIServerMgr) GetFileTransfer(c *gin.Context) {
+	var req FileTransferURIReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	transfer, ok := mgr.transfers.get(util.GetToken(c).UserID, req.TransferID)
+	if !ok || transfer.namespace != req.Namespace || transfer.podName != req.PodName ||
+		transfer.containerName != req.ContainerName {
+		resputil.HTTPError(c, http.StatusNotFound, "file transfer not found", resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, transfer.info())
+}
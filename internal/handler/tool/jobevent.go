@@ -0,0 +1,188 @@
+package tool
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gen"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/eventbus"
+)
+
+const (
+	// jobEventRefreshInterval 从数据库检查作业状态变化的周期，覆盖在其他副本上发生的状态变化
+	jobEventRefreshInterval = 30 * time.Second
+)
+
+type JobEventStreamReq struct {
+	JobName string `form:"jobName"` // 只推送指定作业的事件，用于作业详情页
+}
+
+// jobEventScope 一个连接可以接收的作业事件范围：普通用户只接收自己的作业，
+// 账户管理员接收当前账户中所有作业及自己的镜像构建作业
+type jobEventScope struct {
+	userID       uint
+	accountID    uint
+	accountAdmin bool
+	jobName      string
+}
+
+func (s *jobEventScope) match(e *eventbus.JobEvent) bool {
+	if s.jobName != "" && e.JobName != s.jobName {
+		return false
+	}
+	if e.UserID == s.userID {
+		return e.Kind == eventbus.JobKindBuild || e.AccountID == s.accountID
+	}
+	return s.accountAdmin && e.Kind == eventbus.JobKindVcJob && e.AccountID == s.accountID
+}
+
+// StreamJobEvents godoc
+//
+//	@Summary		Stream job events
+//	@Description	Push phase changes, new events and container restarts of jobs visible to the current user through websocket
+//	@Tags			Job
+//	@Security		Bearer
+//	@Param			token	query	string	true	"access token"
+//	@Param			jobName	query	string	false	"only push events of this job"
+//	@Router			/v1/websocket/jobs/events [get]
+func (mgr *WebsocketMgr) StreamJobEvents(c *gin.Context) {
+	var req JobEventStreamReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	token := util.GetToken(c)
+	scope := &jobEventScope{
+		userID:       token.UserID,
+		accountID:    token.AccountID,
+		accountAdmin: token.RoleAccount == model.RoleAdmin,
+		jobName:      req.JobName,
+	}
+
+	ws, err := upgradeWebsocket(c, nil)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	defer ws.Close()
+
+	events, cancel := eventbus.Subscribe(scope.match)
+	defer cancel()
+
+	// 读取客户端消息以感知连接关闭
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(jobEventRefreshInterval)
+	defer ticker.Stop()
+
+	// phases 记录已推送的作业状态，避免定期检查时重复推送
+	phases := make(map[string]string)
+	since := time.Now()
+	push := func(e *eventbus.JobEvent) error {
+		if e.Type == eventbus.JobEventPhase {
+			phases[string(e.Kind)+"/"+e.JobName] = e.Phase
+		}
+		if err := ws.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
+			return err
+		}
+		return ws.WriteJSON(e)
+	}
+
+	for {
+		select {
+		case e := <-events:
+			if err := push(e); err != nil {
+				klog.Warningf("stop pushing job events to user %s: %v", token.Username, err)
+				return
+			}
+		case <-ticker.C:
+			now := time.Now()
+			changed, err := scope.changedPhases(c, since, phases)
+			if err != nil {
+				klog.Warningf("failed to check job phases for user %s: %v", token.Username, err)
+				continue
+			}
+			since = now
+			for _, e := range changed {
+				if err := push(e); err != nil {
+					klog.Warningf("stop pushing job events to user %s: %v", token.Username, err)
+					return
+				}
+			}
+		case <-closed:
+			return
+		case <-c.Done():
+			return
+		}
+	}
+}
+
+// changedPhases 查询 since 之后更新过、且状态与已推送状态不同的作业
+func (s *jobEventScope) changedPhases(c *gin.Context, since time.Time, phases map[string]string) ([]*eventbus.JobEvent, error) {
+	j := query.Job
+	conds := []gen.Condition{j.AccountID.Eq(s.accountID), j.UpdatedAt.Gt(since)}
+	if !s.accountAdmin {
+		conds = append(conds, j.UserID.Eq(s.userID))
+	}
+	if s.jobName != "" {
+		conds = append(conds, j.JobName.Eq(s.jobName))
+	}
+	jobs, err := j.WithContext(c).Select(j.JobName, j.UserID, j.AccountID, j.Status).Where(conds...).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	k := query.Kaniko
+	buildConds := []gen.Condition{k.UserID.Eq(s.userID), k.UpdatedAt.Gt(since)}
+	if s.jobName != "" {
+		buildConds = append(buildConds, k.ImagePackName.Eq(s.jobName))
+	}
+	builds, err := k.WithContext(c).Select(k.ImagePackName, k.UserID, k.Status).Where(buildConds...).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]*eventbus.JobEvent, 0)
+	for _, job := range jobs {
+		if phases[string(eventbus.JobKindVcJob)+"/"+job.JobName] == string(job.Status) {
+			continue
+		}
+		changed = append(changed, &eventbus.JobEvent{
+			Type:      eventbus.JobEventPhase,
+			Kind:      eventbus.JobKindVcJob,
+			JobName:   job.JobName,
+			UserID:    job.UserID,
+			AccountID: job.AccountID,
+			Phase:     string(job.Status),
+			Timestamp: time.Now(),
+		})
+	}
+	for _, build := range builds {
+		if phases[string(eventbus.JobKindBuild)+"/"+build.ImagePackName] == string(build.Status) {
+			continue
+		}
+		changed = append(changed, &eventbus.JobEvent{
+			Type:      eventbus.JobEventPhase,
+			Kind:      eventbus.JobKindBuild,
+			JobName:   build.ImagePackName,
+			UserID:    build.UserID,
+			Phase:     string(build.Status),
+			Timestamp: time.Now(),
+		})
+	}
+	return changed, nil
+}
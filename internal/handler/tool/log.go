@@ -5,14 +5,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	v1 "k8s.io/api/core/v1"
 
-	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/internal/resputil"
-	"github.com/raids-lab/crater/internal/util"
 )
 
 // 实现流式日志函数
@@ -29,9 +26,7 @@ func (mgr *APIServerMgr) StreamPodContainerLog(c *gin.Context) {
 		return
 	}
 
-	token := util.GetToken(c)
-	if token.RolePlatform != model.RoleAdmin && !strings.Contains(req.PodName, token.Username) {
-		resputil.Error(c, "user not allowed to visit pod log", resputil.UserNotAllowed)
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead); !ok {
 		return
 	}
 
@@ -113,6 +108,10 @@ func (mgr *APIServerMgr) GetPodContainerLog(c *gin.Context) {
 		return
 	}
 
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead); !ok {
+		return
+	}
+
 	// 获取指定 Pod 的日志请求
 	logReq := mgr.kubeClient.CoreV1().Pods(req.Namespace).GetLogs(req.PodName, &v1.PodLogOptions{
 		Container:  req.ContainerName,
@@ -3,7 +3,6 @@ package tool
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	v1 "k8s.io/api/core/v1"
@@ -17,10 +16,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/raids-lab/crater/dao/model"
-	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
-	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
 )
@@ -36,6 +34,8 @@ type APIServerMgr struct {
 	client         client.Client
 	kubeClient     kubernetes.Interface
 	serviceManager crclient.ServiceManagerInterface // Add serviceManager field
+	authorizer     *podAuthorizer
+	transfers      *fileTransferStore
 }
 
 // PortMapping 结构体定义
@@ -100,6 +100,8 @@ func NewAPIServerMgr(conf *handler.RegisterConfig) handler.Manager {
 		client:         conf.Client,
 		kubeClient:     conf.KubeClient,
 		serviceManager: conf.ServiceManager,
+		authorizer:     newPodAuthorizer(conf.Client, conf.KubeClient),
+		transfers:      newFileTransferStore(),
 	}
 }
 
@@ -114,6 +116,11 @@ func (mgr *APIServerMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET(":namespace/pods/:name/containers/:container/log", mgr.GetPodContainerLog)
 	g.GET(":namespace/pods/:name/containers/:container/log/stream", mgr.StreamPodContainerLog)
 
+	// File transfer routes
+	g.POST(":namespace/pods/:name/containers/:container/files", mgr.UploadContainerFile)
+	g.GET(":namespace/pods/:name/containers/:container/files", mgr.DownloadContainerFile)
+	g.GET(":namespace/pods/:name/containers/:container/files/transfers/:tid", mgr.GetFileTransfer)
+
 	// New ingress routes
 	g.GET(":namespace/pods/:name/ingresses", mgr.GetPodIngresses)
 	g.POST(":namespace/pods/:name/ingresses", mgr.CreatePodIngress)
@@ -166,10 +173,9 @@ func (mgr *APIServerMgr) GetPodIngresses(c *gin.Context) {
 		return
 	}
 
-	// Fetch the pod
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
+	// Fetch the pod and check whether the user can access it
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead)
+	if !ok {
 		return
 	}
 
@@ -294,10 +300,9 @@ func (mgr *APIServerMgr) CreatePodIngress(c *gin.Context) {
 		return
 	}
 
-	// Fetch the pod
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
+	// Fetch the pod and check whether the user can access it
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite)
+	if !ok {
 		return
 	}
 
@@ -315,7 +320,7 @@ func (mgr *APIServerMgr) CreatePodIngress(c *gin.Context) {
 	ingressPath, err := mgr.serviceManager.CreateIngress(
 		c,
 		[]metav1.OwnerReference{
-			*metav1.NewControllerRef(&pod, v1.SchemeGroupVersion.WithKind("Pod")),
+			*metav1.NewControllerRef(pod, v1.SchemeGroupVersion.WithKind("Pod")),
 		},
 		podSelector,
 		port,
@@ -360,10 +365,9 @@ func (mgr *APIServerMgr) DeletePodIngress(c *gin.Context) {
 		return
 	}
 
-	// Fetch the pod
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, fmt.Sprintf("Failed to fetch pod: %v", err), resputil.NotSpecified)
+	// Fetch the pod and check whether the user can access it
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite)
+	if !ok {
 		return
 	}
 
@@ -466,9 +470,8 @@ func (mgr *APIServerMgr) GetPodNodeports(c *gin.Context) {
 		return
 	}
 
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead)
+	if !ok {
 		return
 	}
 
@@ -539,10 +542,9 @@ func (mgr *APIServerMgr) CreatePodNodeport(c *gin.Context) {
 		return
 	}
 
-	// Fetch the pod
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
+	// Fetch the pod and check whether the user can access it
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite)
+	if !ok {
 		return
 	}
 
@@ -558,7 +560,7 @@ func (mgr *APIServerMgr) CreatePodNodeport(c *gin.Context) {
 	host, nodePort, err := mgr.serviceManager.CreateNodePort(
 		c,
 		[]metav1.OwnerReference{
-			*metav1.NewControllerRef(&pod, v1.SchemeGroupVersion.WithKind("Pod")),
+			*metav1.NewControllerRef(pod, v1.SchemeGroupVersion.WithKind("Pod")),
 		},
 		podSelector,
 		port,
@@ -605,10 +607,9 @@ func (mgr *APIServerMgr) DeletePodNodeport(c *gin.Context) {
 		return
 	}
 
-	// Fetch the pod
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		resputil.Error(c, fmt.Sprintf("Failed to fetch pod: %v", err), resputil.NotSpecified)
+	// Fetch the pod and check whether the user can access it
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite)
+	if !ok {
 		return
 	}
 
@@ -680,6 +681,7 @@ func (mgr *APIServerMgr) deleteIngress(c *gin.Context, namespace, ingressName st
 }
 
 func (mgr *APIServerMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionJobsEditAny))
 	g.PUT(":namespace/pods/:name/resources", mgr.UpdatePodResources)
 	g.PUT(":namespace/pods/:name/containers/:container/resources", mgr.UpdatePodResources)
 }
@@ -726,24 +728,11 @@ func (mgr *APIServerMgr) GetPodContainers(c *gin.Context) {
 		return
 	}
 
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{Namespace: req.Namespace, Name: req.PodName}, &pod); err != nil {
-		if strings.Contains(err.Error(), "unknown namespace") {
-			// try to get the pod from the kube client
-			if podPtr, err := mgr.kubeClient.CoreV1().Pods(req.Namespace).Get(c, req.PodName, metav1.GetOptions{}); err != nil {
-				resputil.Error(c, err.Error(), resputil.NotSpecified)
-				return
-			} else {
-				pod = *podPtr
-			}
-		} else {
-			resputil.Error(c, err.Error(), resputil.NotSpecified)
-			return
-		}
+	pod, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead)
+	if !ok {
+		return
 	}
 
-	// TODO(liyilong): Check if the user has permission to view the pod.
-
 	var resourceRequestMap = make(map[string]v1.ResourceList)
 	for i := range pod.Spec.InitContainers {
 		container := &pod.Spec.InitContainers[i]
@@ -821,6 +810,10 @@ func (mgr *APIServerMgr) GetPodEvents(c *gin.Context) {
 		return
 	}
 
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessRead); !ok {
+		return
+	}
+
 	// get events
 	events, err := mgr.kubeClient.CoreV1().Events(req.Namespace).List(c, metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("involvedObject.name=%s", req.PodName),
@@ -872,23 +865,8 @@ func (mgr *APIServerMgr) UpdatePodResources(c *gin.Context) {
 		return
 	}
 
-	var pod v1.Pod
-	if err := mgr.client.Get(c, client.ObjectKey{
-		Namespace: uri.Namespace,
-		Name:      uri.PodName,
-	}, &pod); err != nil {
-		resputil.Error(c, fmt.Sprintf("fetch pod: %v", err), resputil.NotSpecified)
-		return
-	}
-
-	if !isJobPod(&pod) {
-		resputil.Error(c, fmt.Sprintf("%s is not job pod", pod.Name), resputil.NotSpecified)
-		return
-	}
-
-	jobName := pod.OwnerReferences[0].Name
-	if !checkUserPermissionForJob(c, jobName) {
-		resputil.Error(c, fmt.Sprintf("no permission for pod: %s", pod.Name), resputil.NotSpecified)
+	pod, ok := mgr.authorizer.authorize(c, uri.Namespace, uri.PodName, podAccessWrite)
+	if !ok {
 		return
 	}
 
@@ -897,7 +875,7 @@ func (mgr *APIServerMgr) UpdatePodResources(c *gin.Context) {
 		containerName = &uri.Container
 	}
 
-	if err := mgr.EditPodResource(c, &pod, containerName, req.Resources); err != nil {
+	if err := mgr.EditPodResource(c, pod, containerName, req.Resources); err != nil {
 		resputil.Error(c, fmt.Sprintf("Edit resources: %v", err), resputil.NotSpecified)
 		return
 	}
@@ -914,29 +892,6 @@ func isJobPod(pod *v1.Pod) bool {
 	return owner.Kind == "Job" && owner.APIVersion == "batch.volcano.sh/v1alpha1"
 }
 
-func checkUserPermissionForJob(c *gin.Context, jobName string) bool {
-	token := util.GetToken(c)
-	if token.RolePlatform == model.RoleAdmin {
-		return true
-	}
-
-	jobDB := query.Job
-
-	job, err := jobDB.WithContext(c).Where(jobDB.Name.Eq(jobName)).First()
-	if err != nil {
-		return false
-	}
-
-	if job.AccountID != token.AccountID {
-		return false
-	}
-	if job.UserID != token.UserID {
-		return false
-	}
-
-	return true
-}
-
 func (mgr *APIServerMgr) EditPodResource(
 	c *gin.Context,
 	pod *v1.Pod,
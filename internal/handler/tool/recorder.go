@@ -0,0 +1,279 @@
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	// defaultRecordingMaxSize 单个录像的默认大小上限
+	defaultRecordingMaxSize = 16 << 20
+	// 录像开始时终端大小未知，先使用默认值，前端发送 resize 后记录为 "r" 帧
+	defaultRecordingWidth  = 80
+	defaultRecordingHeight = 24
+	// recordingSaveTimeout 会话结束后保存录像元数据的超时时间
+	recordingSaveTimeout = 10 * time.Second
+	// recordingNotice 开始录像时提示用户，不计入录像内容
+	recordingNotice = "\r\n\x1b[33m[Crater] This terminal session is being recorded.\x1b[0m\r\n"
+	// recordingLimitNotice 必须录像的会话达到大小上限，会话被关闭时提示用户
+	recordingLimitNotice = "\r\n\x1b[31m[Crater] Recording size limit reached, the session is closed.\x1b[0m\r\n"
+)
+
+// castHeader asciinema v2 录像的首行
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// terminalRecorder 将终端的输入输出按 asciinema v2 格式逐帧写入录像。
+// 容器输出和客户端输入在不同的 goroutine 中记录，写入时需要加锁
+type terminalRecorder struct {
+	mu        sync.Mutex
+	record    *model.TerminalRecording
+	start     time.Time
+	file      *os.File
+	buf       bytes.Buffer
+	w         *bufio.Writer
+	size      int64
+	maxSize   int64
+	truncated bool
+	closed    bool
+	// pending 上一帧输出末尾不完整的 UTF-8 字符，与下一帧合并后再记录
+	pending []byte
+	// mandatory 会话必须录像，录像达到大小上限时结束会话
+	mandatory bool
+	// onLimit 必须录像的会话达到大小上限时调用，用于结束会话，保证他人在作业中的操作都有录像
+	onLimit func()
+}
+
+// shouldRecordTerminal 作业所有者以外的用户 (平台管理员、账户管理员、拥有 jobs:edit-any 权限的用户)
+// 打开的会话必须录像，所有者的会话根据配置决定
+func shouldRecordTerminal(grant podGrant) bool {
+	return grant.onBehalfOfOwner() || config.GetConfig().Terminal.Recording.EnableForUsers
+}
+
+// newTerminalRecorder 创建录像记录并写入录像头，不需要录像时返回 nil
+func newTerminalRecorder(c *gin.Context, req *PodContainerTerminalReq, grant podGrant) (*terminalRecorder, error) {
+	token := util.GetToken(c)
+	if !shouldRecordTerminal(grant) {
+		return nil, nil
+	}
+	conf := config.GetConfig().Terminal.Recording
+
+	now := time.Now()
+	record := &model.TerminalRecording{
+		UserID:        token.UserID,
+		Username:      token.Username,
+		IsAdmin:       token.RolePlatform == model.RoleAdmin,
+		Namespace:     req.Namespace,
+		PodName:       req.PodName,
+		ContainerName: req.ContainerName,
+		StartedAt:     now,
+	}
+	r := query.TerminalRecording
+	if err := r.WithContext(c).Create(record); err != nil {
+		return nil, fmt.Errorf("create terminal recording: %w", err)
+	}
+
+	rec := &terminalRecorder{
+		record:    record,
+		start:     now,
+		maxSize:   conf.MaxSize,
+		mandatory: grant.onBehalfOfOwner(),
+	}
+	if rec.maxSize == 0 {
+		rec.maxSize = defaultRecordingMaxSize
+	}
+
+	var dest io.Writer = &rec.buf
+	if conf.Dir != "" {
+		file, path, err := createRecordingFile(conf.Dir, record)
+		if err == nil {
+			_, err = r.WithContext(c).Where(r.ID.Eq(record.ID)).Update(r.Path, path)
+		}
+		if err != nil {
+			if file != nil {
+				_ = file.Close()
+			}
+			if _, delErr := r.WithContext(c).Unscoped().Where(r.ID.Eq(record.ID)).Delete(); delErr != nil {
+				klog.Errorf("delete terminal recording %d failed: %v", record.ID, delErr)
+			}
+			return nil, err
+		}
+		record.Path = path
+		rec.file = file
+		dest = file
+	}
+	rec.w = bufio.NewWriter(dest)
+
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     defaultRecordingWidth,
+		Height:    defaultRecordingHeight,
+		Timestamp: now.Unix(),
+		Title:     fmt.Sprintf("%s@%s/%s/%s", token.Username, req.Namespace, req.PodName, req.ContainerName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rec.writeLine(header)
+	return rec, nil
+}
+
+// createRecordingFile 在录像目录下按月份分目录创建录像文件，返回文件和路径
+func createRecordingFile(dir string, record *model.TerminalRecording) (*os.File, string, error) {
+	monthDir := filepath.Join(dir, record.StartedAt.Format("200601"))
+	if err := os.MkdirAll(monthDir, 0o750); err != nil {
+		return nil, "", fmt.Errorf("create recording directory: %w", err)
+	}
+	path := filepath.Join(monthDir, fmt.Sprintf("%d-%s.cast", record.ID, record.PodName))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, "", fmt.Errorf("create recording file: %w", err)
+	}
+	return file, path, nil
+}
+
+// Output 记录容器的输出，末尾不完整的 UTF-8 字符留到下一帧，避免被替换为乱码
+func (rec *terminalRecorder) Output(p []byte) {
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	data := append(rec.pending, p...)
+	rec.pending = nil
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				rec.pending = data[i:]
+				data = data[:i]
+			}
+			break
+		}
+	}
+	if len(data) > 0 {
+		rec.writeFrame("o", string(data))
+	}
+}
+
+// Input 记录用户的输入。必须录像的会话已达到大小上限时返回 false，输入不能再发送到容器
+func (rec *terminalRecorder) Input(data string) bool {
+	if rec == nil {
+		return true
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.writeFrame("i", data)
+	return !rec.truncated || !rec.mandatory
+}
+
+// Resize 记录终端大小的变化
+func (rec *terminalRecorder) Resize(cols, rows uint16) {
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.writeFrame("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// writeFrame 写入一帧 [时间, 类型, 数据]，调用方需持有锁
+func (rec *terminalRecorder) writeFrame(kind, data string) {
+	if rec.closed || rec.truncated {
+		return
+	}
+	elapsed := math.Round(time.Since(rec.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]any{elapsed, kind, data})
+	if err != nil {
+		return
+	}
+	if rec.size+int64(len(line))+1 > rec.maxSize {
+		rec.truncated = true
+		if rec.mandatory && rec.onLimit != nil {
+			klog.Warningf("mandatory terminal recording %d of %s reached the size limit, closing the session",
+				rec.record.ID, rec.record.Username)
+			// 调用方持有录像锁，在新的 goroutine 中结束会话
+			go rec.onLimit()
+		}
+		return
+	}
+	rec.writeLine(line)
+}
+
+func (rec *terminalRecorder) writeLine(line []byte) {
+	_, _ = rec.w.Write(line)
+	_ = rec.w.WriteByte('\n')
+	rec.size += int64(len(line)) + 1
+}
+
+// Close 结束录像并保存会话时长、大小等元数据
+func (rec *terminalRecorder) Close() {
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.closed {
+		return
+	}
+	if len(rec.pending) > 0 {
+		rec.writeFrame("o", string(rec.pending))
+		rec.pending = nil
+	}
+	rec.closed = true
+
+	if err := rec.w.Flush(); err != nil {
+		klog.Errorf("flush terminal recording %d failed: %v", rec.record.ID, err)
+	}
+	if rec.file != nil {
+		if err := rec.file.Close(); err != nil {
+			klog.Errorf("close terminal recording %d failed: %v", rec.record.ID, err)
+		}
+	}
+
+	now := time.Now()
+	record := rec.record
+	record.EndedAt = &now
+	record.Duration = math.Round(now.Sub(rec.start).Seconds()*1000) / 1000
+	record.Size = rec.size
+	record.Truncated = rec.truncated
+
+	ctx, cancel := context.WithTimeout(context.Background(), recordingSaveTimeout)
+	defer cancel()
+	r := query.TerminalRecording
+	update := r.WithContext(ctx).Where(r.ID.Eq(record.ID))
+	var err error
+	if rec.file == nil {
+		record.Data = rec.buf.Bytes()
+		_, err = update.Select(r.EndedAt, r.Duration, r.Size, r.Truncated, r.Data).Updates(record)
+	} else {
+		_, err = update.Select(r.EndedAt, r.Duration, r.Size, r.Truncated).Updates(record)
+	}
+	if err != nil {
+		klog.Errorf("save terminal recording %d failed: %v", record.ID, err)
+	}
+}
@@ -0,0 +1,165 @@
+package tool
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gen"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	handler.Registers = append(handler.Registers, NewTerminalRecordingMgr)
+}
+
+// defaultRecordingPageSize 默认每页录像数
+const defaultRecordingPageSize = 50
+
+type TerminalRecordingMgr struct {
+	name string
+}
+
+func NewTerminalRecordingMgr(_ *handler.RegisterConfig) handler.Manager {
+	return &TerminalRecordingMgr{
+		name: "recordings",
+	}
+}
+
+func (mgr *TerminalRecordingMgr) GetName() string { return mgr.name }
+
+func (mgr *TerminalRecordingMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *TerminalRecordingMgr) RegisterProtected(_ *gin.RouterGroup) {}
+
+func (mgr *TerminalRecordingMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionAuditRead))
+	g.GET("", mgr.ListRecordings)
+	g.GET(":id/download", mgr.DownloadRecording)
+}
+
+type (
+	ListRecordingsReq struct {
+		Username  *string    `form:"username"`  // 按打开终端的用户过滤
+		Namespace *string    `form:"namespace"` // 按 Pod 命名空间过滤
+		PodName   *string    `form:"podName"`   // 按 Pod 名称过滤，支持前缀匹配
+		From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+		Page      int        `form:"page"`     // 页码，从 0 开始
+		PageSize  int        `form:"pageSize"` // 每页大小
+	}
+
+	ListRecordingsResp struct {
+		Recordings []*model.TerminalRecording `json:"recordings"`
+		Total      int64                      `json:"total"`
+	}
+
+	RecordingIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+)
+
+func (req *ListRecordingsReq) conditions() []gen.Condition {
+	r := query.TerminalRecording
+	conds := []gen.Condition{}
+	if req.Username != nil {
+		conds = append(conds, r.Username.Eq(*req.Username))
+	}
+	if req.Namespace != nil {
+		conds = append(conds, r.Namespace.Eq(*req.Namespace))
+	}
+	if req.PodName != nil {
+		conds = append(conds, r.PodName.Like(*req.PodName+"%"))
+	}
+	if req.From != nil {
+		conds = append(conds, r.StartedAt.Gte(*req.From))
+	}
+	if req.To != nil {
+		conds = append(conds, r.StartedAt.Lt(*req.To))
+	}
+	return conds
+}
+
+// ListRecordings godoc
+//
+//	@Summary		查询终端录像
+//	@Description	按用户、Pod 和时间范围查询 Web 终端会话录像
+//	@Tags			TerminalRecording
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			username	query		string									false	"打开终端的用户"
+//	@Param			namespace	query		string									false	"Pod 命名空间"
+//	@Param			podName		query		string									false	"Pod 名称前缀"
+//	@Param			from		query		string									false	"开始时间 (RFC3339)"
+//	@Param			to			query		string									false	"结束时间 (RFC3339)"
+//	@Param			page		query		int										false	"页码，从 0 开始"
+//	@Param			pageSize	query		int										false	"每页大小"
+//	@Success		200			{object}	resputil.Response[ListRecordingsResp]	"终端录像"
+//	@Failure		400			{object}	resputil.Response[any]					"请求参数错误"
+//	@Failure		500			{object}	resputil.Response[any]					"其他错误"
+//	@Router			/v1/admin/recordings [get]
+func (mgr *TerminalRecordingMgr) ListRecordings(c *gin.Context) {
+	var req ListRecordingsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultRecordingPageSize
+	}
+	if req.Page < 0 {
+		req.Page = 0
+	}
+
+	r := query.TerminalRecording
+	recordings, total, err := r.WithContext(c).Omit(r.Data).Where(req.conditions()...).Order(r.ID.Desc()).
+		FindByPage(req.Page*req.PageSize, req.PageSize)
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("list terminal recordings failed, detail: %v", err), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, ListRecordingsResp{Recordings: recordings, Total: total})
+}
+
+// DownloadRecording godoc
+//
+//	@Summary		下载终端录像
+//	@Description	下载 asciinema v2 格式的录像文件，可使用 asciinema play 回放
+//	@Tags			TerminalRecording
+//	@Produce		application/x-asciicast
+//	@Security		Bearer
+//	@Param			id	path		uint					true	"录像ID"
+//	@Success		200	{file}		file					"录像文件"
+//	@Failure		400	{object}	resputil.Response[any]	"请求参数错误"
+//	@Failure		404	{object}	resputil.Response[any]	"录像不存在"
+//	@Router			/v1/admin/recordings/{id}/download [get]
+func (mgr *TerminalRecordingMgr) DownloadRecording(c *gin.Context) {
+	var req RecordingIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	r := query.TerminalRecording
+	record, err := r.WithContext(c).Where(r.ID.Eq(req.ID)).First()
+	if err != nil {
+		resputil.HTTPError(c, http.StatusNotFound, fmt.Sprintf("terminal recording %d not found", req.ID), resputil.NotSpecified)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s.cast", record.Username, record.PodName, record.StartedAt.Format("20060102-150405"))
+	if record.Path != "" {
+		c.FileAttachment(record.Path, filename)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/x-asciicast", record.Data)
+}
@@ -0,0 +1,467 @@
+package tool
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	// defaultReattachTimeout 浏览器断开后保留 Shell 的默认时间
+	defaultReattachTimeout = 5 * time.Minute
+	// terminalScrollbackSize 保留的最近输出，重新连接或观看时先回放这部分内容
+	terminalScrollbackSize = 64 << 10
+	// terminalSessionIDBytes 会话ID和分享令牌的随机字节数
+	terminalSessionIDBytes = 16
+	// 每个用户、每个 Pod 默认的会话数上限，包括已断开但仍可重新连接的会话
+	defaultMaxSessionsPerUser = 10
+	defaultMaxSessionsPerPod  = 20
+)
+
+var (
+	errTerminalSessionClosed = errors.New("terminal session is closed")
+	errTerminalSessionLimit  = errors.New("too many terminal sessions, close some of them and retry")
+)
+
+// terminalClient 连接到终端会话的一个 WebSocket 客户端。
+// gorilla/websocket 不支持并发写，输出广播和控制消息通过 mu 串行写入
+type terminalClient struct {
+	ws       *websocket.Conn
+	mu       sync.Mutex
+	readOnly bool
+}
+
+func (cl *terminalClient) write(p []byte) error {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if err := cl.ws.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
+		return err
+	}
+	return cl.ws.WriteMessage(websocket.TextMessage, p)
+}
+
+func (cl *terminalClient) close(reason string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason)
+	_ = cl.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(WriteTimeout))
+	_ = cl.ws.Close()
+}
+
+// terminalSession 服务端保存的终端会话。
+// Shell 的 exec 流不依赖浏览器连接，浏览器断开后在 ReattachTimeout 内可以重新连接；
+// 同一时间只有一个可输入的客户端，持有分享令牌的用户可以只读观看。
+// 会话保存在当前副本的内存中，多副本部署时 Ingress 需要开启会话保持
+type terminalSession struct {
+	id            string
+	userID        uint
+	username      string
+	namespace     string
+	podName       string
+	containerName string
+	createdAt     time.Time
+
+	recorder *terminalRecorder
+	stdinR   *io.PipeReader
+	stdinW   *io.PipeWriter
+	sizeChan chan remotecommand.TerminalSize
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu         sync.Mutex
+	writer     *terminalClient
+	viewers    map[*terminalClient]struct{}
+	scrollback []byte
+	detachedAt *time.Time
+	timer      *time.Timer
+	shareToken string
+}
+
+// TerminalSessionResp 终端会话的信息
+type TerminalSessionResp struct {
+	ID            string     `json:"id"`
+	Username      string     `json:"username"`
+	ContainerName string     `json:"containerName"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	Attached      bool       `json:"attached"`   // 是否有可输入的客户端连接
+	DetachedAt    *time.Time `json:"detachedAt"` // 断开连接的时间，超过重连时间后会话被关闭
+	Viewers       int        `json:"viewers"`    // 只读观看的客户端数
+	Shared        bool       `json:"shared"`     // 是否已生成分享令牌
+}
+
+func randomTerminalToken() (string, error) {
+	b := make([]byte, terminalSessionIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func terminalSessionLimits() (perUser, perPod int) {
+	conf := config.GetConfig().Terminal
+	perUser, perPod = conf.MaxSessionsPerUser, conf.MaxSessionsPerPod
+	if perUser == 0 {
+		perUser = defaultMaxSessionsPerUser
+	}
+	if perPod == 0 {
+		perPod = defaultMaxSessionsPerPod
+	}
+	return perUser, perPod
+}
+
+func reattachTimeout() time.Duration {
+	if timeout := config.GetConfig().Terminal.ReattachTimeout; timeout > 0 {
+		return time.Duration(timeout) * time.Second
+	}
+	return defaultReattachTimeout
+}
+
+// Write 实现 io.Writer，接收容器的输出并广播给所有客户端
+func (s *terminalSession) Write(p []byte) (int, error) {
+	s.recorder.Output(p)
+
+	s.mu.Lock()
+	s.scrollback = append(s.scrollback, p...)
+	if over := len(s.scrollback) - terminalScrollbackSize; over > 0 {
+		s.scrollback = append(s.scrollback[:0], s.scrollback[over:]...)
+	}
+	clients := s.clientsLocked()
+	s.mu.Unlock()
+
+	for _, cl := range clients {
+		if err := cl.write(p); err != nil {
+			s.detach(cl)
+			cl.close("write failed")
+		}
+	}
+	return len(p), nil
+}
+
+// Next 实现 remotecommand.TerminalSizeQueue
+func (s *terminalSession) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-s.sizeChan:
+		return &size
+	case <-s.done:
+		return nil
+	}
+}
+
+func (s *terminalSession) clientsLocked() []*terminalClient {
+	clients := make([]*terminalClient, 0, len(s.viewers)+1)
+	if s.writer != nil {
+		clients = append(clients, s.writer)
+	}
+	for cl := range s.viewers {
+		clients = append(clients, cl)
+	}
+	return clients
+}
+
+// attach 连接客户端并回放最近的输出，一直阻塞到客户端断开或会话结束。
+// 新的可输入客户端会替换旧的客户端，例如用户在新标签页中重新打开了同一个会话
+func (s *terminalSession) attach(ws *websocket.Conn, readOnly bool) {
+	cl := &terminalClient{ws: ws, readOnly: readOnly}
+
+	s.mu.Lock()
+	select {
+	case <-s.done:
+		s.mu.Unlock()
+		cl.close(errTerminalSessionClosed.Error())
+		return
+	default:
+	}
+	var replaced *terminalClient
+	if readOnly {
+		s.viewers[cl] = struct{}{}
+	} else {
+		replaced = s.writer
+		s.writer = cl
+		s.detachedAt = nil
+		if s.timer != nil {
+			s.timer.Stop()
+			s.timer = nil
+		}
+	}
+	// 在锁内回放，保证回放内容和之后广播的输出之间不会丢失或重复
+	scrollback := append([]byte(nil), s.scrollback...)
+	if len(scrollback) > 0 {
+		_ = cl.write(scrollback)
+	}
+	s.mu.Unlock()
+
+	if replaced != nil {
+		replaced.close("attached from another client")
+	}
+
+	s.readLoop(cl)
+	s.detach(cl)
+}
+
+// readLoop 处理客户端的输入和终端大小调整，只读客户端的消息被丢弃
+func (s *terminalSession) readLoop(cl *terminalClient) {
+	for {
+		_, message, err := cl.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if cl.readOnly {
+			continue
+		}
+
+		var msg TerminalMessage
+		if err := json.Unmarshal(message, &msg); err == nil {
+			if msg.Op == "resize" {
+				s.recorder.Resize(msg.Cols, msg.Rows)
+				select {
+				case s.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}:
+				case <-s.done:
+					return
+				}
+				continue
+			}
+			if msg.Op == "stdin" {
+				message = []byte(msg.Data)
+			}
+		}
+
+		if !s.recorder.Input(string(message)) {
+			return
+		}
+		if _, err := s.stdinW.Write(message); err != nil {
+			return
+		}
+	}
+}
+
+// detach 断开客户端。可输入的客户端断开后开始计时，超时未重新连接则关闭会话
+func (s *terminalSession) detach(cl *terminalClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cl.readOnly {
+		delete(s.viewers, cl)
+		return
+	}
+	if s.writer != cl {
+		return
+	}
+	s.writer = nil
+	now := time.Now()
+	s.detachedAt = &now
+	s.timer = time.AfterFunc(reattachTimeout(), func() {
+		klog.Infof("terminal session %s on %s/%s expired after detach", s.id, s.namespace, s.podName)
+		s.kill()
+	})
+}
+
+// kill 结束 Shell，exec 流返回后由 run 清理会话
+func (s *terminalSession) kill() {
+	s.cancel()
+	_ = s.stdinW.CloseWithError(errTerminalSessionClosed)
+}
+
+// run 运行 exec 流直到 Shell 退出或会话被关闭，然后断开所有客户端并结束录像
+func (s *terminalSession) run(ctx context.Context, executor remotecommand.Executor, onExit func()) {
+	err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             s.stdinR,
+		Stdout:            s,
+		Stderr:            s,
+		Tty:               true,
+		TerminalSizeQueue: s,
+	})
+	if err != nil && ctx.Err() == nil {
+		klog.Warningf("terminal session %s on %s/%s exited: %v", s.id, s.namespace, s.podName, err)
+	}
+
+	s.mu.Lock()
+	close(s.done)
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	clients := s.clientsLocked()
+	s.writer = nil
+	s.viewers = map[*terminalClient]struct{}{}
+	s.mu.Unlock()
+
+	s.cancel()
+	_ = s.stdinR.Close()
+	for _, cl := range clients {
+		cl.close("session closed")
+	}
+	s.recorder.Close()
+	onExit()
+}
+
+// closeForRecordingLimit 必须录像的会话达到大小上限，提示所有客户端后结束会话
+func (s *terminalSession) closeForRecordingLimit() {
+	s.mu.Lock()
+	clients := s.clientsLocked()
+	s.mu.Unlock()
+	for _, cl := range clients {
+		_ = cl.write([]byte(recordingLimitNotice))
+	}
+	s.kill()
+}
+
+// share 生成只读分享令牌，重新生成会使旧令牌失效
+func (s *terminalSession) share() (string, error) {
+	token, err := randomTerminalToken()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.shareToken = token
+	s.mu.Unlock()
+	return token, nil
+}
+
+// unshare 撤销分享令牌并断开所有只读客户端
+func (s *terminalSession) unshare() {
+	s.mu.Lock()
+	s.shareToken = ""
+	viewers := make([]*terminalClient, 0, len(s.viewers))
+	for cl := range s.viewers {
+		viewers = append(viewers, cl)
+	}
+	s.mu.Unlock()
+	for _, cl := range viewers {
+		cl.close("share revoked")
+	}
+}
+
+func (s *terminalSession) checkShareToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shareToken != "" && subtle.ConstantTimeCompare([]byte(s.shareToken), []byte(token)) == 1
+}
+
+func (s *terminalSession) info() TerminalSessionResp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return TerminalSessionResp{
+		ID:            s.id,
+		Username:      s.username,
+		ContainerName: s.containerName,
+		CreatedAt:     s.createdAt,
+		Attached:      s.writer != nil,
+		DetachedAt:    s.detachedAt,
+		Viewers:       len(s.viewers),
+		Shared:        s.shareToken != "",
+	}
+}
+
+// terminalSessionStore 当前副本上的所有终端会话
+type terminalSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*terminalSession
+}
+
+func newTerminalSessionStore() *terminalSessionStore {
+	return &terminalSessionStore{sessions: make(map[string]*terminalSession)}
+}
+
+// start 创建会话并在后台运行 exec 流，会话不随请求的 Context 结束。
+// 用户或 Pod 的会话数达到上限时，先结束该用户最早断开的会话，仍超出上限时返回错误
+func (st *terminalSessionStore) start(
+	executor remotecommand.Executor,
+	session *terminalSession,
+) error {
+	st.mu.Lock()
+	if err := st.admitLocked(session); err != nil {
+		st.mu.Unlock()
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	session.stdinR, session.stdinW = io.Pipe()
+	session.sizeChan = make(chan remotecommand.TerminalSize)
+	session.cancel = cancel
+	session.done = make(chan struct{})
+	session.viewers = make(map[*terminalClient]struct{})
+	session.createdAt = time.Now()
+	if session.recorder != nil {
+		session.recorder.onLimit = session.closeForRecordingLimit
+	}
+	st.sessions[session.id] = session
+	st.mu.Unlock()
+
+	go session.run(ctx, executor, func() {
+		st.mu.Lock()
+		delete(st.sessions, session.id)
+		st.mu.Unlock()
+	})
+	return nil
+}
+
+// admitLocked 检查会话数上限，调用方需持有 st.mu。
+// 被结束的会话立即从列表中移除，不再计入上限
+func (st *terminalSessionStore) admitLocked(session *terminalSession) error {
+	perUser, perPod := terminalSessionLimits()
+	for {
+		var userCount, podCount int
+		var oldestDetached *terminalSession
+		var oldestDetachedAt time.Time
+		for _, s := range st.sessions {
+			sameUser := s.userID == session.userID
+			if sameUser {
+				userCount++
+			}
+			if s.namespace == session.namespace && s.podName == session.podName {
+				podCount++
+			}
+			if !sameUser {
+				continue
+			}
+			s.mu.Lock()
+			if s.detachedAt != nil && (oldestDetached == nil || s.detachedAt.Before(oldestDetachedAt)) {
+				oldestDetached, oldestDetachedAt = s, *s.detachedAt
+			}
+			s.mu.Unlock()
+		}
+		if userCount < perUser && podCount < perPod {
+			return nil
+		}
+		if oldestDetached == nil {
+			return errTerminalSessionLimit
+		}
+		klog.Infof("terminal session limit reached for user %s, close detached session %s",
+			session.username, oldestDetached.id)
+		delete(st.sessions, oldestDetached.id)
+		oldestDetached.kill()
+	}
+}
+
+func (st *terminalSessionStore) get(id string) (*terminalSession, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[id]
+	return s, ok
+}
+
+// listByPod 返回 Pod 上的所有会话，按创建时间排序
+func (st *terminalSessionStore) listByPod(namespace, podName string) []*terminalSession {
+	st.mu.Lock()
+	sessions := make([]*terminalSession, 0)
+	for _, s := range st.sessions {
+		if s.namespace == namespace && s.podName == podName {
+			sessions = append(sessions, s)
+		}
+	}
+	st.mu.Unlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].createdAt.Before(sessions[j].createdAt)
+	})
+	return sessions
+}
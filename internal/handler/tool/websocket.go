@@ -1,8 +1,7 @@
 package tool
 
 import (
-	"context"
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -13,10 +12,12 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/raids-lab/crater/internal/handler"
 	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
 )
 
@@ -30,6 +31,8 @@ type WebsocketMgr struct {
 	config     *rest.Config
 	client     client.Client
 	kubeClient kubernetes.Interface
+	authorizer *podAuthorizer
+	sessions   *terminalSessionStore
 }
 
 func NewWebsocketMgr(conf *handler.RegisterConfig) handler.Manager {
@@ -38,6 +41,8 @@ func NewWebsocketMgr(conf *handler.RegisterConfig) handler.Manager {
 		config:     conf.KubeConfig,
 		client:     conf.Client,
 		kubeClient: conf.KubeClient,
+		authorizer: newPodAuthorizer(conf.Client, conf.KubeClient),
+		sessions:   newTerminalSessionStore(),
 	}
 }
 
@@ -48,6 +53,12 @@ func (mgr *WebsocketMgr) RegisterAdmin(_ *gin.RouterGroup)  {}
 
 func (mgr *WebsocketMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("namespaces/:namespace/pods/:name/containers/:container/terminal", mgr.GetPodContainerTerminal)
+	g.GET("namespaces/:namespace/pods/:name/terminal/sessions", mgr.ListTerminalSessions)
+	g.DELETE("namespaces/:namespace/pods/:name/terminal/sessions/:sid", mgr.KillTerminalSession)
+	g.POST("namespaces/:namespace/pods/:name/terminal/sessions/:sid/share", mgr.ShareTerminalSession)
+	g.DELETE("namespaces/:namespace/pods/:name/terminal/sessions/:sid/share", mgr.UnshareTerminalSession)
+	g.GET("terminal/sessions/:sid/watch", mgr.WatchTerminalSession)
+	g.GET("jobs/events", mgr.StreamJobEvents)
 }
 
 type (
@@ -57,13 +68,43 @@ type (
 		PodName       string `uri:"name" binding:"required"`
 		ContainerName string `uri:"container" binding:"required"`
 	}
+
+	PodContainerTerminalQuery struct {
+		// SessionID 重新连接到已有的会话，为空时创建新会话
+		SessionID string `form:"session"`
+	}
+
+	PodTerminalSessionsReq struct {
+		// from uri
+		Namespace string `uri:"namespace" binding:"required"`
+		PodName   string `uri:"name" binding:"required"`
+	}
+
+	PodTerminalSessionReq struct {
+		// from uri
+		Namespace string `uri:"namespace" binding:"required"`
+		PodName   string `uri:"name" binding:"required"`
+		SessionID string `uri:"sid" binding:"required"`
+	}
+
+	TerminalShareResp struct {
+		SessionID string `json:"sessionID"`
+		Token     string `json:"token"` // 只读分享令牌，观看时作为 token 查询参数
+	}
+
+	WatchTerminalSessionReq struct {
+		// from uri
+		SessionID string `uri:"sid" binding:"required"`
+		// from query
+		Token string `form:"token" binding:"required"`
+	}
 )
 
 const (
 	// WriteTimeout specifies the maximum duration for completing a write operation.
 	WriteTimeout = 10 * time.Second
-	// EndOfTransmission represents the signal for ending the transmission (Ctrl+D).
-	EndOfTransmission = "\u0004"
+	// TerminalSessionHeader 升级 WebSocket 时在响应头中返回会话ID，浏览器刷新后也可以通过会话列表找回
+	TerminalSessionHeader = "X-Terminal-Session"
 )
 
 // 首先定义终端大小消息的结构
@@ -74,95 +115,43 @@ type TerminalMessage struct {
 	Rows uint16 `json:"rows"` // 行数
 }
 
-type streamHandler struct {
-	ws       *websocket.Conn
-	sizeChan chan remotecommand.TerminalSize
-	doneChan chan struct{}
-}
-
-// 实现TerminalSizeQueue接口的Next方法
-func (h *streamHandler) Next() *remotecommand.TerminalSize {
-	select {
-	case size := <-h.sizeChan:
-		return &size
-	case <-h.doneChan:
-		return nil
-	}
-}
-
-func (h *streamHandler) Write(p []byte) (int, error) {
-	if err := h.ws.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
-		// If setting the write deadline fails, return the error immediately.
-		return 0, err
+func upgradeWebsocket(c *gin.Context, header http.Header) (*websocket.Conn, error) {
+	var upgrade = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
 	}
-	err := h.ws.WriteMessage(websocket.TextMessage, p)
-	return len(p), err
-}
-
-// References:
-// - https://github.com/kubernetes/client-go/issues/554
-// - https://github.com/juicedata/juicefs-csi-driver/pull/1053
-func (h *streamHandler) Read(p []byte) (int, error) {
-	_, message, err := h.ws.ReadMessage()
-	if err != nil {
-		// Returns "0x04" on error
-		return copy(p, EndOfTransmission), err
-	}
-
-	// 尝试解析为终端消息
-	var msg TerminalMessage
-	if err := json.Unmarshal(message, &msg); err == nil {
-		// 如果是resize操作
-		if msg.Op == "resize" {
-			h.sizeChan <- remotecommand.TerminalSize{
-				Width:  msg.Cols,
-				Height: msg.Rows,
-			}
-			return 0, nil
-		}
-
-		// 如果是stdin操作，使用Data字段
-		if msg.Op == "stdin" {
-			return copy(p, msg.Data), nil
+	// Allow all origins in debug mode
+	if config.IsDebugMode() {
+		upgrade.CheckOrigin = func(_ *http.Request) bool {
+			return true
 		}
 	}
-
-	return copy(p, message), nil
+	return upgrade.Upgrade(c.Writer, c.Request, header)
 }
 
-// TODO: 通过 Pod Labels，检查当前访问的 Pod 是否属于当前用户
+// GetPodContainerTerminal 打开容器终端。Shell 在服务端的会话中运行，浏览器断开后在重连时间内
+// 可以通过 session 查询参数重新连接，只有会话的创建者可以重新连接
 func (mgr *WebsocketMgr) GetPodContainerTerminal(c *gin.Context) {
 	var req PodContainerTerminalReq
 	if err := c.ShouldBindUri(&req); err != nil {
 		resputil.BadRequestError(c, err.Error())
 		return
 	}
-	var upgrade = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-	}
-	// Allow all origins in debug mode
-	if config.IsDebugMode() {
-		upgrade.CheckOrigin = func(_ *http.Request) bool {
-			return true
-		}
-	}
-	ws, err := upgrade.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
+	var query PodContainerTerminalQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
 		resputil.BadRequestError(c, err.Error())
 		return
 	}
-	defer ws.Close()
-
-	ctx, cancel := context.WithCancel(c)
-	defer cancel()
+	// 在升级为 WebSocket 之前校验权限，拒绝时返回普通的 HTTP 错误
+	_, grant, ok := mgr.authorizer.authorizeGrant(c, req.Namespace, req.PodName, podAccessWrite)
+	if !ok {
+		return
+	}
 
-	stream := &streamHandler{
-		ws:       ws,
-		sizeChan: make(chan remotecommand.TerminalSize),
-		doneChan: make(chan struct{}),
+	if query.SessionID != "" {
+		mgr.reattachTerminalSession(c, &req, query.SessionID)
+		return
 	}
-	defer close(stream.doneChan)
 
 	// Reference: https://github.com/juicedata/juicefs-csi-driver/pull/1053
 	request := mgr.kubeClient.CoreV1().RESTClient().Post().
@@ -184,15 +173,222 @@ func (mgr *WebsocketMgr) GetPodContainerTerminal(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
-	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:             stream,
-		Stdout:            stream,
-		Stderr:            stream,
-		Tty:               true,
-		TerminalSizeQueue: stream,
-	})
+	sessionID, err := randomTerminalToken()
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	// 打开他人作业的终端时必须录像，无法录像时拒绝打开终端；所有者的会话录像失败不影响使用
+	token := util.GetToken(c)
+	recorder, err := newTerminalRecorder(c, &req, grant)
+	if err != nil {
+		if grant.onBehalfOfOwner() {
+			resputil.Error(c, fmt.Sprintf("failed to start terminal recording: %v", err), resputil.ServiceError)
+			return
+		}
+		klog.Warningf("start terminal recording for %s/%s failed: %v", req.Namespace, req.PodName, err)
+	}
+
+	// 在升级为 WebSocket 之前启动会话，会话数超出上限时返回普通的 HTTP 错误。
+	// 连接前的输出保存在最近输出中，连接后回放
+	session := &terminalSession{
+		id:            sessionID,
+		userID:        token.UserID,
+		username:      token.Username,
+		namespace:     req.Namespace,
+		podName:       req.PodName,
+		containerName: req.ContainerName,
+		recorder:      recorder,
+	}
+	if err = mgr.sessions.start(executor, session); err != nil {
+		recorder.Close()
+		resputil.HTTPError(c, http.StatusTooManyRequests, err.Error(), resputil.RateLimited)
+		return
+	}
+
+	ws, err := upgradeWebsocket(c, http.Header{TerminalSessionHeader: []string{sessionID}})
+	if err != nil {
+		session.kill()
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if recorder != nil {
+		_ = ws.WriteMessage(websocket.TextMessage, []byte(recordingNotice))
+	}
+	session.attach(ws, false)
+}
+
+func (mgr *WebsocketMgr) reattachTerminalSession(c *gin.Context, req *PodContainerTerminalReq, sessionID string) {
+	session, ok := mgr.sessions.get(sessionID)
+	if !ok || session.namespace != req.Namespace || session.podName != req.PodName ||
+		session.containerName != req.ContainerName {
+		resputil.HTTPError(c, http.StatusNotFound, "terminal session not found or expired", resputil.NotSpecified)
+		return
+	}
+	if session.userID != util.GetToken(c).UserID {
+		resputil.HTTPError(c, http.StatusForbidden, "only the owner can reattach to the terminal session", resputil.UserNotAllowed)
+		return
+	}
+	ws, err := upgradeWebsocket(c, http.Header{TerminalSessionHeader: []string{sessionID}})
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	session.attach(ws, false)
+}
+
+// ListTerminalSessions godoc
+//
+//	@Summary		查询 Pod 上的终端会话
+//	@Description	返回当前副本上该 Pod 的所有终端会话，包括已断开但仍可重新连接的会话
+//	@Tags			Terminal
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string											true	"命名空间"
+//	@Param			name		path		string											true	"Pod 名称"
+//	@Success		200			{object}	resputil.Response[[]TerminalSessionResp]		"终端会话"
+//	@Failure		400			{object}	resputil.Response[any]							"请求参数错误"
+//	@Failure		403			{object}	resputil.Response[any]							"无权访问该 Pod"
+//	@Router			/v1/websocket/namespaces/{namespace}/pods/{name}/terminal/sessions [get]
+func (mgr *WebsocketMgr) ListTerminalSessions(c *gin.Context) {
+	var req PodTerminalSessionsReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite); !ok {
+		return
+	}
+	sessions := mgr.sessions.listByPod(req.Namespace, req.PodName)
+	resp := make([]TerminalSessionResp, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, s.info())
+	}
+	resputil.Success(c, resp)
+}
+
+// KillTerminalSession godoc
+//
+//	@Summary		关闭终端会话
+//	@Description	结束会话中的 Shell 并断开所有客户端，可以访问该 Pod 的用户都可以关闭
+//	@Tags			Terminal
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string					true	"命名空间"
+//	@Param			name		path		string					true	"Pod 名称"
+//	@Param			sid			path		string					true	"会话ID"
+//	@Success		200			{object}	resputil.Response[any]	"关闭成功"
+//	@Failure		400			{object}	resputil.Response[any]	"请求参数错误"
+//	@Failure		404			{object}	resputil.Response[any]	"会话不存在"
+//	@Router			/v1/websocket/namespaces/{namespace}/pods/{name}/terminal/sessions/{sid} [delete]
+func (mgr *WebsocketMgr) KillTerminalSession(c *gin.Context) {
+	session, ok := mgr.podTerminalSession(c, false)
+	if !ok {
+		return
+	}
+	session.kill()
+	resputil.Success(c, nil)
+}
+
+// ShareTerminalSession godoc
+//
+//	@Summary		分享终端会话
+//	@Description	生成只读分享令牌，持有令牌的登录用户可以实时观看会话，重新生成会使旧令牌失效
+//	@Tags			Terminal
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string									true	"命名空间"
+//	@Param			name		path		string									true	"Pod 名称"
+//	@Param			sid			path		string									true	"会话ID"
+//	@Success		200			{object}	resputil.Response[TerminalShareResp]	"分享令牌"
+//	@Failure		400			{object}	resputil.Response[any]					"请求参数错误"
+//	@Failure		403			{object}	resputil.Response[any]					"不是会话的创建者"
+//	@Failure		404			{object}	resputil.Response[any]					"会话不存在"
+//	@Router			/v1/websocket/namespaces/{namespace}/pods/{name}/terminal/sessions/{sid}/share [post]
+func (mgr *WebsocketMgr) ShareTerminalSession(c *gin.Context) {
+	session, ok := mgr.podTerminalSession(c, true)
+	if !ok {
+		return
+	}
+	shareToken, err := session.share()
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
+	resputil.Success(c, TerminalShareResp{SessionID: session.id, Token: shareToken})
+}
+
+// UnshareTerminalSession godoc
+//
+//	@Summary		取消分享终端会话
+//	@Description	撤销只读分享令牌并断开所有观看者
+//	@Tags			Terminal
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			namespace	path		string					true	"命名空间"
+//	@Param			name		path		string					true	"Pod 名称"
+//	@Param			sid			path		string					true	"会话ID"
+//	@Success		200			{object}	resputil.Response[any]	"取消成功"
+//	@Failure		400			{object}	resputil.Response[any]	"请求参数错误"
+//	@Failure		403			{object}	resputil.Response[any]	"不是会话的创建者"
+//	@Failure		404			{object}	resputil.Response[any]	"会话不存在"
+//	@Router			/v1/websocket/namespaces/{namespace}/pods/{name}/terminal/sessions/{sid}/share [delete]
+func (mgr *WebsocketMgr) UnshareTerminalSession(c *gin.Context) {
+	session, ok := mgr.podTerminalSession(c, true)
+	if !ok {
+		return
+	}
+	session.unshare()
+	resputil.Success(c, nil)
+}
+
+// podTerminalSession 校验 Pod 的访问权限并返回会话，ownerOnly 为 true 时只有会话的创建者可以操作
+func (mgr *WebsocketMgr) podTerminalSession(c *gin.Context, ownerOnly bool) (*terminalSession, bool) {
+	var req PodTerminalSessionReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return nil, false
+	}
+	if _, ok := mgr.authorizer.authorize(c, req.Namespace, req.PodName, podAccessWrite); !ok {
+		return nil, false
+	}
+	session, ok := mgr.sessions.get(req.SessionID)
+	if !ok || session.namespace != req.Namespace || session.podName != req.PodName {
+		resputil.HTTPError(c, http.StatusNotFound, "terminal session not found or expired", resputil.NotSpecified)
+		return nil, false
+	}
+	if ownerOnly && session.userID != util.GetToken(c).UserID {
+		resputil.HTTPError(c, http.StatusForbidden, "only the owner can share the terminal session", resputil.UserNotAllowed)
+		return nil, false
+	}
+	return session, true
+}
+
+// WatchTerminalSession 使用分享令牌只读观看终端会话，观看者的输入被丢弃
+func (mgr *WebsocketMgr) WatchTerminalSession(c *gin.Context) {
+	var req WatchTerminalSessionReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	session, ok := mgr.sessions.get(req.SessionID)
+	if !ok || !session.checkShareToken(req.Token) {
+		resputil.HTTPError(c, http.StatusNotFound, "terminal session not found or share link revoked", resputil.NotSpecified)
+		return
+	}
+	ws, err := upgradeWebsocket(c, nil)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	session.attach(ws, true)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,7 @@ import (
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/utils"
@@ -41,11 +43,14 @@ func (mgr *UserMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *UserMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.Use(middleware.RequirePermission(model.PermissionUsersManage))
 	g.GET("", mgr.ListUser)
 	g.GET("/baseinfo", mgr.ListUserBaseInfo)
 	g.DELETE("/:name", mgr.DeleteUser)
-	g.PUT("/:name/role", mgr.UpdateRole)
+	g.PUT("/:name/role", middleware.RequirePermission(model.PermissionRolesManage), mgr.UpdateRole)
 	g.PUT("/:name/attributes", mgr.UpdateUserAttributesByAdmin)
+	g.POST("/:name/logout", mgr.ForceLogoutUser)
+	g.DELETE("/:name/mfa", mgr.ResetUserMFA)
 }
 
 type UserResp struct {
@@ -98,7 +103,15 @@ type UserBaseInfoResp struct {
 func (mgr *UserMgr) DeleteUser(c *gin.Context) {
 	name := c.Param("name")
 	u := query.User
-	_, err := u.WithContext(c).Where(u.Name.Eq(name)).Delete()
+	user, err := u.WithContext(c).Where(u.Name.Eq(name)).First()
+	if err != nil {
+		resputil.Error(c, fmt.Sprintf("user %s not found", name), resputil.NotSpecified)
+		return
+	}
+	if !checkManagedUser(c, user) {
+		return
+	}
+	_, err = u.WithContext(c).Where(u.ID.Eq(user.ID)).Delete()
 
 	if err != nil {
 		resputil.Error(c, fmt.Sprintf("delete user failed, detail: %v", err), resputil.NotSpecified)
@@ -210,7 +223,7 @@ func (mgr *UserMgr) GetUser(c *gin.Context) {
 // UpdateRole godoc
 //
 //	@Summary		更新角色
-//	@Description	更新角色
+//	@Description	更新用户的平台角色，需要 roles:manage 权限。不能修改自己的角色，也不能授予高于自己的角色
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
@@ -239,8 +252,24 @@ func (mgr *UserMgr) UpdateRole(c *gin.Context) {
 		return
 	}
 	u := query.User
-	_, err := u.WithContext(c).Where(u.Name.Eq(name)).Update(u.Role, req.Role)
+	user, err := u.WithContext(c).Where(u.Name.Eq(name)).First()
 	if err != nil {
+		resputil.Error(c, fmt.Sprintf("user %s not found", name), resputil.NotSpecified)
+		return
+	}
+	token := util.GetToken(c)
+	if user.ID == token.UserID {
+		resputil.HTTPError(c, http.StatusForbidden, "can not change your own role", resputil.UserNotAllowed)
+		return
+	}
+	if req.Role > token.RolePlatform {
+		resputil.HTTPError(c, http.StatusForbidden, "can not grant a role higher than your own", resputil.UserNotAllowed)
+		return
+	}
+	if !checkManagedUser(c, user) {
+		return
+	}
+	if _, err = u.WithContext(c).Where(u.ID.Eq(user.ID)).Update(u.Role, req.Role); err != nil {
 		resputil.Error(c, fmt.Sprintf("update user role failed, detail: %v", err), resputil.NotSpecified)
 		return
 	}
@@ -318,6 +347,9 @@ func (mgr *UserMgr) UpdateUserAttributesByAdmin(c *gin.Context) {
 		resputil.Error(c, "User not found", resputil.NotSpecified)
 		return
 	}
+	if !checkManagedUser(c, user) {
+		return
+	}
 
 	user.Attributes = datatypes.NewJSONType(attributes)
 	user.Nickname = attributes.Nickname
@@ -329,3 +361,14 @@ func (mgr *UserMgr) UpdateUserAttributesByAdmin(c *gin.Context) {
 	klog.Infof("update user attributes success by admin, username: %s", name)
 	resputil.Success(c, "用户属性更新成功")
 }
+
+// checkManagedUser 校验当前用户能否管理目标用户：被委派 users:manage 权限的用户不能管理平台管理员。
+// 校验失败时已写入响应
+func checkManagedUser(c *gin.Context, target *model.User) bool {
+	if target.Role == model.RoleAdmin && util.GetToken(c).RolePlatform != model.RoleAdmin {
+		resputil.HTTPError(c, http.StatusForbidden,
+			fmt.Sprintf("only platform admins can manage platform admin %s", target.Name), resputil.UserNotAllowed)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package vcjob
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	handler.Registers = append(handler.Registers, NewAccountJobMgr)
+}
+
+// AccountJobMgr 账户管理员查看和删除账户内的作业，与 AccountMgr 共用 /v1/accounts/{aid}/manage 路由前缀
+type AccountJobMgr struct {
+	name   string
+	client client.Client
+}
+
+func NewAccountJobMgr(conf *handler.RegisterConfig) handler.Manager {
+	return &AccountJobMgr{
+		name:   "accounts",
+		client: conf.Client,
+	}
+}
+
+func (mgr *AccountJobMgr) GetName() string { return mgr.name }
+
+func (mgr *AccountJobMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *AccountJobMgr) RegisterProtected(g *gin.RouterGroup) {
+	manage := g.Group(handler.AccountManagePath, middleware.AuthAccountAdmin(handler.AccountManageParam))
+	manage.GET("jobs", mgr.ListAccountJobs)
+	manage.DELETE("jobs/:job", mgr.DeleteAccountJob)
+}
+
+func (mgr *AccountJobMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	AccountJobListReq struct {
+		Days int `form:"days"` // 返回最近几天创建的作业，-1 表示全部，默认为 7 天
+	}
+
+	AccountJobReq struct {
+		JobName string `uri:"job" binding:"required"`
+	}
+)
+
+// ListAccountJobs godoc
+//
+//	@Summary		账户管理员获取账户内的作业
+//	@Description	获取账户内所有成员的作业，默认返回最近 7 天创建的作业
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid		path		uint						true	"account id"
+//	@Param			days	query		int							false	"Number of days to look back, -1 for all"
+//	@Success		200		{object}	resputil.Response[[]JobResp]	"作业列表"
+//	@Failure		403		{object}	resputil.Response[any]		"不是账户管理员"
+//	@Failure		500		{object}	resputil.Response[any]		"其他错误"
+//	@Router			/v1/accounts/{aid}/manage/jobs [get]
+func (mgr *AccountJobMgr) ListAccountJobs(c *gin.Context) {
+	var req AccountJobListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	j := query.Job
+	q := j.WithContext(c).Preload(j.Account).Preload(j.User).Where(j.AccountID.Eq(util.GetManagedAccountID(c)))
+	if req.Days != -1 {
+		days := 7
+		if req.Days > 0 {
+			days = req.Days
+		}
+		q = q.Where(j.CreatedAt.Gte(time.Now().AddDate(0, 0, -days)))
+	}
+
+	jobs, err := q.Find()
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	resputil.Success(c, convertJobResp(jobs))
+}
+
+// DeleteAccountJob godoc
+//
+//	@Summary		账户管理员删除账户内的作业
+//	@Description	删除账户内任意成员的作业
+//	@Tags			AccountManage
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint					true	"account id"
+//	@Param			job	path		string					true	"Job Name"
+//	@Success		200	{object}	resputil.Response[any]	"Success"
+//	@Failure		400	{object}	resputil.Response[any]	"Request parameter error"
+//	@Failure		403	{object}	resputil.Response[any]	"不是账户管理员"
+//	@Failure		500	{object}	resputil.Response[any]	"Other errors"
+//	@Router			/v1/accounts/{aid}/manage/jobs/{job} [delete]
+func (mgr *AccountJobMgr) DeleteAccountJob(c *gin.Context) {
+	var req AccountJobReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
+	j := query.Job
+	if _, err := j.WithContext(c).Where(j.JobName.Eq(req.JobName), j.AccountID.Eq(util.GetManagedAccountID(c))).First(); err != nil {
+		resputil.Error(c, "job not found in this account", resputil.NotSpecified)
+		return
+	}
+
+	removeJob(c, mgr.client, req.JobName)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
+	"github.com/raids-lab/crater/pkg/sshkey"
 )
 
 type VolumeType uint
@@ -115,6 +116,11 @@ func GenerateVolumeMounts(
 		SubPath:   "start.sh",
 	})
 
+	// 挂载用户登记的 SSH 公钥，开启 SSH 时 sshd 从该目录读取 authorized_keys
+	sshKeyVolume, sshKeyMount := sshkey.Volume(token.UserID)
+	pvc = append(pvc, sshKeyVolume)
+	volumeMounts = append(volumeMounts, sshKeyMount)
+
 	return pvc, volumeMounts, nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/middleware"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/config"
@@ -30,6 +31,7 @@ import (
 	"github.com/raids-lab/crater/pkg/imageregistry"
 	"github.com/raids-lab/crater/pkg/monitor"
 	"github.com/raids-lab/crater/pkg/packer"
+	"github.com/raids-lab/crater/pkg/sshkey"
 	"github.com/raids-lab/crater/pkg/utils"
 )
 
@@ -95,9 +97,9 @@ func (mgr *VolcanojobMgr) RegisterProtected(g *gin.RouterGroup) {
 }
 
 func (mgr *VolcanojobMgr) RegisterAdmin(g *gin.RouterGroup) {
-	g.GET("", mgr.GetAllJobsInDays)
+	g.GET("", middleware.RequirePermission(model.PermissionJobsReadAny), mgr.GetAllJobsInDays)
 	// delete job
-	g.DELETE(":name", mgr.DeleteJobForAdmin)
+	g.DELETE(":name", middleware.RequirePermission(model.PermissionJobsDeleteAny), mgr.DeleteJobForAdmin)
 }
 
 const (
@@ -190,19 +192,24 @@ func (mgr *VolcanojobMgr) deleteJob(c *gin.Context) {
 
 	// Get job record from database
 	token := util.GetToken(c)
-	j := query.Job
 	_, err := getJob(c, req.JobName, &token)
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
 
+	removeJob(c, mgr.client, req.JobName)
+}
+
+// removeJob 删除集群中的作业，已结束或已不存在的作业同时删除数据库记录，调用前需要完成权限校验
+func removeJob(c *gin.Context, cl client.Client, jobName string) {
+	j := query.Job
 	shouldDeleteRecord := false
 	shouldDeleteJob := false
 
 	job := &batch.Job{}
 	namespace := config.GetConfig().Namespaces.Job
-	if err := mgr.client.Get(c, client.ObjectKey{Name: req.JobName, Namespace: namespace}, job); err != nil {
+	if err := cl.Get(c, client.ObjectKey{Name: jobName, Namespace: namespace}, job); err != nil {
 		if errors.IsNotFound(err) {
 			shouldDeleteRecord = true
 		} else {
@@ -223,13 +230,13 @@ func (mgr *VolcanojobMgr) deleteJob(c *gin.Context) {
 	}
 
 	if shouldDeleteRecord {
-		if _, err := j.WithContext(c).Where(j.JobName.Eq(req.JobName)).Delete(); err != nil {
+		if _, err := j.WithContext(c).Where(j.JobName.Eq(jobName)).Delete(); err != nil {
 			resputil.Error(c, err.Error(), resputil.NotSpecified)
 			return
 		}
 	} else {
 		// update job status as deleted
-		if _, err := j.WithContext(c).Where(j.JobName.Eq(req.JobName)).Updates(model.Job{
+		if _, err := j.WithContext(c).Where(j.JobName.Eq(jobName)).Updates(model.Job{
 			Status:             model.Deleted,
 			CompletedTimestamp: time.Now(),
 		}); err != nil {
@@ -240,7 +247,7 @@ func (mgr *VolcanojobMgr) deleteJob(c *gin.Context) {
 
 	// 直接删除 Job，OwnerReference 会自动删除 Ingress 和 Service
 	if shouldDeleteJob {
-		if err := mgr.client.Delete(c, job); err != nil {
+		if err := cl.Delete(c, job); err != nil {
 			resputil.Error(c, err.Error(), resputil.NotSpecified)
 			return
 		}
@@ -448,8 +455,12 @@ type (
 
 	// SSHInfo 定义 SSH 信息的结构体
 	SSHInfo struct {
-		IP   string `json:"ip"`
-		Port string `json:"port"`
+		IP        string `json:"ip"`
+		Port      string `json:"port"`
+		Username  string `json:"username"`            // 登录容器的用户
+		Command   string `json:"command"`             // 可直接执行的 ssh 命令
+		ProxyJump string `json:"proxyJump,omitempty"` // 跳板机，为空表示直接连接节点
+		SSHConfig string `json:"sshConfig"`           // 可追加到 ~/.ssh/config 的配置
 	}
 
 	// SSHResp 定义返回的 SSH 信息的结构体
@@ -597,14 +608,17 @@ func (mgr *VolcanojobMgr) OpenSSH(c *gin.Context) {
 			resputil.Error(c, "invalid ssh enabled value", resputil.NotSpecified)
 			return
 		}
-		resputil.Success(c, SSHInfo{
-			IP:   splits[0],
-			Port: splits[1],
-		})
+		resputil.Success(c, newSSHInfo(job.JobName, splits[0], splits[1]))
 		return
 	}
 
-	// 检查并创建sshd用户，然后启动sshd服务
+	// 同步作业所属用户最新的公钥，作业创建后新增的公钥也能登录。管理员为其他用户的作业开启 SSH 时，
+	// 容器挂载的是作业所属用户的 ConfigMap
+	if err = sshkey.Sync(c, mgr.kubeClient, job.UserID); err != nil {
+		klog.Errorf("failed to sync ssh keys before opening ssh: %v", err)
+	}
+
+	// 检查并创建sshd用户，让 sshd 同时读取用户登记的公钥，然后启动sshd服务
 	commands := []string{
 		"sh",
 		"-c",
@@ -615,7 +629,8 @@ func (mgr *VolcanojobMgr) OpenSSH(c *gin.Context) {
 			echo "SSHD user creation completed";
 		else
 			echo "SSHD user already exists, skipping creation";
-		fi && 
+		fi &&
+		` + sshkey.SetupScript() + ` &&
 		service ssh restart`,
 	}
 
@@ -651,10 +666,7 @@ func (mgr *VolcanojobMgr) OpenSSH(c *gin.Context) {
 	}
 
 	// 4. Update the pod annotation with the SSH information
-	sshInfo := SSHInfo{
-		IP:   ip,
-		Port: fmt.Sprintf("%d", port),
-	}
+	sshInfo := newSSHInfo(job.JobName, ip, fmt.Sprintf("%d", port))
 	sshInfoStr := fmt.Sprintf("%s:%d", ip, port)
 	pod.Annotations[AnnotationKeySSHEnabled] = sshInfoStr
 	if err := mgr.client.Update(c, &pod); err != nil {
@@ -664,6 +676,30 @@ func (mgr *VolcanojobMgr) OpenSSH(c *gin.Context) {
 	resputil.Success(c, sshInfo)
 }
 
+// newSSHInfo 生成可直接使用的 ssh 命令和 ~/.ssh/config 配置，配置了跳板机时通过 ProxyJump 连接
+func newSSHInfo(jobName, ip, port string) SSHInfo {
+	conf := config.GetConfig().SSH
+	username := conf.LoginUser
+	if username == "" {
+		username = "root"
+	}
+
+	command := fmt.Sprintf("ssh -p %s %s@%s", port, username, ip)
+	sshConfig := fmt.Sprintf("Host %s\n  HostName %s\n  Port %s\n  User %s\n", jobName, ip, port, username)
+	if conf.ProxyJump != "" {
+		command = fmt.Sprintf("ssh -J %s -p %s %s@%s", conf.ProxyJump, port, username, ip)
+		sshConfig += fmt.Sprintf("  ProxyJump %s\n", conf.ProxyJump)
+	}
+	return SSHInfo{
+		IP:        ip,
+		Port:      port,
+		Username:  username,
+		Command:   command,
+		ProxyJump: conf.ProxyJump,
+		SSHConfig: sshConfig,
+	}
+}
+
 // GetJobPods godoc
 //
 //	@Summary		获取任务的Pod列表
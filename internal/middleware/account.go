@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// AuthAccountAdmin 校验当前用户是否为路径参数 param 指定账户的管理员，
+// 平台管理员和拥有 accounts:manage 权限的用户可以管理所有账户。
+// 通过校验后被管理的账户 ID 保存在上下文中，可通过 util.GetManagedAccountID 获取
+func AuthAccountAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, err := strconv.ParseUint(c.Param(param), 10, 64)
+		if err != nil || accountID == 0 {
+			resputil.BadRequestError(c, "invalid account id")
+			c.Abort()
+			return
+		}
+		aid := uint(accountID)
+
+		a := query.Account
+		if _, err := a.WithContext(c).Where(a.ID.Eq(aid)).First(); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				resputil.HTTPError(c, http.StatusNotFound, "account not found", resputil.InvalidRequest)
+			} else {
+				resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+			}
+			c.Abort()
+			return
+		}
+
+		allowed, err := isAccountAdmin(c, aid)
+		if err != nil {
+			resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			resputil.HTTPError(c, http.StatusForbidden, "Permission denied, not admin of this account", resputil.UserNotAllowed)
+			c.Abort()
+			return
+		}
+
+		c.Set(util.ManagedAccountIDKey, aid)
+		c.Next()
+	}
+}
+
+func isAccountAdmin(c *gin.Context, accountID uint) (bool, error) {
+	token := util.GetToken(c)
+	if token.RolePlatform == model.RoleAdmin || HasPermission(c, model.PermissionAccountsManage) {
+		return true, nil
+	}
+
+	// 个人访问令牌绑定在签发时的账户上，且权限范围可能降低了账户角色，只按令牌中的信息判断
+	if _, ok := util.GetAPITokenID(c); ok {
+		return token.AccountID == accountID && token.RoleAccount == model.RoleAdmin, nil
+	}
+
+	ua := query.UserAccount
+	_, err := ua.WithContext(c).
+		Where(ua.UserID.Eq(token.UserID), ua.AccountID.Eq(accountID), ua.Role.Eq(uint8(model.RoleAdmin))).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
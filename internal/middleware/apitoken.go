@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// apiTokenTouchInterval 最近使用时间的更新间隔，避免每个请求都写数据库
+const apiTokenTouchInterval = time.Minute
+
+var (
+	errAPITokenInvalid  = errors.New("invalid api token")
+	errAPITokenExpired  = errors.New("api token expired")
+	errAPITokenReadOnly = errors.New("read-only api token can not modify resources")
+	errAPITokenUser     = errors.New("user of the api token is not active")
+	errAPITokenAccount  = errors.New("user is not a member of the account bound to the api token")
+)
+
+// checkAPIToken 校验个人访问令牌，并根据数据库中的用户、账户信息和令牌权限范围构造请求上下文。
+// 令牌权限范围不是 admin 时，用户在账户和平台中的角色降为普通用户
+func checkAPIToken(c *gin.Context, raw string) (util.JWTMessage, error) {
+	t := query.APIToken
+	token, err := t.WithContext(c).Where(t.TokenHash.Eq(util.HashAPIToken(raw))).First()
+	if err != nil {
+		return util.JWTMessage{}, errAPITokenInvalid
+	}
+	now := time.Now()
+	if token.Expired(now) {
+		return util.JWTMessage{}, errAPITokenExpired
+	}
+	if token.Scope == model.APITokenScopeReadOnly && !isReadOnlyMethod(c.Request.Method) {
+		return util.JWTMessage{}, errAPITokenReadOnly
+	}
+
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil || user.Status != model.StatusActive {
+		return util.JWTMessage{}, errAPITokenUser
+	}
+	a := query.Account
+	account, err := a.WithContext(c).Where(a.ID.Eq(token.AccountID)).First()
+	if err != nil {
+		return util.JWTMessage{}, errAPITokenAccount
+	}
+	uq := query.UserAccount
+	userAccount, err := uq.WithContext(c).Where(uq.UserID.Eq(user.ID), uq.AccountID.Eq(account.ID)).First()
+	if err != nil {
+		return util.JWTMessage{}, errAPITokenAccount
+	}
+	publicAccessMode := model.AccessModeNA
+	if defaultUserAccount, err := uq.WithContext(c).
+		Where(uq.UserID.Eq(user.ID), uq.AccountID.Eq(model.DefaultAccountID)).First(); err == nil {
+		publicAccessMode = defaultUserAccount.AccessMode
+	}
+
+	msg := util.JWTMessage{
+		UserID:            user.ID,
+		Username:          user.Name,
+		AccountID:         account.ID,
+		AccountName:       account.Name,
+		RoleAccount:       userAccount.Role,
+		AccountAccessMode: userAccount.AccessMode,
+		PublicAccessMode:  publicAccessMode,
+		RolePlatform:      user.Role,
+	}
+	if token.Scope != model.APITokenScopeAdmin {
+		msg.RoleAccount = model.RoleUser
+		msg.RolePlatform = model.RoleUser
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenTouchInterval {
+		if _, err := t.WithContext(c).Where(t.ID.Eq(token.ID)).UpdateSimple(t.LastUsedAt.Value(now)); err != nil {
+			klog.Warningf("failed to update last used time of api token %d: %v", token.ID, err)
+		}
+	}
+	c.Set(util.APITokenIDKey, token.ID)
+	c.Set(util.APITokenScopeKey, token.Scope)
+	return msg, nil
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+const (
+	// auditBodyReadLimit 读取请求体的最大长度，超过时不记录请求体
+	auditBodyReadLimit = 64 << 10
+	// auditBodyMaxLength 审计日志中保存的请求体最大长度
+	auditBodyMaxLength = 4096
+	// auditResponseCaptureLimit 为解析错误码而缓存的响应体长度
+	auditResponseCaptureLimit = 2048
+	// auditMessageMaxLength 审计日志中保存的错误信息最大长度
+	auditMessageMaxLength = 1024
+
+	auditMaskedValue = "******"
+)
+
+// sensitiveFieldKeywords 字段名包含这些关键字时，请求体中的值会被替换为掩码。
+// code 和 otp 覆盖两步验证码、邮箱验证码和密码重置验证码
+var sensitiveFieldKeywords = []string{
+	"password", "passwd", "secret", "token", "credential", "privatekey", "accesskey", "totp", "otp", "code", "recovery",
+}
+
+// auditResponseWriter 在写出响应的同时缓存响应体的开头部分，用于解析错误码
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) capture(b []byte) {
+	if remaining := auditResponseCaptureLimit - w.body.Len(); remaining > 0 {
+		w.body.Write(b[:min(len(b), remaining)])
+	}
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Audit 记录修改类请求 (POST, PUT, PATCH, DELETE) 的审计日志，需要放在 AuthProtected 之后
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isReadOnlyMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body := readAuditBody(c)
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		auditLog := newAuditLog(c, body, time.Since(start))
+		resultCode, message := parseAuditResponse(writer.body.Bytes())
+		auditLog.ResultCode = resultCode
+		auditLog.Message = message
+		saveAuditLog(c, auditLog)
+	}
+}
+
+func newAuditLog(c *gin.Context, body string, latency time.Duration) *model.AuditLog {
+	token := util.GetToken(c)
+	apiTokenID, _ := util.GetAPITokenID(c)
+	params := make(map[string]string, len(c.Params))
+	for _, param := range c.Params {
+		params[param.Key] = param.Value
+	}
+	return &model.AuditLog{
+		UserID:      token.UserID,
+		Username:    token.Username,
+		AccountID:   token.AccountID,
+		AccountName: token.AccountName,
+		APITokenID:  apiTokenID,
+		ClientIP:    c.ClientIP(),
+		Method:      c.Request.Method,
+		Route:       c.FullPath(),
+		Path:        truncateString(c.Request.URL.Path, 1024),
+		Params:      datatypes.NewJSONType(params),
+		Body:        body,
+		StatusCode:  c.Writer.Status(),
+		LatencyMs:   latency.Milliseconds(),
+	}
+}
+
+func saveAuditLog(c *gin.Context, auditLog *model.AuditLog) {
+	// 请求可能已被客户端取消，审计日志仍需写入
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := query.AuditLog.WithContext(ctx).Create(auditLog); err != nil {
+		klog.Errorf("failed to save audit log of %s %s by %s: %v", auditLog.Method, auditLog.Path, auditLog.Username, err)
+	}
+}
+
+// readAuditBody 读取并还原请求体，只记录 JSON 请求体，并对敏感字段脱敏
+func readAuditBody(c *gin.Context) string {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 {
+		return ""
+	}
+	if !strings.HasPrefix(c.ContentType(), gin.MIMEJSON) {
+		return "[" + c.ContentType() + " body omitted]"
+	}
+
+	head, err := io.ReadAll(io.LimitReader(c.Request.Body, auditBodyReadLimit+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), c.Request.Body), Closer: c.Request.Body}
+	if err != nil {
+		return ""
+	}
+	if len(head) > auditBodyReadLimit {
+		return "[body too large]"
+	}
+	return sanitizeAuditBody(head)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// sanitizeAuditBody 将 JSON 中名称包含敏感关键字的字段替换为掩码，并截断过长的内容
+func sanitizeAuditBody(body []byte) string {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "[invalid json body]"
+	}
+	sanitized, err := json.Marshal(maskSensitiveFields(data))
+	if err != nil {
+		return ""
+	}
+	return truncateString(string(sanitized), auditBodyMaxLength)
+}
+
+func maskSensitiveFields(data any) any {
+	switch v := data.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSensitiveField(key) {
+				v[key] = auditMaskedValue
+			} else {
+				v[key] = maskSensitiveFields(value)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = maskSensitiveFields(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, keyword := range sensitiveFieldKeywords {
+		if strings.Contains(key, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAuditResponse 从 resputil 格式的响应中解析错误码和错误信息
+func parseAuditResponse(body []byte) (code int, message string) {
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, ""
+	}
+	return resp.Code, truncateString(resp.Msg, auditMessageMaxLength)
+}
+
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// 截断时丢弃被切断的多字节字符
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// AuditDenied 记录一次被拒绝的访问。只读请求不经过 Audit 记录，拒绝查看日志、打开终端等操作时需要显式写入审计日志；
+// 修改类请求的拒绝结果由 Audit 在请求结束后记录
+func AuditDenied(c *gin.Context, reason string) {
+	if !isReadOnlyMethod(c.Request.Method) {
+		return
+	}
+	auditLog := newAuditLog(c, "", 0)
+	auditLog.StatusCode = http.StatusForbidden
+	auditLog.ResultCode = int(resputil.UserNotAllowed)
+	auditLog.Message = truncateString(reason, auditMessageMaxLength)
+	saveAuditLog(c, auditLog)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeAuditBody(t *testing.T) {
+	body := `{"name":"alice","password":"p@ss","code":"123456","mfaCode":"654321",` +
+		`"nested":{"access_key":"ak","items":[{"clientSecret":"s","value":1}]}}`
+	got := sanitizeAuditBody([]byte(body))
+	for _, secret := range []string{"p@ss", "123456", "654321", `"ak"`, `"s"`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("sanitized body %s still contains %s", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"name":"alice"`) || !strings.Contains(got, `"value":1`) {
+		t.Errorf("sanitized body %s lost normal fields", got)
+	}
+
+	if got := sanitizeAuditBody([]byte("not json")); got != "[invalid json body]" {
+		t.Errorf("unexpected result for invalid body: %s", got)
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	if got := truncateString("中文", 4); got != "中" {
+		t.Errorf("expected truncated string to drop partial rune, got %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -29,6 +30,27 @@ func AuthProtected() gin.HandlerFunc {
 			}
 			authToken = t[1]
 		}
+
+		// 个人访问令牌每次请求都从数据库校验
+		if util.IsAPIToken(authToken) {
+			token, err := checkAPIToken(c, authToken)
+			if err != nil {
+				switch {
+				case errors.Is(err, errAPITokenReadOnly):
+					resputil.HTTPError(c, http.StatusForbidden, err.Error(), resputil.UserNotAllowed)
+				case errors.Is(err, errAPITokenExpired):
+					resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenExpired)
+				default:
+					resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenInvalid)
+				}
+				c.Abort()
+				return
+			}
+			util.SetJWTContext(c, token)
+			c.Next()
+			return
+		}
+
 		token, err := util.GetTokenMgr().CheckToken(authToken)
 		if err != nil {
 			resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenExpired)
@@ -36,6 +58,13 @@ func AuthProtected() gin.HandlerFunc {
 			return
 		}
 
+		// 每个请求都校验会话和用户状态，结果短暂缓存
+		if err := ValidateSession(c, &token); err != nil {
+			resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.TokenInvalid)
+			c.Abort()
+			return
+		}
+
 		// 如果查询方法不是 GET (e.g. POST, PUT, DELETE), 从数据库中校验权限
 		if c.Request.Method != "GET" {
 			u := query.User
@@ -73,10 +102,17 @@ func AuthProtected() gin.HandlerFunc {
 	}
 }
 
+// AuthAdmin 管理接口的入口校验：平台管理员，或被分配了任意管理权限的用户可以进入，
+// 具体接口再通过 RequirePermission 校验所需的权限
 func AuthAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := util.GetToken(c)
-		if token.RolePlatform != model.RoleAdmin {
+		if token.RolePlatform == model.RoleAdmin {
+			c.Next()
+			return
+		}
+		permissions, err := contextPermissions(c)
+		if err != nil || len(permissions) == 0 {
 			resputil.HTTPError(c, http.StatusUnauthorized, "Not Admin", resputil.TokenInvalid)
 			c.Abort()
 			return
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// LoadPermissions 计算用户的有效权限：平台角色的默认权限加上被分配的权限角色中的权限。
+// 使用权限范围不是 admin 的个人访问令牌时，不具有任何管理权限
+func LoadPermissions(c context.Context, userID uint, rolePlatform model.Role, apiTokenID uint) ([]model.Permission, error) {
+	if apiTokenID != 0 {
+		t := query.APIToken
+		token, err := t.WithContext(c).Where(t.ID.Eq(apiTokenID)).First()
+		if err != nil {
+			return nil, err
+		}
+		if token.Scope != model.APITokenScopeAdmin {
+			return nil, nil
+		}
+	}
+
+	permissions := model.DefaultPermissions(rolePlatform)
+	upr := query.UserPermissionRole
+	assigned, err := upr.WithContext(c).Where(upr.UserID.Eq(userID)).Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(assigned) > 0 {
+		pr := query.PermissionRole
+		roles, err := pr.WithContext(c).Where(pr.ID.In(lo.Map(assigned, func(r *model.UserPermissionRole, _ int) uint {
+			return r.RoleID
+		})...)).Find()
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			permissions = append(permissions, role.Permissions.Data()...)
+		}
+	}
+	return lo.Uniq(permissions), nil
+}
+
+// contextPermissions 返回当前请求用户的有效权限，同一请求中只计算一次
+func contextPermissions(c *gin.Context) ([]model.Permission, error) {
+	if permissions, ok := c.Get(util.PermissionsKey); ok {
+		return permissions.([]model.Permission), nil
+	}
+	token := util.GetToken(c)
+	apiTokenID, _ := util.GetAPITokenID(c)
+	permissions, err := LoadPermissions(c, token.UserID, token.RolePlatform, apiTokenID)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(util.PermissionsKey, permissions)
+	return permissions, nil
+}
+
+// HasPermission 判断当前请求用户是否拥有指定权限，用于处理函数内部的细粒度校验
+func HasPermission(c *gin.Context, permission model.Permission) bool {
+	permissions, err := contextPermissions(c)
+	if err != nil {
+		return false
+	}
+	return lo.Contains(permissions, permission)
+}
+
+// RequirePermission 路由级别的权限校验，用户需要拥有所有列出的权限
+func RequirePermission(required ...model.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, err := contextPermissions(c)
+		if err != nil {
+			resputil.HTTPError(c, http.StatusInternalServerError, err.Error(), resputil.NotSpecified)
+			c.Abort()
+			return
+		}
+		if missing, _ := lo.Difference(required, permissions); len(missing) > 0 {
+			resputil.HTTPError(c, http.StatusForbidden, "Permission denied, missing "+string(missing[0]), resputil.UserNotAllowed)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	defaultRateLimitRPS        = 10
+	defaultAdminRateMultiplier = 5
+	// rateLimiterIdleTTL 超过该时间未使用的令牌桶会被清理，重新创建时桶是满的，不影响限流效果
+	rateLimiterIdleTTL    = 10 * time.Minute
+	rateLimiterSweepEvery = time.Minute
+)
+
+// RateLimitedRequests 被限流拒绝的请求数，由 MetricsMgr 注册到 Prometheus 指标中
+var RateLimitedRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "api_rate_limited_requests_total",
+		Help: "Total number of API requests rejected by the per-user rate limiter",
+	},
+	[]string{"group", "method", "role"},
+)
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// limiterStore 保存每个用户在每个路由组上的令牌桶。
+// 多副本部署时每个副本单独计数，实际限额为配置值乘以副本数
+type limiterStore struct {
+	mu        sync.Mutex
+	entries   map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+func newLimiterStore() *limiterStore {
+	return &limiterStore{entries: make(map[string]*limiterEntry)}
+}
+
+func (s *limiterStore) get(key string, limit rate.Limit, burst int, now time.Time) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > rateLimiterSweepEvery {
+		for k, e := range s.entries {
+			if now.Sub(e.lastSeen) > rateLimiterIdleTTL {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	e, ok := s.entries[key]
+	if !ok || e.limiter.Limit() != limit || e.limiter.Burst() != burst {
+		e = &limiterEntry{limiter: rate.NewLimiter(limit, burst)}
+		s.entries[key] = e
+	}
+	e.lastSeen = now
+	return e.limiter
+}
+
+// RateLimit 按用户和路由组限流，必须在 AuthProtected 之后使用。
+// prefix 为路由前缀 (如 api/v1)，前缀后的第一段路径作为路由组名称
+func RateLimit(prefix string) gin.HandlerFunc {
+	store := newLimiterStore()
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+	return func(c *gin.Context) {
+		conf := config.GetConfig().RateLimit
+		if !conf.Enable {
+			c.Next()
+			return
+		}
+		group := routeGroup(c.FullPath(), prefix)
+		if group == "" {
+			c.Next()
+			return
+		}
+
+		token := util.GetToken(c)
+		isAdmin := token.RolePlatform == model.RoleAdmin
+		ruleKey, rule := matchRateLimitRule(conf.Groups, conf.Default, group, c.Request.Method)
+		limit, burst := rule.limits(isAdmin)
+
+		now := time.Now()
+		key := fmt.Sprintf("%d|%s|%s", token.UserID, prefix, ruleKey)
+		limiter := store.get(key, limit, burst, now)
+		reservation := limiter.ReserveN(now, 1)
+		delay := reservation.DelayFrom(now)
+		if reservation.OK() && delay == 0 {
+			c.Next()
+			return
+		}
+		reservation.CancelAt(now)
+
+		role := "user"
+		if isAdmin {
+			role = "admin"
+		}
+		RateLimitedRequests.WithLabelValues(group, c.Request.Method, role).Inc()
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(delay, limit)))
+		resputil.HTTPError(c, http.StatusTooManyRequests, "Too many requests, please retry later", resputil.RateLimited)
+		c.Abort()
+	}
+}
+
+// IPRateLimit 按客户端 IP 限流，用于找回密码等未登录即可访问的接口，不受限流配置开关影响。
+// 使用同一个中间件的路由共享令牌桶
+func IPRateLimit(group string, every time.Duration, burst int) gin.HandlerFunc {
+	store := newLimiterStore()
+	limit := rate.Every(every)
+	return func(c *gin.Context) {
+		now := time.Now()
+		limiter := store.get(c.ClientIP(), limit, burst, now)
+		reservation := limiter.ReserveN(now, 1)
+		delay := reservation.DelayFrom(now)
+		if reservation.OK() && delay == 0 {
+			c.Next()
+			return
+		}
+		reservation.CancelAt(now)
+
+		RateLimitedRequests.WithLabelValues(group, c.Request.Method, "anonymous").Inc()
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(delay, limit)))
+		resputil.HTTPError(c, http.StatusTooManyRequests, "Too many requests, please retry later", resputil.RateLimited)
+		c.Abort()
+	}
+}
+
+// routeGroup 返回路由模板中前缀后的第一段，如 /api/v1/vcjobs/:name 返回 vcjobs
+func routeGroup(fullPath, prefix string) string {
+	rest, ok := strings.CutPrefix(fullPath, prefix)
+	if !ok {
+		return ""
+	}
+	group, _, _ := strings.Cut(rest, "/")
+	return group
+}
+
+// matchRateLimitRule 依次匹配 "<group>:<METHOD>"、"<group>" 和默认规则
+func matchRateLimitRule(
+	groups map[string]config.RateLimitRule,
+	def config.RateLimitRule,
+	group, method string,
+) (string, rateLimitRule) {
+	for _, key := range []string{group + ":" + method, group} {
+		if rule, ok := groups[key]; ok {
+			return key, rateLimitRule(rule)
+		}
+	}
+	return group, rateLimitRule(def)
+}
+
+type rateLimitRule config.RateLimitRule
+
+// limits 返回补全默认值后的速率和桶容量
+func (r rateLimitRule) limits(isAdmin bool) (rate.Limit, int) {
+	userRPS := r.RPS
+	if userRPS == 0 {
+		userRPS = defaultRateLimitRPS
+	}
+	rps, burst := userRPS, r.Burst
+	if isAdmin {
+		rps, burst = r.AdminRPS, r.AdminBurst
+		if rps == 0 {
+			rps = defaultAdminRateMultiplier * userRPS
+		}
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(2 * rps))
+	}
+	return rate.Limit(rps), burst
+}
+
+// retryAfterSeconds 返回下一个令牌可用前需要等待的秒数，至少为 1 秒
+func retryAfterSeconds(delay time.Duration, limit rate.Limit) int {
+	if delay <= 0 || delay == rate.InfDuration {
+		delay = time.Duration(float64(time.Second) / float64(limit))
+	}
+	return max(1, int(math.Ceil(delay.Seconds())))
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func TestRouteGroup(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/vcjobs/:name/pods": "vcjobs",
+		"/api/v1/context":           "context",
+		"/api/v1/admin/users":       "admin",
+		"/api/auth/login":           "",
+		"":                          "",
+	}
+	for path, want := range cases {
+		if got := routeGroup(path, "/api/v1/"); got != want {
+			t.Errorf("routeGroup(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestMatchRateLimitRule(t *testing.T) {
+	groups := map[string]config.RateLimitRule{
+		"vcjobs":      {RPS: 2, Burst: 10},
+		"vcjobs:POST": {RPS: 0.2, Burst: 5, AdminRPS: 1},
+	}
+	def := config.RateLimitRule{}
+
+	key, rule := matchRateLimitRule(groups, def, "vcjobs", "POST")
+	if key != "vcjobs:POST" {
+		t.Errorf("expected method rule, got %s", key)
+	}
+	if limit, burst := rule.limits(false); limit != 0.2 || burst != 5 {
+		t.Errorf("user limits = %v/%d, want 0.2/5", limit, burst)
+	}
+	if limit, burst := rule.limits(true); limit != 1 || burst != 2 {
+		t.Errorf("admin limits = %v/%d, want 1/2", limit, burst)
+	}
+
+	if key, _ = matchRateLimitRule(groups, def, "vcjobs", "GET"); key != "vcjobs" {
+		t.Errorf("expected group rule, got %s", key)
+	}
+
+	key, rule = matchRateLimitRule(groups, def, "context", "GET")
+	if key != "context" {
+		t.Errorf("expected default rule keyed by group, got %s", key)
+	}
+	if limit, burst := rule.limits(false); limit != defaultRateLimitRPS || burst != 2*defaultRateLimitRPS {
+		t.Errorf("default user limits = %v/%d", limit, burst)
+	}
+	if limit, _ := rule.limits(true); limit != defaultAdminRateMultiplier*defaultRateLimitRPS {
+		t.Errorf("default admin limit = %v", limit)
+	}
+}
+
+func TestLimiterStore(t *testing.T) {
+	store := newLimiterStore()
+	now := time.Now()
+	limiter := store.get("1|vcjobs", rate.Limit(1), 2, now)
+	if !limiter.AllowN(now, 2) || limiter.AllowN(now, 1) {
+		t.Fatal("expected the bucket to allow exactly its burst")
+	}
+	if store.get("1|vcjobs", rate.Limit(1), 2, now) != limiter {
+		t.Error("expected the same limiter for the same key")
+	}
+	if got := retryAfterSeconds(limiter.ReserveN(now, 1).DelayFrom(now), rate.Limit(1)); got != 1 {
+		t.Errorf("retryAfterSeconds = %d, want 1", got)
+	}
+
+	store.get("2|vcjobs", rate.Limit(1), 2, now.Add(rateLimiterIdleTTL+2*rateLimiterSweepEvery))
+	if _, ok := store.entries["1|vcjobs"]; ok {
+		t.Error("expected idle limiter to be swept")
+	}
+}
+
+func TestIPRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	limit := IPRateLimit("auth/password", time.Hour, 2)
+	router.POST("/forgot", limit, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/reset", limit, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(path, addr string) int {
+		req := httptest.NewRequest(http.MethodPost, path, http.NoBody)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	// 两个路由共享同一 IP 的令牌桶
+	if request("/forgot", "10.0.0.1:1000") != http.StatusOK || request("/reset", "10.0.0.1:1001") != http.StatusOK {
+		t.Fatal("expected requests within the burst to pass")
+	}
+	if code := request("/reset", "10.0.0.1:1002"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 after the burst, got %d", code)
+	}
+	if code := request("/forgot", "10.0.0.2:1000"); code != http.StatusOK {
+		t.Errorf("expected another IP to pass, got %d", code)
+	}
+}
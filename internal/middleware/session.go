@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// sessionCacheTTL 会话和用户状态的缓存时间，在其他副本上撤销的会话最迟在该时间后失效
+const sessionCacheTTL = 15 * time.Second
+
+var (
+	ErrSessionRevoked = errors.New("session has been revoked or expired")
+	ErrUserInactive   = errors.New("user is not active")
+)
+
+type cacheEntry struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+// validityCache 缓存会话和用户是否有效，避免每个请求都查询数据库
+type validityCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func (vc *validityCache) get(key string, now time.Time) (valid, ok bool) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	entry, ok := vc.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.valid, true
+}
+
+func (vc *validityCache) set(key string, valid bool, now time.Time) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	// 清理过期条目，缓存大小受活跃会话数限制
+	for k, entry := range vc.entries {
+		if now.After(entry.expiresAt) {
+			delete(vc.entries, k)
+		}
+	}
+	vc.entries[key] = cacheEntry{valid: valid, expiresAt: now.Add(sessionCacheTTL)}
+}
+
+func (vc *validityCache) delete(keys ...string) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	for _, key := range keys {
+		delete(vc.entries, key)
+	}
+}
+
+var sessionCache = &validityCache{entries: make(map[string]cacheEntry)}
+
+func sessionCacheKey(sessionID string) string { return "session/" + sessionID }
+
+func userCacheKey(userID uint) string { return fmt.Sprintf("user/%d", userID) }
+
+// ValidateSession 校验令牌所属的用户仍处于激活状态，且令牌所属的会话未被撤销。
+// 在会话表引入前签发的令牌没有会话 ID，无法被撤销，一律视为失效，用户需要重新登录
+func ValidateSession(c context.Context, token *util.JWTMessage) error {
+	now := time.Now()
+
+	userKey := userCacheKey(token.UserID)
+	valid, ok := sessionCache.get(userKey, now)
+	if !ok {
+		u := query.User
+		user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+		valid = err == nil && user.Status == model.StatusActive
+		sessionCache.set(userKey, valid, now)
+	}
+	if !valid {
+		return ErrUserInactive
+	}
+
+	if token.SessionID == "" {
+		return ErrSessionRevoked
+	}
+	sessionKey := sessionCacheKey(token.SessionID)
+	valid, ok = sessionCache.get(sessionKey, now)
+	if !ok {
+		s := query.UserSession
+		session, err := s.WithContext(c).Where(s.SessionID.Eq(token.SessionID)).First()
+		valid = err == nil && session.UserID == token.UserID && session.Active(now)
+		sessionCache.set(sessionKey, valid, now)
+	}
+	if !valid {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// InvalidateSessions 使当前副本立即感知会话被撤销
+func InvalidateSessions(sessionIDs ...string) {
+	keys := make([]string, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, sessionCacheKey(sessionID))
+	}
+	sessionCache.delete(keys...)
+}
+
+// InvalidateUser 使当前副本立即感知用户状态变化
+func InvalidateUser(userID uint) {
+	sessionCache.delete(userCacheKey(userID))
+}
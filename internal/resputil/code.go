@@ -17,6 +17,7 @@ const (
 	RegisterTimeout    ErrorCode = 40104
 	RegisterNotFound   ErrorCode = 40105
 	InvalidCredentials ErrorCode = 40106
+	OIDCNotLinked      ErrorCode = 40107
 
 	// User is not allowed to access the resource
 	UserNotAllowed ErrorCode = 40301
@@ -27,6 +28,9 @@ const (
 	// Container related
 	ServiceSshdNotFound ErrorCode = 40401
 
+	// Too many requests from the user, retry after the Retry-After header
+	RateLimited ErrorCode = 42901
+
 	ServiceError ErrorCode = 50001
 
 	// Indicates laziness of the developer
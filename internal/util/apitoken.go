@@ -0,0 +1,36 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APITokenPrefix 个人访问令牌的前缀，用于和 JWT 区分
+const APITokenPrefix = "crater_pat_"
+
+// apiTokenDisplayLength 列表中展示的令牌前缀长度
+const apiTokenDisplayLength = len(APITokenPrefix) + 6
+
+// GenerateAPIToken 生成新的个人访问令牌，返回明文、展示用的前缀和保存到数据库的摘要
+func GenerateAPIToken() (token, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	token = APITokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, token[:apiTokenDisplayLength], HashAPIToken(token), nil
+}
+
+// HashAPIToken 计算令牌的 SHA-256 摘要，令牌本身是高熵随机数，不需要加盐
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIToken 判断请求中的令牌是否为个人访问令牌
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
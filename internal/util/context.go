@@ -18,6 +18,19 @@ const (
 
 	AccountAccessModeKey = "x-access-mode"
 	PublicAccessModeKey  = "x-public-access-mode"
+
+	SessionIDKey = "x-session-id"
+
+	// PermissionsKey 当前请求用户的有效权限，由权限中间件按需计算
+	PermissionsKey = "x-permissions"
+
+	// APITokenIDKey 使用个人访问令牌认证时，记录令牌的 ID
+	APITokenIDKey = "x-api-token-id"
+	// APITokenScopeKey 使用个人访问令牌认证时，记录令牌的权限范围
+	APITokenScopeKey = "x-api-token-scope"
+
+	// ManagedAccountIDKey 账户管理接口中被管理的账户 ID，由账户管理员中间件设置
+	ManagedAccountIDKey = "x-managed-account-id"
 )
 
 const (
@@ -39,6 +52,7 @@ func SetJWTContext(
 	c.Set(RolePlatformKey, msg.RolePlatform)
 	c.Set(AccountAccessModeKey, msg.AccountAccessMode)
 	c.Set(PublicAccessModeKey, msg.PublicAccessMode)
+	c.Set(SessionIDKey, msg.SessionID)
 }
 
 func GetToken(ctx *gin.Context) JWTMessage {
@@ -58,5 +72,24 @@ func GetToken(ctx *gin.Context) JWTMessage {
 	msg.AccountAccessMode = accessModeKey.(model.AccessMode)
 	publicAcessModeKey, _ := ctx.Get(PublicAccessModeKey)
 	msg.PublicAccessMode = publicAcessModeKey.(model.AccessMode)
+	msg.SessionID = ctx.GetString(SessionIDKey)
 	return msg
 }
+
+// GetAPITokenID 返回请求使用的个人访问令牌 ID，使用 JWT 认证时返回 false
+func GetAPITokenID(ctx *gin.Context) (uint, bool) {
+	id := ctx.GetUint(APITokenIDKey)
+	return id, id != 0
+}
+
+// IsReadOnlyAPIToken 判断请求是否使用只读的个人访问令牌认证。
+// 只读令牌在中间件中只允许 GET 请求，终端、文件下载等通过 GET 执行操作的接口需要再次检查
+func IsReadOnlyAPIToken(ctx *gin.Context) bool {
+	scope, ok := ctx.Get(APITokenScopeKey)
+	return ok && scope == model.APITokenScopeReadOnly
+}
+
+// GetManagedAccountID 返回账户管理接口中被管理的账户 ID
+func GetManagedAccountID(ctx *gin.Context) uint {
+	return ctx.GetUint(ManagedAccountIDKey)
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	defaultPasswordMinLength      = 8
+	defaultPasswordMinCharClasses = 2
+	defaultPasswordHistory        = 3
+	// passwordMaxLength bcrypt 只使用密码的前 72 字节
+	passwordMaxLength = 72
+
+	resetCodeDigits = 6
+)
+
+// PasswordPolicy 普通认证用户的密码强度要求
+type PasswordPolicy struct {
+	MinLength      int
+	MinCharClasses int
+	// History 不能重复使用的最近密码数量 (包括当前密码)，0 表示不检查
+	History int
+}
+
+// GetPasswordPolicy 读取配置中的密码策略，未配置的字段使用默认值
+func GetPasswordPolicy() PasswordPolicy {
+	conf := config.GetConfig().PasswordPolicy
+	policy := PasswordPolicy{
+		MinLength:      conf.MinLength,
+		MinCharClasses: conf.MinCharClasses,
+		History:        conf.History,
+	}
+	if policy.MinLength == 0 {
+		policy.MinLength = defaultPasswordMinLength
+	}
+	if policy.MinCharClasses == 0 {
+		policy.MinCharClasses = defaultPasswordMinCharClasses
+	}
+	switch {
+	case policy.History == 0:
+		policy.History = defaultPasswordHistory
+	case policy.History < 0:
+		policy.History = 0
+	}
+	return policy
+}
+
+// Check 检查密码是否满足长度和字符种类要求，且不包含用户名
+func (p PasswordPolicy) Check(username, password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if len(password) > passwordMaxLength {
+		return fmt.Errorf("password must be at most %d bytes", passwordMaxLength)
+	}
+	if classes := passwordCharClasses(password); classes < p.MinCharClasses {
+		return fmt.Errorf("password must contain at least %d of lowercase letters, uppercase letters, digits and symbols",
+			p.MinCharClasses)
+	}
+	if len(username) >= 3 && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return errors.New("password must not contain the username")
+	}
+	return nil
+}
+
+func passwordCharClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	return classes
+}
+
+// GenerateResetCode 生成忘记密码时发送的数字验证码，返回明文和保存到数据库的哈希
+func GenerateResetCode(userID uint) (code, hash string, err error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", "", err
+	}
+	code = fmt.Sprintf("%0*d", resetCodeDigits, n.Int64())
+	return code, HashResetCode(userID, code), nil
+}
+
+// HashResetCode 计算验证码的哈希，加入用户 ID 使相同的验证码在不同用户间哈希不同
+func HashResetCode(userID uint, code string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, code)))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,6 +24,7 @@ type (
 		RolePlatform     model.Role       `json:"rp"`
 		AccessMode       model.AccessMode `json:"am"`
 		PublicAccessMode model.AccessMode `json:"pa"`
+		SessionID        string           `json:"si"`
 		jwt.RegisteredClaims
 	}
 	JWTMessage struct {
@@ -34,6 +36,7 @@ type (
 		AccountAccessMode model.AccessMode `json:"accessMode"`       // AccessMode in account
 		PublicAccessMode  model.AccessMode `json:"publicaccessmode"` // Public Accessmode
 		RolePlatform      model.Role       `json:"rolePlatform"`     // Role in platform (e.g. guest, user, admin)
+		SessionID         string           `json:"sessionID"`        // Login session, empty for tokens issued before sessions
 	}
 )
 
@@ -79,6 +82,7 @@ func (tm *TokenManager) createToken(msg *JWTMessage, ttl int) (string, error) {
 		RolePlatform:     msg.RolePlatform,
 		AccessMode:       msg.AccountAccessMode,
 		PublicAccessMode: msg.PublicAccessMode,
+		SessionID:        msg.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
@@ -88,6 +92,11 @@ func (tm *TokenManager) createToken(msg *JWTMessage, ttl int) (string, error) {
 	return token.SignedString([]byte(tm.secretKey))
 }
 
+// RefreshTokenTTL returns how long a refresh token, and therefore a login session, is valid
+func (tm *TokenManager) RefreshTokenTTL() time.Duration {
+	return time.Hour * time.Duration(tm.refreshTokenTTL)
+}
+
 // CreateTokens creates a new access token and a new refresh token
 func (tm *TokenManager) CreateTokens(msg *JWTMessage) (
 	accessToken string, refreshToken string, err error) {
@@ -109,6 +118,10 @@ func (tm *TokenManager) CheckToken(requestToken string) (JWTMessage, error) {
 	_, err := jwt.ParseWithClaims(requestToken, &claims, func(_ *jwt.Token) (any, error) {
 		return []byte(tm.secretKey), nil
 	})
+	if err == nil && len(claims.Audience) > 0 {
+		// 两步验证的挑战令牌不能作为访问令牌使用
+		err = jwt.ErrTokenInvalidAudience
+	}
 	return JWTMessage{
 		UserID:            claims.UserID,
 		AccountID:         claims.QueueID,
@@ -118,5 +131,52 @@ func (tm *TokenManager) CheckToken(requestToken string) (JWTMessage, error) {
 		RolePlatform:      claims.RolePlatform,
 		AccountAccessMode: claims.AccessMode,
 		PublicAccessMode:  claims.PublicAccessMode,
+		SessionID:         claims.SessionID,
 	}, err
 }
+
+// MFAPurpose 两步验证挑战令牌的用途
+type MFAPurpose string
+
+const (
+	// MFAPurposeVerify 已启用两步验证，需要输入验证码或恢复码
+	MFAPurposeVerify MFAPurpose = "mfa-verify"
+	// MFAPurposeSetup 必须启用两步验证但尚未绑定，需要先绑定验证器
+	MFAPurposeSetup MFAPurpose = "mfa-setup"
+
+	mfaTokenTTL = 5 * time.Minute
+)
+
+// CreateMFAToken 密码等第一步验证通过后签发的短期挑战令牌，只能用于完成两步验证
+func (tm *TokenManager) CreateMFAToken(userID uint, purpose MFAPurpose) (string, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		Audience:  jwt.ClaimStrings{string(purpose)},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(mfaTokenTTL)),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(tm.secretKey))
+}
+
+// CheckMFAToken 校验挑战令牌，返回用户 ID 和令牌用途
+func (tm *TokenManager) CheckMFAToken(requestToken string) (uint, MFAPurpose, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(requestToken, &claims, func(_ *jwt.Token) (any, error) {
+		return []byte(tm.secretKey), nil
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if len(claims.Audience) != 1 {
+		return 0, "", jwt.ErrTokenInvalidAudience
+	}
+	purpose := MFAPurpose(claims.Audience[0])
+	if purpose != MFAPurposeVerify && purpose != MFAPurposeSetup {
+		return 0, "", jwt.ErrTokenInvalidAudience
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, "", jwt.ErrTokenInvalidSubject
+	}
+	return uint(userID), purpose, nil
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 中 SHA1 的测试向量
+func TestTOTPCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	cases := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for ts, want := range cases {
+		if got := totpCode(key, ts/totpPeriod, 8); got != want {
+			t.Errorf("totpCode at %d = %s, want %s", ts, got, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	step, ok := ValidateTOTP(secret, "050471", now, 0)
+	if !ok || step != 1111111111/totpPeriod {
+		t.Fatalf("expected code to be valid at step %d, got %d %v", 1111111111/totpPeriod, step, ok)
+	}
+	if _, ok = ValidateTOTP(secret, "050471", now, step); ok {
+		t.Error("expected used code to be rejected")
+	}
+	if _, ok = ValidateTOTP(secret, "050471", now.Add(5*time.Minute), 0); ok {
+		t.Error("expected expired code to be rejected")
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	if HashRecoveryCode("ABCD-efgh") != HashRecoveryCode("abcdefgh") {
+		t.Error("expected recovery code hash to ignore case and separators")
+	}
+}
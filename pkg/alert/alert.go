@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
@@ -18,8 +19,8 @@ import (
 )
 
 type alertMgr struct {
-	handler alertHandlerInterface
-	err     error
+	handlers  []alertHandlerInterface
+	templates *templateRenderer
 }
 
 var (
@@ -28,6 +29,11 @@ var (
 )
 
 func GetAlertMgr() AlertInterface {
+	return getAlerter()
+}
+
+// getAlerter 返回包内使用的通知管理器，首次调用时初始化
+func getAlerter() *alertMgr {
 	once.Do(func() {
 		alerter = initAlertMgr()
 	})
@@ -35,38 +41,123 @@ func GetAlertMgr() AlertInterface {
 }
 
 func initAlertMgr() *alertMgr {
-	// 初始化选择具体要使用的 alert handler
-	// 目前只支持 SMTP，下一步支持 WPS Robot
-	// 后续可以考虑从 Config 中进行配置
-	smtpHandler, err := newSMTPAlerter()
-	if err != nil {
-		klog.Error("Init alert mgr error")
+	// 根据配置初始化所有启用的通知渠道
+	cfg := config.GetConfig()
+	handlers := make([]alertHandlerInterface, 0)
+	if cfg.SMTP.Enable {
+		smtpHandler, err := newSMTPAlerter()
+		if err != nil {
+			klog.Errorf("Init SMTP alert handler error: %v", err)
+		} else {
+			handlers = append(handlers, smtpHandler)
+		}
+	}
+	if cfg.Notification.Webhook.Enable {
+		handlers = append(handlers, newWebhookAlerter())
+	}
+	robots := []struct {
+		channel model.NotificationChannel
+		config  config.NotificationRobot
+	}{
+		{model.NotificationChannelFeishu, cfg.Notification.Feishu},
+		{model.NotificationChannelDingTalk, cfg.Notification.DingTalk},
+		{model.NotificationChannelWeCom, cfg.Notification.WeCom},
+		{model.NotificationChannelWPS, cfg.Notification.WPS},
+	}
+	for _, robot := range robots {
+		if robot.config.Enable {
+			handlers = append(handlers, newRobotAlerter(robot.channel, robot.config))
+		}
+	}
+	if len(handlers) == 0 {
+		klog.Warning("No notification channel is enabled")
 	}
 	return &alertMgr{
-		handler: smtpHandler,
-		err:     err,
+		handlers:  handlers,
+		templates: newTemplateRenderer(cfg.Notification.TemplateDir, cfg.Notification.DefaultLanguage),
+	}
+}
+
+// getHandler 返回指定类型的通知渠道，未启用时返回 nil
+func (a *alertMgr) getHandler(channel model.NotificationChannel) alertHandlerInterface {
+	for _, handler := range a.handlers {
+		if handler.Channel() == channel {
+			return handler
+		}
+	}
+	return nil
+}
+
+// GetEnabledChannels 返回平台启用的通知渠道
+func (a *alertMgr) GetEnabledChannels() []model.NotificationChannel {
+	channels := make([]model.NotificationChannel, 0, len(a.handlers))
+	for _, handler := range a.handlers {
+		channels = append(channels, handler.Channel())
 	}
+	return channels
+}
+
+// sendMessage 将通知并发发送到接收者可用的所有渠道，channels 不为空时只发送到指定渠道。
+// 只要有一个渠道发送成功即视为成功，其余渠道的失败只记录日志
+func (a *alertMgr) sendMessage(
+	ctx context.Context,
+	receiver *model.UserAttribute,
+	channels []model.NotificationChannel,
+	subject, body string,
+) error {
+	handlers := make([]alertHandlerInterface, 0, len(a.handlers))
+	for _, handler := range a.handlers {
+		if len(channels) > 0 && !lo.Contains(channels, handler.Channel()) {
+			continue
+		}
+		if handler.Available(receiver) {
+			handlers = append(handlers, handler)
+		}
+	}
+	if len(handlers) == 0 {
+		klog.Warningf("%s does not have any available notification channel", receiver.Name)
+		return nil
+	}
+
+	errs := make([]error, len(handlers))
+	var wg sync.WaitGroup
+	for i, handler := range handlers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := handler.SendMessageTo(ctx, receiver, subject, body); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", handler.Channel(), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	failed := lo.Compact(errs)
+	if len(failed) == len(handlers) {
+		return errors.Join(failed...)
+	}
+	if len(failed) > 0 {
+		klog.Warningf("Some notification channels failed for %s: %v", receiver.Name, errors.Join(failed...))
+	}
+	return nil
 }
 
 func (a *alertMgr) SendVerificationCode(ctx context.Context, code string, receiver *model.UserAttribute) error {
-	if a.err != nil {
-		return a.err
+	// 验证码只能通过邮件发送
+	handler := a.getHandler(model.NotificationChannelEmail)
+	if handler == nil {
+		return fmt.Errorf("email notification channel is not enabled")
 	}
 
-	subject := "邮箱验证码"
-	body := fmt.Sprintf(`
-		<div style="font-family: Arial, sans-serif; padding: 20px; color: #333;">
-			<h2 style="color: #2c3e50;">Crater 邮箱验证</h2>
-			<p>您好，</p>
-			<p>您的邮箱验证码为：</p>
-			<div style="background-color: #f8f9fa; padding: 10px; border-radius: 5px; font-size: 18px; font-weight: bold; text-align: center; letter-spacing: 2px;">
-				%s
-			</div>
-			<p style="font-size: 12px; color: #7f8c8d; margin-top: 20px;">该验证码有效期为10分钟，请勿将验证码泄露给他人。</p>
-		</div>
-	`, code)
-
-	err := a.handler.SendMessageTo(ctx, receiver, subject, body)
+	subject, body, err := a.templates.render(receiver, templateVerificationCode, &TemplateContext{
+		ReceiverName: receiver.Nickname,
+		Code:         code,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = handler.SendMessageTo(ctx, receiver, subject, body)
 	if err != nil {
 		return err
 	}
@@ -79,11 +170,15 @@ func (a *alertMgr) SendVerificationCode(ctx context.Context, code string, receiv
 type JobInformation struct {
 	Name              string
 	JobName           string
+	UserID            uint
+	AccountID         uint
 	Username          string
-	jobURL            string
+	JobURL            string
 	Receiver          model.UserAttribute
 	CreationTimestamp time.Time
 	RunningTimestamp  time.Time
+
+	job *model.Job
 }
 
 func (a *alertMgr) getJobAlertInfo(ctx context.Context, jobName string) (*JobInformation, error) {
@@ -101,28 +196,54 @@ func (a *alertMgr) getJobAlertInfo(ctx context.Context, jobName string) (*JobInf
 	return &JobInformation{
 		Name:              job.Name,
 		JobName:           job.JobName,
+		UserID:            job.UserID,
+		AccountID:         job.AccountID,
 		Username:          job.User.Attributes.Data().Nickname,
-		jobURL:            jobURL,
+		JobURL:            jobURL,
 		Receiver:          receiver,
 		CreationTimestamp: job.CreationTimestamp,
 		RunningTimestamp:  job.RunningTimestamp,
+		job:               job,
 	}, nil
 }
 
+// newJobTemplateContext 根据作业信息构造模板数据，接收者为作业所属用户
+func newJobTemplateContext(info *JobInformation, deleteTime time.Time) *TemplateContext {
+	data := &TemplateContext{
+		JobInformation: *info,
+		ReceiverName:   info.Username,
+		DeleteTime:     deleteTime,
+	}
+	if info.job == nil {
+		return data
+	}
+
+	resources := info.job.Resources.Data()
+	if len(resources) > 0 {
+		data.Resources = make(map[string]string, len(resources))
+		for name, quantity := range resources {
+			data.Resources[string(name)] = quantity.String()
+		}
+	}
+	data.Nodes = info.job.Nodes.Data()
+	if info.job.ProfileData != nil {
+		if profile := info.job.ProfileData.Data(); profile != nil {
+			data.GPUUtilAvg = profile.GPUUtilAvg
+		}
+	}
+	return data
+}
+
 // Job 相关邮件
 // condition 为条件函数，返回 true 则发送通知
-// bodyFormatter 为邮件内容格式化函数，返回格式化后的邮件内容
+// deleteTime 为清理任务计划删除作业的时间，仅用于提醒类通知
 func (a *alertMgr) sendJobNotification(
 	ctx context.Context,
-	jobName, subject string,
+	jobName string,
 	alertType model.AlertType,
 	condition func(info *JobInformation) bool,
-	bodyFormatter func(info *JobInformation) string,
+	deleteTime time.Time,
 ) error {
-	if a.err != nil {
-		return a.err
-	}
-
 	info, err := a.getJobAlertInfo(ctx, jobName)
 	if err != nil {
 		return err
@@ -148,11 +269,45 @@ func (a *alertMgr) sendJobNotification(
 		return alertErr
 	}
 
-	body := bodyFormatter(info)
-	if err := a.handler.SendMessageTo(ctx, &info.Receiver, subject, body); err != nil {
+	// 使用作业所属用户的语言渲染通知
+	data := newJobTemplateContext(info, deleteTime)
+	subject, body, err := a.templates.render(&info.Receiver, jobTemplates[alertType], data)
+	if err != nil {
 		return err
 	}
 
+	// 根据用户偏好决定是否发送，跳过的通知同样留下记录
+	status := model.AlertStatusSent
+	channels, notBefore, skipReason := a.checkPreference(ctx, info.UserID, alertType)
+	if skipReason != "" {
+		status = model.AlertStatusSkipped
+		klog.Infof("job %s type %s skipped: %s", jobName, alertType.String(), skipReason)
+	} else {
+		if !notBefore.IsZero() {
+			status = model.AlertStatusDeferred
+			skipReason = fmt.Sprintf("in quiet hours, deferred until %s", notBefore.Format(time.DateTime))
+		}
+		if err := a.enqueueMessage(ctx, jobDedupeKey(jobName, alertType, info.UserID),
+			info.UserID, &info.Receiver, channels, notBefore, subject, body); err != nil {
+			return err
+		}
+	}
+
+	// 无论外部渠道是否发送，都写入站内收件箱
+	a.addInboxMessage(ctx, &model.InboxMessage{
+		UserID:    info.UserID,
+		Category:  model.InboxCategoryJob,
+		Title:     subject,
+		Content:   htmlToText(body),
+		Link:      info.JobURL,
+		JobName:   jobName,
+		AlertType: alertType.String(),
+	})
+
+	// 作业清理相关的提醒抄送给账户管理员
+	if alertType.IsCleanerAlert() {
+		a.notifyAccountAdmins(ctx, info, alertType, data)
+	}
 	// 审计，留下所有发送邮件记录
 	if alertErr != nil && errors.Is(alertErr, gorm.ErrRecordNotFound) {
 		// 1. 邮件没发送过，创建新纪录
@@ -162,15 +317,24 @@ func (a *alertMgr) sendJobNotification(
 			AllowRepeat:    false,
 			AlertTimestamp: utils.GetLocalTime(),
 			SendCount:      1,
+			Status:         status,
+			Message:        skipReason,
+		}
+		if status == model.AlertStatusSkipped {
+			newRecord.SendCount = 0
 		}
 		if err := alertDB.WithContext(ctx).Create(newRecord); err != nil {
 			return err
 		}
 	} else {
 		// 2. 邮件已经发送过，更新记录
-		record.SendCount++
+		if status != model.AlertStatusSkipped {
+			record.SendCount++
+		}
 		record.AlertTimestamp = utils.GetLocalTime()
 		record.AllowRepeat = false
+		record.Status = status
+		record.Message = skipReason
 		if err := alertDB.WithContext(ctx).Save(record); err != nil {
 			return err
 		}
@@ -179,139 +343,48 @@ func (a *alertMgr) sendJobNotification(
 	return nil
 }
 
+// jobDedupeKey 作业通知在发件箱中的去重键
+func jobDedupeKey(jobName string, alertType model.AlertType, userID uint) string {
+	return fmt.Sprintf("job/%s/%s/%d", jobName, alertType.String(), userID)
+}
+
 // 作业开始通知，只有当作业创建和运行间隔超过 10 分钟时才发送
 func (a *alertMgr) JobRunningAlert(ctx context.Context, jobName string) error {
-	return a.sendJobNotification(ctx, jobName, "作业已开始运行", model.JobRunningAlert,
+	return a.sendJobNotification(ctx, jobName, model.JobRunningAlert,
 		func(info *JobInformation) bool {
 			timeRangeMinite := 10
 			return info.RunningTimestamp.Sub(info.CreationTimestamp).Minutes() > float64(timeRangeMinite)
 		},
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业已开始运行",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已开始运行。", info.Name, info.JobName),
-				info.jobURL,
-				"查看作业详情",
-			)
-		},
+		time.Time{},
 	)
 }
 
 // 作业失败通知
 func (a *alertMgr) JobFailureAlert(ctx context.Context, jobName string) error {
-	return a.sendJobNotification(ctx, jobName, "作业运行失败", model.JobFailedAlert,
-		nil, // 无需额外判断
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业运行失败",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 运行失败。请查看日志了解详细信息。", info.Name, info.JobName),
-				info.jobURL,
-				"查看失败详情",
-			)
-		},
-	)
+	return a.sendJobNotification(ctx, jobName, model.JobFailedAlert, nil, time.Time{})
 }
 
 // 作业完成通知
 func (a *alertMgr) JobCompleteAlert(ctx context.Context, jobName string) error {
-	return a.sendJobNotification(ctx, jobName, "作业已成功完成", model.JobCompletedAlert,
-		nil, // 无需额外判断
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业已成功完成",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已成功运行完成。", info.Name, info.JobName),
-				info.jobURL,
-				"查看作业结果",
-			)
-		},
-	)
+	return a.sendJobNotification(ctx, jobName, model.JobCompletedAlert, nil, time.Time{})
 }
 
 // 低GPU利用率作业删除通知
 func (a *alertMgr) DeleteJob(ctx context.Context, jobName string, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "作业已被系统删除 - GPU利用率过低", model.LowGPUJobDeletedAlert,
-		nil,
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业已被系统删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 因GPU利用率持续过低，已被系统自动删除。请确保您的作业能够充分利用申请的GPU资源，或调整资源申请量以匹配实际需求。", info.Name, info.JobName),
-				info.jobURL,
-				"查看作业详情",
-			)
-		},
-	)
+	return a.sendJobNotification(ctx, jobName, model.LowGPUJobDeletedAlert, nil, time.Time{})
 }
 
 // 长时间运行作业删除通知
 func (a *alertMgr) CleanJob(ctx context.Context, jobName string, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "作业已被系统删除 - 运行时间超限", model.LongTimeJobDeletedAlert,
-		nil,
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业已被系统删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 因运行时间达到平台上限，已被系统自动删除。如需长时间运行作业，请联系管理员申请特殊权限。", info.Name, info.JobName),
-				info.jobURL,
-				"查看作业详情",
-			)
-		},
-	)
+	return a.sendJobNotification(ctx, jobName, model.LongTimeJobDeletedAlert, nil, time.Time{})
 }
 
 // RemindLowUsageJob 发送低资源使用率告警
 func (a *alertMgr) RemindLowUsageJob(ctx context.Context, jobName string, deleteTime time.Time, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "警告：作业即将被删除 - GPU利用率过低", model.LowGPUJobRemindedAlert,
-		nil,
-		func(info *JobInformation) string {
-			deleteTimeStr := deleteTime.Format("2006-01-02 15:04:05")
-			return generateHTMLEmail(
-				info.Username,
-				"警告：作业即将被删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 申请了GPU资源，但资源利用率持续过低。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或调整您的作业以提高资源利用率。",
-					info.Name, info.JobName, deleteTimeStr),
-				info.jobURL,
-				"立即查看作业",
-			)
-		},
-	)
+	return a.sendJobNotification(ctx, jobName, model.LowGPUJobRemindedAlert, nil, deleteTime)
 }
 
 // RemindLongTimeRunningJob 发送长时间运行告警
 func (a *alertMgr) RemindLongTimeRunningJob(ctx context.Context, jobName string, deleteTime time.Time, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "警告：作业即将被删除 - 运行时间过长", model.LongTimeJobRemindedAlert,
-		nil,
-		func(info *JobInformation) string {
-			deleteTimeStr := deleteTime.Format("2006-01-02 15:04:05")
-			return generateHTMLEmail(
-				info.Username,
-				"警告：作业即将被删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已运行较长时间，达到了平台设定的运行时间上限。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或考虑对作业进行优化以减少运行时间。",
-					info.Name, info.JobName, deleteTimeStr),
-				info.jobURL,
-				"立即查看作业",
-			)
-		},
-	)
-}
-
-// 生成HTML格式的邮件内容
-func generateHTMLEmail(username, title, message, url, buttonText string) string {
-	return fmt.Sprintf(`
-		<div style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px; color: #333; border: 1px solid #e0e0e0; border-radius: 5px;">
-			<h2 style="color: #2c3e50; border-bottom: 1px solid #eee; padding-bottom: 10px;">%s</h2>
-			<p>尊敬的 <strong>%s</strong>：</p>
-			<p>%s</p>
-			<div style="margin: 25px 0;">
-				<a href="%s" style="background-color: #3498db; color: white; padding: 10px 20px; text-decoration: none; border-radius: 4px; display: inline-block; font-weight: bold;">%s</a>
-			</div>
-			<p style="margin-top: 30px; font-size: 12px; color: #7f8c8d;">此邮件由系统自动发送，请勿直接回复。如有疑问，请联系系统管理员。</p>
-			<div style="margin-top: 20px; padding-top: 15px; border-top: 1px solid #eee; font-size: 12px; color: #95a5a6; text-align: center;">
-				© Crater 计算平台
-			</div>
-		</div>
-	`, title, username, message, url, buttonText)
+	return a.sendJobNotification(ctx, jobName, model.LongTimeJobRemindedAlert, nil, deleteTime)
 }
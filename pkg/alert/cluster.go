@@ -0,0 +1,165 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/monitor"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const (
+	defaultClusterCheckInterval  = 60 * time.Second
+	defaultBuildFailureThreshold = 3
+	defaultBuildFailureWindow    = time.Hour
+
+	// buildFailuresTarget 镜像构建失败异常的对象
+	buildFailuresTarget = "buildkit"
+	// prometheusTarget Prometheus 异常的对象
+	prometheusTarget = "prometheus"
+)
+
+// ClusterChecker 周期性检查集群资源总量、镜像构建和 Prometheus 的状态，
+// 节点状态由 NodeReconciler 实时检查。与定时任务一样只在主副本上运行
+type ClusterChecker struct {
+	client     client.Client
+	promClient monitor.PrometheusInterface
+
+	interval              time.Duration
+	buildFailureThreshold int
+	buildFailureWindow    time.Duration
+}
+
+var _ manager.LeaderElectionRunnable = &ClusterChecker{}
+
+func NewClusterChecker(cli client.Client, promClient monitor.PrometheusInterface) *ClusterChecker {
+	cfg := config.GetConfig().ClusterAlert
+	checker := &ClusterChecker{
+		client:                cli,
+		promClient:            promClient,
+		interval:              defaultClusterCheckInterval,
+		buildFailureThreshold: defaultBuildFailureThreshold,
+		buildFailureWindow:    defaultBuildFailureWindow,
+	}
+	if cfg.CheckInterval > 0 {
+		checker.interval = time.Duration(cfg.CheckInterval) * time.Second
+	}
+	if cfg.BuildFailureThreshold > 0 {
+		checker.buildFailureThreshold = cfg.BuildFailureThreshold
+	}
+	if cfg.BuildFailureWindow > 0 {
+		checker.buildFailureWindow = time.Duration(cfg.BuildFailureWindow) * time.Minute
+	}
+	return checker
+}
+
+// Start 实现 manager.Runnable，周期性执行检查直到 ctx 结束
+func (c *ClusterChecker) Start(ctx context.Context) error {
+	klog.Info("ClusterChecker: started")
+	wait.UntilWithContext(ctx, c.check, c.interval)
+	klog.Info("ClusterChecker: stopped")
+	return nil
+}
+
+// NeedLeaderElection 实现 manager.LeaderElectionRunnable
+func (c *ClusterChecker) NeedLeaderElection() bool {
+	return true
+}
+
+func (c *ClusterChecker) check(ctx context.Context) {
+	checks := []struct {
+		kind  model.IncidentKind
+		check func(ctx context.Context) (map[string]string, error)
+	}{
+		{model.IncidentKindResourceDropped, c.checkResources},
+		{model.IncidentKindBuildFailures, c.checkBuilds},
+		{model.IncidentKindPrometheusUnreachable, c.checkPrometheus},
+	}
+	for _, item := range checks {
+		failing, err := item.check(ctx)
+		if err != nil {
+			// 检查本身失败时不改变异常状态
+			klog.Errorf("ClusterChecker: failed to check %s: %v", item.kind, err)
+			continue
+		}
+		if err := SyncIncidents(ctx, item.kind, failing); err != nil {
+			klog.Errorf("ClusterChecker: failed to sync %s incidents: %v", item.kind, err)
+		}
+	}
+}
+
+// checkResources 比较节点可分配资源的总量与上次 SyncResource 同步到数据库的数量
+func (c *ClusterChecker) checkResources(ctx context.Context) (map[string]string, error) {
+	var nodes v1.NodeList
+	if err := c.client.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	totals := make(map[string]*resource.Quantity)
+	for i := range nodes.Items {
+		for name, quantity := range nodes.Items[i].Status.Allocatable {
+			if total, ok := totals[name.String()]; ok {
+				total.Add(quantity)
+			} else {
+				total := quantity.DeepCopy()
+				totals[name.String()] = &total
+			}
+		}
+	}
+
+	resources, err := query.Resource.WithContext(ctx).Find()
+	if err != nil {
+		return nil, err
+	}
+	failing := make(map[string]string)
+	for _, r := range resources {
+		var current int64
+		if total, ok := totals[r.ResourceName]; ok {
+			current = total.Value()
+		}
+		if current < r.Amount {
+			failing[r.ResourceName] = fmt.Sprintf("allocatable total %d is less than the synced amount %d", current, r.Amount)
+		}
+	}
+	return failing, nil
+}
+
+// checkBuilds 统计最近一段时间内失败的镜像构建作业
+func (c *ClusterChecker) checkBuilds(ctx context.Context) (map[string]string, error) {
+	k := query.Kaniko
+	since := utils.GetLocalTime().Add(-c.buildFailureWindow)
+	count, err := k.WithContext(ctx).Where(
+		k.Status.Eq(string(model.BuildJobFailed)),
+		k.UpdatedAt.Gte(since),
+	).Count()
+	if err != nil {
+		return nil, err
+	}
+	failing := make(map[string]string)
+	if count >= int64(c.buildFailureThreshold) {
+		failing[buildFailuresTarget] = fmt.Sprintf("%d image builds failed in the last %s", count, c.buildFailureWindow)
+	}
+	return failing, nil
+}
+
+// checkPrometheus 检查 Prometheus API 是否可以访问
+func (c *ClusterChecker) checkPrometheus(ctx context.Context) (map[string]string, error) {
+	failing := make(map[string]string)
+	if c.promClient == nil {
+		return failing, nil
+	}
+	if err := c.promClient.CheckHealth(ctx); err != nil {
+		failing[prometheusTarget] = err.Error()
+	}
+	return failing, nil
+}
@@ -0,0 +1,335 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"gorm.io/datatypes"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"k8s.io/klog/v2"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const (
+	templateDigest = "digest"
+	// digestGPUResourcePrefix 统计卡时时计入的资源前缀
+	digestGPUResourcePrefix = "nvidia.com/"
+)
+
+// DigestRequest 作业摘要定时任务的配置
+type DigestRequest struct {
+	Period model.DigestPeriod `json:"period"`
+}
+
+// DigestJob 摘要中列出的作业
+type DigestJob struct {
+	Name     string
+	JobName  string
+	Username string
+	URL      string
+	// Reason 即将被清理的原因，为对应提醒的通知类型
+	Reason string
+}
+
+// DigestSummary 一组作业在统计周期内的汇总
+type DigestSummary struct {
+	Started        []DigestJob
+	Succeeded      []DigestJob
+	Failed         []DigestJob
+	CloseToCleanup []DigestJob
+	// GPUHours 统计周期内消耗的卡时
+	GPUHours float64
+	// GPUUtilAvg 按卡时加权的平均 GPU 利用率（0~1），没有性能数据时为空
+	GPUUtilAvg *float32
+}
+
+// Empty 判断统计周期内是否没有任何作业活动
+func (s *DigestSummary) Empty() bool {
+	return len(s.Started) == 0 && len(s.Succeeded) == 0 && len(s.Failed) == 0 &&
+		len(s.CloseToCleanup) == 0 && s.GPUHours == 0
+}
+
+// AccountDigest 账户管理员收到的账户成员作业汇总
+type AccountDigest struct {
+	Account string
+	Summary DigestSummary
+}
+
+// DigestData 作业摘要模板的数据
+type DigestData struct {
+	Period   model.DigestPeriod
+	Start    time.Time
+	End      time.Time
+	Own      DigestSummary
+	Accounts []AccountDigest
+}
+
+// GetDigestFunc 根据定时任务配置返回发送作业摘要的函数
+func GetDigestFunc(jobConfig datatypes.JSON) (func(ctx context.Context) (any, error), error) {
+	req := &DigestRequest{}
+	if err := json.Unmarshal(jobConfig, req); err != nil {
+		return nil, err
+	}
+	if req.Period != model.DigestPeriodDaily && req.Period != model.DigestPeriodWeekly {
+		return nil, fmt.Errorf("unsupported digest period: %q", req.Period)
+	}
+	return func(ctx context.Context) (any, error) {
+		return getAlerter().SendDigests(ctx, req.Period)
+	}, nil
+}
+
+// SendDigests 为订阅了该周期摘要的用户汇总作业情况并放入发件箱，
+// 账户管理员还会收到所管理账户中其他成员的作业汇总
+func (a *alertMgr) SendDigests(ctx context.Context, period model.DigestPeriod) (map[string][]string, error) {
+	end := utils.GetLocalTime()
+	start := end.Add(-period.Duration())
+
+	prefs, err := query.NotificationPreference.WithContext(ctx).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	sent, skipped, failed := []string{}, []string{}, []string{}
+	u := query.User
+	for _, pref := range prefs {
+		channels := pref.GetDigestChannels(period)
+		if len(channels) == 0 {
+			continue
+		}
+		user, err := u.WithContext(ctx).Where(u.ID.Eq(pref.UserID)).First()
+		if err != nil {
+			klog.Errorf("failed to get user %d for digest: %v", pref.UserID, err)
+			continue
+		}
+
+		data, err := a.buildDigest(ctx, user.ID, period, start, end)
+		if err != nil {
+			klog.Errorf("failed to build %s digest for %s: %v", period, user.Name, err)
+			failed = append(failed, user.Name)
+			continue
+		}
+		if data.Own.Empty() && len(data.Accounts) == 0 {
+			skipped = append(skipped, user.Name)
+			continue
+		}
+
+		receiver := user.Attributes.Data()
+		subject, body, err := a.templates.render(&receiver, templateDigest, &TemplateContext{
+			JobInformation: JobInformation{
+				JobURL: fmt.Sprintf("https://%s/portal/jobs", config.GetConfig().Host),
+			},
+			ReceiverName: receiver.Nickname,
+			Digest:       data,
+		})
+		if err == nil {
+			dedupeKey := fmt.Sprintf("digest/%s/%s/%d", period, end.Format(time.DateOnly), user.ID)
+			err = a.enqueueMessage(ctx, dedupeKey, user.ID, &receiver, channels, time.Time{}, subject, body)
+		}
+		if err != nil {
+			klog.Errorf("failed to send %s digest to %s: %v", period, user.Name, err)
+			failed = append(failed, user.Name)
+			continue
+		}
+		sent = append(sent, user.Name)
+	}
+
+	klog.Infof("%s digest: %d sent, %d skipped, %d failed", period, len(sent), len(skipped), len(failed))
+	return map[string][]string{
+		"sent":    sent,
+		"skipped": skipped,
+		"failed":  failed,
+	}, nil
+}
+
+// buildDigest 汇总用户自己的作业，以及其作为管理员的账户中其他成员的作业
+func (a *alertMgr) buildDigest(
+	ctx context.Context,
+	userID uint,
+	period model.DigestPeriod,
+	start, end time.Time,
+) (*DigestData, error) {
+	jobs, err := a.listDigestJobs(ctx, start, end, query.Job.UserID.Eq(userID))
+	if err != nil {
+		return nil, err
+	}
+	own, err := a.summarizeJobs(ctx, jobs, start, end, false)
+	if err != nil {
+		return nil, err
+	}
+	data := &DigestData{
+		Period: period,
+		Start:  start,
+		End:    end,
+		Own:    *own,
+	}
+
+	ua := query.UserAccount
+	adminOf, err := ua.WithContext(ctx).Where(ua.UserID.Eq(userID), ua.Role.Eq(uint8(model.RoleAdmin))).Find()
+	if err != nil {
+		return nil, err
+	}
+	ac := query.Account
+	for _, membership := range adminOf {
+		account, err := ac.WithContext(ctx).Where(ac.ID.Eq(membership.AccountID)).First()
+		if err != nil {
+			return nil, err
+		}
+		jobs, err := a.listDigestJobs(ctx, start, end,
+			query.Job.AccountID.Eq(account.ID), query.Job.UserID.Neq(userID))
+		if err != nil {
+			return nil, err
+		}
+		summary, err := a.summarizeJobs(ctx, jobs, start, end, true)
+		if err != nil {
+			return nil, err
+		}
+		if summary.Empty() {
+			continue
+		}
+		data.Accounts = append(data.Accounts, AccountDigest{
+			Account: account.Nickname,
+			Summary: *summary,
+		})
+	}
+	return data, nil
+}
+
+// listDigestJobs 查询在统计周期结束前创建、且没有在统计周期开始前结束的作业
+func (a *alertMgr) listDigestJobs(
+	ctx context.Context,
+	start, end time.Time,
+	conds ...gen.Condition,
+) ([]*model.Job, error) {
+	j := query.Job
+	terminated := []string{string(batch.Completed), string(batch.Failed), string(batch.Aborted), string(batch.Terminated)}
+	return j.WithContext(ctx).
+		Preload(j.User).
+		Where(conds...).
+		Where(
+			j.CreationTimestamp.Lt(end),
+			field.Or(j.Status.NotIn(terminated...), j.CompletedTimestamp.Gte(start)),
+		).
+		Find()
+}
+
+// summarizeJobs 统计作业在 [start, end) 内的启动、结束、清理提醒和 GPU 使用情况，
+// withUser 为 true 时在作业条目中注明所属用户
+func (a *alertMgr) summarizeJobs(
+	ctx context.Context,
+	jobs []*model.Job,
+	start, end time.Time,
+	withUser bool,
+) (*DigestSummary, error) {
+	host := config.GetConfig().Host
+	inWindow := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(start) && t.Before(end)
+	}
+	toDigestJob := func(job *model.Job) DigestJob {
+		item := DigestJob{
+			Name:    job.Name,
+			JobName: job.JobName,
+			URL:     fmt.Sprintf("https://%s/portal/jobs/detail/%s", host, job.JobName),
+		}
+		if withUser {
+			item.Username = job.User.Nickname
+		}
+		return item
+	}
+
+	summary := &DigestSummary{}
+	runningJobs := make(map[string]*model.Job)
+	var utilWeighted, utilHours float64
+	for _, job := range jobs {
+		if inWindow(job.RunningTimestamp) {
+			summary.Started = append(summary.Started, toDigestJob(job))
+		}
+		if inWindow(job.CompletedTimestamp) {
+			switch job.Status {
+			case batch.Completed:
+				summary.Succeeded = append(summary.Succeeded, toDigestJob(job))
+			case batch.Failed:
+				summary.Failed = append(summary.Failed, toDigestJob(job))
+			}
+		}
+		if job.Status == batch.Running {
+			runningJobs[job.JobName] = job
+		}
+
+		hours := digestGPUHours(job, start, end)
+		summary.GPUHours += hours
+		if hours > 0 && job.ProfileData != nil {
+			if profile := job.ProfileData.Data(); profile != nil && profile.GPUUtilAvg != nil {
+				utilWeighted += float64(*profile.GPUUtilAvg) * hours
+				utilHours += hours
+			}
+		}
+	}
+	if utilHours > 0 {
+		avg := float32(utilWeighted / utilHours)
+		summary.GPUUtilAvg = &avg
+	}
+
+	// 周期内收到清理提醒且仍在运行的作业
+	if len(runningJobs) > 0 {
+		alertDB := query.Alert
+		reminders, err := alertDB.WithContext(ctx).Where(
+			alertDB.JobName.In(lo.Keys(runningJobs)...),
+			alertDB.AlertType.In(model.LowGPUJobRemindedAlert.String(), model.LongTimeJobRemindedAlert.String()),
+			alertDB.AlertTimestamp.Gte(start),
+			alertDB.AlertTimestamp.Lt(end),
+		).Find()
+		if err != nil {
+			return nil, err
+		}
+		for _, reminder := range reminders {
+			item := toDigestJob(runningJobs[reminder.JobName])
+			item.Reason = reminder.AlertType
+			summary.CloseToCleanup = append(summary.CloseToCleanup, item)
+		}
+	}
+
+	for _, items := range [][]DigestJob{summary.Started, summary.Succeeded, summary.Failed, summary.CloseToCleanup} {
+		sort.Slice(items, func(i, k int) bool { return items[i].JobName < items[k].JobName })
+	}
+	return summary, nil
+}
+
+// digestGPUHours 计算作业在 [start, end) 内消耗的卡时
+func digestGPUHours(job *model.Job, start, end time.Time) float64 {
+	if job.RunningTimestamp.IsZero() {
+		return 0
+	}
+	var gpus float64
+	for name, quantity := range job.Resources.Data() {
+		if strings.HasPrefix(name.String(), digestGPUResourcePrefix) {
+			gpus += quantity.AsApproximateFloat64()
+		}
+	}
+	if gpus == 0 {
+		return 0
+	}
+
+	from := job.RunningTimestamp
+	if from.Before(start) {
+		from = start
+	}
+	to := end
+	if !job.CompletedTimestamp.IsZero() && job.CompletedTimestamp.Before(end) {
+		to = job.CompletedTimestamp
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return gpus * to.Sub(from).Hours()
+}
@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestDigestGPUHours(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	end := start.Add(24 * time.Hour)
+	resources := datatypes.NewJSONType(v1.ResourceList{
+		v1.ResourceCPU:                     resource.MustParse("8"),
+		v1.ResourceName("nvidia.com/a100"): resource.MustParse("2"),
+	})
+
+	cases := []struct {
+		name     string
+		job      *model.Job
+		expected float64
+	}{
+		{"not running", &model.Job{Resources: resources}, 0},
+		{"started before window", &model.Job{
+			Resources:        resources,
+			RunningTimestamp: start.Add(-time.Hour),
+		}, 48},
+		{"finished in window", &model.Job{
+			Resources:          resources,
+			RunningTimestamp:   start.Add(2 * time.Hour),
+			CompletedTimestamp: start.Add(5 * time.Hour),
+		}, 6},
+		{"cpu only", &model.Job{
+			Resources:        datatypes.NewJSONType(v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}),
+			RunningTimestamp: start,
+		}, 0},
+	}
+	for _, c := range cases {
+		if hours := digestGPUHours(c.job, start, end); hours != c.expected {
+			t.Errorf("%s: expected %.1f GPU hours, got %.1f", c.name, c.expected, hours)
+		}
+	}
+}
+
+func TestDigestTemplate(t *testing.T) {
+	data := &TemplateContext{
+		ReceiverName: "Alice",
+		Digest: &DigestData{
+			Period: model.DigestPeriodWeekly,
+			Own: DigestSummary{
+				Failed:     []DigestJob{{Name: "train", JobName: "sg-alice-1"}},
+				GPUHours:   12.5,
+				GPUUtilAvg: ptr.To[float32](0.42),
+			},
+			Accounts: []AccountDigest{{
+				Account: "lab",
+				Summary: DigestSummary{CloseToCleanup: []DigestJob{
+					{Name: "idle", JobName: "sg-bob-1", Username: "Bob", Reason: model.LowGPUJobRemindedAlert.String()},
+				}},
+			}},
+		},
+	}
+	for _, lang := range model.GetAllNotificationLanguages() {
+		subject, body, err := newTemplateRenderer("", string(lang)).render(nil, templateDigest, data)
+		if err != nil {
+			t.Fatalf("render %s digest failed: %v", lang, err)
+		}
+		for _, s := range []string{"sg-alice-1", "12.5", "42.0%", "lab", "sg-bob-1", "Bob"} {
+			if !strings.Contains(body, s) {
+				t.Errorf("%s digest body missing %q", lang, s)
+			}
+		}
+		if subject == "" {
+			t.Errorf("%s digest subject is empty", lang)
+		}
+	}
+}
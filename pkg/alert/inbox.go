@@ -0,0 +1,96 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+// inboxBroker 在当前副本内分发站内消息变更事件，用于实时推送未读数
+type inboxBroker struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan struct{}]struct{}
+}
+
+var broker = &inboxBroker{
+	subscribers: make(map[uint]map[chan struct{}]struct{}),
+}
+
+// SubscribeInbox 订阅用户收件箱的变更事件，返回的取消函数需要在连接关闭时调用。
+// 事件只在写入消息的副本内分发，订阅方需要自行定期刷新以覆盖其他副本写入的消息
+func SubscribeInbox(userID uint) (events <-chan struct{}, cancel func()) {
+	ch := make(chan struct{}, 1)
+	broker.mu.Lock()
+	if broker.subscribers[userID] == nil {
+		broker.subscribers[userID] = make(map[chan struct{}]struct{})
+	}
+	broker.subscribers[userID][ch] = struct{}{}
+	broker.mu.Unlock()
+
+	return ch, func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		delete(broker.subscribers[userID], ch)
+		if len(broker.subscribers[userID]) == 0 {
+			delete(broker.subscribers, userID)
+		}
+	}
+}
+
+// NotifyInboxChanged 通知订阅者收件箱发生变化（新消息或已读状态变化）
+func NotifyInboxChanged(userID uint) {
+	broker.mu.RLock()
+	defer broker.mu.RUnlock()
+	for ch := range broker.subscribers[userID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// 已有未处理的事件，无需重复通知
+		}
+	}
+}
+
+// addInboxMessage 写入站内消息，失败时只记录日志，不影响其他渠道的通知
+func (a *alertMgr) addInboxMessage(ctx context.Context, msg *model.InboxMessage) {
+	if err := query.InboxMessage.WithContext(ctx).Create(msg); err != nil {
+		klog.Errorf("failed to create inbox message for user %d: %v", msg.UserID, err)
+		return
+	}
+	NotifyInboxChanged(msg.UserID)
+}
+
+// ApprovalOrderAlert 将审批工单的处理结果写入创建者的收件箱
+func (a *alertMgr) ApprovalOrderAlert(ctx context.Context, orderID uint) error {
+	ao := query.ApprovalOrder
+	order, err := ao.WithContext(ctx).Where(ao.ID.Eq(orderID)).First()
+	if err != nil {
+		return err
+	}
+
+	var result string
+	switch order.Status {
+	case model.ApprovalOrderStatusApproved:
+		result = "已通过"
+	case model.ApprovalOrderStatusRejected:
+		result = "已被拒绝"
+	default:
+		return nil
+	}
+
+	content := fmt.Sprintf("您的审批工单 %s %s。", order.Name, result)
+	if order.ReviewNotes != "" {
+		content = fmt.Sprintf("%s\n审批备注：%s", content, order.ReviewNotes)
+	}
+	a.addInboxMessage(ctx, &model.InboxMessage{
+		UserID:   order.CreatorID,
+		Category: model.InboxCategoryApproval,
+		Title:    fmt.Sprintf("审批工单%s", result),
+		Content:  content,
+	})
+	return nil
+}
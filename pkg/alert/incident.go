@@ -0,0 +1,159 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const templateClusterIncident = "cluster_incident"
+
+// OpenIncident 记录集群异常。同一对象的同类异常在恢复前只记录一次并只通知一次，
+// 之后每次调用更新最近出现时间和详情，并将出现次数加一
+func OpenIncident(ctx context.Context, kind model.IncidentKind, target, message string) error {
+	return recordIncident(ctx, kind, target, message, true)
+}
+
+// RefreshIncident 与 OpenIncident 相同，但异常尚未恢复时不增加出现次数。
+// 用于事件驱动的检查：对象状态变化但仍然异常，或重启后不知道之前的状态时只更新详情
+func RefreshIncident(ctx context.Context, kind model.IncidentKind, target, message string) error {
+	return recordIncident(ctx, kind, target, message, false)
+}
+
+func recordIncident(ctx context.Context, kind model.IncidentKind, target, message string, occurred bool) error {
+	now := utils.GetLocalTime()
+	ci := query.ClusterIncident
+	incident, err := ci.WithContext(ctx).Where(
+		ci.Kind.Eq(string(kind)),
+		ci.Target.Eq(target),
+		ci.Status.Eq(string(model.IncidentStatusOpen)),
+	).First()
+	if err == nil {
+		exprs := []field.AssignExpr{ci.Message.Value(message), ci.LastSeenAt.Value(now)}
+		if occurred {
+			exprs = append(exprs, ci.Occurrences.Add(1))
+		}
+		_, err = ci.WithContext(ctx).Where(ci.ID.Eq(incident.ID)).UpdateSimple(exprs...)
+		return err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	incident = &model.ClusterIncident{
+		Kind:        kind,
+		Target:      target,
+		Status:      model.IncidentStatusOpen,
+		Message:     message,
+		Occurrences: 1,
+		OpenedAt:    now,
+		LastSeenAt:  now,
+	}
+	if err := ci.WithContext(ctx).Create(incident); err != nil {
+		return err
+	}
+	klog.Warningf("cluster incident opened: %s %s: %s", kind, target, message)
+	return getAlerter().notifyIncident(ctx, incident)
+}
+
+// ResolveIncident 将对象尚未恢复的异常标记为已恢复，并通知管理员
+func ResolveIncident(ctx context.Context, kind model.IncidentKind, target string) error {
+	ci := query.ClusterIncident
+	incidents, err := ci.WithContext(ctx).Where(
+		ci.Kind.Eq(string(kind)),
+		ci.Target.Eq(target),
+		ci.Status.Eq(string(model.IncidentStatusOpen)),
+	).Find()
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetLocalTime()
+	for _, incident := range incidents {
+		incident.Status = model.IncidentStatusResolved
+		incident.ResolvedAt = &now
+		if _, err := ci.WithContext(ctx).Where(ci.ID.Eq(incident.ID)).UpdateSimple(
+			ci.Status.Value(string(model.IncidentStatusResolved)),
+			ci.ResolvedAt.Value(now),
+		); err != nil {
+			return err
+		}
+		klog.Infof("cluster incident resolved: %s %s", kind, target)
+		if err := getAlerter().notifyIncident(ctx, incident); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncIncidents 根据一次完整检查的结果更新某类异常：failing 中的对象记录为异常，
+// 其余尚未恢复的同类异常标记为已恢复。failing 的 key 为异常对象，value 为异常详情
+func SyncIncidents(ctx context.Context, kind model.IncidentKind, failing map[string]string) error {
+	var errs []error
+	for target, message := range failing {
+		if err := OpenIncident(ctx, kind, target, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	ci := query.ClusterIncident
+	open, err := ci.WithContext(ctx).Where(
+		ci.Kind.Eq(string(kind)),
+		ci.Status.Eq(string(model.IncidentStatusOpen)),
+	).Find()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, incident := range open {
+		if _, ok := failing[incident.Target]; ok {
+			continue
+		}
+		if err := ResolveIncident(ctx, kind, incident.Target); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// adminReceiver 返回接收集群告警的管理员，邮件发送到 SMTP.Notify
+func (a *alertMgr) adminReceiver() (*model.UserAttribute, []model.NotificationChannel) {
+	cfg := config.GetConfig()
+	receiver := &model.UserAttribute{
+		Name: "admin",
+	}
+	if cfg.SMTP.Notify != "" {
+		receiver.Email = &cfg.SMTP.Notify
+	}
+
+	channels := make([]model.NotificationChannel, 0, len(cfg.ClusterAlert.Channels))
+	for _, channel := range cfg.ClusterAlert.Channels {
+		channels = append(channels, model.NotificationChannel(channel))
+	}
+	if len(channels) == 0 {
+		channels = append(channels, model.NotificationChannelEmail)
+	}
+	return receiver, channels
+}
+
+// notifyIncident 将异常出现或恢复的通知放入发件箱
+func (a *alertMgr) notifyIncident(ctx context.Context, incident *model.ClusterIncident) error {
+	receiver, channels := a.adminReceiver()
+	subject, body, err := a.templates.render(receiver, templateClusterIncident, &TemplateContext{
+		Incident: incident,
+	})
+	if err != nil {
+		return err
+	}
+	dedupeKey := fmt.Sprintf("incident/%d/%s", incident.ID, incident.Status)
+	return a.enqueueMessage(ctx, dedupeKey, 0, receiver, channels, time.Time{}, subject, body)
+}
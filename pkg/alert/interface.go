@@ -16,6 +16,7 @@ import (
 //  4. 作业因低利用率已经被释放通知
 //  5. 作业异常的资源使用警告
 //  6. 发送邮箱验证码
+//  7. 审批工单处理结果通知（站内消息）
 type AlertInterface interface {
 	JobRunningAlert(ctx context.Context, jobName string) error
 	JobFailureAlert(ctx context.Context, jobName string) error
@@ -25,9 +26,16 @@ type AlertInterface interface {
 	RemindLongTimeRunningJob(ctx context.Context, jobName string, deleteTime time.Time, extra map[string]any) error
 	RemindLowUsageJob(ctx context.Context, jobName string, deleteTime time.Time, extra map[string]any) error
 	SendVerificationCode(ctx context.Context, code string, receiver *model.UserAttribute) error
+	ApprovalOrderAlert(ctx context.Context, orderID uint) error
+	GetEnabledChannels() []model.NotificationChannel
 }
 
-// alertHandlerInterface 是具体的通知组件对外部提供的接口，WPS Robot 或者 SMTP 邮件通知都应该实现这两个接口
+// alertHandlerInterface 是具体的通知渠道对外部提供的接口，SMTP 邮件、Webhook 以及各类 IM 机器人都应该实现这些接口
 type alertHandlerInterface interface {
+	// Channel 返回通知渠道的类型
+	Channel() model.NotificationChannel
+	// Available 判断接收者能否通过该渠道接收通知（如是否配置了邮箱或个人机器人）
+	Available(receiver *model.UserAttribute) bool
+	// SendMessageTo 发送通知，body 为 HTML 格式，非邮件渠道需要自行转换
 	SendMessageTo(ctx context.Context, receiver *model.UserAttribute, subject, body string) error
 }
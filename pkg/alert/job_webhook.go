@@ -0,0 +1,361 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const (
+	// jobWebhookEventKey 请求头中的事件类型
+	jobWebhookEventKey = "X-Crater-Event"
+	// jobWebhookDeliveryKey 请求头中的投递记录 ID，重试时保持不变，可用于接收方去重
+	jobWebhookDeliveryKey = "X-Crater-Delivery"
+	// JobWebhookPingEvent 测试 Webhook 时发送的事件
+	JobWebhookPingEvent = "ping"
+	// jobWebhookDialTimeout 建立连接的超时时间
+	jobWebhookDialTimeout = 30 * time.Second
+)
+
+// JobEventExitCode 作业中终止的容器的退出信息
+type JobEventExitCode struct {
+	ExitCode   int32      `json:"exitCode"`
+	Signal     int32      `json:"signal,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// JobEventJob 作业事件中的作业信息
+type JobEventJob struct {
+	Name               string             `json:"name"`
+	JobName            string             `json:"jobName"`
+	JobType            string             `json:"jobType"`
+	User               string             `json:"user"`
+	Account            string             `json:"account"`
+	Phase              string             `json:"phase"`
+	Nodes              []string           `json:"nodes"`
+	CreationTimestamp  *time.Time         `json:"creationTimestamp,omitempty"`
+	RunningTimestamp   *time.Time         `json:"runningTimestamp,omitempty"`
+	CompletedTimestamp *time.Time         `json:"completedTimestamp,omitempty"`
+	ExitCodes          []JobEventExitCode `json:"exitCodes,omitempty"`
+	URL                string             `json:"url"`
+}
+
+// JobEventPayload 作业生命周期 Webhook 的请求体
+type JobEventPayload struct {
+	Event     string      `json:"event"`
+	Timestamp int64       `json:"timestamp"`
+	Job       JobEventJob `json:"job"`
+}
+
+// EmitJobEvent 作业状态变化时，为订阅了该状态的 Webhook 生成投递记录，由 OutboxWorker 异步投递
+func EmitJobEvent(ctx context.Context, jobName string) error {
+	j := query.Job
+	job, err := j.WithContext(ctx).Preload(j.User, j.Account).Where(j.JobName.Eq(jobName)).First()
+	if err != nil {
+		return err
+	}
+	phase := string(job.Status)
+
+	jw := query.JobWebhook
+	webhooks, err := jw.WithContext(ctx).Where(
+		jw.AccountID.Eq(job.AccountID),
+		jw.Enabled.Is(true),
+		field.Or(
+			jw.JobName.Eq(job.JobName),
+			field.And(jw.JobName.Eq(""), field.Or(jw.UserID.Eq(job.UserID), jw.AllMembers.Is(true))),
+		),
+	).Find()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	deliveries := make([]*model.JobWebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(phase) {
+			continue
+		}
+		if data == nil {
+			if data, err = json.Marshal(newJobEventPayload(job, config.GetConfig().Host)); err != nil {
+				return fmt.Errorf("marshal job event: %w", err)
+			}
+		}
+		deliveries = append(deliveries, &model.JobWebhookDelivery{
+			WebhookID:     webhook.ID,
+			JobName:       job.JobName,
+			Event:         phase,
+			Payload:       string(data),
+			Status:        model.OutboxStatusPending,
+			MaxAttempts:   outboxMaxAttempts,
+			NextAttemptAt: time.Now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := query.JobWebhookDelivery.WithContext(ctx).Create(deliveries...); err != nil {
+		return err
+	}
+	wakeOutbox()
+	return nil
+}
+
+// newJobEventPayload 根据作业记录生成事件，退出码来自作业记录中采集的终止状态
+func newJobEventPayload(job *model.Job, host string) *JobEventPayload {
+	timestamp := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+
+	payload := &JobEventPayload{
+		Event:     string(job.Status),
+		Timestamp: time.Now().Unix(),
+		Job: JobEventJob{
+			Name:               job.Name,
+			JobName:            job.JobName,
+			JobType:            string(job.JobType),
+			User:               job.User.Name,
+			Account:            job.Account.Name,
+			Phase:              string(job.Status),
+			Nodes:              job.Nodes.Data(),
+			CreationTimestamp:  timestamp(job.CreationTimestamp),
+			RunningTimestamp:   timestamp(job.RunningTimestamp),
+			CompletedTimestamp: timestamp(job.CompletedTimestamp),
+			URL:                fmt.Sprintf("https://%s/portal/jobs/detail/%s", host, job.JobName),
+		},
+	}
+	if payload.Job.Nodes == nil {
+		payload.Job.Nodes = []string{}
+	}
+	if job.TerminatedStates != nil {
+		for _, state := range job.TerminatedStates.Data() {
+			payload.Job.ExitCodes = append(payload.Job.ExitCodes, JobEventExitCode{
+				ExitCode:   state.ExitCode,
+				Signal:     state.Signal,
+				Reason:     state.Reason,
+				Message:    state.Message,
+				FinishedAt: timestamp(state.FinishedAt.Time),
+			})
+		}
+	}
+	return payload
+}
+
+// PingJobWebhook 立即向 Webhook 发送一次测试事件，用于确认接收方可以访问并正确校验签名
+func PingJobWebhook(ctx context.Context, webhook *model.JobWebhook) error {
+	data, err := json.Marshal(&JobEventPayload{
+		Event:     JobWebhookPingEvent,
+		Timestamp: time.Now().Unix(),
+		Job: JobEventJob{
+			JobName: webhook.JobName,
+			Nodes:   []string{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal ping event: %w", err)
+	}
+	return postJobWebhook(ctx, newJobWebhookHTTPClient(), webhook, JobWebhookPingEvent, "", data)
+}
+
+// ResendJobWebhookDelivery 重新投递作业事件，重新计算尝试次数
+func ResendJobWebhookDelivery(ctx context.Context, id uint) error {
+	d := query.JobWebhookDelivery
+	info, err := d.WithContext(ctx).
+		Where(d.ID.Eq(id), d.Status.Neq(string(model.OutboxStatusSending))).
+		UpdateSimple(
+			d.Status.Value(string(model.OutboxStatusPending)),
+			d.Attempts.Value(0),
+			d.NextAttemptAt.Value(time.Now()),
+		)
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return fmt.Errorf("job webhook delivery %d not found or is being sent", id)
+	}
+	wakeOutbox()
+	return nil
+}
+
+// postJobWebhook 发送作业事件，配置了密钥时在请求头中附带签名
+func postJobWebhook(
+	ctx context.Context,
+	client *http.Client,
+	webhook *model.JobWebhook,
+	event, deliveryID string,
+	data []byte,
+) error {
+	header := map[string]string{
+		jobWebhookEventKey: event,
+	}
+	if deliveryID != "" {
+		header[jobWebhookDeliveryKey] = deliveryID
+	}
+	if webhook.Secret != "" {
+		header[webhookSignatureKey] = signWebhookPayload(webhook.Secret, data)
+	}
+	_, err := postNotification(ctx, client, webhook.URL, data, header)
+	// Webhook 由普通用户配置，错误信息会返回给用户，不能包含内部地址
+	if errors.Is(err, ErrJobWebhookAddressDenied) {
+		return ErrJobWebhookAddressDenied
+	}
+	return err
+}
+
+// ErrJobWebhookAddressDenied 作业 Webhook 的目标地址属于本地或集群内部网络
+var ErrJobWebhookAddressDenied = errors.New("webhook address is not allowed")
+
+// alwaysDeniedPrefixes 除 net.IP 自带判断外始终禁止访问的地址段
+var alwaysDeniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // 本网络，Linux 上会连接到本机
+	netip.MustParsePrefix("100.64.0.0/10"), // 共享地址空间，常被用作集群网络
+}
+
+// CheckJobWebhookAddress 判断作业 Webhook 能否访问该地址，
+// 环回、私有、链路本地、组播地址和 denied 中的地址段都不允许访问
+func CheckJobWebhookAddress(ip net.IP, denied []netip.Prefix) error {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ErrJobWebhookAddressDenied
+	}
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return ErrJobWebhookAddressDenied
+	}
+	for _, prefix := range slices.Concat(alwaysDeniedPrefixes, denied) {
+		if prefix.Contains(addr) {
+			return ErrJobWebhookAddressDenied
+		}
+	}
+	return nil
+}
+
+// JobWebhookDeniedPrefixes 解析配置中额外禁止访问的地址段，配置校验已保证格式正确
+func JobWebhookDeniedPrefixes() []netip.Prefix {
+	cidrs := config.GetConfig().Notification.JobWebhookDeniedCIDRs
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		}
+	}
+	return prefixes
+}
+
+// newJobWebhookHTTPClient 投递作业 Webhook 使用的 HTTP 客户端
+func newJobWebhookHTTPClient() *http.Client {
+	return guardJobWebhookClient(newNotificationHTTPClient(), JobWebhookDeniedPrefixes())
+}
+
+// guardJobWebhookClient 限制客户端只能访问公网地址，防止用户通过 Webhook 访问集群内部服务。
+// 在建立连接时检查解析后的地址，避免 DNS 重绑定绕过检查；不使用代理，也不跟随重定向
+func guardJobWebhookClient(client *http.Client, denied []netip.Prefix) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   jobWebhookDialTimeout,
+		KeepAlive: jobWebhookDialTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return CheckJobWebhookAddress(net.ParseIP(host), denied)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	client.Transport = transport
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return client
+}
+
+func (w *OutboxWorker) processJobWebhooks(ctx context.Context) {
+	deliveries, err := claimDue(ctx, func(d *model.JobWebhookDelivery) uint { return d.ID })
+	if err != nil {
+		klog.Errorf("OutboxWorker: failed to claim job webhook deliveries: %v", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		w.deliverJobWebhook(ctx, delivery)
+	}
+}
+
+func (w *OutboxWorker) deliverJobWebhook(ctx context.Context, delivery *model.JobWebhookDelivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	defer cancel()
+
+	jw := query.JobWebhook
+	webhook, err := jw.WithContext(ctx).Where(jw.ID.Eq(delivery.WebhookID)).First()
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 已删除或停用的 Webhook 不再重试
+		err = fmt.Errorf("webhook %d has been deleted", delivery.WebhookID)
+		delivery.Attempts = delivery.MaxAttempts - 1
+	case err == nil && !webhook.Enabled:
+		err = fmt.Errorf("webhook %d is disabled", webhook.ID)
+		delivery.Attempts = delivery.MaxAttempts - 1
+	case err == nil:
+		err = postJobWebhook(sendCtx, w.client, webhook, delivery.Event,
+			strconv.FormatUint(uint64(delivery.ID), 10), []byte(delivery.Payload))
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	record := model.OutboxDelivery{
+		Attempt:   delivery.Attempts,
+		Timestamp: now,
+		Replica:   utils.GetReplicaName(),
+	}
+	switch {
+	case err == nil:
+		delivery.Status = model.OutboxStatusSent
+		delivery.SentAt = &now
+	case delivery.Attempts >= delivery.MaxAttempts:
+		record.Error = err.Error()
+		delivery.Status = model.OutboxStatusFailed
+		delivery.LastError = err.Error()
+		klog.Errorf("OutboxWorker: job webhook delivery %d of %s failed after %d attempts: %v",
+			delivery.ID, delivery.JobName, delivery.Attempts, err)
+	default:
+		record.Error = err.Error()
+		delivery.Status = model.OutboxStatusPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(outboxBackoff(delivery.Attempts))
+		klog.Warningf("OutboxWorker: job webhook delivery %d of %s failed (attempt %d), retry at %s: %v",
+			delivery.ID, delivery.JobName, delivery.Attempts, delivery.NextAttemptAt.Format(time.DateTime), err)
+	}
+	delivery.Deliveries = datatypes.NewJSONType(append(delivery.Deliveries.Data(), record))
+
+	if err := query.GetDB().WithContext(ctx).
+		Model(delivery).
+		Select("Status", "Attempts", "NextAttemptAt", "LastError", "SentAt", "Deliveries").
+		Updates(delivery).Error; err != nil {
+		klog.Errorf("OutboxWorker: failed to update job webhook delivery %d: %v", delivery.ID, err)
+	}
+}
@@ -0,0 +1,136 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestPostJobWebhook(t *testing.T) {
+	finishedAt := time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC)
+	job := &model.Job{
+		Name:              "demo",
+		JobName:           "sg-alice-1",
+		JobType:           model.JobTypePytorch,
+		User:              model.User{Name: "alice"},
+		Account:           model.Account{Name: "default"},
+		Status:            batch.Failed,
+		CreationTimestamp: time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+		Nodes:             datatypes.NewJSONType([]string{"node-1"}),
+		TerminatedStates: ptr.To(datatypes.NewJSONType([]v1.ContainerStateTerminated{
+			{ExitCode: 137, Reason: "OOMKilled", FinishedAt: metav1.NewTime(finishedAt)},
+		})),
+	}
+	data, err := json.Marshal(newJobEventPayload(job, "crater"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		payload   JobEventPayload
+		header    http.Header
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		header = r.Header
+		signature = signWebhookPayload("test-secret", body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &model.JobWebhook{URL: server.URL, Secret: "test-secret"}
+	if err := postJobWebhook(context.Background(), server.Client(), webhook, string(batch.Failed), "42", data); err != nil {
+		t.Fatalf("post job webhook failed: %v", err)
+	}
+
+	if header.Get(webhookSignatureKey) != signature {
+		t.Errorf("signature mismatch: got %q, want %q", header.Get(webhookSignatureKey), signature)
+	}
+	if header.Get(jobWebhookEventKey) != "Failed" || header.Get(jobWebhookDeliveryKey) != "42" {
+		t.Errorf("unexpected event headers: %v", header)
+	}
+	if payload.Job.JobName != "sg-alice-1" || payload.Job.Phase != "Failed" || payload.Job.Nodes[0] != "node-1" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if payload.Job.RunningTimestamp != nil || payload.Job.CreationTimestamp == nil {
+		t.Errorf("unexpected timestamps: %+v", payload.Job)
+	}
+	if len(payload.Job.ExitCodes) != 1 || payload.Job.ExitCodes[0].ExitCode != 137 ||
+		!payload.Job.ExitCodes[0].FinishedAt.Equal(finishedAt) {
+		t.Errorf("unexpected exit codes: %+v", payload.Job.ExitCodes)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal secret"))
+	})
+	err = postJobWebhook(context.Background(), server.Client(), webhook, string(batch.Failed), "43", data)
+	if err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+	if strings.Contains(err.Error(), "internal secret") {
+		t.Errorf("error must not contain the response body: %v", err)
+	}
+}
+
+func TestJobWebhookAddressGuard(t *testing.T) {
+	denied := []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}
+	for ip, allowed := range map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.96.0.1":       false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::ffff:10.0.0.1": false,
+		"fd00::1":         false,
+		"203.0.113.7":     false,
+	} {
+		if err := CheckJobWebhookAddress(net.ParseIP(ip), denied); (err == nil) != allowed {
+			t.Errorf("address %s: allowed = %v, got err %v", ip, allowed, err)
+		}
+	}
+
+	// 测试服务器监听在环回地址上，受保护的客户端必须拒绝连接
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := guardJobWebhookClient(&http.Client{}, nil)
+	err := postJobWebhook(context.Background(), client, &model.JobWebhook{URL: server.URL}, JobWebhookPingEvent, "", []byte("{}"))
+	if !errors.Is(err, ErrJobWebhookAddressDenied) {
+		t.Errorf("expected loopback webhook to be denied, got %v", err)
+	}
+}
+
+func TestJobWebhookSubscribed(t *testing.T) {
+	webhook := &model.JobWebhook{}
+	if !webhook.Subscribed("Running") {
+		t.Error("webhook without events should receive all events")
+	}
+	webhook.Events = datatypes.NewJSONType([]string{"Completed", "Failed"})
+	if webhook.Subscribed("Running") || !webhook.Subscribed("Failed") {
+		t.Errorf("unexpected subscription of %v", webhook.Events.Data())
+	}
+}
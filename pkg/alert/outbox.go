@@ -0,0 +1,252 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const (
+	// outboxPollInterval 后台任务扫描发件箱的周期
+	outboxPollInterval = 5 * time.Second
+	// outboxBatchSize 每次扫描最多投递的通知数
+	outboxBatchSize = 50
+	// outboxMaxAttempts 每条通知的最大尝试次数
+	outboxMaxAttempts = 6
+	// outboxBaseBackoff 第一次重试的等待时间，之后每次翻倍
+	outboxBaseBackoff = 30 * time.Second
+	// outboxMaxBackoff 重试等待时间的上限
+	outboxMaxBackoff = time.Hour
+	// outboxSendTimeout 单次投递的超时时间，也是 sending 状态的租约时长，超时未完成的通知会被重新投递
+	outboxSendTimeout = 2 * time.Minute
+)
+
+// outboxWake 有新通知入队时唤醒后台任务，避免等待下一个扫描周期
+var outboxWake = make(chan struct{}, 1)
+
+func wakeOutbox() {
+	select {
+	case outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueueMessage 将通知写入发件箱，由 OutboxWorker 异步投递，notBefore 不为零时推迟到该时间后投递。
+// dedupeKey 相同且尚未投递完成的通知不会重复入队
+func (a *alertMgr) enqueueMessage(
+	ctx context.Context,
+	dedupeKey string,
+	userID uint,
+	receiver *model.UserAttribute,
+	channels []model.NotificationChannel,
+	notBefore time.Time,
+	subject, body string,
+) error {
+	o := query.OutboxMessage
+	if dedupeKey != "" {
+		count, err := o.WithContext(ctx).Where(
+			o.DedupeKey.Eq(dedupeKey),
+			o.Status.In(string(model.OutboxStatusPending), string(model.OutboxStatusSending)),
+		).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			klog.Infof("notification %s is already in outbox", dedupeKey)
+			return nil
+		}
+	}
+
+	nextAttemptAt := time.Now()
+	if notBefore.After(nextAttemptAt) {
+		nextAttemptAt = notBefore
+	}
+	msg := &model.OutboxMessage{
+		DedupeKey:     dedupeKey,
+		UserID:        userID,
+		Receiver:      datatypes.NewJSONType(*receiver),
+		Channels:      datatypes.NewJSONType(channels),
+		Subject:       subject,
+		Body:          body,
+		Status:        model.OutboxStatusPending,
+		MaxAttempts:   outboxMaxAttempts,
+		NextAttemptAt: nextAttemptAt,
+	}
+	if err := o.WithContext(ctx).Create(msg); err != nil {
+		return err
+	}
+	wakeOutbox()
+	return nil
+}
+
+// ResendOutboxMessage 将发送失败的通知重新放入发件箱，重新计算尝试次数
+func ResendOutboxMessage(ctx context.Context, id uint) error {
+	o := query.OutboxMessage
+	info, err := o.WithContext(ctx).
+		Where(o.ID.Eq(id), o.Status.Neq(string(model.OutboxStatusSending))).
+		UpdateSimple(
+			o.Status.Value(string(model.OutboxStatusPending)),
+			o.Attempts.Value(0),
+			o.NextAttemptAt.Value(time.Now()),
+		)
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return fmt.Errorf("outbox message %d not found or is being sent", id)
+	}
+	wakeOutbox()
+	return nil
+}
+
+// OutboxWorker 从发件箱中取出到期的通知和作业 Webhook 事件进行投递，失败时按指数退避重试。
+// 与定时任务一样只在主副本上运行
+type OutboxWorker struct {
+	alerter *alertMgr
+	// client 投递作业 Webhook 事件使用的 HTTP 客户端
+	client *http.Client
+}
+
+var _ manager.LeaderElectionRunnable = &OutboxWorker{}
+
+func NewOutboxWorker() *OutboxWorker {
+	return &OutboxWorker{
+		alerter: getAlerter(),
+		client:  newJobWebhookHTTPClient(),
+	}
+}
+
+// Start 实现 manager.Runnable，持续投递发件箱中的通知直到 ctx 结束
+func (w *OutboxWorker) Start(ctx context.Context) error {
+	klog.Info("OutboxWorker: started")
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		w.processOutbox(ctx)
+		select {
+		case <-ctx.Done():
+			klog.Info("OutboxWorker: stopped")
+			return nil
+		case <-ticker.C:
+		case <-outboxWake:
+		}
+	}
+}
+
+// NeedLeaderElection 实现 manager.LeaderElectionRunnable
+func (w *OutboxWorker) NeedLeaderElection() bool {
+	return true
+}
+
+func (w *OutboxWorker) processOutbox(ctx context.Context) {
+	messages, err := w.claimMessages(ctx)
+	if err != nil {
+		klog.Errorf("OutboxWorker: failed to claim outbox messages: %v", err)
+	}
+	for _, msg := range messages {
+		w.deliver(ctx, msg)
+	}
+	w.processJobWebhooks(ctx)
+}
+
+// claimMessages 取出到期的通知并标记为 sending，租约到期仍未完成的通知会被重新取出
+func (w *OutboxWorker) claimMessages(ctx context.Context) ([]*model.OutboxMessage, error) {
+	return claimDue(ctx, func(msg *model.OutboxMessage) uint { return msg.ID })
+}
+
+// claimDue 在事务中锁定并取出到期待投递的记录，标记为 sending 并设置租约，
+// 多个副本同时扫描时已被锁定的记录会被跳过
+func claimDue[T any](ctx context.Context, idOf func(*T) uint) ([]*T, error) {
+	var items []*T
+	err := query.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]model.OutboxStatus{model.OutboxStatusPending, model.OutboxStatusSending}, now).
+			Order("next_attempt_at").
+			Limit(outboxBatchSize).
+			Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, idOf(item))
+		}
+		return tx.Model(new(T)).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":          model.OutboxStatusSending,
+				"next_attempt_at": now.Add(outboxSendTimeout),
+			}).Error
+	})
+	return items, err
+}
+
+func (w *OutboxWorker) deliver(ctx context.Context, msg *model.OutboxMessage) {
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	defer cancel()
+
+	receiver := msg.Receiver.Data()
+	err := w.alerter.sendMessage(sendCtx, &receiver, msg.Channels.Data(), msg.Subject, msg.Body)
+
+	now := time.Now()
+	msg.Attempts++
+	delivery := model.OutboxDelivery{
+		Attempt:   msg.Attempts,
+		Timestamp: now,
+		Replica:   utils.GetReplicaName(),
+	}
+	switch {
+	case err == nil:
+		msg.Status = model.OutboxStatusSent
+		msg.SentAt = &now
+	case msg.Attempts >= msg.MaxAttempts:
+		delivery.Error = err.Error()
+		msg.Status = model.OutboxStatusFailed
+		msg.LastError = err.Error()
+		klog.Errorf("OutboxWorker: notification %d to %s failed after %d attempts: %v", msg.ID, receiver.Name, msg.Attempts, err)
+	default:
+		delivery.Error = err.Error()
+		msg.Status = model.OutboxStatusPending
+		msg.LastError = err.Error()
+		msg.NextAttemptAt = now.Add(outboxBackoff(msg.Attempts))
+		klog.Warningf("OutboxWorker: notification %d to %s failed (attempt %d), retry at %s: %v",
+			msg.ID, receiver.Name, msg.Attempts, msg.NextAttemptAt.Format(time.DateTime), err)
+	}
+	msg.Deliveries = datatypes.NewJSONType(append(msg.Deliveries.Data(), delivery))
+
+	if err := query.GetDB().WithContext(ctx).
+		Model(msg).
+		Select("Status", "Attempts", "NextAttemptAt", "LastError", "SentAt", "Deliveries").
+		Updates(msg).Error; err != nil {
+		klog.Errorf("OutboxWorker: failed to update outbox message %d: %v", msg.ID, err)
+	}
+}
+
+// outboxBackoff 计算第 attempts 次失败后的重试等待时间
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
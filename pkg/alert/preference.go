@@ -0,0 +1,127 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+// GetNotificationPreference 获取用户的通知偏好设置，未设置时返回默认设置（接收所有通知）
+func GetNotificationPreference(ctx context.Context, userID uint) (*model.NotificationPreference, error) {
+	p := query.NotificationPreference
+	pref, err := p.WithContext(ctx).Where(p.UserID.Eq(userID)).First()
+	if err == nil {
+		return pref, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	alertTypes := make([]model.AlertTypePreference, 0)
+	for _, alertType := range model.GetAllAlertTypes() {
+		alertTypes = append(alertTypes, model.AlertTypePreference{AlertType: alertType, Enabled: true})
+	}
+	return &model.NotificationPreference{
+		UserID:     userID,
+		AlertTypes: datatypes.NewJSONType(alertTypes),
+	}, nil
+}
+
+// checkPreference 根据用户偏好判断是否发送该类型的通知，
+// 返回允许的渠道（为空表示所有可用渠道）、处于免打扰时段时推迟发送到的时间，以及跳过发送时的原因
+func (a *alertMgr) checkPreference(
+	ctx context.Context,
+	userID uint,
+	alertType model.AlertType,
+) (channels []model.NotificationChannel, notBefore time.Time, skipReason string) {
+	pref, err := GetNotificationPreference(ctx, userID)
+	if err != nil {
+		// 读取偏好失败时按默认设置发送，避免漏发
+		klog.Errorf("failed to get notification preference of user %d: %v", userID, err)
+		return nil, time.Time{}, ""
+	}
+
+	typePref := pref.GetAlertTypePreference(alertType)
+	if !typePref.Enabled {
+		return nil, time.Time{}, "disabled by user preference"
+	}
+	// 免打扰时段内的通知推迟到时段结束后投递，而不是丢弃
+	if !alertType.IsCleanerAlert() {
+		if endAt, ok := pref.QuietHoursEndAt(utils.GetLocalTime()); ok {
+			return typePref.Channels, endAt, ""
+		}
+	}
+	return typePref.Channels, time.Time{}, ""
+}
+
+// notifyAccountAdmins 将作业清理相关的提醒抄送给开启了该选项的账户管理员
+func (a *alertMgr) notifyAccountAdmins(
+	ctx context.Context,
+	info *JobInformation,
+	alertType model.AlertType,
+	data *TemplateContext,
+) {
+	ua := query.UserAccount
+	admins, err := ua.WithContext(ctx).Where(
+		ua.AccountID.Eq(info.AccountID),
+		ua.Role.Eq(uint8(model.RoleAdmin)),
+		ua.UserID.Neq(info.UserID),
+	).Find()
+	if err != nil {
+		klog.Errorf("failed to list admins of account %d: %v", info.AccountID, err)
+		return
+	}
+
+	u := query.User
+	for _, admin := range admins {
+		pref, err := GetNotificationPreference(ctx, admin.UserID)
+		if err != nil {
+			klog.Errorf("failed to get notification preference of user %d: %v", admin.UserID, err)
+			continue
+		}
+		if !pref.ReceiveAccountReminders {
+			continue
+		}
+		typePref := pref.GetAlertTypePreference(alertType)
+		if !typePref.Enabled {
+			continue
+		}
+
+		user, err := u.WithContext(ctx).Where(u.ID.Eq(admin.UserID)).First()
+		if err != nil {
+			klog.Errorf("failed to get account admin %d: %v", admin.UserID, err)
+			continue
+		}
+		// 使用管理员自己的语言渲染，并注明作业所属成员
+		receiver := user.Attributes.Data()
+		adminData := *data
+		adminData.ReceiverName = receiver.Nickname
+		adminData.Member = info.Username
+		subject, body, err := a.templates.render(&receiver, jobTemplates[alertType], &adminData)
+		if err != nil {
+			klog.Errorf("failed to render notification of job %s for account admin %s: %v", info.JobName, user.Name, err)
+			continue
+		}
+		a.addInboxMessage(ctx, &model.InboxMessage{
+			UserID:    admin.UserID,
+			Category:  model.InboxCategoryJob,
+			Title:     subject,
+			Content:   htmlToText(body),
+			Link:      info.JobURL,
+			JobName:   info.JobName,
+			AlertType: alertType.String(),
+		})
+		if err := a.enqueueMessage(ctx, jobDedupeKey(info.JobName, alertType, admin.UserID),
+			admin.UserID, &receiver, typePref.Channels, time.Time{}, subject, body); err != nil {
+			klog.Errorf("failed to notify account admin %s of job %s: %v", user.Name, info.JobName, err)
+		}
+	}
+}
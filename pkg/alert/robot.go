@@ -0,0 +1,202 @@
+package alert
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+// RobotAlerter 通过 IM 群机器人发送通知，支持飞书、钉钉、企业微信和 WPS。
+// 接收者在用户属性中配置了个人机器人时优先发送到个人机器人，否则发送到共享群机器人，
+// 并在渠道支持时通过手机号提醒接收者。
+type RobotAlerter struct {
+	channel model.NotificationChannel
+	config  config.NotificationRobot
+	client  *http.Client
+	// personalClient 发送到用户配置的个人机器人，与作业 Webhook 一样只能访问公网地址
+	personalClient *http.Client
+}
+
+// robotResponse 兼容各机器人接口的返回格式，非零错误码视为发送失败
+type robotResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func newRobotAlerter(channel model.NotificationChannel, robotConfig config.NotificationRobot) alertHandlerInterface {
+	return &RobotAlerter{
+		channel:        channel,
+		config:         robotConfig,
+		client:         newNotificationHTTPClient(),
+		personalClient: newJobWebhookHTTPClient(),
+	}
+}
+
+func (ra *RobotAlerter) Channel() model.NotificationChannel {
+	return ra.channel
+}
+
+func (ra *RobotAlerter) Available(receiver *model.UserAttribute) bool {
+	return ra.personalWebhook(receiver) != "" || ra.config.WebhookURL != ""
+}
+
+func (ra *RobotAlerter) SendMessageTo(ctx context.Context, receiver *model.UserAttribute, subject, body string) error {
+	webhookURL := ra.personalWebhook(receiver)
+	shared := webhookURL == ""
+	if shared {
+		webhookURL = ra.config.WebhookURL
+	}
+	if webhookURL == "" {
+		klog.Warningf("%s does not have a %s robot webhook", receiver.Name, ra.channel)
+		return nil
+	}
+
+	// 共享群机器人需要标明接收者
+	var mobile string
+	content := fmt.Sprintf("[Crater] %s\n%s", subject, htmlToText(body))
+	if shared {
+		if receiver.Phone != nil {
+			mobile = *receiver.Phone
+		}
+		content = fmt.Sprintf("@%s\n%s", receiver.Nickname, content)
+	}
+
+	payload, err := ra.buildPayload(subject, content, mobile, shared)
+	if err != nil {
+		return err
+	}
+	if shared && ra.config.Secret != "" && ra.channel == model.NotificationChannelDingTalk {
+		webhookURL = signDingTalkURL(webhookURL, ra.config.Secret)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s robot payload: %w", ra.channel, err)
+	}
+	client := ra.personalClient
+	if shared {
+		client = ra.client
+	}
+	respBody, err := postNotification(ctx, client, webhookURL, data, nil)
+	if err == nil {
+		err = checkRobotResponse(respBody)
+	}
+	if err != nil {
+		klog.Errorf("Failed to send %s robot notification to %s: %v", ra.channel, receiver.Name, err)
+		return err
+	}
+
+	klog.Infof("Sent %s robot notification to %s", ra.channel, receiver.Name)
+	return nil
+}
+
+// personalWebhook 返回接收者在用户属性中配置的个人机器人 Webhook
+func (ra *RobotAlerter) personalWebhook(receiver *model.UserAttribute) string {
+	var webhook *string
+	switch ra.channel {
+	case model.NotificationChannelFeishu:
+		webhook = receiver.FeishuWebhook
+	case model.NotificationChannelDingTalk:
+		webhook = receiver.DingTalkWebhook
+	case model.NotificationChannelWeCom:
+		webhook = receiver.WeComWebhook
+	case model.NotificationChannelWPS:
+		webhook = receiver.WPSWebhook
+	}
+	if webhook == nil {
+		return ""
+	}
+	return *webhook
+}
+
+// buildPayload 按照各机器人的消息格式构造请求体
+func (ra *RobotAlerter) buildPayload(subject, content, mobile string, shared bool) (map[string]any, error) {
+	switch ra.channel {
+	case model.NotificationChannelFeishu:
+		payload := map[string]any{
+			"msg_type": "text",
+			"content":  map[string]any{"text": content},
+		}
+		if shared && ra.config.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			payload["timestamp"] = timestamp
+			payload["sign"] = signFeishu(timestamp, ra.config.Secret)
+		}
+		return payload, nil
+	case model.NotificationChannelDingTalk:
+		at := map[string]any{}
+		if mobile != "" {
+			// 钉钉要求被 @ 的手机号同时出现在消息内容中
+			content = fmt.Sprintf("%s\n@%s", content, mobile)
+			at["atMobiles"] = []string{mobile}
+		}
+		return map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"title": subject, "text": content},
+			"at":       at,
+		}, nil
+	case model.NotificationChannelWeCom:
+		text := map[string]any{"content": content}
+		if mobile != "" {
+			text["mentioned_mobile_list"] = []string{mobile}
+		}
+		return map[string]any{
+			"msgtype": "text",
+			"text":    text,
+		}, nil
+	case model.NotificationChannelWPS:
+		return map[string]any{
+			"msgtype": "text",
+			"text":    map[string]any{"content": content},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported robot channel: %s", ra.channel)
+	}
+}
+
+func checkRobotResponse(respBody []byte) error {
+	if len(respBody) == 0 {
+		return nil
+	}
+	var resp robotResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		// 部分机器人返回非 JSON 内容，HTTP 状态码正常即视为成功
+		return nil
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("robot returned code %d: %s", resp.Code, resp.Msg)
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("robot returned errcode %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
+
+// signFeishu 计算飞书机器人签名，签名字符串作为 HMAC 的密钥
+func signFeishu(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signDingTalkURL 计算钉钉机器人签名，并附加到 Webhook 的查询参数中
+func signDingTalkURL(webhookURL, secret string) string {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	sign := url.QueryEscape(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return fmt.Sprintf("%s&timestamp=%s&sign=%s", webhookURL, timestamp, sign)
+}
@@ -45,6 +45,14 @@ func newSMTPAlerter() (alertHandlerInterface, error) {
 	}, nil
 }
 
+func (sa *SMTPAlerter) Channel() model.NotificationChannel {
+	return model.NotificationChannelEmail
+}
+
+func (sa *SMTPAlerter) Available(receiver *model.UserAttribute) bool {
+	return receiver.Email != nil && *receiver.Email != ""
+}
+
 func (sa *SMTPAlerter) SendMessageTo(_ context.Context, receiver *model.UserAttribute, subject, body string) error {
 	if receiver.Email == nil {
 		klog.Warningf("%s does not have an email address", receiver.Name)
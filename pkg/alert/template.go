@@ -0,0 +1,176 @@
+package alert
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+// 内置的通知模板，管理员可以在 Notification.TemplateDir 中放置同名文件进行覆盖
+//
+//go:embed templates
+var builtinTemplates embed.FS
+
+const (
+	// layoutTemplate 公共布局，定义 body 以及各消息共用的片段
+	layoutTemplate = "layout"
+
+	templateVerificationCode    = "verification_code"
+	templateJobRunning          = "job_running"
+	templateJobFailed           = "job_failed"
+	templateJobCompleted        = "job_completed"
+	templateJobLowGPUReminded   = "job_low_gpu_reminded"
+	templateJobLowGPUDeleted    = "job_low_gpu_deleted"
+	templateJobLongTimeReminded = "job_long_time_reminded"
+	templateJobLongTimeDeleted  = "job_long_time_deleted"
+)
+
+// defaultNotificationLanguage 未配置默认语言时使用中文模板
+const defaultNotificationLanguage = model.NotificationLanguageZh
+
+// jobTemplates 作业通知类型对应的模板
+var jobTemplates = map[model.AlertType]string{
+	model.JobRunningAlert:          templateJobRunning,
+	model.JobFailedAlert:           templateJobFailed,
+	model.JobCompletedAlert:        templateJobCompleted,
+	model.LowGPUJobRemindedAlert:   templateJobLowGPUReminded,
+	model.LowGPUJobDeletedAlert:    templateJobLowGPUDeleted,
+	model.LongTimeJobRemindedAlert: templateJobLongTimeReminded,
+	model.LongTimeJobDeletedAlert:  templateJobLongTimeDeleted,
+}
+
+// TemplateContext 是渲染通知模板时传入的数据
+type TemplateContext struct {
+	JobInformation
+
+	// ReceiverName 收件人的称呼
+	ReceiverName string
+	// Member 抄送给账户管理员时为作业所属成员的称呼，否则为空
+	Member string
+	// Resources 作业申请的资源，如 cpu=4, nvidia.com/gpu=1
+	Resources map[string]string
+	// Nodes 作业运行的节点
+	Nodes []string
+	// GPUUtilAvg 作业的平均 GPU 利用率（0~1），未采集时为空
+	GPUUtilAvg *float32
+	// DeleteTime 清理任务计划删除作业的时间
+	DeleteTime time.Time
+	// Code 邮箱验证码
+	Code string
+	// Digest 作业摘要
+	Digest *DigestData
+	// Incident 集群异常
+	Incident *model.ClusterIncident
+}
+
+// templateRenderer 按语言渲染通知模板，优先使用覆盖目录中的模板文件
+type templateRenderer struct {
+	dir             string
+	defaultLanguage model.NotificationLanguage
+}
+
+func newTemplateRenderer(dir, defaultLanguage string) *templateRenderer {
+	lang := model.NotificationLanguage(defaultLanguage)
+	if !lo.Contains(model.GetAllNotificationLanguages(), lang) {
+		lang = defaultNotificationLanguage
+	}
+	return &templateRenderer{
+		dir:             dir,
+		defaultLanguage: lang,
+	}
+}
+
+// language 返回接收者的通知语言，未设置或不支持时使用默认语言
+func (r *templateRenderer) language(receiver *model.UserAttribute) model.NotificationLanguage {
+	if receiver != nil && receiver.Language != nil {
+		lang := model.NotificationLanguage(*receiver.Language)
+		if lo.Contains(model.GetAllNotificationLanguages(), lang) {
+			return lang
+		}
+	}
+	return r.defaultLanguage
+}
+
+// render 使用接收者的语言渲染模板，返回邮件标题和 HTML 正文
+func (r *templateRenderer) render(
+	receiver *model.UserAttribute,
+	name string,
+	data *TemplateContext,
+) (subject, body string, err error) {
+	lang := r.language(receiver)
+	tmpl, err := r.parse(lang, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", fmt.Errorf("render subject of template %s/%s: %w", lang, name, err)
+	}
+	// 标题是纯文本，还原 html/template 的转义
+	subject = html.UnescapeString(strings.TrimSpace(buf.String()))
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", fmt.Errorf("render body of template %s/%s: %w", lang, name, err)
+	}
+	return subject, buf.String(), nil
+}
+
+// parse 依次解析公共布局和消息模板，消息模板中的定义会覆盖布局中的同名定义。
+// 每次渲染都重新读取，管理员修改覆盖目录中的模板后无需重启
+func (r *templateRenderer) parse(lang model.NotificationLanguage, name string) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(template.FuncMap{
+		"percent": func(v *float32) string {
+			if v == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%.1f%%", *v*100)
+		},
+		"datetime": func(t time.Time) string {
+			return t.Format(time.DateTime)
+		},
+		"join": strings.Join,
+	})
+	for _, file := range []string{layoutTemplate, name} {
+		content, err := r.readTemplate(lang, file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("parse template %s/%s: %w", lang, file, err)
+		}
+	}
+	return tmpl, nil
+}
+
+func (r *templateRenderer) readTemplate(lang model.NotificationLanguage, name string) ([]byte, error) {
+	file := name + ".html"
+	if r.dir != "" {
+		content, err := os.ReadFile(filepath.Join(r.dir, string(lang), file))
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read template %s/%s: %w", lang, file, err)
+		}
+	}
+	content, err := builtinTemplates.ReadFile(path.Join("templates", string(lang), file))
+	if err != nil {
+		return nil, fmt.Errorf("template %s/%s not found: %w", lang, file, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,112 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func newTestTemplateContext() *TemplateContext {
+	return &TemplateContext{
+		JobInformation: JobInformation{
+			Name:    "demo",
+			JobName: "sg-alice-1",
+			JobURL:  "https://crater/portal/jobs/detail/sg-alice-1",
+		},
+		ReceiverName: "Alice",
+		Resources:    map[string]string{"nvidia.com/gpu": "1"},
+		Nodes:        []string{"node-1"},
+		GPUUtilAvg:   ptr.To[float32](0.05),
+		DeleteTime:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local),
+	}
+}
+
+func TestTemplateRendererLanguage(t *testing.T) {
+	for _, lang := range model.GetAllNotificationLanguages() {
+		for alertType, name := range jobTemplates {
+			receiver := &model.UserAttribute{Language: ptr.To(string(lang))}
+			subject, body, err := newTemplateRenderer("", "").render(receiver, name, newTestTemplateContext())
+			if err != nil {
+				t.Fatalf("render %s/%s failed: %v", lang, name, err)
+			}
+			if subject == "" || !strings.Contains(body, "sg-alice-1") || !strings.Contains(body, "node-1") {
+				t.Errorf("unexpected %s notification of %s: %q %q", lang, alertType, subject, body)
+			}
+		}
+	}
+
+	renderer := newTemplateRenderer("", "en")
+	subject, body, err := renderer.render(&model.UserAttribute{}, templateJobLowGPUReminded, newTestTemplateContext())
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if subject != "Warning: Job Will Be Deleted - Low GPU Utilization" {
+		t.Errorf("expected default language en, got subject %q", subject)
+	}
+	if !strings.Contains(body, "2025-01-02 03:04:05") || !strings.Contains(body, "5.0%") {
+		t.Errorf("delete time or GPU utilization missing in body: %q", body)
+	}
+
+	data := newTestTemplateContext()
+	data.Member = "Bob"
+	subject, _, err = newTemplateRenderer("", "").render(nil, templateJobLowGPUDeleted, data)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(subject, "Bob") {
+		t.Errorf("expected member in subject, got %q", subject)
+	}
+}
+
+func TestTemplateRendererOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "zh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	override := `{{define "subject"}}自定义 & 失败{{end}}{{define "title"}}失败{{end}}{{define "content"}}{{.Name}} 失败了{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "zh", templateJobFailed+".html"), []byte(override), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	renderer := newTemplateRenderer(dir, "zh")
+	subject, body, err := renderer.render(nil, templateJobFailed, newTestTemplateContext())
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if subject != "自定义 & 失败" || !strings.Contains(body, "demo 失败了") {
+		t.Errorf("override template not used: %q %q", subject, body)
+	}
+
+	// 未覆盖的模板仍使用内置版本
+	if _, _, err := renderer.render(nil, templateJobCompleted, newTestTemplateContext()); err != nil {
+		t.Errorf("render built-in template failed: %v", err)
+	}
+}
+
+func TestClusterIncidentTemplate(t *testing.T) {
+	resolvedAt := time.Date(2025, 1, 2, 4, 0, 0, 0, time.Local)
+	incident := &model.ClusterIncident{
+		Kind:       model.IncidentKindNodeNotReady,
+		Target:     "node-1",
+		Status:     model.IncidentStatusResolved,
+		Message:    "Ready=Unknown",
+		OpenedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local),
+		ResolvedAt: &resolvedAt,
+	}
+	subject, body, err := newTemplateRenderer("", "en").render(nil, templateClusterIncident, &TemplateContext{Incident: incident})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if subject != "[Resolved] Node NotReady: node-1" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if !strings.Contains(body, "2025-01-02 04:00:00") || strings.Contains(body, "Dear") {
+		t.Errorf("unexpected body %q", body)
+	}
+}
@@ -0,0 +1,178 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	webhookSignatureKey   = "X-Crater-Signature"
+)
+
+// WebhookAlerter 将通知以 JSON 的形式发送到通用的 HTTP 接口
+type WebhookAlerter struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// webhookReceiver 是 Webhook 通知中的接收者信息
+type webhookReceiver struct {
+	Name     string  `json:"name"`
+	Nickname string  `json:"nickname"`
+	Email    *string `json:"email,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+}
+
+// webhookPayload 是通用 Webhook 的请求体
+type webhookPayload struct {
+	Subject   string          `json:"subject"`
+	Content   string          `json:"content"` // 纯文本内容
+	HTML      string          `json:"html"`    // HTML 内容，与邮件一致
+	Receiver  webhookReceiver `json:"receiver"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+func newWebhookAlerter() alertHandlerInterface {
+	webhookConfig := config.GetConfig().Notification.Webhook
+	return &WebhookAlerter{
+		url:    webhookConfig.URL,
+		secret: webhookConfig.Secret,
+		client: newNotificationHTTPClient(),
+	}
+}
+
+func (wa *WebhookAlerter) Channel() model.NotificationChannel {
+	return model.NotificationChannelWebhook
+}
+
+// Available 通用 Webhook 由接收方自行分发，对所有接收者都可用
+func (wa *WebhookAlerter) Available(_ *model.UserAttribute) bool {
+	return true
+}
+
+func (wa *WebhookAlerter) SendMessageTo(ctx context.Context, receiver *model.UserAttribute, subject, body string) error {
+	payload := webhookPayload{
+		Subject: subject,
+		Content: htmlToText(body),
+		HTML:    body,
+		Receiver: webhookReceiver{
+			Name:     receiver.Name,
+			Nickname: receiver.Nickname,
+			Email:    receiver.Email,
+			Phone:    receiver.Phone,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	header := map[string]string{}
+	if wa.secret != "" {
+		header[webhookSignatureKey] = signWebhookPayload(wa.secret, data)
+	}
+
+	if _, err := postNotification(ctx, wa.client, wa.url, data, header); err != nil {
+		klog.Errorf("Failed to send webhook notification to %s: %v", receiver.Name, err)
+		return err
+	}
+
+	klog.Infof("Sent webhook notification to %s", receiver.Name)
+	return nil
+}
+
+// signWebhookPayload 使用 HMAC-SHA256 对请求体签名，接收方用相同的密钥校验
+func signWebhookPayload(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newNotificationHTTPClient() *http.Client {
+	timeout := defaultWebhookTimeout
+	if seconds := config.GetConfig().Notification.Timeout; seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// postNotification 发送 JSON 请求，非 2xx 状态码视为失败，返回响应体
+func postNotification(ctx context.Context, client *http.Client, url string, data []byte, header map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, &webhookStatusError{StatusCode: resp.StatusCode}
+	}
+	return respBody, nil
+}
+
+// webhookStatusError 接收方返回了非 2xx 状态码。
+// 部分 Webhook 由普通用户配置，错误信息可能返回给用户，不包含响应体
+type webhookStatusError struct {
+	StatusCode int
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("status code %d", e.StatusCode)
+}
+
+var (
+	htmlLinkPattern      = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlLineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li)>`)
+	htmlTagPattern       = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlToText 将邮件的 HTML 内容转换为 IM 消息使用的纯文本
+func htmlToText(body string) string {
+	text := htmlLinkPattern.ReplaceAllString(body, "$2: $1")
+	text = htmlLineBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,71 @@
+package alert
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestWebhookAlerter(t *testing.T) {
+	secret := "test-secret"
+	var (
+		payload   webhookPayload
+		signature string
+		body      []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhookSignatureKey)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	alerter := &WebhookAlerter{url: server.URL, secret: secret, client: server.Client()}
+	receiver := &model.UserAttribute{Name: "alice", Nickname: "Alice", Email: ptr.To("alice@example.com")}
+	html := `<p>您的作业 <strong>demo</strong> 已成功运行完成。</p><a href="https://crater/jobs/demo">查看作业结果</a>`
+
+	if err := alerter.SendMessageTo(context.Background(), receiver, "作业已成功完成", html); err != nil {
+		t.Fatalf("send webhook failed: %v", err)
+	}
+
+	if payload.Subject != "作业已成功完成" || payload.Receiver.Name != "alice" || payload.HTML != html {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if expected := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != expected {
+		t.Errorf("expected signature %s, got %s", expected, signature)
+	}
+}
+
+func TestWebhookAlerterFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	alerter := &WebhookAlerter{url: server.URL, client: server.Client()}
+	err := alerter.SendMessageTo(context.Background(), &model.UserAttribute{Name: "alice"}, "subject", "<p>body</p>")
+	if err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	text := htmlToText(`<p>尊敬的 <strong>Alice</strong>：</p><p>作业 &lt;demo&gt; 已完成<br>请查看</p><a href="https://crater/jobs/demo">查看作业</a>`)
+	expected := "尊敬的 Alice：\n作业 <demo> 已完成\n请查看\n查看作业: https://crater/jobs/demo"
+	if text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
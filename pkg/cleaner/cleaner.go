@@ -14,6 +14,7 @@ import (
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/pkg/monitor"
+	"github.com/raids-lab/crater/pkg/utils"
 )
 
 const (
@@ -101,6 +102,7 @@ func WrapCleanerFunc(jobName string, cleanerFunc CleanerFunc) func() {
 			ExecuteTime: time.Now(),
 			Message:     "",
 			Status:      status,
+			Replica:     utils.GetReplicaName(),
 		}
 
 		// 将结果序列化为JSON
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -13,6 +15,8 @@ import (
 
 type Config struct {
 	// EnableLeaderElection enables leader election for controller manager to ensure high availability.
+	// Background loops (cron jobs, task controller) only run on the elected leader,
+	// so it must be enabled when running multiple backend replicas.
 	// Optional: Defaults to false if not specified.
 	EnableLeaderElection bool `json:"enableLeaderElection"`
 
@@ -219,6 +223,89 @@ type Config struct {
 		Notify string `json:"notify"`
 	} `json:"smtp"`
 
+	// Notification contains configuration for notification channels besides SMTP email.
+	// Alerts fan out to every enabled channel on which the receiver can be reached.
+	// Optional: If no channel is enabled, only SMTP email notifications will be sent.
+	Notification struct {
+		// Timeout is the HTTP request timeout in seconds for webhook and robot channels.
+		// Optional: Defaults to 10 seconds if not specified.
+		Timeout int `json:"timeout"`
+
+		// TemplateDir is a directory containing html/template files that override the built-in notification templates.
+		// Files are looked up as <TemplateDir>/<language>/<name>.html, e.g. /etc/crater/templates/en/job_failed.html.
+		// Optional: The built-in templates are used if not specified or if a file does not exist.
+		TemplateDir string `json:"templateDir"`
+
+		// DefaultLanguage is the notification language for users who have not chosen one ("zh" or "en").
+		// Optional: Defaults to "zh" if not specified.
+		DefaultLanguage string `json:"defaultLanguage"`
+
+		// Webhook contains configuration for the generic JSON webhook channel.
+		// Every notification is posted as a JSON document to URL.
+		// Optional: If Enable is false, the generic webhook channel will be disabled.
+		Webhook struct {
+			// Enable toggles the generic webhook channel.
+			// Optional: Defaults to false if not specified.
+			Enable bool `json:"enable"`
+
+			// URL is the HTTP endpoint that receives notifications.
+			// Required if Enable is true: Must be a valid HTTP(S) URL.
+			URL string `json:"url"`
+
+			// Secret is used to sign the request body with HMAC-SHA256 in the X-Crater-Signature header.
+			// Optional: Requests are not signed if not specified.
+			Secret string `json:"secret"`
+		} `json:"webhook"`
+
+		// Feishu contains configuration for the Feishu (Lark) custom robot channel.
+		// Optional: If Enable is false, the Feishu channel will be disabled.
+		Feishu NotificationRobot `json:"feishu"`
+
+		// DingTalk contains configuration for the DingTalk custom robot channel.
+		// Optional: If Enable is false, the DingTalk channel will be disabled.
+		DingTalk NotificationRobot `json:"dingtalk"`
+
+		// WeCom contains configuration for the WeCom (WeChat Work) group robot channel.
+		// Optional: If Enable is false, the WeCom channel will be disabled.
+		WeCom NotificationRobot `json:"wecom"`
+
+		// WPS contains configuration for the WPS Office group robot channel.
+		// Optional: If Enable is false, the WPS channel will be disabled.
+		WPS NotificationRobot `json:"wps"`
+
+		// JobWebhookDeniedCIDRs lists extra networks that user job webhooks must not reach,
+		// typically the cluster pod and service CIDRs when they are not in a private range.
+		// Loopback, private, link-local, shared and unspecified addresses are always denied.
+		// Optional: No extra networks are denied if not specified.
+		JobWebhookDeniedCIDRs []string `json:"jobWebhookDeniedCIDRs"`
+	} `json:"notification"`
+
+	// ClusterAlert contains configuration for alerting platform administrators about cluster-level conditions,
+	// such as NotReady nodes, missing GPUs, dropped resources, failing image builds and unreachable Prometheus.
+	// Optional: If Enable is false, cluster conditions will not be checked.
+	ClusterAlert struct {
+		// Enable toggles cluster condition checks and admin alerts.
+		// Optional: Defaults to false if not specified.
+		Enable bool `json:"enable"`
+
+		// CheckInterval is the interval in seconds between periodic checks of resources, builds and Prometheus.
+		// Optional: Defaults to 60 seconds if not specified.
+		CheckInterval int `json:"checkInterval"`
+
+		// Channels lists the notification channels used for admin alerts.
+		// Email alerts are sent to SMTP.Notify, other channels use their shared webhook or robot.
+		// Optional: Defaults to ["email"] if not specified.
+		Channels []string `json:"channels"`
+
+		// BuildFailureThreshold is the number of failed image builds within BuildFailureWindow that opens an incident.
+		// Optional: Defaults to 3 if not specified.
+		BuildFailureThreshold int `json:"buildFailureThreshold"`
+
+		// BuildFailureWindow is the time window in minutes for counting failed image builds.
+		// Optional: Defaults to 60 minutes if not specified.
+		BuildFailureWindow int `json:"buildFailureWindow"`
+	} `json:"clusterAlert"`
+
 	// RaidsLab contains configuration for Raids Lab integration features.
 	// Optional: If Enable is false, Raids Lab features will be disabled.
 	RaidsLab struct {
@@ -255,6 +342,158 @@ type Config struct {
 		UIDServerURL string `json:"uidServerURL"`
 	} `json:"raidsLab"`
 
+	// OIDC contains configuration for login through a generic OpenID Connect provider
+	// (e.g. Keycloak or campus SSO) with the authorization code flow.
+	// Optional: If Enable is false, OIDC login will be disabled.
+	OIDC struct {
+		// Enable toggles OIDC login.
+		// Optional: Defaults to false if not specified.
+		Enable bool `json:"enable"`
+
+		// Issuer is the issuer URL of the provider, the discovery document is read from
+		// <issuer>/.well-known/openid-configuration.
+		// Required if Enable is true.
+		Issuer string `json:"issuer"`
+
+		// ClientID and ClientSecret are the credentials of the client registered at the provider.
+		// Required if Enable is true: ClientSecret may be empty for public clients.
+		ClientID     string `json:"clientID"`
+		ClientSecret string `json:"clientSecret"`
+
+		// RedirectURL is the frontend page the provider redirects back to, which posts
+		// the code and state to /auth/login with auth method "oidc", or to /v1/auth/oidc/link
+		// when a logged-in user links the identity to an existing account.
+		// Required if Enable is true: Must be registered at the provider.
+		RedirectURL string `json:"redirectURL"`
+
+		// Scopes requested from the provider.
+		// Optional: Defaults to ["openid", "profile", "email"] if not specified.
+		Scopes []string `json:"scopes"`
+
+		// Claims maps user attributes to claim names of the ID token or userinfo.
+		// Users are matched by the issuer and subject claims, the username is only used to name new users.
+		// Optional: Defaults to preferred_username, name, email and groups.
+		Claims struct {
+			Username string `json:"username"`
+			Nickname string `json:"nickname"`
+			Email    string `json:"email"`
+			Groups   string `json:"groups"`
+		} `json:"claims"`
+
+		// GroupAccounts maps provider groups to account names. Users are added to the mapped
+		// accounts as members when they log in.
+		// Optional: Users only join the default account if not specified.
+		GroupAccounts map[string]string `json:"groupAccounts"`
+	} `json:"oidc"`
+
+	// PasswordPolicy contains strength rules for passwords of normal auth users,
+	// checked on signup, password change and password reset.
+	// Optional: Defaults are used for fields not specified.
+	PasswordPolicy struct {
+		// MinLength is the minimum number of characters of a password.
+		// Optional: Defaults to 8 if not specified.
+		MinLength int `json:"minLength"`
+
+		// MinCharClasses is the minimum number of character classes (lowercase, uppercase,
+		// digits and symbols) a password must contain.
+		// Optional: Defaults to 2 if not specified.
+		MinCharClasses int `json:"minCharClasses"`
+
+		// History is the number of recent passwords that can not be reused, including the current one.
+		// Optional: Defaults to 3 if not specified, set to -1 to disable the check.
+		History int `json:"history"`
+	} `json:"passwordPolicy"`
+
+	// MFA contains settings of TOTP two-factor authentication.
+	// Optional: Users can still opt in to 2FA if not specified.
+	MFA struct {
+		// Issuer is shown in authenticator apps next to the username.
+		// Optional: Defaults to "Crater" if not specified.
+		Issuer string `json:"issuer"`
+
+		// EnforceForAdmins requires platform admins to enable 2FA, they are asked to
+		// bind an authenticator app on their next login.
+		// Optional: Defaults to false if not specified.
+		EnforceForAdmins bool `json:"enforceForAdmins"`
+	} `json:"mfa"`
+
+	// RateLimit contains per-user token bucket limits of the authenticated API.
+	// Optional: Requests are not limited if not specified.
+	RateLimit struct {
+		// Enable toggles rate limiting of /api/v1 requests.
+		// Optional: Defaults to false if not specified.
+		Enable bool `json:"enable"`
+
+		// Default is applied to route groups without a specific rule.
+		// Optional: Defaults to 10 requests per second with a burst of 20 if not specified.
+		Default RateLimitRule `json:"default"`
+
+		// Groups overrides the default rule per route group. Keys are the first path segment
+		// after /api/v1 or /api/v1/admin (e.g. "vcjobs"), optionally suffixed with the HTTP
+		// method (e.g. "vcjobs:POST") to limit single endpoints more strictly.
+		// Optional: The default rule is used for all groups if not specified.
+		Groups map[string]RateLimitRule `json:"groups"`
+	} `json:"rateLimit"`
+
+	// Terminal contains settings of the web terminal.
+	// Optional: Sessions of normal users are not recorded if not specified.
+	Terminal struct {
+		// ReattachTimeout is how long in seconds a shell is kept alive after its browser disconnects,
+		// the client can reattach to the session within this time.
+		// Optional: Defaults to 300 seconds if not specified.
+		ReattachTimeout int `json:"reattachTimeout"`
+
+		// MaxSessionsPerUser and MaxSessionsPerPod limit live shells on one replica, including detached ones.
+		// When a limit is reached, the oldest detached shell of the user is closed to make room.
+		// Optional: Default to 10 per user and 20 per pod if not specified.
+		MaxSessionsPerUser int `json:"maxSessionsPerUser"`
+		MaxSessionsPerPod  int `json:"maxSessionsPerPod"`
+
+		// Recording contains settings of terminal session recording in asciinema v2 format.
+		// Sessions opened in other users' jobs (by platform admins, account admins or
+		// users with jobs:edit-any) are always recorded.
+		Recording struct {
+			// EnableForUsers records sessions opened by job owners as well.
+			// Optional: Defaults to false if not specified.
+			EnableForUsers bool `json:"enableForUsers"`
+
+			// Dir is the directory on the shared storage where recordings are saved,
+			// the backend must mount the shared PVC at this path.
+			// Optional: Recordings are saved in the database if not specified.
+			Dir string `json:"dir"`
+
+			// MaxSize is the maximum size of a single recording in bytes, later frames are dropped.
+			// Sessions in other users' jobs are closed when their recording reaches the limit.
+			// Optional: Defaults to 16 MiB if not specified.
+			MaxSize int64 `json:"maxSize"`
+		} `json:"recording"`
+	} `json:"terminal"`
+
+	// FileTransfer contains limits of uploading and downloading files through the container API.
+	// Optional: Both limits default to 1 GiB if not specified.
+	FileTransfer struct {
+		// MaxUploadSize is the maximum size in bytes of an uploaded file or extracted tarball.
+		// Optional: Defaults to 1 GiB if not specified.
+		MaxUploadSize int64 `json:"maxUploadSize"`
+
+		// MaxDownloadSize is the maximum size in bytes of a downloaded file or directory before compression.
+		// Optional: Defaults to 1 GiB if not specified.
+		MaxDownloadSize int64 `json:"maxDownloadSize"`
+	} `json:"fileTransfer"`
+
+	// SSH contains settings of SSH access to job containers.
+	// Optional: Users log in as root without a jump host if not specified.
+	SSH struct {
+		// LoginUser is the user in the container that SSH commands log in as.
+		// Optional: Defaults to "root" if not specified.
+		LoginUser string `json:"loginUser"`
+
+		// ProxyJump is the jump host used to reach node ports from outside the cluster,
+		// in the "[user@]host[:port]" form accepted by ssh -J.
+		// Optional: SSH commands connect to the node directly if not specified.
+		ProxyJump string `json:"proxyJump"`
+	} `json:"ssh"`
+
 	// SchedulerPlugins contains configuration for Kubernetes scheduler plugin integrations.
 	// Optional: Individual plugins can be enabled/disabled independently.
 	SchedulerPlugins struct {
@@ -288,6 +527,41 @@ type Config struct {
 	} `json:"schedulerPlugins"`
 }
 
+// NotificationRobot is the configuration of an IM robot notification channel.
+// Messages are sent to the receiver's personal robot webhook if configured in user attributes,
+// otherwise to the shared WebhookURL, mentioning the receiver by phone number when supported.
+type NotificationRobot struct {
+	// Enable toggles the robot channel.
+	// Optional: Defaults to false if not specified.
+	Enable bool `json:"enable"`
+
+	// WebhookURL is the webhook of a shared group robot.
+	// Optional: Only receivers with a personal robot webhook are notified if not specified.
+	WebhookURL string `json:"webhookURL"`
+
+	// Secret is the signing secret of the shared group robot (Feishu and DingTalk only).
+	// Optional: Requests are not signed if not specified.
+	Secret string `json:"secret"`
+}
+
+// RateLimitRule is a token bucket limit of one route group. Each user has a separate bucket per rule.
+type RateLimitRule struct {
+	// RPS is the number of requests per second a normal user can make on average.
+	RPS float64 `json:"rps"`
+
+	// Burst is the maximum number of requests a normal user can make at once.
+	// Optional: Defaults to twice the RPS if not specified.
+	Burst int `json:"burst"`
+
+	// AdminRPS is the number of requests per second of platform admins.
+	// Optional: Defaults to five times the RPS if not specified.
+	AdminRPS float64 `json:"adminRPS"`
+
+	// AdminBurst is the maximum number of requests a platform admin can make at once.
+	// Optional: Defaults to twice the AdminRPS if not specified.
+	AdminBurst int `json:"adminBurst"`
+}
+
 type RaidsLabOpenAPI struct {
 	URL          string `json:"url"`
 	ChameleonKey string `json:"chameleonKey"`
@@ -403,6 +677,22 @@ func (c *Config) ValidateConfig() error {
 		}
 	}
 
+	if c.Notification.Webhook.Enable && c.Notification.Webhook.URL == "" {
+		errors = append(errors, "notification.webhook.url is required when notification webhook is enabled")
+	}
+	if c.ClusterAlert.Enable && !c.SMTP.Enable &&
+		(len(c.ClusterAlert.Channels) == 0 || slices.Contains(c.ClusterAlert.Channels, "email")) {
+		errors = append(errors, "smtp must be enabled to send cluster alerts by email")
+	}
+	if lang := c.Notification.DefaultLanguage; lang != "" && lang != "zh" && lang != "en" {
+		errors = append(errors, "notification.defaultLanguage must be zh or en")
+	}
+	for _, cidr := range c.Notification.JobWebhookDeniedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errors = append(errors, fmt.Sprintf("notification.jobWebhookDeniedCIDRs contains invalid CIDR %q", cidr))
+		}
+	}
+
 	if c.RaidsLab.Enable {
 		if c.RaidsLab.LDAP.UserName == "" {
 			errors = append(errors, "raidsLab.ldap.userName is required when raidsLab is enabled")
@@ -430,6 +720,50 @@ func (c *Config) ValidateConfig() error {
 		}
 	}
 
+	if c.OIDC.Enable {
+		if c.OIDC.Issuer == "" {
+			errors = append(errors, "oidc.issuer is required when oidc is enabled")
+		}
+		if c.OIDC.ClientID == "" {
+			errors = append(errors, "oidc.clientID is required when oidc is enabled")
+		}
+		if c.OIDC.RedirectURL == "" {
+			errors = append(errors, "oidc.redirectURL is required when oidc is enabled")
+		}
+	}
+
+	if c.PasswordPolicy.MinLength < 0 || c.PasswordPolicy.MinLength > 72 {
+		errors = append(errors, "passwordPolicy.minLength must be between 0 and 72")
+	}
+	if c.PasswordPolicy.MinCharClasses < 0 || c.PasswordPolicy.MinCharClasses > 4 {
+		errors = append(errors, "passwordPolicy.minCharClasses must be between 0 and 4")
+	}
+
+	if c.RateLimit.Enable {
+		rules := map[string]RateLimitRule{"default": c.RateLimit.Default}
+		for name, rule := range c.RateLimit.Groups {
+			rules["groups."+name] = rule
+		}
+		for name, rule := range rules {
+			if rule.RPS < 0 || rule.Burst < 0 || rule.AdminRPS < 0 || rule.AdminBurst < 0 {
+				errors = append(errors, fmt.Sprintf("rateLimit.%s must not be negative", name))
+			}
+		}
+	}
+
+	if c.Terminal.ReattachTimeout < 0 {
+		errors = append(errors, "terminal.reattachTimeout must not be negative")
+	}
+	if c.Terminal.MaxSessionsPerUser < 0 || c.Terminal.MaxSessionsPerPod < 0 {
+		errors = append(errors, "terminal session limits must not be negative")
+	}
+	if c.Terminal.Recording.MaxSize < 0 {
+		errors = append(errors, "terminal.recording.maxSize must not be negative")
+	}
+	if c.FileTransfer.MaxUploadSize < 0 || c.FileTransfer.MaxDownloadSize < 0 {
+		errors = append(errors, "fileTransfer limits must not be negative")
+	}
+
 	if c.SchedulerPlugins.SEACS.Enable {
 		if c.SchedulerPlugins.SEACS.PredictionServiceAddress == "" {
 			errors = append(errors, "schedulerPlugins.spjob.predictionServiceAddress is required when SEACS is enabled")
@@ -504,6 +838,39 @@ func (c *Config) PrintConfig() {
 		klog.Info("SMTP: Disabled")
 	}
 
+	// Notification
+	var enabledChannels []string
+	if c.Notification.Webhook.Enable {
+		enabledChannels = append(enabledChannels, "Webhook")
+	}
+	if c.Notification.Feishu.Enable {
+		enabledChannels = append(enabledChannels, "Feishu")
+	}
+	if c.Notification.DingTalk.Enable {
+		enabledChannels = append(enabledChannels, "DingTalk")
+	}
+	if c.Notification.WeCom.Enable {
+		enabledChannels = append(enabledChannels, "WeCom")
+	}
+	if c.Notification.WPS.Enable {
+		enabledChannels = append(enabledChannels, "WPS")
+	}
+	if len(enabledChannels) > 0 {
+		klog.Infof("Notification Channels: %v", enabledChannels)
+	} else {
+		klog.Info("Notification Channels: None")
+	}
+	if c.Notification.TemplateDir != "" {
+		klog.Infof("Notification Templates: %s", c.Notification.TemplateDir)
+	}
+
+	// ClusterAlert
+	if c.ClusterAlert.Enable {
+		klog.Infof("Cluster Alert: Enabled (Channels: %v, Interval: %ds)", c.ClusterAlert.Channels, c.ClusterAlert.CheckInterval)
+	} else {
+		klog.Info("Cluster Alert: Disabled")
+	}
+
 	// RaidsLab
 	if c.RaidsLab.Enable {
 		klog.Infof("RaidsLab: Enabled (LDAP: %s, UID Server: %s)",
@@ -513,6 +880,29 @@ func (c *Config) PrintConfig() {
 		klog.Info("RaidsLab: Disabled")
 	}
 
+	// OIDC
+	if c.OIDC.Enable {
+		klog.Infof("OIDC: Enabled (Issuer: %s, Client: %s, Group Accounts: %d)",
+			c.OIDC.Issuer, c.OIDC.ClientID, len(c.OIDC.GroupAccounts))
+	} else {
+		klog.Info("OIDC: Disabled")
+	}
+
+	// RateLimit
+	if c.RateLimit.Enable {
+		klog.Infof("Rate Limit: Enabled (Default: %.1f rps, Groups: %d)", c.RateLimit.Default.RPS, len(c.RateLimit.Groups))
+	} else {
+		klog.Info("Rate Limit: Disabled")
+	}
+
+	// Terminal
+	recordingStorage := "database"
+	if c.Terminal.Recording.Dir != "" {
+		recordingStorage = c.Terminal.Recording.Dir
+	}
+	klog.Infof("Terminal Recording: admins always, users %t (Storage: %s)",
+		c.Terminal.Recording.EnableForUsers, recordingStorage)
+
 	// Scheduler Plugins
 	var enabledPlugins []string
 	if c.SchedulerPlugins.EMIAS.Enable {
@@ -15,7 +15,9 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/cleaner"
+	"github.com/raids-lab/crater/pkg/ldapsync"
 )
 
 // AddCronJob adds a cron job to the scheduler based on job type
@@ -45,12 +47,26 @@ func (cm *CronJobManager) newCronJobFunc(jobName string, jobType model.CronJobTy
 	switch jobType {
 	case model.CronJobTypeCleanerFunc:
 		return cleaner.GetWrapCleanerFunc(jobName, cm.cleanerClients, jobConfig)
+	case model.CronJobTypeDigestFunc:
+		digestFunc, err := alert.GetDigestFunc(jobConfig)
+		if err != nil {
+			return nil, err
+		}
+		return cleaner.WrapCleanerFunc(jobName, digestFunc), nil
+	case model.CronJobTypeLDAPSyncFunc:
+		syncFunc, err := ldapsync.GetLDAPSyncFunc(jobConfig)
+		if err != nil {
+			return nil, err
+		}
+		return cleaner.WrapCleanerFunc(jobName, syncFunc), nil
 	default:
 		return nil, fmt.Errorf("unsupported cron job type: %s", jobType)
 	}
 }
 
-// UpdateJobConfig updates the configuration of an existing cron job
+// UpdateJobConfig updates the configuration of an existing cron job.
+// The change is persisted to database; if this replica is the leader it takes effect
+// immediately, otherwise the leader picks it up on its next resync.
 func (cm *CronJobManager) UpdateJobConfig(
 	ctx *gin.Context,
 	name string,
@@ -59,36 +75,44 @@ func (cm *CronJobManager) UpdateJobConfig(
 	suspend *bool,
 	config *string,
 ) error {
-	cm.cronMutex.Lock()
-	defer cm.cronMutex.Unlock()
-
-	var (
-		cur    *model.CronJobConfig
-		update *model.CronJobConfig
-		err    error
-	)
-
-	err = query.GetDB().Transaction(func(tx *gorm.DB) error {
-		cur, err = cm.getCurrentJobConfigFromDB(tx, name)
+	err := query.GetDB().Transaction(func(tx *gorm.DB) error {
+		cur, err := cm.getCurrentJobConfigFromDB(tx, name)
 		if err != nil {
 			return err
 		}
 
-		update = cm.prepareUpdateConfig(cur, jobType, spec, suspend, config)
-
-		// Handle suspend state transition
-		if suspend != nil && cm.shouldSuspendJob(cur.GetSuspend(), *suspend) {
-			return cm.updateSuspendedJobConfig(tx, name, cur, update)
-		}
-
-		// Handle active job (not suspended)
-		if suspend != nil && !(*suspend) {
-			return cm.updateActiveJobConfig(ctx, tx, name, cur, update)
+		update := cm.prepareUpdateConfig(cur, jobType, spec, suspend, config)
+		if err := cm.validateJobConfig(update); err != nil {
+			return err
 		}
 
 		return tx.Model(cur).Where(query.CronJobConfig.Name.Eq(name)).Updates(update).Error
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	cm.SyncCronJob(ctx)
+	return nil
+}
+
+// validateJobConfig checks the spec and config of a non-suspended job before it is persisted,
+// so that a follower replica can reject invalid updates without touching the scheduler
+func (cm *CronJobManager) validateJobConfig(conf *model.CronJobConfig) error {
+	if conf.GetSuspend() {
+		return nil
+	}
+	if _, err := cron.ParseStandard(conf.Spec); err != nil {
+		err = fmt.Errorf("CronJobManager.validateJobConfig: invalid spec %q for job %s: %w", conf.Spec, conf.Name, err)
+		klog.Error(err)
+		return err
+	}
+	if _, err := cm.newCronJobFunc(conf.Name, conf.Type, conf.Config); err != nil {
+		err = fmt.Errorf("CronJobManager.validateJobConfig: invalid config for job %s: %w", conf.Name, err)
+		klog.Error(err)
+		return err
+	}
+	return nil
 }
 
 // getCurrentJobConfigFromDB retrieves current job configuration from database with row-level lock
@@ -136,58 +160,6 @@ func (cm *CronJobManager) prepareUpdateConfig(
 	return update
 }
 
-// shouldSuspendJob checks if job should be suspended
-func (cm *CronJobManager) shouldSuspendJob(wasSuspended, shouldSuspend bool) bool {
-	return !wasSuspended && shouldSuspend
-}
-
-// updateSuspendedJobConfig handles suspending an active job
-func (cm *CronJobManager) updateSuspendedJobConfig(
-	tx *gorm.DB,
-	name string,
-	cur *model.CronJobConfig,
-	update *model.CronJobConfig,
-) error {
-	curEntryID := cur.EntryID
-	update.EntryID = -1
-	if err := tx.Model(cur).Where(query.CronJobConfig.Name.Eq(name)).Updates(update).Error; err != nil {
-		err := fmt.Errorf("CronJobManager.updateSuspendedJobConfig failed to update cron job config for job %s: %w", name, err)
-		klog.Error(err)
-		return err
-	}
-	cm.cron.Remove(cron.EntryID(curEntryID))
-	return nil
-}
-
-// updateActiveJobConfig handles job need to active (not suspended)
-func (cm *CronJobManager) updateActiveJobConfig(
-	ctx *gin.Context,
-	tx *gorm.DB,
-	name string,
-	cur *model.CronJobConfig,
-	update *model.CronJobConfig,
-) error {
-	if cur.GetSuspend() {
-		if cm.jobNeedsUpdate(cur, update) {
-			cm.cron.Remove(cron.EntryID(cur.EntryID))
-		}
-	}
-	entryID, err := cm.AddCronJob(ctx, name, update.Spec, update.Type, update.Config)
-	if err != nil {
-		err := fmt.Errorf("addCronJob failed: %w", err)
-		klog.Error(err)
-		return err
-	}
-	update.EntryID = int(entryID)
-	if err := tx.Model(cur).Where(query.CronJobConfig.Name.Eq(name)).Updates(update).Error; err != nil {
-		err := fmt.Errorf("DB failed to update cron job config for job %s: %w", name, err)
-		cm.cron.Remove(entryID)
-		klog.Error(err)
-		return err
-	}
-	return nil
-}
-
 // jobNeedsUpdate checks if job configuration has changed
 func (cm *CronJobManager) jobNeedsUpdate(
 	cur *model.CronJobConfig,
@@ -205,47 +177,65 @@ func (cm *CronJobManager) jobNeedsUpdate(
 	return false
 }
 
-// SyncCronJob synchronizes cron jobs from database and starts the scheduler
-func (cm *CronJobManager) SyncCronJob() {
-	db := query.GetDB()
+// SyncCronJob synchronizes the scheduler with cron job configs in database.
+// It only takes effect on the leader replica: suspended or changed jobs are removed,
+// and newly enabled or changed jobs are (re)registered.
+func (cm *CronJobManager) SyncCronJob(ctx context.Context) {
 	cm.cronMutex.Lock()
 	defer cm.cronMutex.Unlock()
-	cm.cron.Start()
-	err := db.Transaction(func(tx *gorm.DB) error {
-		var configs []*model.CronJobConfig
-		if err := db.Where(query.CronJobConfig.Suspend.Is(false)).Find(&configs).Error; err != nil {
-			err := fmt.Errorf("CronJobManager.SyncCronJob: failed to load cron job configs: %w", err)
+	if !cm.leading {
+		return
+	}
+
+	var configs []*model.CronJobConfig
+	if err := query.GetDB().WithContext(ctx).Find(&configs).Error; err != nil {
+		err := fmt.Errorf("CronJobManager.SyncCronJob: failed to load cron job configs: %w", err)
+		klog.Error(err)
+		return
+	}
+
+	desired := make(map[string]*model.CronJobConfig, len(configs))
+	for _, conf := range configs {
+		if !conf.GetSuspend() {
+			desired[conf.Name] = conf
+		}
+	}
+
+	for name, entry := range cm.entries {
+		conf, ok := desired[name]
+		if ok && !cm.jobNeedsUpdate(entry.config, conf) {
+			continue
+		}
+		cm.cron.Remove(entry.entryID)
+		delete(cm.entries, name)
+		klog.Infof("CronJobManager.SyncCronJob: removed cron job %s", name)
+	}
+
+	for name, conf := range desired {
+		if _, ok := cm.entries[name]; ok {
+			continue
+		}
+		entryID, err := cm.AddCronJob(nil, conf.Name, conf.Spec, conf.Type, conf.Config)
+		if err != nil {
+			err := fmt.Errorf("CronJobManager.AddCronJob: failed to add cron job %s with spec %s: %w", conf.Name, conf.Spec, err)
 			klog.Error(err)
-			return nil
+			continue
 		}
-		klog.Infof("CronJobManager.SyncCronJob: loaded %d non-suspended cron jobs from database", len(configs))
+		cm.entries[name] = &scheduledEntry{entryID: entryID, config: conf}
+		klog.Infof("CronJobManager.SyncCronJob: scheduled cron job %s with spec %s", name, conf.Spec)
 
-		for _, conf := range configs {
-			entryID, err := cm.AddCronJob(nil, conf.Name, conf.Spec, conf.Type, conf.Config)
+		if int(entryID) != conf.EntryID {
+			err := query.GetDB().WithContext(ctx).
+				Model(&model.CronJobConfig{}).
+				Where(query.CronJobConfig.Name.Eq(conf.Name)).
+				Update("entry_id", int(entryID)).
+				Error
 			if err != nil {
-				err := fmt.Errorf("CronJobManager.AddCronJob: failed to add cron job %s with spec %s: %w", conf.Name, conf.Spec, err)
+				err := fmt.Errorf("DB failed to update entry_id for job %s: %w", conf.Name, err)
 				klog.Error(err)
-				continue
-			}
-			if int(entryID) != conf.EntryID {
-				err := tx.
-					Model(&model.CronJobConfig{}).
-					Where(query.CronJobConfig.Name.Eq(conf.Name)).
-					Update("entry_id", int(entryID)).
-					Error
-				if err != nil {
-					err := fmt.Errorf("DB failed to update entry_id for job %s: %w", conf.Name, err)
-					klog.Error(err)
-				}
 			}
 		}
-		return nil
-	})
-
-	if err != nil {
-		klog.Error(err)
 	}
-	klog.Info("CronJobManager.SyncCronJob: cron scheduler started")
 }
 
 // GetAllCronJobs retrieves all cron job configurations from database
@@ -257,9 +247,11 @@ func (cm *CronJobManager) GetAllCronJobs(ctx context.Context) ([]*model.CronJobC
 	return configs, nil
 }
 
-// StopCron stops the cron scheduler
+// StopCron stops the cron scheduler and waits for running jobs to finish
 func (cm *CronJobManager) StopCron() {
 	cm.cronMutex.Lock()
-	defer cm.cronMutex.Unlock()
-	cm.cron.Stop()
+	cm.leading = false
+	stopCtx := cm.cron.Stop()
+	cm.cronMutex.Unlock()
+	<-stopCtx.Done()
 }
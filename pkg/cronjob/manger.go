@@ -1,17 +1,38 @@
 package cronjob
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/pkg/cleaner"
 	"github.com/raids-lab/crater/pkg/monitor"
+	"github.com/raids-lab/crater/pkg/utils"
 )
 
+const (
+	// cronJobResyncPeriod 主副本从数据库重新同步定时任务配置的周期，
+	// 用于让在其他副本上修改的配置生效
+	cronJobResyncPeriod = 30 * time.Second
+)
+
+// scheduledEntry 记录当前副本已经注册到调度器中的定时任务
+type scheduledEntry struct {
+	entryID cron.EntryID
+	config  *model.CronJobConfig
+}
+
+// CronJobManager 管理定时任务。
+// 多副本部署时，只有 controller-runtime 选举出的主副本会真正执行定时任务，
+// 其他副本只负责读写数据库中的配置。
 type CronJobManager struct {
 	Client         client.Client
 	KubeClient     kubernetes.Interface
@@ -19,8 +40,14 @@ type CronJobManager struct {
 	cleanerClients *cleaner.Clients
 	cron           *cron.Cron
 	cronMutex      sync.RWMutex
+	// leading 表示当前副本是否为主副本（调度器是否在运行）
+	leading bool
+	// entries 记录已注册的定时任务，key 为任务名称
+	entries map[string]*scheduledEntry
 }
 
+var _ manager.LeaderElectionRunnable = &CronJobManager{}
+
 func NewCronJobManager(cli client.Client, kubeClient kubernetes.Interface, promClient monitor.PrometheusInterface) *CronJobManager {
 	return &CronJobManager{
 		Client:     cli,
@@ -31,6 +58,35 @@ func NewCronJobManager(cli client.Client, kubeClient kubernetes.Interface, promC
 			KubeClient: kubeClient,
 			PromClient: promClient,
 		},
-		cron: cron.New(cron.WithLocation(time.Local)),
+		cron:    cron.New(cron.WithLocation(time.Local)),
+		entries: make(map[string]*scheduledEntry),
 	}
 }
+
+// Start 实现 manager.Runnable，在当前副本成为主副本后由 controller-runtime 调用，
+// 启动调度器并周期性地从数据库同步配置，直到 ctx 结束
+func (cm *CronJobManager) Start(ctx context.Context) error {
+	klog.Infof("CronJobManager: replica %s is the leader, starting cron scheduler", utils.GetReplicaName())
+
+	cm.cronMutex.Lock()
+	cm.leading = true
+	cm.cron.Start()
+	cm.cronMutex.Unlock()
+
+	wait.UntilWithContext(ctx, cm.SyncCronJob, cronJobResyncPeriod)
+
+	cm.StopCron()
+	return nil
+}
+
+// NeedLeaderElection 实现 manager.LeaderElectionRunnable，定时任务只在主副本上运行
+func (cm *CronJobManager) NeedLeaderElection() bool {
+	return true
+}
+
+// IsLeading 返回当前副本是否正在执行定时任务
+func (cm *CronJobManager) IsLeading() bool {
+	cm.cronMutex.RLock()
+	defer cm.cronMutex.RUnlock()
+	return cm.leading
+}
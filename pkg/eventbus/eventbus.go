@@ -0,0 +1,114 @@
+// Package eventbus 在当前副本内分发作业状态变化等事件，用于向前端实时推送，
+// 替代前端对作业列表和详情接口的轮询
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// subscriberBufferSize 每个订阅者缓冲的事件数，订阅者处理不及时时多出的事件会被丢弃
+const subscriberBufferSize = 64
+
+// JobEventType 作业事件的类型
+type JobEventType string
+
+const (
+	JobEventPhase   JobEventType = "phase"   // 作业状态变化
+	JobEventEvent   JobEventType = "event"   // 作业产生了新的 Kubernetes 事件
+	JobEventRestart JobEventType = "restart" // 作业的容器重启
+)
+
+// JobKind 产生事件的作业类别
+type JobKind string
+
+const (
+	JobKindVcJob JobKind = "vcjob" // Volcano 作业
+	JobKindBuild JobKind = "build" // 镜像构建作业
+)
+
+// JobEvent 作业事件
+type JobEvent struct {
+	Type      JobEventType `json:"type"`
+	Kind      JobKind      `json:"kind"`
+	JobName   string       `json:"jobName"`
+	UserID    uint         `json:"userID"`
+	AccountID uint         `json:"accountID,omitempty"` // 镜像构建作业不属于账户，为 0
+	Phase     string       `json:"phase,omitempty"`
+	// Reason 和 Message 为 Kubernetes 事件或容器重启的原因
+	Reason       string    `json:"reason,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	PodName      string    `json:"podName,omitempty"`
+	Container    string    `json:"container,omitempty"`
+	RestartCount int32     `json:"restartCount,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type subscriber struct {
+	ch     chan *JobEvent
+	filter func(*JobEvent) bool
+}
+
+// Bus 进程内的作业事件总线。事件只在发布事件的副本（即运行 Reconciler 的主副本）内分发，
+// 订阅方需要自行定期刷新以覆盖其他副本上的连接
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe 订阅 filter 返回 true 的事件，filter 为空时订阅所有事件。
+// 返回的取消函数需要在连接关闭时调用
+func (b *Bus) Subscribe(filter func(*JobEvent) bool) (events <-chan *JobEvent, cancel func()) {
+	sub := &subscriber{
+		ch:     make(chan *JobEvent, subscriberBufferSize),
+		filter: filter,
+	}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, sub)
+	}
+}
+
+// Publish 将事件分发给所有匹配的订阅者，不会阻塞发布方
+func (b *Bus) Publish(event *JobEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			klog.V(4).Infof("drop %s event of job %s for a slow subscriber", event.Type, event.JobName)
+		}
+	}
+}
+
+var defaultBus = NewBus()
+
+// Subscribe 订阅默认事件总线
+func Subscribe(filter func(*JobEvent) bool) (events <-chan *JobEvent, cancel func()) {
+	return defaultBus.Subscribe(filter)
+}
+
+// Publish 向默认事件总线发布事件
+func Publish(event *JobEvent) {
+	defaultBus.Publish(event)
+}
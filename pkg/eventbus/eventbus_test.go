@@ -0,0 +1,45 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestBusFilter(t *testing.T) {
+	bus := NewBus()
+	own, cancelOwn := bus.Subscribe(func(e *JobEvent) bool { return e.UserID == 1 })
+	defer cancelOwn()
+	all, cancelAll := bus.Subscribe(nil)
+
+	bus.Publish(&JobEvent{Type: JobEventPhase, JobName: "a", UserID: 1, Phase: "Running"})
+	bus.Publish(&JobEvent{Type: JobEventPhase, JobName: "b", UserID: 2, Phase: "Running"})
+
+	if e := <-own; e.JobName != "a" || e.Timestamp.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+	select {
+	case e := <-own:
+		t.Errorf("event of other user delivered: %+v", e)
+	default:
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 events without filter, got %d", len(all))
+	}
+
+	cancelAll()
+	bus.Publish(&JobEvent{Type: JobEventPhase, JobName: "c", UserID: 2})
+	if len(all) != 2 {
+		t.Error("event delivered after cancel")
+	}
+}
+
+func TestBusSlowSubscriber(t *testing.T) {
+	bus := NewBus()
+	events, cancel := bus.Subscribe(nil)
+	defer cancel()
+	for i := 0; i < subscriberBufferSize+10; i++ {
+		bus.Publish(&JobEvent{Type: JobEventEvent, JobName: "a"})
+	}
+	if len(events) != subscriberBufferSize {
+		t.Errorf("expected %d buffered events, got %d", subscriberBufferSize, len(events))
+	}
+}
@@ -0,0 +1,110 @@
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+// Directory 读取 LDAP 组成员，测试时可以替换为内存实现
+type Directory interface {
+	// GroupMembers 返回组内成员的用户名
+	GroupMembers(ctx context.Context, group string) ([]string, error)
+	Close() error
+}
+
+// memberUIDAttribute posixGroup 的成员属性，值直接是用户名而不是 DN
+const memberUIDAttribute = "memberUid"
+
+type ldapDirectory struct {
+	conn *ldap.Conn
+	conf *Config
+	// usernames 缓存成员 DN 对应的用户名，同一用户可能属于多个组
+	usernames map[string]string
+}
+
+// dialDirectory 使用 raidsLab.ldap 中的地址和管理员账号连接 LDAP
+func dialDirectory(conf *Config) (Directory, error) {
+	ldapConf := config.GetConfig().RaidsLab.LDAP
+	if ldapConf.Address == "" {
+		return nil, errors.New("raidsLab.ldap.address is not configured")
+	}
+	conn, err := ldap.DialURL(ldapConf.Address)
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.Bind(ldapConf.UserName, ldapConf.Password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if conf.GroupBaseDN == "" {
+		conf.GroupBaseDN = ldapConf.SearchDN
+	}
+	return &ldapDirectory{conn: conn, conf: conf, usernames: make(map[string]string)}, nil
+}
+
+func (d *ldapDirectory) GroupMembers(_ context.Context, group string) ([]string, error) {
+	req := ldap.NewSearchRequest(
+		d.conf.GroupBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(d.conf.GroupFilter, ldap.EscapeFilter(group)),
+		[]string{d.conf.MemberAttribute},
+		nil,
+	)
+	result, err := d.conn.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) != 1 {
+		return nil, fmt.Errorf("expected 1 entry for group %s, got %d", group, len(result.Entries))
+	}
+
+	values := result.Entries[0].GetAttributeValues(d.conf.MemberAttribute)
+	if strings.EqualFold(d.conf.MemberAttribute, memberUIDAttribute) {
+		return values, nil
+	}
+	members := make([]string, 0, len(values))
+	for _, dn := range values {
+		name, err := d.usernameOf(dn)
+		if err != nil {
+			return nil, fmt.Errorf("resolve member %s of group %s: %w", dn, group, err)
+		}
+		if name != "" {
+			members = append(members, name)
+		}
+	}
+	return members, nil
+}
+
+// usernameOf 读取成员 DN 的用户名属性，嵌套的组等没有该属性的条目返回空字符串
+func (d *ldapDirectory) usernameOf(dn string) (string, error) {
+	if name, ok := d.usernames[dn]; ok {
+		return name, nil
+	}
+	req := ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{d.conf.UsernameAttribute}, nil,
+	)
+	result, err := d.conn.Search(req)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return "", nil
+		}
+		return "", err
+	}
+	name := ""
+	if len(result.Entries) == 1 {
+		name = result.Entries[0].GetAttributeValue(d.conf.UsernameAttribute)
+	}
+	d.usernames[dn] = name
+	return name, nil
+}
+
+func (d *ldapDirectory) Close() error {
+	return d.conn.Close()
+}
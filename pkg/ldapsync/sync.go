@@ -0,0 +1,373 @@
+// Package ldapsync 定期将 LDAP 组的成员同步到 Crater 账户
+package ldapsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/datatypes"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+const (
+	defaultGroupFilter       = "(&(objectClass=group)(cn=%s))"
+	defaultMemberAttribute   = "member"
+	defaultUsernameAttribute = "sAMAccountName"
+)
+
+// Config LDAP 组同步定时任务的配置，保存在定时任务配置的 config 字段中
+type Config struct {
+	// DryRun 只生成差异报告，不修改账户成员
+	DryRun bool `json:"dryRun"`
+	// GroupBaseDN 搜索组的基准 DN，默认使用 raidsLab.ldap.searchDN
+	GroupBaseDN string `json:"groupBaseDN"`
+	// GroupFilter 搜索组的过滤条件，%s 替换为组名
+	GroupFilter string `json:"groupFilter"`
+	// MemberAttribute 组的成员属性，member 的值为用户 DN，memberUid 的值为用户名
+	MemberAttribute string `json:"memberAttribute"`
+	// UsernameAttribute 用户条目中与 Crater 用户名对应的属性
+	UsernameAttribute string `json:"usernameAttribute"`
+	// DefaultRole 新加入成员的角色 (user 或 admin)，默认为 user
+	DefaultRole string `json:"defaultRole"`
+	// DefaultAccessMode 新加入成员的访问模式 (ro 或 rw)，默认为 rw
+	DefaultAccessMode string `json:"defaultAccessMode"`
+	// RemoveMembers 是否移除不在组内的成员，默认为 true。账户管理员不会被移除
+	RemoveMembers *bool `json:"removeMembers"`
+	// Groups LDAP 组与账户的映射
+	Groups []GroupMapping `json:"groups"`
+}
+
+// GroupMapping 一个 LDAP 组对应的 Crater 账户，Role 和 AccessMode 为空时使用默认值
+type GroupMapping struct {
+	Group      string `json:"group"`
+	Account    string `json:"account"` // 账户名称，如 q-1
+	Role       string `json:"role"`
+	AccessMode string `json:"accessMode"`
+}
+
+type (
+	// Report 一次同步的结果，保存到定时任务的执行记录中
+	Report struct {
+		DryRun  bool          `json:"dryRun"`
+		Drift   bool          `json:"drift"` // 是否存在 LDAP 组与账户成员不一致
+		Added   int           `json:"added"`
+		Removed int           `json:"removed"`
+		Failed  int           `json:"failed"`
+		Groups  []GroupReport `json:"groups"`
+	}
+
+	// GroupReport 单个组的同步结果，DryRun 时 Added 和 Removed 为将要执行的变更
+	GroupReport struct {
+		Group       string   `json:"group"`
+		Account     string   `json:"account"`
+		LDAPMembers int      `json:"ldapMembers"`
+		Added       []string `json:"added"`
+		Removed     []string `json:"removed"`
+		// UnknownUsers 组内尚未在 Crater 登录过的用户，首次登录后的下一次同步会加入账户
+		UnknownUsers []string `json:"unknownUsers"`
+		// KeptAdmins 不在组内但作为账户管理员保留的成员
+		KeptAdmins []string `json:"keptAdmins"`
+		// Mismatched 角色或访问模式与映射不一致的成员，只报告不修改
+		Mismatched []string `json:"mismatched"`
+		Error      string   `json:"error,omitempty"`
+	}
+)
+
+// GetLDAPSyncFunc 根据定时任务配置返回同步函数
+func GetLDAPSyncFunc(jobConfig datatypes.JSON) (func(ctx context.Context) (any, error), error) {
+	conf, err := parseConfig(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) (any, error) {
+		report, err := run(ctx, conf)
+		if report == nil {
+			return nil, err
+		}
+		return report, err
+	}, nil
+}
+
+// Preview 按定时任务配置执行一次试运行，返回差异报告但不修改账户成员
+func Preview(ctx context.Context, jobConfig datatypes.JSON) (*Report, error) {
+	conf, err := parseConfig(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	conf.DryRun = true
+	return run(ctx, conf)
+}
+
+func run(ctx context.Context, conf *Config) (*Report, error) {
+	dir, err := dialDirectory(conf)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	return Sync(ctx, dir, conf)
+}
+
+func parseConfig(jobConfig datatypes.JSON) (*Config, error) {
+	conf := &Config{}
+	if len(jobConfig) > 0 {
+		if err := json.Unmarshal(jobConfig, conf); err != nil {
+			return nil, err
+		}
+	}
+	if conf.GroupFilter == "" {
+		conf.GroupFilter = defaultGroupFilter
+	}
+	if !strings.Contains(conf.GroupFilter, "%s") {
+		return nil, fmt.Errorf("groupFilter %q must contain %%s", conf.GroupFilter)
+	}
+	if conf.MemberAttribute == "" {
+		conf.MemberAttribute = defaultMemberAttribute
+	}
+	if conf.UsernameAttribute == "" {
+		conf.UsernameAttribute = defaultUsernameAttribute
+	}
+	if _, err := parseRole(conf.DefaultRole, model.RoleUser); err != nil {
+		return nil, err
+	}
+	if _, err := parseAccessMode(conf.DefaultAccessMode, model.AccessModeRW); err != nil {
+		return nil, err
+	}
+	for _, g := range conf.Groups {
+		if g.Group == "" || g.Account == "" {
+			return nil, errors.New("group and account are required in group mappings")
+		}
+		if _, err := parseRole(g.Role, model.RoleUser); err != nil {
+			return nil, err
+		}
+		if _, err := parseAccessMode(g.AccessMode, model.AccessModeRW); err != nil {
+			return nil, err
+		}
+	}
+	return conf, nil
+}
+
+func parseRole(s string, def model.Role) (model.Role, error) {
+	switch s {
+	case "":
+		return def, nil
+	case "user":
+		return model.RoleUser, nil
+	case "admin":
+		return model.RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("invalid role %q, must be user or admin", s)
+	}
+}
+
+func parseAccessMode(s string, def model.AccessMode) (model.AccessMode, error) {
+	switch s {
+	case "":
+		return def, nil
+	case "ro":
+		return model.AccessModeRO, nil
+	case "rw":
+		return model.AccessModeRW, nil
+	default:
+		return 0, fmt.Errorf("invalid access mode %q, must be ro or rw", s)
+	}
+}
+
+// Sync 读取每个映射组的成员并与账户成员比较，非试运行时加入缺少的成员、移除多余的成员。
+// 单个组出错不影响其他组，错误记录在该组的报告中
+func Sync(ctx context.Context, dir Directory, conf *Config) (*Report, error) {
+	report := &Report{DryRun: conf.DryRun, Groups: make([]GroupReport, 0, len(conf.Groups))}
+	for _, mapping := range conf.Groups {
+		gr, err := syncGroup(ctx, dir, conf, mapping)
+		if err != nil {
+			klog.Errorf("ldap sync: group %s to account %s failed: %v", mapping.Group, mapping.Account, err)
+			gr.Error = err.Error()
+			report.Failed++
+		}
+		report.Added += len(gr.Added)
+		report.Removed += len(gr.Removed)
+		if len(gr.Added) > 0 || len(gr.Removed) > 0 || len(gr.Mismatched) > 0 {
+			report.Drift = true
+		}
+		report.Groups = append(report.Groups, gr)
+	}
+	if report.Failed > 0 && report.Failed == len(conf.Groups) {
+		return report, errors.New("all groups failed to sync")
+	}
+	return report, nil
+}
+
+func syncGroup(ctx context.Context, dir Directory, conf *Config, mapping GroupMapping) (GroupReport, error) {
+	gr := GroupReport{Group: mapping.Group, Account: mapping.Account}
+	// 配置已在 parseConfig 中校验过
+	defaultRole, _ := parseRole(conf.DefaultRole, model.RoleUser)
+	role, _ := parseRole(mapping.Role, defaultRole)
+	defaultAccess, _ := parseAccessMode(conf.DefaultAccessMode, model.AccessModeRW)
+	access, _ := parseAccessMode(mapping.AccessMode, defaultAccess)
+
+	a := query.Account
+	account, err := a.WithContext(ctx).Where(a.Name.Eq(mapping.Account)).First()
+	if err != nil {
+		return gr, fmt.Errorf("get account %s: %w", mapping.Account, err)
+	}
+	if account.ID == model.DefaultAccountID {
+		return gr, errors.New("the default account can not be synchronized")
+	}
+
+	ldapMembers, err := dir.GroupMembers(ctx, mapping.Group)
+	if err != nil {
+		return gr, fmt.Errorf("read ldap group: %w", err)
+	}
+	gr.LDAPMembers = len(ldapMembers)
+
+	current, err := accountMembers(ctx, account.ID)
+	if err != nil {
+		return gr, err
+	}
+	users, err := usersByName(ctx, ldapMembers)
+	if err != nil {
+		return gr, err
+	}
+
+	remove := conf.RemoveMembers == nil || *conf.RemoveMembers
+	plan := planGroup(ldapMembers, users, current, role, access, remove)
+	gr.UnknownUsers = plan.unknown
+	gr.KeptAdmins = plan.keptAdmins
+	gr.Mismatched = plan.mismatched
+	if conf.DryRun {
+		gr.Added = plan.add
+		gr.Removed = memberNames(plan.remove)
+		return gr, nil
+	}
+
+	uq := query.UserAccount
+	gr.Added = make([]string, 0, len(plan.add))
+	for _, name := range plan.add {
+		if err = uq.WithContext(ctx).Create(&model.UserAccount{
+			UserID:     users[name],
+			AccountID:  account.ID,
+			Role:       role,
+			AccessMode: access,
+		}); err != nil {
+			return gr, fmt.Errorf("add %s to account: %w", name, err)
+		}
+		gr.Added = append(gr.Added, name)
+		klog.Infof("ldap sync: user %s joined account %s by group %s", name, account.Name, mapping.Group)
+	}
+	gr.Removed = make([]string, 0, len(plan.remove))
+	for _, m := range plan.remove {
+		if _, err = uq.WithContext(ctx).Where(uq.AccountID.Eq(account.ID), uq.UserID.Eq(m.UserID)).Delete(); err != nil {
+			return gr, fmt.Errorf("remove %s from account: %w", m.Name, err)
+		}
+		gr.Removed = append(gr.Removed, m.Name)
+		klog.Infof("ldap sync: user %s left account %s, not in group %s", m.Name, account.Name, mapping.Group)
+	}
+	return gr, nil
+}
+
+// member 账户的一个成员
+type member struct {
+	UserID     uint
+	Name       string
+	Role       model.Role
+	AccessMode model.AccessMode
+}
+
+func accountMembers(ctx context.Context, accountID uint) ([]member, error) {
+	var members []member
+	u := query.User
+	uq := query.UserAccount
+	err := uq.WithContext(ctx).Where(uq.AccountID.Eq(accountID)).
+		Join(u, u.ID.EqCol(uq.UserID)).
+		Select(uq.UserID, u.Name, uq.Role, uq.AccessMode).
+		Scan(&members)
+	return members, err
+}
+
+func usersByName(ctx context.Context, names []string) (map[string]uint, error) {
+	users := make(map[string]uint, len(names))
+	if len(names) == 0 {
+		return users, nil
+	}
+	u := query.User
+	found, err := u.WithContext(ctx).Select(u.ID, u.Name).Where(u.Name.In(names...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range found {
+		users[user.Name] = user.ID
+	}
+	return users, nil
+}
+
+type groupPlan struct {
+	add        []string
+	remove     []member
+	unknown    []string
+	keptAdmins []string
+	mismatched []string
+}
+
+// planGroup 比较 LDAP 组成员和账户成员，计算需要的变更。
+// users 为组内已在 Crater 中存在的用户；账户管理员即使不在组内也不会被移除
+func planGroup(
+	ldapMembers []string,
+	users map[string]uint,
+	current []member,
+	role model.Role,
+	access model.AccessMode,
+	remove bool,
+) groupPlan {
+	plan := groupPlan{add: []string{}, remove: []member{}, unknown: []string{}, keptAdmins: []string{}, mismatched: []string{}}
+
+	inGroup := make(map[string]bool, len(ldapMembers))
+	for _, name := range ldapMembers {
+		inGroup[name] = true
+	}
+	inAccount := make(map[string]bool, len(current))
+	for _, m := range current {
+		inAccount[m.Name] = true
+		switch {
+		case inGroup[m.Name]:
+			if m.Role != role || m.AccessMode != access {
+				plan.mismatched = append(plan.mismatched, m.Name)
+			}
+		case m.Role == model.RoleAdmin:
+			plan.keptAdmins = append(plan.keptAdmins, m.Name)
+		case remove:
+			plan.remove = append(plan.remove, m)
+		}
+	}
+
+	for name := range inGroup {
+		if inAccount[name] {
+			continue
+		}
+		if _, ok := users[name]; ok {
+			plan.add = append(plan.add, name)
+		} else {
+			plan.unknown = append(plan.unknown, name)
+		}
+	}
+
+	slices.Sort(plan.add)
+	slices.Sort(plan.unknown)
+	slices.Sort(plan.keptAdmins)
+	slices.Sort(plan.mismatched)
+	slices.SortFunc(plan.remove, func(a, b member) int { return strings.Compare(a.Name, b.Name) })
+	return plan
+}
+
+func memberNames(members []member) []string {
+	names := make([]string, 0, len(members))
+	for _, m := range members {
+		names = append(names, m.Name)
+	}
+	return names
+}
@@ -0,0 +1,64 @@
+package ldapsync
+
+import (
+	"slices"
+	"testing"
+
+	"gorm.io/datatypes"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestPlanGroup(t *testing.T) {
+	ldapMembers := []string{"alice", "bob", "carol", "dave"}
+	users := map[string]uint{"alice": 1, "bob": 2, "carol": 3}
+	current := []member{
+		{UserID: 1, Name: "alice", Role: model.RoleUser, AccessMode: model.AccessModeRW},
+		{UserID: 2, Name: "bob", Role: model.RoleUser, AccessMode: model.AccessModeRO},
+		{UserID: 4, Name: "eve", Role: model.RoleUser, AccessMode: model.AccessModeRW},
+		{UserID: 5, Name: "frank", Role: model.RoleAdmin, AccessMode: model.AccessModeRW},
+	}
+
+	plan := planGroup(ldapMembers, users, current, model.RoleUser, model.AccessModeRW, true)
+	if !slices.Equal(plan.add, []string{"carol"}) {
+		t.Errorf("add = %v, want [carol]", plan.add)
+	}
+	if names := memberNames(plan.remove); !slices.Equal(names, []string{"eve"}) {
+		t.Errorf("remove = %v, want [eve]", names)
+	}
+	if !slices.Equal(plan.unknown, []string{"dave"}) {
+		t.Errorf("unknown = %v, want [dave]", plan.unknown)
+	}
+	if !slices.Equal(plan.keptAdmins, []string{"frank"}) {
+		t.Errorf("keptAdmins = %v, want [frank]", plan.keptAdmins)
+	}
+	if !slices.Equal(plan.mismatched, []string{"bob"}) {
+		t.Errorf("mismatched = %v, want [bob]", plan.mismatched)
+	}
+
+	plan = planGroup(ldapMembers, users, current, model.RoleUser, model.AccessModeRW, false)
+	if len(plan.remove) != 0 {
+		t.Errorf("expected no removal when removeMembers is false, got %v", memberNames(plan.remove))
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	conf, err := parseConfig(datatypes.JSON(`{"dryRun": true, "groups": [{"group": "gpu", "account": "q-2"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.GroupFilter != defaultGroupFilter || conf.MemberAttribute != defaultMemberAttribute {
+		t.Errorf("defaults not applied: %+v", conf)
+	}
+
+	for _, invalid := range []string{
+		`{"groups": [{"group": "gpu"}]}`,
+		`{"defaultRole": "root"}`,
+		`{"groups": [{"group": "gpu", "account": "q-2", "accessMode": "ao"}]}`,
+		`{"groupFilter": "(cn=gpu)"}`,
+	} {
+		if _, err := parseConfig(datatypes.JSON(invalid)); err == nil {
+			t.Errorf("expected error for config %s", invalid)
+		}
+	}
+}
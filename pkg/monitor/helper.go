@@ -9,6 +9,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// CheckHealth 执行一次最简单的查询，判断 Prometheus API 是否可以访问
+func (p *PrometheusClient) CheckHealth(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	_, _, err := p.v1api.Query(ctx, "vector(1)", time.Now())
+	return err
+}
+
 func (p *PrometheusClient) queryMetric(expression string) (float32, error) {
 	// 构建查询参数
 
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"context"
 	"time"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -8,6 +9,9 @@ import (
 
 // PrometheusClient is a client for Prometheus
 type PrometheusInterface interface {
+	// CheckHealth checks whether the Prometheus API is reachable
+	CheckHealth(ctx context.Context) error
+
 	///////////// Node Related //////////////
 
 	// QueryNodeCPUUsageRatio queries the CPU usage ratio of a node
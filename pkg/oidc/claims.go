@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// ClaimMapping 指定用户信息对应的声明名称
+type ClaimMapping struct {
+	Username string
+	Nickname string
+	Email    string
+	Groups   string
+}
+
+// UserInfo 从声明中提取的用户信息，Issuer 和 Subject 唯一标识身份提供方中的用户
+type UserInfo struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Nickname string
+	Email    string
+	Groups   []string
+}
+
+// MapClaims 按映射从声明中提取用户信息，昵称缺省时使用用户名。
+// 用户组声明可以是字符串数组，也可以是逗号分隔的字符串
+func MapClaims(claims map[string]any, mapping ClaimMapping) (*UserInfo, error) {
+	info := &UserInfo{
+		Issuer:   stringClaim(claims, "iss"),
+		Subject:  stringClaim(claims, "sub"),
+		Username: stringClaim(claims, mapping.Username),
+		Nickname: stringClaim(claims, mapping.Nickname),
+		Email:    stringClaim(claims, mapping.Email),
+	}
+	if info.Issuer == "" || info.Subject == "" {
+		return nil, errors.New("claim \"iss\" or \"sub\" not found")
+	}
+	if info.Username == "" {
+		return nil, fmt.Errorf("claim %q not found", mapping.Username)
+	}
+	if info.Nickname == "" {
+		info.Nickname = info.Username
+	}
+
+	switch groups := claims[mapping.Groups].(type) {
+	case []any:
+		for _, group := range groups {
+			if s, ok := group.(string); ok && s != "" {
+				info.Groups = append(info.Groups, s)
+			}
+		}
+	case string:
+		for _, group := range strings.Split(groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				info.Groups = append(info.Groups, group)
+			}
+		}
+	}
+	return info, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	if name == "" {
+		return ""
+	}
+	s, _ := claims[name].(string)
+	return strings.TrimSpace(s)
+}
+
+// stateClaims 保存在浏览器 Cookie 中的登录状态，用于在回调时校验 state 并取回 nonce
+type stateClaims struct {
+	State string `json:"state"`
+	Nonce string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// NewState 生成随机的 state 和 nonce，并返回签名后的 Cookie 值。
+// 登录状态不保存在服务端，因此可以在任意副本上完成回调
+func NewState(secret string, ttl time.Duration) (state, nonce, cookie string, err error) {
+	if state, err = randomString(); err != nil {
+		return "", "", "", err
+	}
+	if nonce, err = randomString(); err != nil {
+		return "", "", "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &stateClaims{
+		State: state,
+		Nonce: nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	})
+	cookie, err = token.SignedString([]byte(secret))
+	return state, nonce, cookie, err
+}
+
+// VerifyState 校验 Cookie 中的登录状态与回调的 state 一致，返回发起登录时的 nonce
+func VerifyState(secret, cookie, state string) (string, error) {
+	claims := &stateClaims{}
+	if _, err := jwt.ParseWithClaims(cookie, claims, func(_ *jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired()); err != nil {
+		return "", fmt.Errorf("invalid login state: %w", err)
+	}
+	if state == "" || claims.State != state {
+		return "", errors.New("login state mismatch")
+	}
+	return claims.Nonce, nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,279 @@
+// Package oidc 实现通用 OpenID Connect 授权码登录：通过 Discovery 获取端点，
+// 用授权码换取 ID Token，使用 JWKS 校验签名后返回用户声明
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// DefaultScopes 未配置 scopes 时请求的权限
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// Provider 一个 OpenID Connect 身份提供方
+type Provider struct {
+	issuer      string
+	userinfoURL string
+	jwksURL     string
+	oauth2      oauth2.Config
+	client      *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+}
+
+type discovery struct {
+	Issuer           string `json:"issuer"`
+	AuthorizationURL string `json:"authorization_endpoint"`
+	TokenURL         string `json:"token_endpoint"`
+	UserinfoURL      string `json:"userinfo_endpoint"`
+	JWKSURL          string `json:"jwks_uri"`
+}
+
+// NewProvider 通过 issuer 的 Discovery 文档创建身份提供方
+func NewProvider(ctx context.Context, issuer, clientID, clientSecret, redirectURL string, scopes []string) (*Provider, error) {
+	client := &http.Client{Timeout: defaultHTTPTimeout}
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	var doc discovery
+	if err := getJSON(ctx, client, issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch, expected %q got %q", issuer, doc.Issuer)
+	}
+	if doc.AuthorizationURL == "" || doc.TokenURL == "" || doc.JWKSURL == "" {
+		return nil, errors.New("oidc discovery: missing authorization, token or jwks endpoint")
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	return &Provider{
+		issuer:      doc.Issuer,
+		userinfoURL: doc.UserinfoURL,
+		jwksURL:     doc.JWKSURL,
+		oauth2: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  doc.AuthorizationURL,
+				TokenURL: doc.TokenURL,
+			},
+		},
+		client: client,
+		keys:   make(map[string]crypto.PublicKey),
+	}, nil
+}
+
+// AuthCodeURL 返回跳转到身份提供方登录页的地址
+func (p *Provider) AuthCodeURL(state, nonce string) string {
+	return p.oauth2.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// Exchange 用授权码换取 ID Token 并校验，返回 ID Token 中的声明，
+// 提供方支持 UserInfo 端点时补充 ID Token 中缺少的声明
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (map[string]any, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := p.oauth2.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("oidc token exchange: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("oidc token exchange: no id_token in response")
+	}
+	claims, err := p.verifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.userinfoURL != "" {
+		var userinfo map[string]any
+		if err := getJSON(ctx, p.client, p.userinfoURL, token.AccessToken, &userinfo); err != nil {
+			return nil, fmt.Errorf("oidc userinfo: %w", err)
+		}
+		// UserInfo 中的 sub 必须与 ID Token 一致
+		if sub, _ := userinfo["sub"].(string); sub != "" && sub != claims["sub"] {
+			return nil, errors.New("oidc userinfo: subject mismatch")
+		}
+		for k, v := range userinfo {
+			if _, exists := claims[k]; !exists {
+				claims[k] = v
+			}
+		}
+	}
+	return claims, nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.oauth2.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id_token: %w", err)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("oidc id_token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+// publicKey 返回签名公钥，找不到 kid 时重新获取 JWKS 以支持提供方轮换密钥
+func (p *Provider) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.RLock()
+	key, ok := p.lookupKey(kid)
+	p.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	keys, err := fetchJWKS(ctx, p.client, p.jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+// lookupKey 按 kid 查找公钥，ID Token 没有 kid 且只有一个公钥时使用该公钥
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func fetchJWKS(ctx context.Context, client *http.Client, url string) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, client, url, "", &set); err != nil {
+		return nil, fmt.Errorf("oidc jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for i := range set.Keys {
+		jwk := &set.Keys[i]
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// 跳过不支持的密钥类型
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, url, bearer string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status code %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
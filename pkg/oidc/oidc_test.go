@@ -0,0 +1,149 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// mockIssuer 是一个最小的 OpenID Connect 提供方，授权码 "good-code" 会换取 nonce 为 nonce 的 ID Token
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{key: key}
+	mux := http.NewServeMux()
+	m.server = httptest.NewServer(mux)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/auth",
+			"token_endpoint":         m.server.URL + "/token",
+			"userinfo_endpoint":      m.server.URL + "/userinfo",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "good-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                m.server.URL,
+			"aud":                "crater",
+			"sub":                "user-1",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"nonce":              m.nonce,
+			"preferred_username": "alice",
+			"email":              "alice@example.com",
+		})
+		token.Header["kid"] = "test"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"sub":    "user-1",
+			"name":   "Alice",
+			"groups": []string{"lab-a", "lab-b"},
+		})
+	})
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func TestProviderExchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, issuer.server.URL, "crater", "secret", "https://crater/auth/oidc", nil)
+	if err != nil {
+		t.Fatalf("new provider failed: %v", err)
+	}
+
+	state, nonce, cookie, err := NewState("state-secret", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := url.Parse(provider.AuthCodeURL(state, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authURL.Query().Get("nonce") != nonce || authURL.Query().Get("client_id") != "crater" {
+		t.Errorf("unexpected auth url %s", authURL)
+	}
+	got, err := VerifyState("state-secret", cookie, state)
+	if err != nil || got != nonce {
+		t.Fatalf("verify state failed: %q %v", got, err)
+	}
+	if _, err := VerifyState("state-secret", cookie, "other"); err == nil {
+		t.Error("expected state mismatch")
+	}
+
+	issuer.nonce = nonce
+	claims, err := provider.Exchange(ctx, "good-code", nonce)
+	if err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+	info, err := MapClaims(claims, ClaimMapping{
+		Username: "preferred_username",
+		Nickname: "name",
+		Email:    "email",
+		Groups:   "groups",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Issuer != issuer.server.URL || info.Subject != "user-1" ||
+		info.Username != "alice" || info.Nickname != "Alice" || info.Email != "alice@example.com" ||
+		len(info.Groups) != 2 || info.Groups[1] != "lab-b" {
+		t.Errorf("unexpected user info %+v", info)
+	}
+
+	if _, err := provider.Exchange(ctx, "good-code", "other-nonce"); err == nil {
+		t.Error("expected nonce mismatch")
+	}
+	if _, err := provider.Exchange(ctx, "bad-code", nonce); err == nil {
+		t.Error("expected exchange failure")
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/eventbus"
 	"github.com/raids-lab/crater/pkg/imageregistry"
 	"github.com/raids-lab/crater/pkg/packer"
 )
@@ -237,6 +238,16 @@ func (r *BuildKitReconciler) updateKanikoStatus(ctx context.Context, kaniko *mod
 	_, err := k.WithContext(ctx).
 		Where(k.ImagePackName.Eq(kaniko.ImagePackName)).
 		Update(k.Status, status)
+	if err == nil && kaniko.Status != status {
+		// 构建状态变化时通知前端
+		eventbus.Publish(&eventbus.JobEvent{
+			Type:    eventbus.JobEventPhase,
+			Kind:    eventbus.JobKindBuild,
+			JobName: kaniko.ImagePackName,
+			UserID:  kaniko.UserID,
+			Phase:   string(status),
+		})
+	}
 	return err
 }
 
@@ -0,0 +1,163 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+const (
+	// gpuResourcePrefix 节点上 GPU 资源名称的前缀
+	gpuResourcePrefix = "nvidia.com/"
+	// gpuCountLabel GPU Feature Discovery 记录的节点 GPU 数量
+	gpuCountLabel = "nvidia.com/gpu.count"
+)
+
+// NodeReconciler 监听节点状态，节点 NotReady 或可分配的 GPU 少于实际数量时通知管理员
+type NodeReconciler struct {
+	client.Client
+	log logr.Logger
+
+	// health 记录每个节点上次同步到数据库的状态。节点心跳会频繁触发 Reconcile，
+	// 状态不变时直接返回，只在状态变化时读写异常记录
+	mu     sync.Mutex
+	health map[string]nodeHealth
+}
+
+// nodeHealth 节点的异常详情，正常时为空
+type nodeHealth struct {
+	notReady   string
+	gpuMissing string
+}
+
+// NewNodeReconciler returns a new reconcile.Reconciler
+func NewNodeReconciler(crClient client.Client) *NodeReconciler {
+	return &NodeReconciler{
+		Client: crClient,
+		log:    ctrl.Log.WithName("node-reconciler"),
+		health: make(map[string]nodeHealth),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("node-reconciler").
+		For(&v1.Node{}).
+		WithOptions(controller.Options{}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// Reconcile 检查节点的 Ready 状态和 GPU 数量，状态变化时记录或恢复对应的集群异常
+func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var node v1.Node
+	if err := r.Get(ctx, req.NamespacedName, &node); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// 节点被移除后不再跟踪其异常
+			r.mu.Lock()
+			delete(r.health, req.Name)
+			r.mu.Unlock()
+			return ctrl.Result{}, errors.Join(
+				alert.ResolveIncident(ctx, model.IncidentKindNodeNotReady, req.Name),
+				alert.ResolveIncident(ctx, model.IncidentKindGPUMissing, req.Name),
+			)
+		}
+		return ctrl.Result{}, err
+	}
+
+	current := nodeHealth{
+		notReady:   nodeNotReadyMessage(&node),
+		gpuMissing: nodeGPUMissingMessage(&node),
+	}
+
+	r.mu.Lock()
+	previous, known := r.health[node.Name]
+	r.mu.Unlock()
+	if known && previous == current {
+		return ctrl.Result{}, nil
+	}
+
+	err := errors.Join(
+		syncNodeIncident(ctx, model.IncidentKindNodeNotReady, node.Name, previous.notReady, current.notReady, known),
+		syncNodeIncident(ctx, model.IncidentKindGPUMissing, node.Name, previous.gpuMissing, current.gpuMissing, known),
+	)
+	if err != nil {
+		// 不更新缓存的状态，重试时重新同步
+		r.log.Error(err, "failed to update node incidents", "node", node.Name)
+		return ctrl.Result{}, err
+	}
+
+	r.mu.Lock()
+	r.health[node.Name] = current
+	r.mu.Unlock()
+	return ctrl.Result{}, nil
+}
+
+// syncNodeIncident 根据节点某类异常的前后详情更新异常记录。
+// 只有从正常变为异常时才计为一次新的出现；异常详情变化或重启后不知道之前的状态时只更新详情
+func syncNodeIncident(ctx context.Context, kind model.IncidentKind, node, previous, current string, known bool) error {
+	switch {
+	case known && previous == current:
+		return nil
+	case current == "":
+		return alert.ResolveIncident(ctx, kind, node)
+	case known && previous == "":
+		return alert.OpenIncident(ctx, kind, node, current)
+	default:
+		return alert.RefreshIncident(ctx, kind, node, current)
+	}
+}
+
+// nodeNotReadyMessage 返回节点 NotReady 的原因，节点 Ready 时返回空字符串
+func nodeNotReadyMessage(node *v1.Node) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != v1.NodeReady {
+			continue
+		}
+		if condition.Status == v1.ConditionTrue {
+			return ""
+		}
+		return fmt.Sprintf("Ready=%s, reason: %s, message: %s", condition.Status, condition.Reason, condition.Message)
+	}
+	return "node has no Ready condition"
+}
+
+// nodeGPUMissingMessage 比较节点可分配的 GPU 与容量及 GPU 标签中的数量，
+// 可分配数量更少时说明有 GPU 掉卡或被设备插件标记为不健康，数量一致时返回空字符串
+func nodeGPUMissingMessage(node *v1.Node) string {
+	var capacity, allocatable int64
+	for name, quantity := range node.Status.Capacity {
+		if strings.HasPrefix(name.String(), gpuResourcePrefix) {
+			capacity += quantity.Value()
+		}
+	}
+	for name, quantity := range node.Status.Allocatable {
+		if strings.HasPrefix(name.String(), gpuResourcePrefix) {
+			allocatable += quantity.Value()
+		}
+	}
+
+	expected := capacity
+	if count, err := strconv.ParseInt(node.Labels[gpuCountLabel], 10, 64); err == nil && count > expected {
+		expected = count
+	}
+	if allocatable < expected {
+		return fmt.Sprintf("allocatable GPUs %d, expected %d (capacity %d)", allocatable, expected, capacity)
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/eventbus"
+)
+
+// setupPodRestartWithManager 监听作业 Pod 的容器重启次数，重启时向前端推送事件。
+// Pod 的重启不会改变 VcJob 的状态，因此需要单独监听
+func (r *VcJobReconciler) setupPodRestartWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("vcjob-pod-restart").
+		For(&v1.Pod{}, builder.WithPredicates(podRestartPredicate())).
+		Complete(reconcile.Func(r.reconcilePodRestart))
+}
+
+// podRestartPredicate 只处理作业 Pod 重启次数增加的更新事件
+func podRestartPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*v1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*v1.Pod)
+			if !ok {
+				return false
+			}
+			if newPod.Namespace != config.GetConfig().Namespaces.Job || newPod.Annotations[batch.JobNameKey] == "" {
+				return false
+			}
+			return podRestartCount(newPod) > podRestartCount(oldPod)
+		},
+	}
+}
+
+func podRestartCount(pod *v1.Pod) int32 {
+	var count int32
+	for i := range pod.Status.ContainerStatuses {
+		count += pod.Status.ContainerStatuses[i].RestartCount
+	}
+	return count
+}
+
+// reconcilePodRestart 推送 Pod 中最近一次重启的容器及其退出原因
+func (r *VcJobReconciler) reconcilePodRestart(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod v1.Pod
+	if err := r.Get(ctx, req.NamespacedName, &pod); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	j := query.Job
+	record, err := j.WithContext(ctx).
+		Select(j.JobName, j.UserID, j.AccountID).
+		Where(j.JobName.Eq(pod.Annotations[batch.JobNameKey])).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var latest *v1.ContainerStatus
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if status.RestartCount == 0 || status.LastTerminationState.Terminated == nil {
+			continue
+		}
+		if latest == nil || status.LastTerminationState.Terminated.FinishedAt.After(
+			latest.LastTerminationState.Terminated.FinishedAt.Time) {
+			latest = status
+		}
+	}
+	if latest == nil {
+		return ctrl.Result{}, nil
+	}
+
+	terminated := latest.LastTerminationState.Terminated
+	eventbus.Publish(&eventbus.JobEvent{
+		Type:         eventbus.JobEventRestart,
+		Kind:         eventbus.JobKindVcJob,
+		JobName:      record.JobName,
+		UserID:       record.UserID,
+		AccountID:    record.AccountID,
+		Reason:       terminated.Reason,
+		Message:      fmt.Sprintf("exit code %d", terminated.ExitCode),
+		PodName:      pod.Name,
+		Container:    latest.Name,
+		RestartCount: latest.RestartCount,
+		Timestamp:    terminated.FinishedAt.Time,
+	})
+	return ctrl.Result{}, nil
+}
@@ -44,6 +44,7 @@ import (
 	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
+	"github.com/raids-lab/crater/pkg/eventbus"
 	"github.com/raids-lab/crater/pkg/monitor"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -76,11 +77,14 @@ func NewVcJobReconciler(
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VcJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := ctrl.NewControllerManagedBy(mgr).
 		Named("vcjob-reconciler").
 		For(&batch.Job{}).
 		WithOptions(controller.Options{}).
-		Complete(r)
+		Complete(r); err != nil {
+		return err
+	}
+	return r.setupPodRestartWithManager(mgr)
 }
 
 //+kubebuilder:rbac:groups=aisystem.github.com,resources=aijobs,verbs=get;list;watch;create;update;patch;delete
@@ -172,6 +176,7 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		if info.RowsAffected == 0 {
 			logger.Info("job not found in database")
 		}
+		r.emitJobEvent(ctx, record, model.Freed)
 		return ctrl.Result{}, nil
 	}
 
@@ -195,6 +200,7 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 			logger.Error(err, "unable to create job record")
 			return ctrl.Result{Requeue: true}, err
 		}
+		r.emitJobEvent(ctx, newRecord, newRecord.Status)
 		return ctrl.Result{}, nil
 	}
 
@@ -232,9 +238,64 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	// 作业状态变化后，通知用户注册的 Webhook 和前端
+	if job.Status.State.Phase != oldRecord.Status {
+		r.emitJobEvent(ctx, oldRecord, job.Status.State.Phase)
+	}
+	if updateRecord.Events != nil {
+		r.publishKubeEvents(oldRecord, updateRecord.Events.Data())
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// emitJobEvent 在作业记录更新后发送作业生命周期事件，失败时只记录日志，不影响作业状态同步
+func (r *VcJobReconciler) emitJobEvent(ctx context.Context, record *model.Job, phase batch.JobPhase) {
+	eventbus.Publish(&eventbus.JobEvent{
+		Type:      eventbus.JobEventPhase,
+		Kind:      eventbus.JobKindVcJob,
+		JobName:   record.JobName,
+		UserID:    record.UserID,
+		AccountID: record.AccountID,
+		Phase:     string(phase),
+	})
+	if err := alert.EmitJobEvent(ctx, record.JobName); err != nil {
+		r.log.Error(err, "unable to emit job webhook event", "job", record.JobName)
+	}
+}
+
+// publishKubeEvents 向前端推送作业新产生的 Kubernetes 事件。
+// events 包含已记录的旧事件，只推送 UID 不在旧记录中的事件，避免每次同步重复推送
+func (r *VcJobReconciler) publishKubeEvents(record *model.Job, events []v1.Event) {
+	known := make(map[types.UID]struct{})
+	if record.Events != nil {
+		for _, event := range record.Events.Data() {
+			known[event.UID] = struct{}{}
+		}
+	}
+	for i := range events {
+		event := &events[i]
+		if _, ok := known[event.UID]; ok {
+			continue
+		}
+		timestamp := event.LastTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = event.EventTime.Time
+		}
+		eventbus.Publish(&eventbus.JobEvent{
+			Type:      eventbus.JobEventEvent,
+			Kind:      eventbus.JobKindVcJob,
+			JobName:   record.JobName,
+			UserID:    record.UserID,
+			AccountID: record.AccountID,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			PodName:   event.InvolvedObject.Name,
+			Timestamp: timestamp,
+		})
+	}
+}
+
 func (r *VcJobReconciler) generateCreateJobModel(ctx context.Context, job *batch.Job) (*model.Job, error) {
 	resources := make(v1.ResourceList, 0)
 	for i := range job.Spec.Tasks {
@@ -0,0 +1,189 @@
+// Package sshkey 管理用户登记的 SSH 公钥：解析校验公钥，并将用户未撤销的公钥同步到作业命名空间中的 ConfigMap。
+// 作业容器挂载该 ConfigMap，开启 SSH 时将公钥复制到 root 所有的目录作为 sshd 的 AuthorizedKeysFile，
+// 并在后台定期重新复制，撤销公钥后运行中的容器也会在一个同步周期内生效
+package sshkey
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	// MountPath 公钥 ConfigMap 在容器中的挂载目录，不使用 SubPath，ConfigMap 更新后 kubelet 会同步到容器中
+	MountPath = "/etc/crater/ssh"
+	// AuthorizedKeysDir 容器中存放公钥副本的目录。ConfigMap 挂载的文件是指向 ..data 的符号链接，
+	// 所有者和权限不受控制，sshd 开启 StrictModes 时会拒绝读取，因此复制到 root 所有的目录中
+	AuthorizedKeysDir = "/etc/ssh/crater"
+	// AuthorizedKeysFile sshd 读取的公钥文件
+	AuthorizedKeysFile = AuthorizedKeysDir + "/authorized_keys"
+	// syncIntervalSeconds 容器中重新复制公钥的间隔
+	syncIntervalSeconds = 30
+
+	authorizedKeysKey = "authorized_keys"
+	sshdConfigFile    = "/etc/ssh/sshd_config"
+	volumeName        = "ssh-keys-volume"
+	labelKeySSHKeys   = "crater.raids.io/ssh-keys"
+	// minRSAKeyBits RSA 公钥的最小长度
+	minRSAKeyBits = 2048
+)
+
+// ParsedKey 解析后的公钥
+type ParsedKey struct {
+	KeyType     string
+	PublicKey   string // authorized_keys 格式，不含选项和注释
+	Fingerprint string // SHA256:...
+	Comment     string
+}
+
+// Parse 解析 authorized_keys 格式的单个公钥，去掉选项和注释，拒绝 DSA 和过短的 RSA 公钥
+func Parse(authorizedKey string) (*ParsedKey, error) {
+	pub, comment, _, rest, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(authorizedKey)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, errors.New("only one public key can be added at a time")
+	}
+
+	switch pub.Type() {
+	case ssh.KeyAlgoDSA:
+		return nil, errors.New("DSA keys are not supported")
+	case ssh.KeyAlgoRSA:
+		if cpk, ok := pub.(ssh.CryptoPublicKey); ok {
+			if rsaKey, ok := cpk.CryptoPublicKey().(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSAKeyBits {
+				return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+			}
+		}
+	}
+
+	return &ParsedKey{
+		KeyType:     pub.Type(),
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		Comment:     comment,
+	}, nil
+}
+
+// ConfigMapName 用户公钥 ConfigMap 的名称，用户名不一定符合 Kubernetes 命名规则，因此使用用户ID
+func ConfigMapName(userID uint) string {
+	return fmt.Sprintf("ssh-keys-%d", userID)
+}
+
+// Volume 返回挂载用户公钥的卷。ConfigMap 不存在时 (用户没有登记公钥) 不影响作业启动
+func Volume(userID uint) (v1.Volume, v1.VolumeMount) {
+	volume := v1.Volume{
+		Name: volumeName,
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: ConfigMapName(userID),
+				},
+				//nolint:mnd // authorized_keys must not be writable by others
+				DefaultMode: ptr.To(int32(0644)),
+				Optional:    ptr.To(true),
+			},
+		},
+	}
+	mount := v1.VolumeMount{
+		Name:      volumeName,
+		MountPath: MountPath,
+		ReadOnly:  true,
+	}
+	return volume, mount
+}
+
+// AuthorizedKeys 生成 authorized_keys 文件内容，注释中记录公钥ID以便排查
+func AuthorizedKeys(keys []*model.SSHKey) string {
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s crater-key-%d\n", key.PublicKey, key.ID)
+	}
+	return b.String()
+}
+
+// Sync 将用户未撤销的公钥同步到作业命名空间中的 ConfigMap
+func Sync(ctx context.Context, kubeClient kubernetes.Interface, userID uint) error {
+	k := query.SSHKey
+	keys, err := k.WithContext(ctx).Where(k.UserID.Eq(userID), k.RevokedAt.IsNull()).Order(k.ID).Find()
+	if err != nil {
+		return fmt.Errorf("list ssh keys of user %d: %w", userID, err)
+	}
+
+	namespace := config.GetConfig().Namespaces.Job
+	name := ConfigMapName(userID)
+	data := map[string]string{authorizedKeysKey: AuthorizedKeys(keys)}
+	configMaps := kubeClient.CoreV1().ConfigMaps(namespace)
+
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{labelKeySSHKeys: "true"},
+			},
+			Data: data,
+		}, metav1.CreateOptions{})
+	} else if err == nil {
+		cm.Data = data
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("sync ssh keys of user %d to configmap %s/%s: %w", userID, namespace, name, err)
+	}
+	return nil
+}
+
+// setupScriptTemplate 开启 SSH 时在容器中执行的脚本片段：
+//  1. 将挂载的公钥复制到 root 所有的目录，ConfigMap 不存在时写入空文件
+//  2. 启动后台循环定期重新复制，已在运行时不重复启动
+//  3. 在 sshd_config 第一个 Match 块之前注释掉原有的 AuthorizedKeysFile 并加入新的配置，
+//     追加到文件末尾时会落入 Match 块中，对其他连接不生效
+//
+// 参数依次为：目录、目录权限、文件权限、挂载的公钥文件、复制后的公钥文件、同步间隔、sshd_config
+const setupScriptTemplate = `mkdir -p %[1]s && chown root:root %[1]s && chmod %[2]s %[1]s &&
+crater_sync_ssh_keys() {
+	if [ -f %[4]s ]; then
+		install -o root -g root -m %[3]s %[4]s %[5]s.tmp;
+	else
+		: > %[5]s.tmp && chown root:root %[5]s.tmp && chmod %[3]s %[5]s.tmp;
+	fi && mv -f %[5]s.tmp %[5]s;
+} &&
+crater_sync_ssh_keys &&
+if ! { [ -f %[1]s/sync.pid ] && kill -0 "$(cat %[1]s/sync.pid)" 2>/dev/null; }; then
+	( while sleep %[6]d; do crater_sync_ssh_keys; done ) </dev/null >/dev/null 2>&1 &
+	echo $! > %[1]s/sync.pid;
+fi &&
+if ! grep -q "%[5]s" %[7]s; then
+	awk -v line="AuthorizedKeysFile .ssh/authorized_keys %[5]s" '
+		!done && tolower($1) == "match" { print line; done = 1 }
+		!done && tolower($1) == "authorizedkeysfile" { print "#" $0; next }
+		{ print }
+		END { if (!done) print line }
+	' %[7]s > %[7]s.crater && cat %[7]s.crater > %[7]s && rm -f %[7]s.crater;
+fi`
+
+// SetupScript 返回开启 SSH 时配置 sshd 读取用户公钥的脚本片段，需要以 root 身份执行。
+// sshd 以登录用户的身份读取 AuthorizedKeysFile，登录用户不是 root 时目录和文件需要对其可读
+func SetupScript() string {
+	dirMode, fileMode := "0700", "0600"
+	if user := config.GetConfig().SSH.LoginUser; user != "" && user != "root" {
+		dirMode, fileMode = "0755", "0644"
+	}
+	return fmt.Sprintf(setupScriptTemplate, AuthorizedKeysDir, dirMode, fileMode,
+		MountPath+"/"+authorizedKeysKey, AuthorizedKeysFile, syncIntervalSeconds, sshdConfigFile)
+}
@@ -0,0 +1,68 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func authorizedKey(t *testing.T, key any) string {
+	t.Helper()
+	pub, err := ssh.NewPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+}
+
+func TestParse(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := authorizedKey(t, pub)
+
+	parsed, err := Parse(`command="rm -rf /" ` + line + " alice@laptop\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.PublicKey != line {
+		t.Errorf("options and comment should be stripped, got %q", parsed.PublicKey)
+	}
+	if parsed.KeyType != ssh.KeyAlgoED25519 || parsed.Comment != "alice@laptop" {
+		t.Errorf("unexpected parsed key %+v", parsed)
+	}
+	if !strings.HasPrefix(parsed.Fingerprint, "SHA256:") {
+		t.Errorf("unexpected fingerprint %q", parsed.Fingerprint)
+	}
+
+	if _, err := Parse(line + "\n" + line); err == nil {
+		t.Error("expected error for multiple keys")
+	}
+	if _, err := Parse("ssh-ed25519 not-base64"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(authorizedKey(t, &weak.PublicKey)); err == nil {
+		t.Error("expected error for 1024-bit RSA key")
+	}
+}
+
+func TestAuthorizedKeys(t *testing.T) {
+	keys := []*model.SSHKey{{PublicKey: "ssh-ed25519 AAAA"}, {PublicKey: "ssh-rsa BBBB"}}
+	keys[0].ID, keys[1].ID = 1, 2
+	want := "ssh-ed25519 AAAA crater-key-1\nssh-rsa BBBB crater-key-2\n"
+	if got := AuthorizedKeys(keys); got != want {
+		t.Errorf("AuthorizedKeys() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"os"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+var (
+	replicaName     string
+	replicaNameOnce sync.Once
+)
+
+// GetReplicaName 返回当前后端副本的名称，用于标识定时任务等由哪个副本执行。
+// 优先使用 Downward API 注入的 POD_NAME 环境变量，否则回退为主机名。
+func GetReplicaName() string {
+	replicaNameOnce.Do(func() {
+		if name, ok := os.LookupEnv("POD_NAME"); ok && name != "" {
+			replicaName = name
+			return
+		}
+		hostname, err := os.Hostname()
+		if err != nil {
+			klog.Errorf("Failed to get hostname: %v", err)
+			replicaName = "unknown"
+			return
+		}
+		replicaName = hostname
+	})
+	return replicaName
+}